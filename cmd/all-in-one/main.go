@@ -0,0 +1,401 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+	_ "time/tzdata" // embed the time zone database for airport-local hours
+
+	"github.com/ansoncht/flight-microservices/internal/allinone/config"
+	posterClient "github.com/ansoncht/flight-microservices/internal/poster/client"
+	posterConfig "github.com/ansoncht/flight-microservices/internal/poster/config"
+	posterService "github.com/ansoncht/flight-microservices/internal/poster/service"
+	processorConfig "github.com/ansoncht/flight-microservices/internal/processor/config"
+	processorService "github.com/ansoncht/flight-microservices/internal/processor/service"
+	readerClient "github.com/ansoncht/flight-microservices/internal/reader/client"
+	readerConfig "github.com/ansoncht/flight-microservices/internal/reader/config"
+	readerService "github.com/ansoncht/flight-microservices/internal/reader/service"
+	appHTTP "github.com/ansoncht/flight-microservices/pkg/http"
+	"github.com/ansoncht/flight-microservices/pkg/kafka"
+	"github.com/ansoncht/flight-microservices/pkg/logger"
+	"github.com/ansoncht/flight-microservices/pkg/memory"
+	"github.com/ansoncht/flight-microservices/pkg/metrics"
+	"github.com/ansoncht/flight-microservices/pkg/repository"
+	"github.com/ansoncht/flight-microservices/pkg/tracing"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	timeout = 10 * time.Second
+	// processorGroupID specifies the consumer group of the processor on the flights topic.
+	processorGroupID = "processor"
+	// posterGroupID specifies the consumer group of the poster on the summaries topic.
+	posterGroupID = "poster"
+)
+
+func main() {
+	// Create a context that listens for OS interrupt signals (e.g., Ctrl+C)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		slog.Error("Failed to load config", "error", err)
+		return
+	}
+
+	// Create a customized logger
+	logger, err := logger.NewLogger(cfg.LoggerConfig)
+	if err != nil {
+		slog.Warn("Failed to create custom logger, using default logger instead", "error", err)
+	}
+
+	slog.SetDefault(&logger)
+
+	// Export the spans of the service, or discard them when tracing is disabled
+	tracerProvider, err := tracing.NewTracerProvider(ctx, cfg.TracingConfig, "flight-all-in-one")
+	if err != nil {
+		slog.Error("Failed to create tracer provider", "error", err)
+		return
+	}
+
+	defer func() {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		if err := tracerProvider.Close(shutdownCtx); err != nil {
+			slog.Error("Failed to shutdown tracer provider", "error", err)
+		}
+	}()
+
+	httpClient, err := appHTTP.NewClient(cfg.HTTPClientConfig)
+	if err != nil {
+		slog.Error("Failed to create HTTP client", "error", err)
+		return
+	}
+
+	// Connect the services through an in-memory message bus and keep summaries in memory
+	broker := memory.NewBroker()
+	summaries := repository.NewMemorySummaryRepository()
+	rollups := repository.NewMemoryRollupRepository()
+
+	// Create reader service to fetch flight and route data
+	reader, err := initializeReaderService(
+		cfg.FlightAPIClientConfig,
+		cfg.RouteAPIClientConfig,
+		broker,
+		cfg.TopicsConfig,
+		httpClient,
+	)
+	if err != nil {
+		slog.Error("Failed to initialize reader service", "error", err)
+		return
+	}
+
+	// Create processor service to gather statistic
+	processor, err := initializeProcessorService(
+		broker,
+		cfg.TopicsConfig,
+		cfg.SummarizerConfig,
+		cfg.AnomalyConfig,
+		summaries,
+		rollups,
+	)
+	if err != nil {
+		slog.Error("Failed to initialize processor service", "error", err)
+		return
+	}
+
+	// Create posters for different social media
+	poster, err := initializePoster(
+		ctx,
+		cfg.PosterConfig,
+		cfg.ThreadsClientConfig,
+		cfg.TwitterClientConfig,
+		broker,
+		cfg.TopicsConfig,
+		httpClient,
+		summaries,
+		rollups,
+	)
+	if err != nil {
+		slog.Error("Failed to initialize poster service", "error", err)
+		return
+	}
+
+	// Check the upstream APIs for the readiness probe
+	checkers, err := initializeCheckers(cfg.FlightAPIClientConfig, cfg.RouteAPIClientConfig, httpClient)
+	if err != nil {
+		slog.Error("Failed to create health checkers", "error", err)
+		return
+	}
+
+	// Create a new HTTP server and handler
+	httpServer, err := initializeHTTPServerWithHandler(cfg.HTTPServerConfig, reader, checkers)
+	if err != nil {
+		slog.Error("Failed to create HTTP server with handler", "error", err)
+		return
+	}
+
+	if err := startBackgroundJobs(ctx, httpServer, processor, poster); err != nil {
+		slog.Error("Failed to run background jobs concurrently", "error", err)
+	}
+
+	safeShutDown(httpClient, reader, processor, poster)
+
+	slog.Info("Flight all-in-one service has fully stopped")
+}
+
+// initializeHTTPServerWithHandler initializes the http server with a handler to trigger reader's workflow
+// and the metrics and health probes of the service.
+func initializeHTTPServerWithHandler(
+	httpCfg appHTTP.ServerConfig,
+	reader *readerService.Reader,
+	checkers map[string]appHTTP.Checker,
+) (*appHTTP.HTTP, error) {
+	health, err := appHTTP.NewHealth(checkers, httpCfg.Timeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create health probes: %w", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/fetch", reader.HTTPHandler)
+	mux.Handle(metrics.Path, metrics.Handler())
+	health.Register(mux)
+
+	httpServer, err := appHTTP.NewServer(httpCfg, mux)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP server: %w", err)
+	}
+
+	return httpServer, nil
+}
+
+// initializeCheckers initializes the checkers of the upstream APIs.
+func initializeCheckers(
+	flightCfg readerConfig.FlightAPIConfig,
+	routeCfg readerConfig.RouteAPIConfig,
+	httpClient *http.Client,
+) (map[string]appHTTP.Checker, error) {
+	flightAPI, err := appHTTP.NewEndpointChecker(httpClient, flightCfg.URL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create flight api checker: %w", err)
+	}
+
+	routeAPI, err := appHTTP.NewEndpointChecker(httpClient, routeCfg.URL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create route api checker: %w", err)
+	}
+
+	return map[string]appHTTP.Checker{
+		"flight_api": flightAPI,
+		"route_api":  routeAPI,
+	}, nil
+}
+
+// initializeReaderService initializes the reader service writing to the flights topic. Failed flights are dropped,
+// as there is no dead-letter topic in memory.
+func initializeReaderService(
+	flightCfg readerConfig.FlightAPIConfig,
+	routeCfg readerConfig.RouteAPIConfig,
+	broker *memory.Broker,
+	topicsCfg config.TopicsConfig,
+	httpClient *http.Client,
+) (*readerService.Reader, error) {
+	flightClient, err := readerClient.NewFlightAPI(flightCfg, httpClient)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create flight api client: %w", err)
+	}
+
+	routeClient, err := readerClient.NewRouteAPI(routeCfg, httpClient)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create route api client: %w", err)
+	}
+
+	messageWriter, err := memory.NewMemoryWriter(broker, memory.WriterConfig{Topic: topicsCfg.Flights})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create message writer: %w", err)
+	}
+
+	reader, err := readerService.NewReader(flightClient, routeClient, messageWriter, &kafka.NopDeadLetterWriter{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create reader service: %w", err)
+	}
+
+	return reader, nil
+}
+
+// initializeProcessorService initializes the processor service reading the flights topic and writing to the
+// summaries topic. Failed records are dropped, as there is no dead-letter topic in memory.
+func initializeProcessorService(
+	broker *memory.Broker,
+	topicsCfg config.TopicsConfig,
+	summarizerCfg processorConfig.SummarizerConfig,
+	anomalyCfg processorConfig.AnomalyConfig,
+	repo repository.SummaryRepository,
+	rollups repository.RollupRepository,
+) (*processorService.Processor, error) {
+	messageWriter, err := memory.NewMemoryWriter(broker, memory.WriterConfig{Topic: topicsCfg.Summaries})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create message writer: %w", err)
+	}
+
+	messageReader, err := memory.NewMemoryReader(
+		broker,
+		memory.ReaderConfig{Topic: topicsCfg.Flights, GroupID: processorGroupID},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create message reader: %w", err)
+	}
+
+	summarizer, err := processorService.NewSummarizer(summarizerCfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create summarizer: %w", err)
+	}
+
+	roller, err := processorService.NewRollup(summarizerCfg, repo, rollups)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create rollup: %w", err)
+	}
+
+	detector, err := processorService.NewAnomalyDetector(anomalyCfg, repo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create anomaly detector: %w", err)
+	}
+
+	processor, err := processorService.NewProcessor(
+		messageWriter,
+		messageReader,
+		summarizer,
+		repo,
+		roller,
+		detector,
+		&kafka.NopDeadLetterWriter{},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create processor service: %w", err)
+	}
+
+	return processor, nil
+}
+
+// initializePoster initializes the poster service reading the summaries topic. Posts are logged instead of
+// published when running dry, and failed posts are dropped, as there is no dead-letter topic in memory.
+func initializePoster(
+	ctx context.Context,
+	posterCfg config.PosterConfig,
+	threadsCfg posterConfig.ThreadsAPIConfig,
+	twitterCfg posterConfig.TwitterAPIConfig,
+	broker *memory.Broker,
+	topicsCfg config.TopicsConfig,
+	httpClient *http.Client,
+	repo repository.SummaryRepository,
+	rollups repository.RollupRepository,
+) (*posterService.Poster, error) {
+	clients := []posterClient.Socials{posterClient.NewLogPoster()}
+
+	if !posterCfg.DryRun {
+		// Create posters for different social media
+		threads, err := posterClient.NewThreadsAPI(ctx, threadsCfg, httpClient)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Threads client: %w", err)
+		}
+
+		twitter, err := posterClient.NewTwitterAPI(twitterCfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Twitter client: %w", err)
+		}
+
+		clients = []posterClient.Socials{threads, twitter}
+	}
+
+	messageReader, err := memory.NewMemoryReader(
+		broker,
+		memory.ReaderConfig{Topic: topicsCfg.Summaries, GroupID: posterGroupID},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create message reader: %w", err)
+	}
+
+	poster, err := posterService.NewPoster(clients, messageReader, repo, rollups, &kafka.NopDeadLetterWriter{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create poster service: %w", err)
+	}
+
+	return poster, nil
+}
+
+// startBackgroundJobs starts the HTTP server, the processor and the poster in background until the context is done.
+func startBackgroundJobs(
+	ctx context.Context,
+	httpServer *appHTTP.HTTP,
+	processor *processorService.Processor,
+	poster *posterService.Poster,
+) error {
+	// Use errgroup to manage concurrent tasks
+	g, gCtx := errgroup.WithContext(ctx)
+
+	g.Go(func() error {
+		if err := httpServer.Serve(gCtx); err != nil && !errors.Is(err, context.Canceled) {
+			return fmt.Errorf("failed to start HTTP server: %w", err)
+		}
+
+		return nil
+	})
+
+	g.Go(func() error {
+		if err := processor.Process(gCtx); err != nil && !errors.Is(err, context.Canceled) {
+			return fmt.Errorf("failed to run processor: %w", err)
+		}
+
+		return nil
+	})
+
+	g.Go(func() error {
+		if err := poster.Post(gCtx); err != nil && !errors.Is(err, context.Canceled) {
+			return fmt.Errorf("failed to run poster: %w", err)
+		}
+
+		return nil
+	})
+
+	g.Go(func() error {
+		<-gCtx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		slog.Info("Shutting down background jobs")
+		if err := httpServer.Close(shutdownCtx); err != nil {
+			return fmt.Errorf("failed to shutdown HTTP server: %w", err)
+		}
+
+		return nil
+	})
+
+	// Wait for all goroutines to finish
+	if err := g.Wait(); err != nil {
+		return fmt.Errorf("failed to run services: %w", err)
+	}
+
+	return nil
+}
+
+// safeShutDown shuts down the http client and the services gracefully.
+func safeShutDown(
+	httpClient *http.Client,
+	reader *readerService.Reader,
+	processor *processorService.Processor,
+	poster *posterService.Poster,
+) {
+	httpClient.CloseIdleConnections()
+	reader.Close()
+	processor.MessageReader.Close()
+	processor.DeadLetterWriter.Close()
+	poster.Close()
+}
@@ -2,25 +2,36 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/ansoncht/flight-microservices/internal/poster/client"
 	"github.com/ansoncht/flight-microservices/internal/poster/service"
+	"github.com/ansoncht/flight-microservices/pkg/bus"
 	appHTTP "github.com/ansoncht/flight-microservices/pkg/http"
-	"github.com/ansoncht/flight-microservices/pkg/kafka"
-	"github.com/ansoncht/flight-microservices/pkg/mongo"
+	"github.com/ansoncht/flight-microservices/pkg/metrics"
 	"github.com/ansoncht/flight-microservices/pkg/repository"
 
 	"github.com/ansoncht/flight-microservices/internal/poster/config"
+	"github.com/ansoncht/flight-microservices/pkg/kafka"
 	"github.com/ansoncht/flight-microservices/pkg/logger"
+	"github.com/ansoncht/flight-microservices/pkg/tracing"
 	"golang.org/x/sync/errgroup"
 )
 
+const (
+	// replayDLQCommand specifies the subcommand which re-injects dead letters into their original topics and exits.
+	replayDLQCommand = "replay-dlq"
+	// timeout specifies how long the admin server and tracer provider wait for in-flight work when shutting down.
+	timeout = 10 * time.Second
+)
+
 func main() {
 	// Create a context that listens for OS interrupt signals (e.g., Ctrl+C)
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
@@ -40,21 +51,48 @@ func main() {
 
 	slog.SetDefault(&logger)
 
-	httpClient, err := appHTTP.NewClient(cfg.HTTPClientConfig)
+	// Export the spans of the service, or discard them when tracing is disabled
+	tracerProvider, err := tracing.NewTracerProvider(ctx, cfg.TracingConfig, "flight-poster")
 	if err != nil {
-		slog.Error("Failed to create HTTP client", "error", err)
+		slog.Error("Failed to create tracer provider", "error", err)
 		return
 	}
 
-	mongoDB, err := mongo.NewMongoClient(ctx, cfg.MongoClientConfig)
+	defer func() {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		if err := tracerProvider.Close(shutdownCtx); err != nil {
+			slog.Error("Failed to shutdown tracer provider", "error", err)
+		}
+	}()
+
+	// Replay dead letters only and exit when running the replay-dlq subcommand
+	if len(os.Args) > 1 && os.Args[1] == replayDLQCommand {
+		replayed, err := bus.ReplayDeadLetters(ctx, cfg.BusConfig, cfg.DLQConfig, nil)
+		if err != nil {
+			slog.Error("Failed to replay dead letters", "error", err, "replayed", replayed)
+			return
+		}
+
+		slog.Info("Dead letters replayed successfully", "replayed", replayed)
+		return
+	}
+
+	httpClient, err := appHTTP.NewClient(cfg.HTTPClientConfig)
 	if err != nil {
-		slog.Error("Failed to create MongoDB client", "error", err)
+		slog.Error("Failed to create HTTP client", "error", err)
 		return
 	}
 
-	repo, err := repository.NewMongoSummaryRepository(mongoDB)
+	repos, err := repository.NewRepositories(
+		ctx,
+		cfg.RepositoryConfig,
+		cfg.MongoClientConfig,
+		cfg.PostgresClientConfig,
+	)
 	if err != nil {
-		slog.Error("Failed to create summary repository", "error", err)
+		slog.Error("Failed to create repositories", "error", err)
 		return
 	}
 
@@ -63,23 +101,40 @@ func main() {
 		ctx,
 		cfg.ThreadsClientConfig,
 		cfg.TwitterClientConfig,
-		cfg.KafkaReaderConfig,
+		cfg.BusConfig,
+		bus.ReaderConfigs{
+			Kafka: cfg.KafkaReaderConfig,
+			NATS:  cfg.NATSReaderConfig,
+			Redis: cfg.RedisReaderConfig,
+		},
+		cfg.DLQConfig,
 		httpClient,
-		repo,
+		repos.Summaries,
+		repos.Rollups,
 	)
 	if err != nil {
 		slog.Error("Failed to initialize poster service", "error", err)
 		return
 	}
 
-	// Run the poster in background
-	if err := startBackgroundJobs(ctx, poster); err != nil {
+	// Create the admin server to expose metrics and health probes
+	adminServer, err := initializeAdminServer(cfg.AdminServerConfig, map[string]appHTTP.Checker{
+		cfg.BusConfig.Driver:        poster,
+		cfg.RepositoryConfig.Driver: appHTTP.CheckerFunc(repos.Check),
+	})
+	if err != nil {
+		slog.Error("Failed to create admin server", "error", err)
+		return
+	}
+
+	// Run the admin server and the poster in background
+	if err := startBackgroundJobs(ctx, adminServer, poster); err != nil {
 		slog.Error("Failed to run background jobs concurrently", "error", err)
 		return
 	}
 
 	// Perform a safe shutdown
-	if err := safeShutDown(ctx, poster, mongoDB); err != nil {
+	if err := safeShutDown(ctx, poster, repos.Close); err != nil {
 		slog.Error("Failed to perform graceful shutdown", "error", err)
 		return
 	}
@@ -91,9 +146,12 @@ func initializePoster(
 	ctx context.Context,
 	threadsCfg config.ThreadsAPIConfig,
 	twittercfg config.TwitterAPIConfig,
-	kafkaReaderCfg kafka.ReaderConfig,
+	busCfg bus.Config,
+	readerCfgs bus.ReaderConfigs,
+	dlqCfg kafka.DLQConfig,
 	httpClient *http.Client,
 	repo repository.SummaryRepository,
+	rollups repository.RollupRepository,
 ) (*service.Poster, error) {
 	// Create posters for different social media
 	threads, err := client.NewThreadsAPI(ctx, threadsCfg, httpClient)
@@ -106,13 +164,18 @@ func initializePoster(
 		return nil, fmt.Errorf("failed to create Twitter client: %w", err)
 	}
 
-	kafkaReader, err := kafka.NewKafkaReader(kafkaReaderCfg)
+	messageReader, err := bus.NewMessageReader(busCfg, readerCfgs)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create kafka reader: %w", err)
+		return nil, fmt.Errorf("failed to create message reader: %w", err)
+	}
+
+	deadLetterWriter, err := bus.NewDeadLetterWriter(busCfg, dlqCfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create dead letter writer: %w", err)
 	}
 
 	clients := []client.Socials{threads, twitter}
-	poster, err := service.NewPoster(clients, kafkaReader, repo)
+	poster, err := service.NewPoster(clients, messageReader, repo, rollups, deadLetterWriter)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create poster service: %w", err)
 	}
@@ -120,11 +183,54 @@ func initializePoster(
 	return poster, nil
 }
 
-// startBackgroundJobs starts the processor service in background.
-func startBackgroundJobs(ctx context.Context, poster *service.Poster) error {
+// initializeAdminServer initializes the admin server exposing the metrics and health probes of the service.
+func initializeAdminServer(
+	adminCfg appHTTP.ServerConfig,
+	checkers map[string]appHTTP.Checker,
+) (*appHTTP.HTTP, error) {
+	health, err := appHTTP.NewHealth(checkers, adminCfg.Timeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create health probes: %w", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(metrics.Path, metrics.Handler())
+	health.Register(mux)
+
+	adminServer, err := appHTTP.NewServer(adminCfg, mux)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP server: %w", err)
+	}
+
+	return adminServer, nil
+}
+
+// startBackgroundJobs starts the admin server and the poster service in background.
+func startBackgroundJobs(ctx context.Context, adminServer *appHTTP.HTTP, poster *service.Poster) error {
 	// Use errgroup to manage concurrent tasks
 	g, gCtx := errgroup.WithContext(ctx)
 
+	// Start the admin server until the service stops
+	g.Go(func() error {
+		if err := adminServer.Serve(gCtx); err != nil && !errors.Is(err, context.Canceled) {
+			return fmt.Errorf("failed to start admin server: %w", err)
+		}
+
+		return nil
+	})
+
+	g.Go(func() error {
+		<-gCtx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		if err := adminServer.Close(shutdownCtx); err != nil {
+			return fmt.Errorf("failed to shutdown admin server: %w", err)
+		}
+
+		return nil
+	})
+
 	// Start the processor service
 	g.Go(func() error {
 		return poster.Post(gCtx)
@@ -141,11 +247,11 @@ func startBackgroundJobs(ctx context.Context, poster *service.Poster) error {
 	return nil
 }
 
-// safeShutDown shut down MongoDB client and kafka reader gracefully.
-func safeShutDown(ctx context.Context, poster *service.Poster, mongodb *mongo.Client) error {
-	if err := mongodb.Client.Disconnect(ctx); err != nil {
-		slog.Error("Failed to shutdown MongoDB client", "error", err)
-		return fmt.Errorf("failed to shutdown mongodb client: %w", err)
+// safeShutDown shut down database client and kafka reader gracefully.
+func safeShutDown(ctx context.Context, poster *service.Poster, closeRepo func(context.Context) error) error {
+	if err := closeRepo(ctx); err != nil {
+		slog.Error("Failed to shutdown database client", "error", err)
+		return fmt.Errorf("failed to shutdown database client: %w", err)
 	}
 
 	poster.Close()
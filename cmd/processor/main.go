@@ -2,21 +2,41 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
+	_ "time/tzdata" // embed the time zone database for airport-local hours
 
+	"github.com/ansoncht/flight-microservices/internal/processor/client"
 	"github.com/ansoncht/flight-microservices/internal/processor/config"
 	"github.com/ansoncht/flight-microservices/internal/processor/service"
+	"github.com/ansoncht/flight-microservices/pkg/bus"
+	appHTTP "github.com/ansoncht/flight-microservices/pkg/http"
 	"github.com/ansoncht/flight-microservices/pkg/kafka"
 	"github.com/ansoncht/flight-microservices/pkg/logger"
-	"github.com/ansoncht/flight-microservices/pkg/mongo"
+	"github.com/ansoncht/flight-microservices/pkg/metrics"
+	"github.com/ansoncht/flight-microservices/pkg/msgbus"
+	"github.com/ansoncht/flight-microservices/pkg/postgres"
 	"github.com/ansoncht/flight-microservices/pkg/repository"
+	"github.com/ansoncht/flight-microservices/pkg/tracing"
 	"golang.org/x/sync/errgroup"
 )
 
+const (
+	// migrateCommand specifies the subcommand which applies database migrations and exits.
+	migrateCommand = "migrate"
+	// replayDLQCommand specifies the subcommand which replays dead letters by requesting the reader service to read
+	// their days again and exits.
+	replayDLQCommand = "replay-dlq"
+	// timeout specifies how long the admin server and tracer provider wait for in-flight work when shutting down.
+	timeout = 10 * time.Second
+)
+
 func main() {
 	// Create a context that listens for OS interrupt signals (e.g., Ctrl+C)
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
@@ -36,38 +56,99 @@ func main() {
 
 	slog.SetDefault(&logger)
 
-	mongoDB, err := mongo.NewMongoClient(ctx, cfg.MongoClientConfig)
+	// Export the spans of the service, or discard them when tracing is disabled
+	tracerProvider, err := tracing.NewTracerProvider(ctx, cfg.TracingConfig, "flight-processor")
 	if err != nil {
-		slog.Error("Failed to create MongoDB client", "error", err)
+		slog.Error("Failed to create tracer provider", "error", err)
+		return
+	}
+
+	defer func() {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		if err := tracerProvider.Close(shutdownCtx); err != nil {
+			slog.Error("Failed to shutdown tracer provider", "error", err)
+		}
+	}()
+
+	// Apply database migrations only and exit when running the migrate subcommand
+	if len(os.Args) > 1 && os.Args[1] == migrateCommand {
+		if err := runMigrations(ctx, cfg.PostgresClientConfig); err != nil {
+			slog.Error("Failed to apply database migrations", "error", err)
+			return
+		}
+
+		slog.Info("Database migrations applied successfully")
 		return
 	}
 
-	repo, err := repository.NewMongoSummaryRepository(mongoDB)
+	// Replay dead letters only and exit when running the replay-dlq subcommand
+	if len(os.Args) > 1 && os.Args[1] == replayDLQCommand {
+		replayed, err := replayDeadLetters(ctx, cfg.BusConfig, cfg.DLQConfig, cfg.HTTPClientConfig, cfg.ReaderAPIConfig)
+		if err != nil {
+			slog.Error("Failed to replay dead letters", "error", err, "replayed", replayed)
+			return
+		}
+
+		slog.Info("Dead letters replayed successfully", "replayed", replayed)
+		return
+	}
+
+	repos, err := repository.NewRepositories(
+		ctx,
+		cfg.RepositoryConfig,
+		cfg.MongoClientConfig,
+		cfg.PostgresClientConfig,
+	)
 	if err != nil {
-		slog.Error("Failed to create summary repository", "error", err)
+		slog.Error("Failed to create repositories", "error", err)
 		return
 	}
 
 	// Create processor service to gather statistic
 	processor, err := initializeProcessorService(
-		cfg.KafkaWriterConfig,
-		cfg.KafkaReaderConfig,
+		cfg.BusConfig,
+		bus.WriterConfigs{
+			Kafka: cfg.KafkaWriterConfig,
+			NATS:  cfg.NATSWriterConfig,
+			Redis: cfg.RedisWriterConfig,
+		},
+		bus.ReaderConfigs{
+			Kafka: cfg.KafkaReaderConfig,
+			NATS:  cfg.NATSReaderConfig,
+			Redis: cfg.RedisReaderConfig,
+		},
+		cfg.KafkaTransactConfig,
+		cfg.DLQConfig,
 		cfg.SummarizerConfig,
-		repo,
+		cfg.AnomalyConfig,
+		repos.Summaries,
+		repos.Rollups,
 	)
 	if err != nil {
 		slog.Error("Failed to initialize processor service", "error", err)
 		return
 	}
 
-	// Run the processor in background
-	if err := startBackgroundJobs(ctx, processor); err != nil {
+	// Create the admin server to expose metrics and health probes
+	adminServer, err := initializeAdminServer(cfg.AdminServerConfig, map[string]appHTTP.Checker{
+		cfg.BusConfig.Driver:        processor.MessageReader,
+		cfg.RepositoryConfig.Driver: appHTTP.CheckerFunc(repos.Check),
+	})
+	if err != nil {
+		slog.Error("Failed to create admin server", "error", err)
+		return
+	}
+
+	// Run the admin server and the processor in background
+	if err := startBackgroundJobs(ctx, adminServer, processor); err != nil {
 		slog.Error("Failed to run background jobs concurrently", "error", err)
 		return
 	}
 
 	// Perform a safe shutdown
-	if err := safeShutDown(ctx, processor, mongoDB); err != nil {
+	if err := safeShutDown(ctx, processor, repos.Close); err != nil {
 		slog.Error("Failed to perform graceful shutdown", "error", err)
 		return
 	}
@@ -75,21 +156,69 @@ func main() {
 	slog.Info("Flight Processor service has fully stopped")
 }
 
-// initializeReaderService initializes the processor service.
+// runMigrations applies all pending PostgreSQL migrations.
+func runMigrations(ctx context.Context, postgresCfg postgres.ClientConfig) error {
+	client, err := postgres.NewPostgresClient(ctx, postgresCfg)
+	if err != nil {
+		return fmt.Errorf("failed to create PostgreSQL client: %w", err)
+	}
+	defer client.Close()
+
+	if err := client.Migrate(ctx); err != nil {
+		return fmt.Errorf("failed to migrate PostgreSQL: %w", err)
+	}
+
+	return nil
+}
+
+// replayDeadLetters replays the dead letters of the processor service, requesting the reader service to read the
+// days they were set aside from again.
+func replayDeadLetters(
+	ctx context.Context,
+	busCfg bus.Config,
+	dlqCfg kafka.DLQConfig,
+	httpCfg appHTTP.ClientConfig,
+	readerCfg config.ReaderAPIConfig,
+) (int, error) {
+	httpClient, err := appHTTP.NewClient(httpCfg)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create HTTP client: %w", err)
+	}
+	defer httpClient.CloseIdleConnections()
+
+	readerAPI, err := client.NewReaderAPI(readerCfg, httpClient)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create reader api client: %w", err)
+	}
+
+	replayed, err := bus.ReplayDeadLetters(ctx, busCfg, dlqCfg, readerAPI)
+	if err != nil {
+		return replayed, fmt.Errorf("failed to replay dead letters: %w", err)
+	}
+
+	return replayed, nil
+}
+
+// initializeProcessorService initializes the processor service.
 func initializeProcessorService(
-	kafkaWriterCfg kafka.WriterConfig,
-	kafkaReaderCfg kafka.ReaderConfig,
+	busCfg bus.Config,
+	writerCfgs bus.WriterConfigs,
+	readerCfgs bus.ReaderConfigs,
+	kafkaTransactCfg kafka.TransactConfig,
+	dlqCfg kafka.DLQConfig,
 	summarizerCfg config.SummarizerConfig,
+	anomalyCfg config.AnomalyConfig,
 	repo repository.SummaryRepository,
+	rollups repository.RollupRepository,
 ) (*service.Processor, error) {
-	kafkaWriter, err := kafka.NewKafkaWriter(kafkaWriterCfg)
+	messageWriter, messageReader, err := initializeMessageQueue(busCfg, writerCfgs, readerCfgs, kafkaTransactCfg)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create kafka writer: %w", err)
+		return nil, err
 	}
 
-	kafkaReader, err := kafka.NewKafkaReader(kafkaReaderCfg)
+	deadLetterWriter, err := bus.NewDeadLetterWriter(busCfg, dlqCfg)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create kafka reader: %w", err)
+		return nil, fmt.Errorf("failed to create dead letter writer: %w", err)
 	}
 
 	summarizer, err := service.NewSummarizer(summarizerCfg)
@@ -97,7 +226,25 @@ func initializeProcessorService(
 		return nil, fmt.Errorf("failed to create summarizer: %w", err)
 	}
 
-	processor, err := service.NewProcessor(kafkaWriter, kafkaReader, summarizer, repo)
+	roller, err := service.NewRollup(summarizerCfg, repo, rollups)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create rollup: %w", err)
+	}
+
+	detector, err := service.NewAnomalyDetector(anomalyCfg, repo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create anomaly detector: %w", err)
+	}
+
+	processor, err := service.NewProcessor(
+		messageWriter,
+		messageReader,
+		summarizer,
+		repo,
+		roller,
+		detector,
+		deadLetterWriter,
+	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create processor service: %w", err)
 	}
@@ -105,11 +252,89 @@ func initializeProcessorService(
 	return processor, nil
 }
 
-// startBackgroundJobs starts the processor service in background.
-func startBackgroundJobs(ctx context.Context, processor *service.Processor) error {
+// initializeMessageQueue initializes the message writer and reader of the processor service on the configured
+// message bus, sharing a transactional session when Kafka transactions are enabled.
+func initializeMessageQueue(
+	busCfg bus.Config,
+	writerCfgs bus.WriterConfigs,
+	readerCfgs bus.ReaderConfigs,
+	kafkaTransactCfg kafka.TransactConfig,
+) (msgbus.MessageWriter, msgbus.MessageReader, error) {
+	if kafkaTransactCfg.Enabled {
+		if busCfg.Driver != bus.DriverKafka {
+			return nil, nil, fmt.Errorf("kafka transactions require the kafka message bus driver")
+		}
+
+		session, err := kafka.NewTransactSession(readerCfgs.Kafka, writerCfgs.Kafka, kafkaTransactCfg)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to create kafka transactional session: %w", err)
+		}
+
+		return session, session, nil
+	}
+
+	messageWriter, err := bus.NewMessageWriter(busCfg, writerCfgs)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create message writer: %w", err)
+	}
+
+	messageReader, err := bus.NewMessageReader(busCfg, readerCfgs)
+	if err != nil {
+		messageWriter.Close()
+		return nil, nil, fmt.Errorf("failed to create message reader: %w", err)
+	}
+
+	return messageWriter, messageReader, nil
+}
+
+// initializeAdminServer initializes the admin server exposing the metrics and health probes of the service.
+func initializeAdminServer(
+	adminCfg appHTTP.ServerConfig,
+	checkers map[string]appHTTP.Checker,
+) (*appHTTP.HTTP, error) {
+	health, err := appHTTP.NewHealth(checkers, adminCfg.Timeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create health probes: %w", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(metrics.Path, metrics.Handler())
+	health.Register(mux)
+
+	adminServer, err := appHTTP.NewServer(adminCfg, mux)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP server: %w", err)
+	}
+
+	return adminServer, nil
+}
+
+// startBackgroundJobs starts the admin server and the processor service in background.
+func startBackgroundJobs(ctx context.Context, adminServer *appHTTP.HTTP, processor *service.Processor) error {
 	// Use errgroup to manage concurrent tasks
 	g, gCtx := errgroup.WithContext(ctx)
 
+	// Start the admin server until the service stops
+	g.Go(func() error {
+		if err := adminServer.Serve(gCtx); err != nil && !errors.Is(err, context.Canceled) {
+			return fmt.Errorf("failed to start admin server: %w", err)
+		}
+
+		return nil
+	})
+
+	g.Go(func() error {
+		<-gCtx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		if err := adminServer.Close(shutdownCtx); err != nil {
+			return fmt.Errorf("failed to shutdown admin server: %w", err)
+		}
+
+		return nil
+	})
+
 	// Start the processor service
 	g.Go(func() error {
 		return processor.Process(gCtx)
@@ -126,14 +351,15 @@ func startBackgroundJobs(ctx context.Context, processor *service.Processor) erro
 	return nil
 }
 
-// safeShutDown shut down MongoDB client and kafka reader gracefully.
-func safeShutDown(ctx context.Context, processor *service.Processor, mongodb *mongo.Client) error {
-	if err := mongodb.Client.Disconnect(ctx); err != nil {
-		slog.Error("Failed to shutdown MongoDB client", "error", err)
-		return fmt.Errorf("failed to shutdown mongodb client: %w", err)
+// safeShutDown shut down database client and kafka reader gracefully.
+func safeShutDown(ctx context.Context, processor *service.Processor, closeRepo func(context.Context) error) error {
+	if err := closeRepo(ctx); err != nil {
+		slog.Error("Failed to shutdown database client", "error", err)
+		return fmt.Errorf("failed to shutdown database client: %w", err)
 	}
 
 	processor.MessageReader.Close()
+	processor.DeadLetterWriter.Close()
 
 	return nil
 }
@@ -14,13 +14,20 @@ import (
 	"github.com/ansoncht/flight-microservices/internal/reader/config"
 	"github.com/ansoncht/flight-microservices/internal/reader/service"
 
+	"github.com/ansoncht/flight-microservices/pkg/bus"
 	appHTTP "github.com/ansoncht/flight-microservices/pkg/http"
 	"github.com/ansoncht/flight-microservices/pkg/kafka"
 	"github.com/ansoncht/flight-microservices/pkg/logger"
+	"github.com/ansoncht/flight-microservices/pkg/metrics"
+	"github.com/ansoncht/flight-microservices/pkg/tracing"
 	"golang.org/x/sync/errgroup"
 )
 
-const timeout = 10 * time.Second
+const (
+	// replayDLQCommand specifies the subcommand which replays dead letters by reading their days again and exits.
+	replayDLQCommand = "replay-dlq"
+	timeout          = 10 * time.Second
+)
 
 func main() {
 	// Create a context that listens for OS interrupt signals (e.g., Ctrl+C)
@@ -41,6 +48,22 @@ func main() {
 
 	slog.SetDefault(&logger)
 
+	// Export the spans of the service, or discard them when tracing is disabled
+	tracerProvider, err := tracing.NewTracerProvider(ctx, cfg.TracingConfig, "flight-reader")
+	if err != nil {
+		slog.Error("Failed to create tracer provider", "error", err)
+		return
+	}
+
+	defer func() {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		if err := tracerProvider.Close(shutdownCtx); err != nil {
+			slog.Error("Failed to shutdown tracer provider", "error", err)
+		}
+	}()
+
 	httpClient, err := appHTTP.NewClient(cfg.HTTPClientConfig)
 	if err != nil {
 		slog.Error("Failed to create HTTP client", "error", err)
@@ -51,7 +74,13 @@ func main() {
 	reader, err := initializeReaderService(
 		cfg.FlightAPIClientConfig,
 		cfg.RouteAPIClientConfig,
-		cfg.KafkaWriterConfig,
+		cfg.BusConfig,
+		bus.WriterConfigs{
+			Kafka: cfg.KafkaWriterConfig,
+			NATS:  cfg.NATSWriterConfig,
+			Redis: cfg.RedisWriterConfig,
+		},
+		cfg.DLQConfig,
 		httpClient,
 	)
 	if err != nil {
@@ -59,8 +88,35 @@ func main() {
 		return
 	}
 
+	// Replay dead letters only and exit when running the replay-dlq subcommand
+	if len(os.Args) > 1 && os.Args[1] == replayDLQCommand {
+		defer reader.Close()
+
+		replayed, err := bus.ReplayDeadLetters(ctx, cfg.BusConfig, cfg.DLQConfig, reader)
+		if err != nil {
+			slog.Error("Failed to replay dead letters", "error", err, "replayed", replayed)
+			return
+		}
+
+		slog.Info("Dead letters replayed successfully", "replayed", replayed)
+		return
+	}
+
+	// Check the upstream APIs and the message queue for the readiness probe
+	checkers, err := initializeCheckers(
+		cfg.FlightAPIClientConfig,
+		cfg.RouteAPIClientConfig,
+		cfg.BusConfig,
+		httpClient,
+		reader,
+	)
+	if err != nil {
+		slog.Error("Failed to create health checkers", "error", err)
+		return
+	}
+
 	// Create a new HTTP server and handler
-	httpServer, err := initializeHTTPServerWithHandler(cfg.HTTPServerConfig, reader)
+	httpServer, err := initializeHTTPServerWithHandler(cfg.HTTPServerConfig, reader, checkers)
 	if err != nil {
 		slog.Error("Failed to create HTTP server with handler", "error", err)
 		return
@@ -87,13 +143,22 @@ func main() {
 	}
 }
 
-// initializeHTTPServerWithHandler initializes the http server with a handler to trigger reader's workflow.
+// initializeHTTPServerWithHandler initializes the http server with a handler to trigger reader's workflow
+// and the metrics and health probes of the service.
 func initializeHTTPServerWithHandler(
 	httpCfg appHTTP.ServerConfig,
 	readerService *service.Reader,
+	checkers map[string]appHTTP.Checker,
 ) (*appHTTP.HTTP, error) {
+	health, err := appHTTP.NewHealth(checkers, httpCfg.Timeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create health probes: %w", err)
+	}
+
 	mux := http.NewServeMux()
 	mux.HandleFunc("/api/v1/fetch", readerService.HTTPHandler)
+	mux.Handle(metrics.Path, metrics.Handler())
+	health.Register(mux)
 
 	httpServer, err := appHTTP.NewServer(httpCfg, mux)
 	if err != nil {
@@ -103,11 +168,38 @@ func initializeHTTPServerWithHandler(
 	return httpServer, nil
 }
 
+// initializeCheckers initializes the checkers of the upstream APIs and the message queue.
+func initializeCheckers(
+	flightCfg config.FlightAPIConfig,
+	routeCfg config.RouteAPIConfig,
+	busCfg bus.Config,
+	httpClient *http.Client,
+	reader *service.Reader,
+) (map[string]appHTTP.Checker, error) {
+	flightAPI, err := appHTTP.NewEndpointChecker(httpClient, flightCfg.URL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create flight api checker: %w", err)
+	}
+
+	routeAPI, err := appHTTP.NewEndpointChecker(httpClient, routeCfg.URL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create route api checker: %w", err)
+	}
+
+	return map[string]appHTTP.Checker{
+		"flight_api":  flightAPI,
+		"route_api":   routeAPI,
+		busCfg.Driver: reader,
+	}, nil
+}
+
 // initializeReaderService initializes the reader service.
 func initializeReaderService(
 	flightCfg config.FlightAPIConfig,
 	routeCfg config.RouteAPIConfig,
-	kafkaCfg kafka.WriterConfig,
+	busCfg bus.Config,
+	writerCfgs bus.WriterConfigs,
+	dlqCfg kafka.DLQConfig,
 	httpClient *http.Client,
 ) (*service.Reader, error) {
 	flightClient, err := client.NewFlightAPI(flightCfg, httpClient)
@@ -120,12 +212,17 @@ func initializeReaderService(
 		return nil, fmt.Errorf("failed to create route api client: %w", err)
 	}
 
-	kafkaWriter, err := kafka.NewKafkaWriter(kafkaCfg)
+	messageWriter, err := bus.NewMessageWriter(busCfg, writerCfgs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create message writer: %w", err)
+	}
+
+	deadLetterWriter, err := bus.NewDeadLetterWriter(busCfg, dlqCfg)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create kafka writer: %w", err)
+		return nil, fmt.Errorf("failed to create dead letter writer: %w", err)
 	}
 
-	reader, err := service.NewReader(flightClient, routeClient, kafkaWriter)
+	reader, err := service.NewReader(flightClient, routeClient, messageWriter, deadLetterWriter)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create reader service: %w", err)
 	}
@@ -0,0 +1,67 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	posterConfig "github.com/ansoncht/flight-microservices/internal/poster/config"
+	processorConfig "github.com/ansoncht/flight-microservices/internal/processor/config"
+	readerConfig "github.com/ansoncht/flight-microservices/internal/reader/config"
+	"github.com/ansoncht/flight-microservices/pkg/http"
+	"github.com/ansoncht/flight-microservices/pkg/logger"
+	"github.com/ansoncht/flight-microservices/pkg/tracing"
+	"github.com/spf13/viper"
+)
+
+// FlightAllInOneConfig holds all configurations related to running the reader, processor and poster in one process.
+type FlightAllInOneConfig struct {
+	HTTPServerConfig      http.ServerConfig                `mapstructure:"http_server"`
+	HTTPClientConfig      http.ClientConfig                `mapstructure:"http_client"`
+	FlightAPIClientConfig readerConfig.FlightAPIConfig     `mapstructure:"flight_api"`
+	RouteAPIClientConfig  readerConfig.RouteAPIConfig      `mapstructure:"route_api"`
+	SummarizerConfig      processorConfig.SummarizerConfig `mapstructure:"summarizer"`
+	AnomalyConfig         processorConfig.AnomalyConfig    `mapstructure:"anomaly"`
+	ThreadsClientConfig   posterConfig.ThreadsAPIConfig    `mapstructure:"threads_api"`
+	TwitterClientConfig   posterConfig.TwitterAPIConfig    `mapstructure:"twitter_api"`
+	PosterConfig          PosterConfig                     `mapstructure:"poster"`
+	TopicsConfig          TopicsConfig                     `mapstructure:"topics"`
+	TracingConfig         tracing.Config                   `mapstructure:"tracing"`
+	LoggerConfig          logger.Config                    `mapstructure:"logger"`
+}
+
+// PosterConfig holds configuration settings for the poster.
+type PosterConfig struct {
+	// DryRun specifies whether posts are logged instead of published to social media.
+	DryRun bool `mapstructure:"dry_run"`
+}
+
+// TopicsConfig holds the topics connecting the services on the in-memory message bus.
+type TopicsConfig struct {
+	// Flights specifies the topic the reader writes flights and routes to for the processor.
+	Flights string `mapstructure:"flights"`
+	// Summaries specifies the topic the processor writes summaries to for the poster.
+	Summaries string `mapstructure:"summaries"`
+}
+
+// LoadConfig loads configuration from environment variables and a YAML file.
+func LoadConfig() (*FlightAllInOneConfig, error) {
+	viper.SetConfigName("all-in-one-config")
+	viper.SetConfigType("yaml")
+	viper.AddConfigPath("../../../configs")
+	viper.AddConfigPath("../../configs")
+	viper.AddConfigPath("./configs")
+	viper.AutomaticEnv()
+	viper.SetEnvPrefix("FLIGHT_ALL_IN_ONE")
+	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_", "-", "_"))
+
+	if err := viper.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var cfg FlightAllInOneConfig
+	if err := viper.Unmarshal(&cfg); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
+	}
+
+	return &cfg, nil
+}
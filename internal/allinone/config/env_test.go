@@ -0,0 +1,48 @@
+package config_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/ansoncht/flight-microservices/internal/allinone/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadConfig_ValidConfigFile_ShouldSucceed(t *testing.T) {
+	os.Setenv("FLIGHT_ALL_IN_ONE_FLIGHT_API_URL", "test")
+	os.Setenv("FLIGHT_ALL_IN_ONE_ROUTE_API_URL", "test")
+	os.Setenv("FLIGHT_ALL_IN_ONE_POSTER_DRY_RUN", "false")
+
+	cfg, err := config.LoadConfig()
+	require.NoError(t, err)
+	require.NotNil(t, cfg)
+	require.Equal(t, "8080", cfg.HTTPServerConfig.Port)
+	require.Equal(t, 75, cfg.HTTPServerConfig.Timeout)
+	require.True(t, cfg.HTTPServerConfig.Middleware.AccessLog)
+	require.True(t, cfg.HTTPServerConfig.Middleware.Recover)
+	require.True(t, cfg.HTTPServerConfig.Middleware.RequestID)
+	require.True(t, cfg.HTTPServerConfig.Middleware.Gzip)
+	require.False(t, cfg.HTTPServerConfig.Middleware.CORS.Enabled)
+	require.Empty(t, cfg.HTTPServerConfig.Middleware.CORS.AllowedOrigins)
+	require.Equal(t, []string{"GET", "OPTIONS"}, cfg.HTTPServerConfig.Middleware.CORS.AllowedMethods)
+	require.Equal(t, 600, cfg.HTTPServerConfig.Middleware.CORS.MaxAge)
+	require.Empty(t, cfg.HTTPServerConfig.Middleware.Timeouts)
+	require.Equal(t, 70, cfg.HTTPClientConfig.Timeout)
+	require.Equal(t, "test", cfg.FlightAPIClientConfig.URL)
+	require.Equal(t, "test", cfg.RouteAPIClientConfig.URL)
+	require.Equal(t, 10, cfg.SummarizerConfig.TopN)
+	require.Equal(t, "Asia/Hong_Kong", cfg.SummarizerConfig.Timezones["vhhh"])
+	require.True(t, cfg.SummarizerConfig.Dedup.Enabled)
+	require.Equal(t, "mad", cfg.AnomalyConfig.Method)
+	require.Equal(t, "https://graph.threads.net", cfg.ThreadsClientConfig.URL)
+	require.False(t, cfg.PosterConfig.DryRun)
+	require.Equal(t, "flights", cfg.TopicsConfig.Flights)
+	require.Equal(t, "summaries", cfg.TopicsConfig.Summaries)
+	require.False(t, cfg.TracingConfig.Enabled)
+	require.Equal(t, "otlp", cfg.TracingConfig.Exporter)
+	require.Empty(t, cfg.TracingConfig.Endpoint)
+	require.True(t, cfg.TracingConfig.Insecure)
+	require.InDelta(t, 1.0, cfg.TracingConfig.SampleRatio, 0.001)
+	require.False(t, cfg.LoggerConfig.JSON)
+	require.Equal(t, "info", cfg.LoggerConfig.Level)
+}
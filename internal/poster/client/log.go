@@ -0,0 +1,24 @@
+package client
+
+import (
+	"context"
+	"log/slog"
+)
+
+// LogPoster logs posts instead of publishing them, for running the poster without social media credentials.
+// It implements the Socials interface.
+type LogPoster struct{}
+
+// NewLogPoster creates a new LogPoster instance.
+func NewLogPoster() *LogPoster {
+	slog.Info("Initializing log poster, posts will not be published")
+
+	return &LogPoster{}
+}
+
+// PublishPost logs the content of the post.
+func (p *LogPoster) PublishPost(_ context.Context, content string) error {
+	slog.Info("Logging post instead of publishing it", "content", content)
+
+	return nil
+}
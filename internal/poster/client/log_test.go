@@ -0,0 +1,17 @@
+package client_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ansoncht/flight-microservices/internal/poster/client"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogPoster_PublishPost_ShouldSucceed(t *testing.T) {
+	poster := client.NewLogPoster()
+	require.NotNil(t, poster)
+
+	err := poster.PublishPost(context.Background(), "test post")
+	require.NoError(t, err)
+}
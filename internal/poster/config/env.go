@@ -4,21 +4,35 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/ansoncht/flight-microservices/pkg/bus"
 	"github.com/ansoncht/flight-microservices/pkg/http"
 	"github.com/ansoncht/flight-microservices/pkg/kafka"
 	"github.com/ansoncht/flight-microservices/pkg/logger"
 	"github.com/ansoncht/flight-microservices/pkg/mongo"
+	"github.com/ansoncht/flight-microservices/pkg/nats"
+	"github.com/ansoncht/flight-microservices/pkg/postgres"
+	"github.com/ansoncht/flight-microservices/pkg/redis"
+	"github.com/ansoncht/flight-microservices/pkg/repository"
+	"github.com/ansoncht/flight-microservices/pkg/tracing"
 	"github.com/spf13/viper"
 )
 
 // FlightPosterConfig holds all configurations related to flight poster.
 type FlightPosterConfig struct {
-	ThreadsClientConfig ThreadsAPIConfig   `mapstructure:"threads_api"`
-	TwitterClientConfig TwitterAPIConfig   `mapstructure:"twitter_api"`
-	KafkaReaderConfig   kafka.ReaderConfig `mapstructure:"kafka_reader"`
-	MongoClientConfig   mongo.ClientConfig `mapstructure:"mongo"`
-	HTTPClientConfig    http.ClientConfig  `mapstructure:"http_client"`
-	LoggerConfig        logger.Config      `mapstructure:"logger"`
+	ThreadsClientConfig  ThreadsAPIConfig      `mapstructure:"threads_api"`
+	TwitterClientConfig  TwitterAPIConfig      `mapstructure:"twitter_api"`
+	BusConfig            bus.Config            `mapstructure:"bus"`
+	KafkaReaderConfig    kafka.ReaderConfig    `mapstructure:"kafka_reader"`
+	NATSReaderConfig     nats.ReaderConfig     `mapstructure:"nats_reader"`
+	RedisReaderConfig    redis.ReaderConfig    `mapstructure:"redis_reader"`
+	DLQConfig            kafka.DLQConfig       `mapstructure:"dlq"`
+	RepositoryConfig     repository.Config     `mapstructure:"repository"`
+	MongoClientConfig    mongo.ClientConfig    `mapstructure:"mongo"`
+	PostgresClientConfig postgres.ClientConfig `mapstructure:"postgres"`
+	HTTPClientConfig     http.ClientConfig     `mapstructure:"http_client"`
+	AdminServerConfig    http.ServerConfig     `mapstructure:"admin_server"`
+	TracingConfig        tracing.Config        `mapstructure:"tracing"`
+	LoggerConfig         logger.Config         `mapstructure:"logger"`
 }
 
 // ThreadsAPIConfig holds configuration settings for the Threads api client.
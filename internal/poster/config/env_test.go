@@ -18,6 +18,7 @@ func TestLoadConfig_ValidConfigFile_ShouldSucceed(t *testing.T) {
 	os.Setenv("FLIGHT_POSTER_KAFKA_READER_TOPIC", "test")
 	os.Setenv("FLIGHT_POSTER_KAFKA_READER_GROUP_ID", "test")
 	os.Setenv("FLIGHT_POSTER_MONGO_URI", "mongodb://localhost:27017")
+	os.Setenv("FLIGHT_POSTER_DLQ_TOPIC", "summaries-dlq")
 
 	cfg, err := config.LoadConfig()
 	require.NoError(t, err)
@@ -32,9 +33,40 @@ func TestLoadConfig_ValidConfigFile_ShouldSucceed(t *testing.T) {
 	require.Equal(t, uint64(5), cfg.MongoClientConfig.PoolSize)
 	require.Equal(t, 5, cfg.MongoClientConfig.ConnectionTimeout)
 	require.Equal(t, 5, cfg.MongoClientConfig.SocketTimeout)
+	require.Equal(t, "mongo", cfg.RepositoryConfig.Driver)
+	require.Equal(t, int32(5), cfg.PostgresClientConfig.PoolSize)
+	require.Equal(t, 5, cfg.PostgresClientConfig.ConnectionTimeout)
 	require.Equal(t, "test", cfg.KafkaReaderConfig.Address)
 	require.Equal(t, "test", cfg.KafkaReaderConfig.Topic)
 	require.Equal(t, "test", cfg.KafkaReaderConfig.GroupID)
+	require.Equal(t, 500, cfg.KafkaReaderConfig.MaxPollRecords)
+	require.Equal(t, int32(52428800), cfg.KafkaReaderConfig.FetchMaxBytes)
+	require.Equal(t, int32(1048576), cfg.KafkaReaderConfig.FetchMaxPartitionBytes)
+	require.Equal(t, 5000, cfg.KafkaReaderConfig.FetchMaxWaitMs)
+	require.False(t, cfg.KafkaReaderConfig.TLS.Enabled)
+	require.Empty(t, cfg.KafkaReaderConfig.SASL.Mechanism)
+	require.Equal(t, "kafka", cfg.BusConfig.Driver)
+	require.Equal(t, 500, cfg.NATSReaderConfig.MaxPollRecords)
+	require.Equal(t, 30, cfg.NATSReaderConfig.AckWait)
+	require.Equal(t, 500, cfg.RedisReaderConfig.MaxPollRecords)
+	require.False(t, cfg.DLQConfig.Enabled)
+	require.Equal(t, "summaries-dlq", cfg.DLQConfig.Topic)
+	require.Equal(t, "9091", cfg.AdminServerConfig.Port)
+	require.Equal(t, 5, cfg.AdminServerConfig.Timeout)
+	require.False(t, cfg.AdminServerConfig.Middleware.AccessLog)
+	require.True(t, cfg.AdminServerConfig.Middleware.Recover)
+	require.True(t, cfg.AdminServerConfig.Middleware.RequestID)
+	require.False(t, cfg.AdminServerConfig.Middleware.Gzip)
+	require.False(t, cfg.AdminServerConfig.Middleware.CORS.Enabled)
+	require.Empty(t, cfg.AdminServerConfig.Middleware.CORS.AllowedOrigins)
+	require.Equal(t, []string{"GET", "OPTIONS"}, cfg.AdminServerConfig.Middleware.CORS.AllowedMethods)
+	require.Equal(t, 600, cfg.AdminServerConfig.Middleware.CORS.MaxAge)
+	require.Empty(t, cfg.AdminServerConfig.Middleware.Timeouts)
+	require.False(t, cfg.TracingConfig.Enabled)
+	require.Equal(t, "otlp", cfg.TracingConfig.Exporter)
+	require.Empty(t, cfg.TracingConfig.Endpoint)
+	require.True(t, cfg.TracingConfig.Insecure)
+	require.InDelta(t, 1.0, cfg.TracingConfig.SampleRatio, 0.001)
 	require.True(t, cfg.LoggerConfig.JSON)
 	require.Equal(t, "info", cfg.LoggerConfig.Level)
 }
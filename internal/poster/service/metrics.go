@@ -0,0 +1,36 @@
+package service
+
+import (
+	"github.com/ansoncht/flight-microservices/internal/poster/client"
+	"github.com/ansoncht/flight-microservices/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Outcomes of posts labelled by the post metric.
+const (
+	postPublished = "published"
+	postFailed    = "failed"
+)
+
+// postsPublished counts the posts published to social media platforms by platform and outcome.
+var postsPublished = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: metrics.Namespace,
+	Subsystem: "poster",
+	Name:      "posts_total",
+	Help:      "Number of posts sent to social media by platform and outcome, either published or failed.",
+}, []string{"platform", "result"})
+
+// platformName returns the name of the social media platform a client posts to.
+func platformName(social client.Socials) string {
+	switch social.(type) {
+	case *client.Threads:
+		return "threads"
+	case *client.Twitter:
+		return "twitter"
+	case *client.LogPoster:
+		return "log"
+	default:
+		return "unknown"
+	}
+}
@@ -2,30 +2,53 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
 
 	"github.com/ansoncht/flight-microservices/internal/poster/client"
 	"github.com/ansoncht/flight-microservices/pkg/kafka"
+	"github.com/ansoncht/flight-microservices/pkg/msgbus"
 	"github.com/ansoncht/flight-microservices/pkg/repository"
-	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/ansoncht/flight-microservices/pkg/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/sync/errgroup"
 )
 
+// HeaderPlatforms specifies the header listing the platforms a message is posted to, separated by commas.
+// Dead letters of posts which failed on some platforms carry it, so that replaying them only posts to those.
+const HeaderPlatforms = "post-platforms"
+
+// tracer specifies the tracer of the poster service.
+var tracer = otel.Tracer("github.com/ansoncht/flight-microservices/internal/poster/service")
+
 // Poster holds dependencies for posting flight summaries to social media platforms.
 type Poster struct {
 	// socials specifies the list of social media clients to post messages.
 	socials []client.Socials
 	// messageReader specifies the message reader to read messages from a message queue.
-	messageReader kafka.MessageReader
+	messageReader msgbus.MessageReader
 	// repo  specifies the repo to interact with the db collection.
 	repo repository.SummaryRepository
+	// rollups specifies the repo to interact with the weekly and monthly summary collections.
+	rollups repository.RollupRepository
+	// deadLetterWriter specifies the writer to set aside messages whose content cannot be posted.
+	deadLetterWriter kafka.DeadLetterWriter
 }
 
-// NewPoster creates a new Poster instance based on the provided social media clients, message reader, and repository.
+// NewPoster creates a new Poster instance based on the provided social media clients, message reader,
+// repositories and dead letter writer.
 func NewPoster(
 	socials []client.Socials,
-	messageReader kafka.MessageReader,
+	messageReader msgbus.MessageReader,
 	repo repository.SummaryRepository,
+	rollups repository.RollupRepository,
+	deadLetterWriter kafka.DeadLetterWriter,
 ) (*Poster, error) {
 	if len(socials) == 0 {
 		return nil, fmt.Errorf("social media clients are empty")
@@ -39,21 +62,37 @@ func NewPoster(
 		return nil, fmt.Errorf("repository is nil")
 	}
 
+	if rollups == nil {
+		return nil, fmt.Errorf("rollup repository is nil")
+	}
+
+	if deadLetterWriter == nil {
+		return nil, fmt.Errorf("dead letter writer is nil")
+	}
+
 	return &Poster{
-		socials:       socials,
-		messageReader: messageReader,
-		repo:          repo,
+		socials:          socials,
+		messageReader:    messageReader,
+		repo:             repo,
+		rollups:          rollups,
+		deadLetterWriter: deadLetterWriter,
 	}, nil
 }
 
+// Check returns an error if the message queue of the poster service cannot be reached.
+func (p *Poster) Check(ctx context.Context) error {
+	return p.messageReader.Check(ctx) //nolint:wrapcheck // the message reader describes its own errors
+}
+
 // Close closes the poster service.
 func (p *Poster) Close() {
 	p.messageReader.Close()
+	p.deadLetterWriter.Close()
 }
 
 // Post posts the flight summary to all social media clients.
 func (p *Poster) Post(ctx context.Context) error {
-	msgChan := make(chan kgo.Record)
+	msgChan := make(chan msgbus.Delivery, msgbus.DeliveryBufferSize)
 	g, gCtx := errgroup.WithContext(ctx)
 
 	g.Go(func() error {
@@ -70,23 +109,39 @@ postingLoop:
 				break postingLoop
 			}
 
-			summary, err := p.repo.Get(gCtx, string(msg.Value))
+			// Continue the trace of the processor which published the summary
+			msgCtx := msg.TraceContext(gCtx)
+
+			content, err := p.formatContent(msgCtx, string(msg.Key), string(msg.Value))
 			if err != nil {
-				return fmt.Errorf("failed to get flight summary: %w", err)
+				msg.Nack(err)
+				return err
+			}
+
+			if content == "" {
+				slog.Warn("Skipping message with unknown key", "key", string(msg.Key))
+				msg.Ack()
+				continue
 			}
 
-			content := summary.FormatForSocialMedia()
+			// Content which cannot be posted is set aside along with the platforms it failed on, so that replaying it
+			// posts it again to those platforms only once they recover
+			delivery := msg
+			g.Go(func() error {
+				failed, err := p.publish(msgCtx, content, platformsOf(delivery.Message))
+				if err != nil {
+					slog.Warn("Failed to publish post", "key", string(delivery.Key), "platforms", failed, "error", err)
 
-			for _, social := range p.socials {
-				platform := social
-				g.Go(func() error {
-					if err := platform.PublishPost(gCtx, content); err != nil {
-						return fmt.Errorf("failed to post content: %w", err)
+					deadLetter := withPlatforms(delivery.Message, failed)
+					if err := p.deadLetterWriter.WriteDeadLetter(msgCtx, deadLetter, err); err != nil {
+						delivery.Nack(err)
+						return fmt.Errorf("failed to write dead letter: %w", err)
 					}
+				}
 
-					return nil
-				})
-			}
+				delivery.Ack()
+				return nil
+			})
 		}
 	}
 
@@ -100,3 +155,116 @@ postingLoop:
 
 	return nil
 }
+
+// publish posts the content concurrently to the social media clients of the given platforms, or to all of them
+// if platforms is nil. It returns the platforms the content failed to be posted to, each posted to independently
+// of the others.
+func (p *Poster) publish(ctx context.Context, content string, platforms []string) ([]string, error) {
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		failed []string
+		errs   []error
+	)
+
+	for _, social := range p.socials {
+		platform := social
+		name := platformName(platform)
+		if platforms != nil && !slices.Contains(platforms, name) {
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			postCtx, span := tracer.Start(ctx, "PublishPost", trace.WithAttributes(
+				attribute.String("platform", name),
+			))
+			err := platform.PublishPost(postCtx, content)
+			tracing.End(span, err)
+
+			if err != nil {
+				postsPublished.WithLabelValues(name, postFailed).Inc()
+
+				mu.Lock()
+				failed = append(failed, name)
+				errs = append(errs, fmt.Errorf("failed to post content to %s: %w", name, err))
+				mu.Unlock()
+				return
+			}
+
+			postsPublished.WithLabelValues(name, postPublished).Inc()
+		}()
+	}
+
+	wg.Wait()
+
+	if len(errs) > 0 {
+		return failed, fmt.Errorf("failed to publish post: %w", errors.Join(errs...))
+	}
+
+	return nil, nil
+}
+
+// platformsOf returns the platforms listed in the platforms header of a message, or nil if it has none.
+func platformsOf(msg msgbus.Message) []string {
+	for _, header := range msg.Headers {
+		if header.Key == HeaderPlatforms {
+			return strings.Split(string(header.Value), ",")
+		}
+	}
+
+	return nil
+}
+
+// withPlatforms returns a copy of a message whose platforms header lists the given platforms.
+func withPlatforms(msg msgbus.Message, platforms []string) msgbus.Message {
+	headers := make([]msgbus.Header, 0, len(msg.Headers)+1)
+	for _, header := range msg.Headers {
+		if header.Key != HeaderPlatforms {
+			headers = append(headers, header)
+		}
+	}
+
+	msg.Headers = append(headers, msgbus.Header{Key: HeaderPlatforms, Value: []byte(strings.Join(platforms, ","))})
+
+	return msg
+}
+
+// formatContent gets the summary referenced by a message and formats it for social media.
+// It returns empty content for message keys which do not reference a summary.
+func (p *Poster) formatContent(ctx context.Context, key string, id string) (string, error) {
+	switch key {
+	case "summary_id":
+		summary, err := p.repo.Get(ctx, id)
+		if err != nil {
+			return "", fmt.Errorf("failed to get flight summary: %w", err)
+		}
+
+		return summary.FormatForSocialMedia(), nil
+	case "anomaly_detected":
+		summary, err := p.repo.Get(ctx, id)
+		if err != nil {
+			return "", fmt.Errorf("failed to get flight summary of anomalies: %w", err)
+		}
+
+		return summary.FormatAnomalyAlert(), nil
+	case "weekly_summary_id":
+		summary, err := p.rollups.GetWeekly(ctx, id)
+		if err != nil {
+			return "", fmt.Errorf("failed to get weekly flight summary: %w", err)
+		}
+
+		return summary.FormatForSocialMedia(), nil
+	case "monthly_summary_id":
+		summary, err := p.rollups.GetMonthly(ctx, id)
+		if err != nil {
+			return "", fmt.Errorf("failed to get monthly flight summary: %w", err)
+		}
+
+		return summary.FormatForSocialMedia(), nil
+	default:
+		return "", nil
+	}
+}
@@ -11,9 +11,9 @@ import (
 	"github.com/ansoncht/flight-microservices/internal/test/mock"
 	"github.com/ansoncht/flight-microservices/pkg/kafka"
 	"github.com/ansoncht/flight-microservices/pkg/model"
+	"github.com/ansoncht/flight-microservices/pkg/msgbus"
 	"github.com/ansoncht/flight-microservices/pkg/repository"
 	"github.com/stretchr/testify/require"
-	"github.com/twmb/franz-go/pkg/kgo"
 	"go.uber.org/mock/gomock"
 )
 
@@ -24,8 +24,9 @@ func TestNewPoster_NonNilClients_ShouldSucceed(t *testing.T) {
 	socials := []client.Socials{mock.NewMockSocials(ctrl)}
 	reader := mock.NewMockMessageReader(ctrl)
 	repo := mock.NewMockSummaryRepository(ctrl)
+	rollups := mock.NewMockRollupRepository(ctrl)
 
-	poster, err := service.NewPoster(socials, reader, repo)
+	poster, err := service.NewPoster(socials, reader, repo, rollups, &kafka.NopDeadLetterWriter{})
 	require.NoError(t, err)
 	require.NotNil(t, poster)
 }
@@ -37,8 +38,10 @@ func TestNewPoster_NilDependencies_ShouldError(t *testing.T) {
 	tests := []struct {
 		name        string
 		socials     []client.Socials
-		reader      kafka.MessageReader
+		reader      msgbus.MessageReader
 		repository  repository.SummaryRepository
+		rollups     repository.RollupRepository
+		deadLetters kafka.DeadLetterWriter
 		expectedErr string
 	}{
 		{
@@ -46,6 +49,8 @@ func TestNewPoster_NilDependencies_ShouldError(t *testing.T) {
 			socials:     nil,
 			reader:      mock.NewMockMessageReader(ctrl),
 			repository:  mock.NewMockSummaryRepository(ctrl),
+			rollups:     mock.NewMockRollupRepository(ctrl),
+			deadLetters: mock.NewMockDeadLetterWriter(ctrl),
 			expectedErr: "social media clients are empty",
 		},
 		{
@@ -53,6 +58,8 @@ func TestNewPoster_NilDependencies_ShouldError(t *testing.T) {
 			socials:     []client.Socials{mock.NewMockSocials(ctrl)},
 			reader:      nil,
 			repository:  mock.NewMockSummaryRepository(ctrl),
+			rollups:     mock.NewMockRollupRepository(ctrl),
+			deadLetters: mock.NewMockDeadLetterWriter(ctrl),
 			expectedErr: "message reader is nil",
 		},
 		{
@@ -60,13 +67,33 @@ func TestNewPoster_NilDependencies_ShouldError(t *testing.T) {
 			socials:     []client.Socials{mock.NewMockSocials(ctrl)},
 			reader:      mock.NewMockMessageReader(ctrl),
 			repository:  nil,
+			rollups:     mock.NewMockRollupRepository(ctrl),
+			deadLetters: mock.NewMockDeadLetterWriter(ctrl),
 			expectedErr: "repository is nil",
 		},
+		{
+			name:        "nil rollup repository",
+			socials:     []client.Socials{mock.NewMockSocials(ctrl)},
+			reader:      mock.NewMockMessageReader(ctrl),
+			repository:  mock.NewMockSummaryRepository(ctrl),
+			rollups:     nil,
+			deadLetters: mock.NewMockDeadLetterWriter(ctrl),
+			expectedErr: "rollup repository is nil",
+		},
+		{
+			name:        "nil dead letter writer",
+			socials:     []client.Socials{mock.NewMockSocials(ctrl)},
+			reader:      mock.NewMockMessageReader(ctrl),
+			repository:  mock.NewMockSummaryRepository(ctrl),
+			rollups:     mock.NewMockRollupRepository(ctrl),
+			deadLetters: nil,
+			expectedErr: "dead letter writer is nil",
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			processor, err := service.NewPoster(tt.socials, tt.reader, tt.repository)
+			processor, err := service.NewPoster(tt.socials, tt.reader, tt.repository, tt.rollups, tt.deadLetters)
 			require.ErrorContains(t, err, tt.expectedErr)
 			require.Nil(t, processor)
 		})
@@ -81,16 +108,18 @@ func TestPost_ValidContent_ShouldSucceed(t *testing.T) {
 	socials := []client.Socials{social}
 	reader := mock.NewMockMessageReader(ctrl)
 	repo := mock.NewMockSummaryRepository(ctrl)
+	rollups := mock.NewMockRollupRepository(ctrl)
+	acker := mock.NewMockAcknowledger(ctrl)
 
-	poster, err := service.NewPoster(socials, reader, repo)
+	poster, err := service.NewPoster(socials, reader, repo, rollups, &kafka.NopDeadLetterWriter{})
 	require.NoError(t, err)
 	require.NotNil(t, poster)
 	defer poster.Close()
 
 	reader.EXPECT().ReadMessages(gomock.Any(), gomock.Any()).DoAndReturn(
-		func(_ context.Context, msgChan chan<- kgo.Record) error {
+		func(_ context.Context, msgChan chan<- msgbus.Delivery) error {
 			defer close(msgChan)
-			msgChan <- kgo.Record{Key: []byte("summary_id"), Value: []byte("test_id")}
+			msgChan <- msgbus.NewDelivery(msgbus.Message{Key: []byte("summary_id"), Value: []byte("test_id")}, acker)
 			return nil
 		},
 	)
@@ -98,6 +127,8 @@ func TestPost_ValidContent_ShouldSucceed(t *testing.T) {
 	repo.EXPECT().Get(gomock.Any(), "test_id").Return(&model.DailyFlightSummary{}, nil)
 	social.EXPECT().PublishPost(gomock.Any(), gomock.Any()).Return(nil)
 
+	acker.EXPECT().Ack(gomock.Any())
+
 	err = poster.Post(context.Background())
 	require.NoError(t, err)
 }
@@ -110,27 +141,31 @@ func TestPost_RepoGetError_ShouldError(t *testing.T) {
 	socials := []client.Socials{social}
 	reader := mock.NewMockMessageReader(ctrl)
 	repo := mock.NewMockSummaryRepository(ctrl)
+	rollups := mock.NewMockRollupRepository(ctrl)
+	acker := mock.NewMockAcknowledger(ctrl)
 
-	poster, err := service.NewPoster(socials, reader, repo)
+	poster, err := service.NewPoster(socials, reader, repo, rollups, &kafka.NopDeadLetterWriter{})
 	require.NoError(t, err)
 	require.NotNil(t, poster)
 	defer poster.Close()
 
 	reader.EXPECT().ReadMessages(gomock.Any(), gomock.Any()).DoAndReturn(
-		func(_ context.Context, msgChan chan<- kgo.Record) error {
+		func(_ context.Context, msgChan chan<- msgbus.Delivery) error {
 			defer close(msgChan)
-			msgChan <- kgo.Record{Key: []byte("summary_id"), Value: []byte("test_id")}
+			msgChan <- msgbus.NewDelivery(msgbus.Message{Key: []byte("summary_id"), Value: []byte("test_id")}, acker)
 			return nil
 		},
 	)
 	reader.EXPECT().Close()
 	repo.EXPECT().Get(gomock.Any(), "test_id").Return(nil, errors.New("test error"))
 
+	acker.EXPECT().Nack(gomock.Any(), gomock.Any())
+
 	err = poster.Post(context.Background())
 	require.ErrorContains(t, err, "failed to get flight summary")
 }
 
-func TestPost_SocialPostError_ShouldError(t *testing.T) {
+func TestPost_SocialPostError_ShouldWriteDeadLetter(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
@@ -138,25 +173,152 @@ func TestPost_SocialPostError_ShouldError(t *testing.T) {
 	socials := []client.Socials{social}
 	reader := mock.NewMockMessageReader(ctrl)
 	repo := mock.NewMockSummaryRepository(ctrl)
+	rollups := mock.NewMockRollupRepository(ctrl)
+	acker := mock.NewMockAcknowledger(ctrl)
+	deadLetters := mock.NewMockDeadLetterWriter(ctrl)
 
-	poster, err := service.NewPoster(socials, reader, repo)
+	poster, err := service.NewPoster(socials, reader, repo, rollups, deadLetters)
 	require.NoError(t, err)
 	require.NotNil(t, poster)
 	defer poster.Close()
 
 	reader.EXPECT().ReadMessages(gomock.Any(), gomock.Any()).DoAndReturn(
-		func(_ context.Context, msgChan chan<- kgo.Record) error {
+		func(_ context.Context, msgChan chan<- msgbus.Delivery) error {
 			defer close(msgChan)
-			msgChan <- kgo.Record{Key: []byte("summary_id"), Value: []byte("test_id")}
+			msgChan <- msgbus.NewDelivery(msgbus.Message{Key: []byte("summary_id"), Value: []byte("test_id")}, acker)
 			return nil
 		},
 	)
 	reader.EXPECT().Close()
+	deadLetters.EXPECT().Close()
+	repo.EXPECT().Get(gomock.Any(), "test_id").Return(&model.DailyFlightSummary{}, nil)
+	social.EXPECT().PublishPost(gomock.Any(), gomock.Any()).Return(errors.New("test error"))
+
+	// The message is set aside and acknowledged so that posting carries on
+	deadLetters.EXPECT().WriteDeadLetter(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, msg msgbus.Message, reason error) error {
+			require.Equal(t, "summary_id", string(msg.Key))
+			require.Equal(t, "test_id", string(msg.Value))
+			require.Equal(t, []msgbus.Header{{Key: service.HeaderPlatforms, Value: []byte("unknown")}}, msg.Headers)
+			require.ErrorContains(t, reason, "failed to post content")
+			return nil
+		},
+	)
+	acker.EXPECT().Ack(gomock.Any())
+
+	err = poster.Post(context.Background())
+	require.NoError(t, err)
+}
+
+func TestPost_PartialPostError_ShouldOnlySetAsideFailedPlatforms(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	social := mock.NewMockSocials(ctrl)
+	socials := []client.Socials{social, client.NewLogPoster()}
+	reader := mock.NewMockMessageReader(ctrl)
+	repo := mock.NewMockSummaryRepository(ctrl)
+	rollups := mock.NewMockRollupRepository(ctrl)
+	acker := mock.NewMockAcknowledger(ctrl)
+	deadLetters := mock.NewMockDeadLetterWriter(ctrl)
+
+	poster, err := service.NewPoster(socials, reader, repo, rollups, deadLetters)
+	require.NoError(t, err)
+	require.NotNil(t, poster)
+
+	reader.EXPECT().ReadMessages(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, msgChan chan<- msgbus.Delivery) error {
+			defer close(msgChan)
+			msgChan <- msgbus.NewDelivery(msgbus.Message{Key: []byte("summary_id"), Value: []byte("test_id")}, acker)
+			return nil
+		},
+	)
 	repo.EXPECT().Get(gomock.Any(), "test_id").Return(&model.DailyFlightSummary{}, nil)
 	social.EXPECT().PublishPost(gomock.Any(), gomock.Any()).Return(errors.New("test error"))
 
+	// The log platform posted the content, so only the failed platform is retried on replay
+	deadLetters.EXPECT().WriteDeadLetter(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, msg msgbus.Message, _ error) error {
+			require.Equal(t, []msgbus.Header{{Key: service.HeaderPlatforms, Value: []byte("unknown")}}, msg.Headers)
+			return nil
+		},
+	)
+	acker.EXPECT().Ack(gomock.Any())
+
+	err = poster.Post(context.Background())
+	require.NoError(t, err)
+}
+
+func TestPost_PlatformsHeader_ShouldOnlyPostToListedPlatforms(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	social := mock.NewMockSocials(ctrl)
+	socials := []client.Socials{social, client.NewLogPoster()}
+	reader := mock.NewMockMessageReader(ctrl)
+	repo := mock.NewMockSummaryRepository(ctrl)
+	rollups := mock.NewMockRollupRepository(ctrl)
+	acker := mock.NewMockAcknowledger(ctrl)
+
+	poster, err := service.NewPoster(socials, reader, repo, rollups, &kafka.NopDeadLetterWriter{})
+	require.NoError(t, err)
+	require.NotNil(t, poster)
+
+	// A replayed dead letter is only posted to the platforms it failed on, so the mocked platform is not posted to
+	replayed := msgbus.Message{
+		Key:     []byte("summary_id"),
+		Value:   []byte("test_id"),
+		Headers: []msgbus.Header{{Key: service.HeaderPlatforms, Value: []byte("log")}},
+	}
+
+	reader.EXPECT().ReadMessages(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, msgChan chan<- msgbus.Delivery) error {
+			defer close(msgChan)
+			msgChan <- msgbus.NewDelivery(replayed, acker)
+			return nil
+		},
+	)
+	repo.EXPECT().Get(gomock.Any(), "test_id").Return(&model.DailyFlightSummary{}, nil)
+	acker.EXPECT().Ack(gomock.Any())
+
 	err = poster.Post(context.Background())
-	require.ErrorContains(t, err, "failed to post content")
+	require.NoError(t, err)
+}
+
+func TestPost_DeadLetterError_ShouldError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	social := mock.NewMockSocials(ctrl)
+	socials := []client.Socials{social}
+	reader := mock.NewMockMessageReader(ctrl)
+	repo := mock.NewMockSummaryRepository(ctrl)
+	rollups := mock.NewMockRollupRepository(ctrl)
+	acker := mock.NewMockAcknowledger(ctrl)
+	deadLetters := mock.NewMockDeadLetterWriter(ctrl)
+
+	poster, err := service.NewPoster(socials, reader, repo, rollups, deadLetters)
+	require.NoError(t, err)
+	require.NotNil(t, poster)
+	defer poster.Close()
+
+	reader.EXPECT().ReadMessages(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, msgChan chan<- msgbus.Delivery) error {
+			defer close(msgChan)
+			msgChan <- msgbus.NewDelivery(msgbus.Message{Key: []byte("summary_id"), Value: []byte("test_id")}, acker)
+			return nil
+		},
+	)
+	reader.EXPECT().Close()
+	deadLetters.EXPECT().Close()
+	repo.EXPECT().Get(gomock.Any(), "test_id").Return(&model.DailyFlightSummary{}, nil)
+	social.EXPECT().PublishPost(gomock.Any(), gomock.Any()).Return(errors.New("test error"))
+
+	deadLetters.EXPECT().WriteDeadLetter(gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("test error"))
+	acker.EXPECT().Nack(gomock.Any(), gomock.Any())
+
+	err = poster.Post(context.Background())
+	require.ErrorContains(t, err, "failed to write dead letter")
 }
 
 func TestPost_ContextCanceled_ShouldError(t *testing.T) {
@@ -170,20 +332,21 @@ func TestPost_ContextCanceled_ShouldError(t *testing.T) {
 	socials := []client.Socials{social}
 	reader := mock.NewMockMessageReader(ctrl)
 	repo := mock.NewMockSummaryRepository(ctrl)
+	rollups := mock.NewMockRollupRepository(ctrl)
 
-	poster, err := service.NewPoster(socials, reader, repo)
+	poster, err := service.NewPoster(socials, reader, repo, rollups, &kafka.NopDeadLetterWriter{})
 	require.NoError(t, err)
 	require.NotNil(t, poster)
 	defer poster.Close()
 
 	reader.EXPECT().ReadMessages(gomock.Any(), gomock.Any()).DoAndReturn(
-		func(ctx context.Context, msgChan chan<- kgo.Record) error {
+		func(ctx context.Context, msgChan chan<- msgbus.Delivery) error {
 			defer close(msgChan)
 
 			select {
 			case <-ctx.Done():
 				return ctx.Err()
-			case msgChan <- kgo.Record{Key: []byte("summary_id"), Value: []byte("test_id")}:
+			case msgChan <- msgbus.NewDelivery(msgbus.Message{Key: []byte("summary_id"), Value: []byte("test_id")}, nil):
 				cancel()
 				time.Sleep(10 * time.Millisecond)
 			}
@@ -197,3 +360,131 @@ func TestPost_ContextCanceled_ShouldError(t *testing.T) {
 	err = poster.Post(ctx)
 	require.ErrorContains(t, err, "context canceled while posting content")
 }
+
+func TestPost_AnomalyEvent_ShouldPostAlert(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	social := mock.NewMockSocials(ctrl)
+	socials := []client.Socials{social}
+	reader := mock.NewMockMessageReader(ctrl)
+	repo := mock.NewMockSummaryRepository(ctrl)
+	rollups := mock.NewMockRollupRepository(ctrl)
+	acker := mock.NewMockAcknowledger(ctrl)
+
+	poster, err := service.NewPoster(socials, reader, repo, rollups, &kafka.NopDeadLetterWriter{})
+	require.NoError(t, err)
+	require.NotNil(t, poster)
+	defer poster.Close()
+
+	summary := &model.DailyFlightSummary{
+		Date:    model.ToMongoDateTime(time.Date(2025, 5, 7, 0, 0, 0, 0, time.UTC)),
+		Airport: "JFK",
+		Anomalies: []model.Anomaly{
+			{Metric: model.AnomalyMetricAirline, Key: "Delta", Value: 0, Baseline: 10},
+		},
+	}
+
+	reader.EXPECT().ReadMessages(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, msgChan chan<- msgbus.Delivery) error {
+			defer close(msgChan)
+			msgChan <- msgbus.NewDelivery(msgbus.Message{Key: []byte("anomaly_detected"), Value: []byte("test_id")}, acker)
+			return nil
+		},
+	)
+	reader.EXPECT().Close()
+	repo.EXPECT().Get(gomock.Any(), "test_id").Return(summary, nil)
+	social.EXPECT().PublishPost(gomock.Any(), "🚨 **Unusual Activity Alert** 🚨\n"+
+		"📍 **Airport**: JFK\n"+
+		"📅 **Date**: 2025-05-07\n"+
+		"⚠️ **Unusual Activity**:\n"+
+		"• Delta: 0 flights (usually 10)\n").Return(nil)
+
+	acker.EXPECT().Ack(gomock.Any())
+
+	err = poster.Post(context.Background())
+	require.NoError(t, err)
+}
+
+func TestPost_RollupContent_ShouldSucceed(t *testing.T) {
+	tests := []struct {
+		name  string
+		key   string
+		setup func(rollups *mock.MockRollupRepository)
+	}{
+		{
+			name: "weekly summary",
+			key:  "weekly_summary_id",
+			setup: func(rollups *mock.MockRollupRepository) {
+				rollups.EXPECT().GetWeekly(gomock.Any(), "test_id").Return(&model.WeeklyFlightSummary{}, nil)
+			},
+		},
+		{
+			name: "monthly summary",
+			key:  "monthly_summary_id",
+			setup: func(rollups *mock.MockRollupRepository) {
+				rollups.EXPECT().GetMonthly(gomock.Any(), "test_id").Return(&model.MonthlyFlightSummary{}, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			social := mock.NewMockSocials(ctrl)
+			socials := []client.Socials{social}
+			reader := mock.NewMockMessageReader(ctrl)
+			repo := mock.NewMockSummaryRepository(ctrl)
+			rollups := mock.NewMockRollupRepository(ctrl)
+
+			poster, err := service.NewPoster(socials, reader, repo, rollups, &kafka.NopDeadLetterWriter{})
+			require.NoError(t, err)
+			require.NotNil(t, poster)
+			defer poster.Close()
+
+			reader.EXPECT().ReadMessages(gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ context.Context, msgChan chan<- msgbus.Delivery) error {
+					defer close(msgChan)
+					msgChan <- msgbus.NewDelivery(msgbus.Message{Key: []byte(tt.key), Value: []byte("test_id")}, nil)
+					return nil
+				},
+			)
+			reader.EXPECT().Close()
+			tt.setup(rollups)
+			social.EXPECT().PublishPost(gomock.Any(), gomock.Any()).Return(nil)
+
+			err = poster.Post(context.Background())
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestPost_UnknownKey_ShouldSkip(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	social := mock.NewMockSocials(ctrl)
+	socials := []client.Socials{social}
+	reader := mock.NewMockMessageReader(ctrl)
+	repo := mock.NewMockSummaryRepository(ctrl)
+	rollups := mock.NewMockRollupRepository(ctrl)
+
+	poster, err := service.NewPoster(socials, reader, repo, rollups, &kafka.NopDeadLetterWriter{})
+	require.NoError(t, err)
+	require.NotNil(t, poster)
+	defer poster.Close()
+
+	reader.EXPECT().ReadMessages(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, msgChan chan<- msgbus.Delivery) error {
+			defer close(msgChan)
+			msgChan <- msgbus.NewDelivery(msgbus.Message{Key: []byte("unknown"), Value: []byte("test_id")}, nil)
+			return nil
+		},
+	)
+	reader.EXPECT().Close()
+
+	err = poster.Post(context.Background())
+	require.NoError(t, err)
+}
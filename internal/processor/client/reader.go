@@ -0,0 +1,73 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+
+	"github.com/ansoncht/flight-microservices/internal/processor/config"
+)
+
+// ReaderAPI holds the configuration for requesting the reader service to read the flights of a day again.
+// It implements the kafka.Resummarizer interface to replay the records set aside from a day stream.
+type ReaderAPI struct {
+	// client specifies the shared HTTP client to submit requests to the reader service.
+	client *http.Client
+	// BaseURL specifies the base URL for the reader service.
+	BaseURL string
+}
+
+// NewReaderAPI creates a new ReaderAPI instance based on the provided configuration and HTTP client.
+func NewReaderAPI(cfg config.ReaderAPIConfig, client *http.Client) (*ReaderAPI, error) {
+	slog.Info("Initializing reader API client", "url", cfg.URL)
+
+	if client == nil {
+		return nil, fmt.Errorf("http client is nil")
+	}
+
+	// Validate the configuration
+	if cfg.URL == "" {
+		return nil, fmt.Errorf("reader api url is empty")
+	}
+
+	return &ReaderAPI{
+		client:  client,
+		BaseURL: cfg.URL,
+	}, nil
+}
+
+// Resummarize requests the reader service to read the flights of the airport on the given date again.
+func (c *ReaderAPI) Resummarize(ctx context.Context, airport string, date string) error {
+	// Parse the base URL
+	endpoint, err := url.Parse(c.BaseURL)
+	if err != nil {
+		return fmt.Errorf("failed to parse url: %w", err)
+	}
+
+	// Add path segments and query parameters
+	endpoint = endpoint.JoinPath("api", "v1", "fetch")
+	query := endpoint.Query()
+	query.Set("airport", airport)
+	query.Set("date", date)
+	endpoint.RawQuery = query.Encode()
+
+	// Create a HTTP GET request
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint.String(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request for reader: %w", err)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to request reader: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,77 @@
+package client_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ansoncht/flight-microservices/internal/processor/client"
+	"github.com/ansoncht/flight-microservices/internal/processor/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewReaderAPI_ValidConfig_ShouldSucceed(t *testing.T) {
+	reader, err := client.NewReaderAPI(config.ReaderAPIConfig{URL: "http://localhost:8080"}, &http.Client{})
+	require.NoError(t, err)
+	require.NotNil(t, reader)
+}
+
+func TestNewReaderAPI_InvalidConfig_ShouldError(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     config.ReaderAPIConfig
+		client  *http.Client
+		wantErr string
+	}{
+		{
+			name:    "Nil HTTP Client",
+			cfg:     config.ReaderAPIConfig{URL: "http://localhost:8080"},
+			client:  nil,
+			wantErr: "http client is nil",
+		},
+		{
+			name:    "Empty URL",
+			cfg:     config.ReaderAPIConfig{URL: ""},
+			client:  &http.Client{},
+			wantErr: "reader api url is empty",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader, err := client.NewReaderAPI(tt.cfg, tt.client)
+			require.ErrorContains(t, err, tt.wantErr)
+			require.Nil(t, reader)
+		})
+	}
+}
+
+func TestResummarize_ShouldRequestDay(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/api/v1/fetch", r.URL.Path)
+		require.Equal(t, "VHHH", r.URL.Query().Get("airport"))
+		require.Equal(t, "2025-05-07", r.URL.Query().Get("date"))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	reader, err := client.NewReaderAPI(config.ReaderAPIConfig{URL: server.URL}, server.Client())
+	require.NoError(t, err)
+
+	err = reader.Resummarize(context.Background(), "VHHH", "2025-05-07")
+	require.NoError(t, err)
+}
+
+func TestResummarize_ReaderError_ShouldError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	reader, err := client.NewReaderAPI(config.ReaderAPIConfig{URL: server.URL}, server.Client())
+	require.NoError(t, err)
+
+	err = reader.Resummarize(context.Background(), "VHHH", "2025-05-07")
+	require.ErrorContains(t, err, "unexpected status code: 500")
+}
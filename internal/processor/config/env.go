@@ -4,25 +4,89 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/ansoncht/flight-microservices/pkg/bus"
+	"github.com/ansoncht/flight-microservices/pkg/http"
 	"github.com/ansoncht/flight-microservices/pkg/kafka"
 	"github.com/ansoncht/flight-microservices/pkg/logger"
 	"github.com/ansoncht/flight-microservices/pkg/mongo"
+	"github.com/ansoncht/flight-microservices/pkg/nats"
+	"github.com/ansoncht/flight-microservices/pkg/postgres"
+	"github.com/ansoncht/flight-microservices/pkg/redis"
+	"github.com/ansoncht/flight-microservices/pkg/repository"
+	"github.com/ansoncht/flight-microservices/pkg/tracing"
 	"github.com/spf13/viper"
 )
 
 // FlightProcessorConfig holds all configurations related to flight processor.
 type FlightProcessorConfig struct {
-	SummarizerConfig  SummarizerConfig   `mapstructure:"summarizer"`
-	MongoClientConfig mongo.ClientConfig `mapstructure:"mongo"`
-	KafkaWriterConfig kafka.WriterConfig `mapstructure:"kafka_writer"`
-	KafkaReaderConfig kafka.ReaderConfig `mapstructure:"kafka_reader"`
-	LoggerConfig      logger.Config      `mapstructure:"logger"`
+	SummarizerConfig     SummarizerConfig      `mapstructure:"summarizer"`
+	AnomalyConfig        AnomalyConfig         `mapstructure:"anomaly"`
+	RepositoryConfig     repository.Config     `mapstructure:"repository"`
+	MongoClientConfig    mongo.ClientConfig    `mapstructure:"mongo"`
+	PostgresClientConfig postgres.ClientConfig `mapstructure:"postgres"`
+	BusConfig            bus.Config            `mapstructure:"bus"`
+	KafkaWriterConfig    kafka.WriterConfig    `mapstructure:"kafka_writer"`
+	KafkaReaderConfig    kafka.ReaderConfig    `mapstructure:"kafka_reader"`
+	NATSWriterConfig     nats.WriterConfig     `mapstructure:"nats_writer"`
+	NATSReaderConfig     nats.ReaderConfig     `mapstructure:"nats_reader"`
+	RedisWriterConfig    redis.WriterConfig    `mapstructure:"redis_writer"`
+	RedisReaderConfig    redis.ReaderConfig    `mapstructure:"redis_reader"`
+	KafkaTransactConfig  kafka.TransactConfig  `mapstructure:"kafka_transaction"`
+	DLQConfig            kafka.DLQConfig       `mapstructure:"dlq"`
+	HTTPClientConfig     http.ClientConfig     `mapstructure:"http_client"`
+	ReaderAPIConfig      ReaderAPIConfig       `mapstructure:"reader_api"`
+	AdminServerConfig    http.ServerConfig     `mapstructure:"admin_server"`
+	TracingConfig        tracing.Config        `mapstructure:"tracing"`
+	LoggerConfig         logger.Config         `mapstructure:"logger"`
 }
 
 // SummarizerConfig holds configuration settings for the summarizer.
 type SummarizerConfig struct {
 	// TopN specifies the number of top airlines and destinations to summarize.
 	TopN int `mapstructure:"top_n"`
+	// RankingMethod specifies how ties are ranked in top N lists, either "competition" (default) or "dense".
+	RankingMethod string `mapstructure:"ranking_method"`
+	// RankOthers specifies whether counts beyond the top N are aggregated into an "Others" entry.
+	RankOthers bool `mapstructure:"rank_others"`
+	// Timezones specifies the IANA time zone of each airport code, used to bucket departures by local hour.
+	// Airports without an entry are bucketed in UTC.
+	Timezones map[string]string `mapstructure:"timezones"`
+	// Statistics specifies the statistics to compute, in the order their sections are added.
+	// Defaults to airlines, destinations, hourly, countries and routes when empty.
+	Statistics []string `mapstructure:"statistics"`
+	// Dedup specifies how duplicate flight records are removed before summarizing.
+	Dedup DedupConfig `mapstructure:"dedup"`
+}
+
+// DedupConfig holds configuration settings for removing duplicate flight records.
+type DedupConfig struct {
+	// Enabled specifies whether duplicate flight records are removed.
+	Enabled bool `mapstructure:"enabled"`
+	// WindowSeconds specifies the maximum difference in first seen time between records of the same flight.
+	WindowSeconds int `mapstructure:"window_seconds"`
+	// Codeshare specifies whether records of the same aircraft are collapsed whatever their flight numbers.
+	Codeshare bool `mapstructure:"codeshare"`
+}
+
+// ReaderAPIConfig holds configuration settings for the reader api client, which reads the day streams of
+// replayed dead letters again.
+type ReaderAPIConfig struct {
+	// URL specifies the base URL for the reader service.
+	URL string `mapstructure:"url"`
+}
+
+// AnomalyConfig holds configuration settings for the anomaly detector.
+type AnomalyConfig struct {
+	// Method specifies the scoring method, either "mad" (median absolute deviation) or "zscore".
+	Method string `mapstructure:"method"`
+	// WindowDays specifies the number of trailing days a day is compared against.
+	WindowDays int `mapstructure:"window_days"`
+	// MinSamples specifies the number of summarized trailing days required before detecting anomalies.
+	MinSamples int `mapstructure:"min_samples"`
+	// Threshold specifies the absolute score above which a count is flagged as an anomaly.
+	Threshold float64 `mapstructure:"threshold"`
+	// MinVolume specifies the count an airline or destination must reach on the day or a trailing day to be scored.
+	MinVolume int `mapstructure:"min_volume"`
 }
 
 // LoadConfig loads configuration from environment variables and a YAML file.
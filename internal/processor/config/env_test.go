@@ -15,6 +15,8 @@ func TestLoadConfig_ValidConfigFile_ShouldSucceed(t *testing.T) {
 	os.Setenv("FLIGHT_PROCESSOR_KAFKA_READER_GROUP_ID", "test")
 	os.Setenv("FLIGHT_PROCESSOR_KAFKA_WRITER_ADDRESS", "test")
 	os.Setenv("FLIGHT_PROCESSOR_KAFKA_WRITER_TOPIC", "test")
+	os.Setenv("FLIGHT_PROCESSOR_DLQ_TOPIC", "flights-dlq")
+	os.Setenv("FLIGHT_PROCESSOR_READER_API_URL", "http://reader:8080")
 
 	cfg, err := config.LoadConfig()
 
@@ -25,11 +27,64 @@ func TestLoadConfig_ValidConfigFile_ShouldSucceed(t *testing.T) {
 	require.Equal(t, uint64(5), cfg.MongoClientConfig.PoolSize)
 	require.Equal(t, 5, cfg.MongoClientConfig.ConnectionTimeout)
 	require.Equal(t, 5, cfg.MongoClientConfig.SocketTimeout)
+	require.Equal(t, "competition", cfg.SummarizerConfig.RankingMethod)
+	require.True(t, cfg.SummarizerConfig.RankOthers)
+	require.Equal(t, "Asia/Hong_Kong", cfg.SummarizerConfig.Timezones["vhhh"])
+	require.Equal(t, []string{"airlines", "destinations", "hourly", "countries", "routes", "durations"}, cfg.SummarizerConfig.Statistics)
+	require.True(t, cfg.SummarizerConfig.Dedup.Enabled)
+	require.Equal(t, 600, cfg.SummarizerConfig.Dedup.WindowSeconds)
+	require.True(t, cfg.SummarizerConfig.Dedup.Codeshare)
+	require.Equal(t, "mad", cfg.AnomalyConfig.Method)
+	require.Equal(t, 28, cfg.AnomalyConfig.WindowDays)
+	require.Equal(t, 7, cfg.AnomalyConfig.MinSamples)
+	require.InDelta(t, 3.5, cfg.AnomalyConfig.Threshold, 0.001)
+	require.Equal(t, 10, cfg.AnomalyConfig.MinVolume)
+	require.Equal(t, "mongo", cfg.RepositoryConfig.Driver)
+	require.Equal(t, int32(5), cfg.PostgresClientConfig.PoolSize)
+	require.Equal(t, 5, cfg.PostgresClientConfig.ConnectionTimeout)
 	require.Equal(t, "test", cfg.KafkaWriterConfig.Address)
 	require.Equal(t, "test", cfg.KafkaWriterConfig.Topic)
+	require.False(t, cfg.KafkaWriterConfig.Async)
+	require.Equal(t, 10, cfg.KafkaWriterConfig.LingerMs)
+	require.Equal(t, int32(1048576), cfg.KafkaWriterConfig.BatchMaxBytes)
+	require.Equal(t, "snappy", cfg.KafkaWriterConfig.Compression)
 	require.Equal(t, "test", cfg.KafkaReaderConfig.Address)
 	require.Equal(t, "test", cfg.KafkaReaderConfig.Topic)
+	require.False(t, cfg.KafkaTransactConfig.Enabled)
+	require.Equal(t, 300, cfg.KafkaTransactConfig.Timeout)
+	require.Equal(t, "kafka", cfg.BusConfig.Driver)
+	require.Equal(t, 500, cfg.NATSReaderConfig.MaxPollRecords)
+	require.Equal(t, 30, cfg.NATSReaderConfig.AckWait)
+	require.False(t, cfg.NATSWriterConfig.Async)
+	require.Equal(t, 500, cfg.RedisReaderConfig.MaxPollRecords)
+	require.Equal(t, int64(0), cfg.RedisWriterConfig.MaxLen)
+	require.False(t, cfg.DLQConfig.Enabled)
+	require.Equal(t, "flights-dlq", cfg.DLQConfig.Topic)
+	require.Equal(t, 80, cfg.HTTPClientConfig.Timeout)
+	require.Equal(t, "http://reader:8080", cfg.ReaderAPIConfig.URL)
 	require.Equal(t, "test", cfg.KafkaReaderConfig.GroupID)
+	require.Equal(t, 500, cfg.KafkaReaderConfig.MaxPollRecords)
+	require.Equal(t, int32(52428800), cfg.KafkaReaderConfig.FetchMaxBytes)
+	require.Equal(t, int32(1048576), cfg.KafkaReaderConfig.FetchMaxPartitionBytes)
+	require.Equal(t, 5000, cfg.KafkaReaderConfig.FetchMaxWaitMs)
+	require.False(t, cfg.KafkaReaderConfig.TLS.Enabled)
+	require.Empty(t, cfg.KafkaReaderConfig.SASL.Mechanism)
+	require.Equal(t, "9090", cfg.AdminServerConfig.Port)
+	require.Equal(t, 5, cfg.AdminServerConfig.Timeout)
+	require.False(t, cfg.AdminServerConfig.Middleware.AccessLog)
+	require.True(t, cfg.AdminServerConfig.Middleware.Recover)
+	require.True(t, cfg.AdminServerConfig.Middleware.RequestID)
+	require.False(t, cfg.AdminServerConfig.Middleware.Gzip)
+	require.False(t, cfg.AdminServerConfig.Middleware.CORS.Enabled)
+	require.Empty(t, cfg.AdminServerConfig.Middleware.CORS.AllowedOrigins)
+	require.Equal(t, []string{"GET", "OPTIONS"}, cfg.AdminServerConfig.Middleware.CORS.AllowedMethods)
+	require.Equal(t, 600, cfg.AdminServerConfig.Middleware.CORS.MaxAge)
+	require.Empty(t, cfg.AdminServerConfig.Middleware.Timeouts)
+	require.False(t, cfg.TracingConfig.Enabled)
+	require.Equal(t, "otlp", cfg.TracingConfig.Exporter)
+	require.Empty(t, cfg.TracingConfig.Endpoint)
+	require.True(t, cfg.TracingConfig.Insecure)
+	require.InDelta(t, 1.0, cfg.TracingConfig.SampleRatio, 0.001)
 	require.True(t, cfg.LoggerConfig.JSON)
 	require.Equal(t, "info", cfg.LoggerConfig.Level)
 }
@@ -0,0 +1,254 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/ansoncht/flight-microservices/internal/processor/config"
+	"github.com/ansoncht/flight-microservices/pkg/model"
+	repo "github.com/ansoncht/flight-microservices/pkg/repository"
+)
+
+// Anomaly scoring methods.
+const (
+	AnomalyMethodMAD    = "mad"
+	AnomalyMethodZScore = "zscore"
+)
+
+const (
+	// madScale scales the median absolute deviation to be comparable with a standard deviation.
+	madScale = 0.6745
+	// minSpread specifies the smallest spread a score is divided by, so that a count which never
+	// changed over the trailing days is not flagged for deviating by a single flight.
+	minSpread = 1.0
+)
+
+// Detector defines the interface for detecting anomalies in daily flight summaries.
+type Detector interface {
+	// DetectAnomalies compares a daily summary against the summaries of the trailing days of its airport.
+	DetectAnomalies(ctx context.Context, summary model.DailyFlightSummary) ([]model.Anomaly, error)
+}
+
+// AnomalyDetector implements the Detector interface.
+type AnomalyDetector struct {
+	// method specifies the scoring method.
+	method string
+	// windowDays specifies the number of trailing days to compare against.
+	windowDays int
+	// minSamples specifies the number of summarized trailing days required to detect anomalies.
+	minSamples int
+	// threshold specifies the absolute score above which a count is anomalous.
+	threshold float64
+	// minVolume specifies the count an airline or destination must reach to be scored.
+	minVolume int
+	// summaries specifies the repository to read the trailing daily summaries from.
+	summaries repo.SummaryRepository
+}
+
+// NewAnomalyDetector creates a new AnomalyDetector instance based on the provided configuration and repository.
+func NewAnomalyDetector(cfg config.AnomalyConfig, summaries repo.SummaryRepository) (*AnomalyDetector, error) {
+	if cfg.Method != AnomalyMethodMAD && cfg.Method != AnomalyMethodZScore {
+		return nil, fmt.Errorf("anomaly method is invalid: %s", cfg.Method)
+	}
+
+	if cfg.WindowDays <= 0 {
+		return nil, fmt.Errorf("anomaly window days is invalid: %d", cfg.WindowDays)
+	}
+
+	if cfg.MinSamples < 2 || cfg.MinSamples > cfg.WindowDays {
+		return nil, fmt.Errorf("anomaly min samples is invalid: %d", cfg.MinSamples)
+	}
+
+	if cfg.Threshold <= 0 {
+		return nil, fmt.Errorf("anomaly threshold is invalid: %f", cfg.Threshold)
+	}
+
+	if cfg.MinVolume < 0 {
+		return nil, fmt.Errorf("anomaly min volume is invalid: %d", cfg.MinVolume)
+	}
+
+	if summaries == nil {
+		return nil, fmt.Errorf("summary repository is nil")
+	}
+
+	return &AnomalyDetector{
+		method:     cfg.Method,
+		windowDays: cfg.WindowDays,
+		minSamples: cfg.MinSamples,
+		threshold:  cfg.Threshold,
+		minVolume:  cfg.MinVolume,
+		summaries:  summaries,
+	}, nil
+}
+
+// DetectAnomalies flags the total flights and each airline and destination count of the summary whose score
+// against the trailing days exceeds the threshold. Nothing is flagged until enough trailing days were summarized.
+func (d *AnomalyDetector) DetectAnomalies(
+	ctx context.Context,
+	summary model.DailyFlightSummary,
+) ([]model.Anomaly, error) {
+	day := summary.Date.Time().UTC()
+
+	history, err := d.summaries.ListByDateRange(
+		ctx,
+		summary.Airport,
+		day.AddDate(0, 0, -d.windowDays),
+		day.AddDate(0, 0, -1),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list trailing summaries: %w", err)
+	}
+
+	history = latestSummaryPerDay(history)
+	if len(history) < d.minSamples {
+		return []model.Anomaly{}, nil
+	}
+
+	anomalies := make([]model.Anomaly, 0)
+
+	totals := make([]int, len(history))
+	for i, previous := range history {
+		totals[i] = previous.TotalFlights
+	}
+
+	if anomaly, ok := d.score(model.AnomalyMetricTotalFlights, "", summary.TotalFlights, totals); ok {
+		anomalies = append(anomalies, anomaly)
+	}
+
+	anomalies = append(anomalies, d.scoreCounts(
+		model.AnomalyMetricAirline,
+		summary.AirlineCounts,
+		history,
+		func(s model.DailyFlightSummary) map[string]int { return s.AirlineCounts },
+	)...)
+
+	anomalies = append(anomalies, d.scoreCounts(
+		model.AnomalyMetricDestination,
+		summary.DestinationCounts,
+		history,
+		func(s model.DailyFlightSummary) map[string]int { return s.DestinationCounts },
+	)...)
+
+	return anomalies, nil
+}
+
+// scoreCounts scores every key seen on the day or the trailing days, counting a key missing on a day as zero.
+// A key whose count stays below the min volume on the day and every trailing day is not scored, as a few flights
+// more or less would flag it constantly. Anomalies are ordered by key.
+func (d *AnomalyDetector) scoreCounts(
+	metric string,
+	current map[string]int,
+	history []model.DailyFlightSummary,
+	counts func(model.DailyFlightSummary) map[string]int,
+) []model.Anomaly {
+	keys := make(map[string]struct{}, len(current))
+	for key := range current {
+		keys[key] = struct{}{}
+	}
+
+	for _, previous := range history {
+		for key := range counts(previous) {
+			keys[key] = struct{}{}
+		}
+	}
+
+	sorted := make([]string, 0, len(keys))
+	for key := range keys {
+		sorted = append(sorted, key)
+	}
+	sort.Strings(sorted)
+
+	anomalies := make([]model.Anomaly, 0)
+	samples := make([]int, len(history))
+
+	for _, key := range sorted {
+		busiest := current[key]
+		for i, previous := range history {
+			samples[i] = counts(previous)[key]
+			busiest = max(busiest, samples[i])
+		}
+
+		if busiest < d.minVolume {
+			continue
+		}
+
+		if anomaly, ok := d.score(metric, key, current[key], samples); ok {
+			anomalies = append(anomalies, anomaly)
+		}
+	}
+
+	return anomalies
+}
+
+// score scores a value against its samples with the configured method and reports whether it is anomalous.
+func (d *AnomalyDetector) score(metric string, key string, value int, samples []int) (model.Anomaly, bool) {
+	var baseline, spread float64
+
+	switch d.method {
+	case AnomalyMethodZScore:
+		baseline, spread = meanAndStdDev(samples)
+	default:
+		baseline, spread = medianAndMAD(samples)
+	}
+
+	score := (float64(value) - baseline) / math.Max(spread, minSpread)
+	if math.Abs(score) <= d.threshold {
+		return model.Anomaly{}, false
+	}
+
+	return model.Anomaly{
+		Metric:   metric,
+		Key:      key,
+		Value:    value,
+		Baseline: baseline,
+		Score:    score,
+		Method:   d.method,
+	}, true
+}
+
+// meanAndStdDev returns the mean and the population standard deviation of the samples.
+func meanAndStdDev(samples []int) (float64, float64) {
+	var sum float64
+	for _, sample := range samples {
+		sum += float64(sample)
+	}
+	mean := sum / float64(len(samples))
+
+	var squares float64
+	for _, sample := range samples {
+		squares += (float64(sample) - mean) * (float64(sample) - mean)
+	}
+
+	return mean, math.Sqrt(squares / float64(len(samples)))
+}
+
+// medianAndMAD returns the median and the median absolute deviation of the samples,
+// scaled to be comparable with a standard deviation.
+func medianAndMAD(samples []int) (float64, float64) {
+	values := make([]float64, len(samples))
+	for i, sample := range samples {
+		values[i] = float64(sample)
+	}
+	center := median(values)
+
+	deviations := make([]float64, len(values))
+	for i, value := range values {
+		deviations[i] = math.Abs(value - center)
+	}
+
+	return center, median(deviations) / madScale
+}
+
+// median returns the median of the values, sorting them in place.
+func median(values []float64) float64 {
+	sort.Float64s(values)
+
+	mid := len(values) / 2 //nolint:mnd // halfway
+	if len(values)%2 == 0 {
+		return (values[mid-1] + values[mid]) / 2 //nolint:mnd // average of the middle pair
+	}
+
+	return values[mid]
+}
@@ -0,0 +1,270 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ansoncht/flight-microservices/internal/processor/config"
+	"github.com/ansoncht/flight-microservices/internal/processor/service"
+	"github.com/ansoncht/flight-microservices/internal/test/mock"
+	"github.com/ansoncht/flight-microservices/pkg/model"
+	"github.com/ansoncht/flight-microservices/pkg/repository"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestNewAnomalyDetector_InvalidConfig_ShouldError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	valid := config.AnomalyConfig{Method: "mad", WindowDays: 28, MinSamples: 7, Threshold: 3.5}
+
+	tests := []struct {
+		name        string
+		mutate      func(cfg *config.AnomalyConfig)
+		summaries   repository.SummaryRepository
+		expectedErr string
+	}{
+		{
+			name:        "invalid method",
+			mutate:      func(cfg *config.AnomalyConfig) { cfg.Method = "iqr" },
+			summaries:   mock.NewMockSummaryRepository(ctrl),
+			expectedErr: "anomaly method is invalid",
+		},
+		{
+			name:        "invalid window days",
+			mutate:      func(cfg *config.AnomalyConfig) { cfg.WindowDays = 0 },
+			summaries:   mock.NewMockSummaryRepository(ctrl),
+			expectedErr: "anomaly window days is invalid",
+		},
+		{
+			name:        "min samples above window",
+			mutate:      func(cfg *config.AnomalyConfig) { cfg.MinSamples = 29 },
+			summaries:   mock.NewMockSummaryRepository(ctrl),
+			expectedErr: "anomaly min samples is invalid",
+		},
+		{
+			name:        "invalid threshold",
+			mutate:      func(cfg *config.AnomalyConfig) { cfg.Threshold = 0 },
+			summaries:   mock.NewMockSummaryRepository(ctrl),
+			expectedErr: "anomaly threshold is invalid",
+		},
+		{
+			name:        "invalid min volume",
+			mutate:      func(cfg *config.AnomalyConfig) { cfg.MinVolume = -1 },
+			summaries:   mock.NewMockSummaryRepository(ctrl),
+			expectedErr: "anomaly min volume is invalid",
+		},
+		{
+			name:        "nil summary repository",
+			mutate:      func(_ *config.AnomalyConfig) {},
+			summaries:   nil,
+			expectedErr: "summary repository is nil",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := valid
+			tt.mutate(&cfg)
+
+			detector, err := service.NewAnomalyDetector(cfg, tt.summaries)
+			require.ErrorContains(t, err, tt.expectedErr)
+			require.Nil(t, detector)
+		})
+	}
+}
+
+// trailingSummaries builds one summary per day before day with the given totals and airline counts.
+func trailingSummaries(day time.Time, totals []int, airlines []map[string]int) []model.DailyFlightSummary {
+	summaries := make([]model.DailyFlightSummary, len(totals))
+	for i, total := range totals {
+		summaries[i] = model.DailyFlightSummary{
+			Date:              model.ToMongoDateTime(day.AddDate(0, 0, i-len(totals))),
+			Airport:           "JFK",
+			TotalFlights:      total,
+			AirlineCounts:     airlines[i],
+			DestinationCounts: map[string]int{"LAX": total},
+		}
+	}
+
+	return summaries
+}
+
+func TestDetectAnomalies_ValidHistory_ShouldFlagOutliers(t *testing.T) {
+	day := time.Date(2025, 5, 7, 0, 0, 0, 0, time.UTC)
+	totals := []int{50, 52, 48, 51, 49, 50, 53}
+	airlines := make([]map[string]int, len(totals))
+	for i, total := range totals {
+		airlines[i] = map[string]int{"United": total - 10, "Delta": 10}
+	}
+	history := trailingSummaries(day, totals, airlines)
+
+	// A strike grounds Delta and a new Alaska route launches
+	summary := model.DailyFlightSummary{
+		Date:              model.ToMongoDateTime(day),
+		Airport:           "JFK",
+		TotalFlights:      46,
+		AirlineCounts:     map[string]int{"United": 40, "Alaska": 6},
+		DestinationCounts: map[string]int{"LAX": 40, "SEA": 6},
+	}
+
+	for _, method := range []string{service.AnomalyMethodMAD, service.AnomalyMethodZScore} {
+		t.Run(method, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			summaries := mock.NewMockSummaryRepository(ctrl)
+			summaries.EXPECT().ListByDateRange(gomock.Any(), "JFK", day.AddDate(0, 0, -28), day.AddDate(0, 0, -1)).
+				Return(history, nil)
+
+			cfg := config.AnomalyConfig{Method: method, WindowDays: 28, MinSamples: 7, Threshold: 3.5}
+			detector, err := service.NewAnomalyDetector(cfg, summaries)
+			require.NoError(t, err)
+
+			anomalies, err := detector.DetectAnomalies(context.Background(), summary)
+			require.NoError(t, err)
+
+			flagged := make([]string, 0, len(anomalies))
+			for _, anomaly := range anomalies {
+				require.Equal(t, method, anomaly.Method)
+				flagged = append(flagged, anomaly.Metric+":"+anomaly.Key)
+			}
+
+			// Total flights of 46 stay within the band, so only the per-airline and per-destination counts move
+			require.Equal(t, []string{"airline:Alaska", "airline:Delta", "destination:LAX", "destination:SEA"}, flagged)
+			require.Equal(t, 0, anomalies[1].Value)
+			require.InDelta(t, 10.0, anomalies[1].Baseline, 0.001)
+			require.Negative(t, anomalies[1].Score)
+			require.Positive(t, anomalies[0].Score)
+		})
+	}
+}
+
+func TestDetectAnomalies_TotalFlightsCollapse_ShouldFlagTotal(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	day := time.Date(2025, 5, 7, 0, 0, 0, 0, time.UTC)
+	totals := []int{50, 52, 48, 51, 49, 50, 53, 47}
+	airlines := make([]map[string]int, len(totals))
+	for i, total := range totals {
+		airlines[i] = map[string]int{"United": total}
+	}
+
+	summaries := mock.NewMockSummaryRepository(ctrl)
+	summaries.EXPECT().ListByDateRange(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(trailingSummaries(day, totals, airlines), nil)
+
+	cfg := config.AnomalyConfig{Method: "mad", WindowDays: 28, MinSamples: 7, Threshold: 3.5}
+	detector, err := service.NewAnomalyDetector(cfg, summaries)
+	require.NoError(t, err)
+
+	anomalies, err := detector.DetectAnomalies(context.Background(), model.DailyFlightSummary{
+		Date:              model.ToMongoDateTime(day),
+		Airport:           "JFK",
+		TotalFlights:      3,
+		AirlineCounts:     map[string]int{"United": 3},
+		DestinationCounts: map[string]int{"LAX": 3},
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, anomalies)
+	require.Equal(t, model.AnomalyMetricTotalFlights, anomalies[0].Metric)
+	require.Empty(t, anomalies[0].Key)
+	require.InDelta(t, 50.0, anomalies[0].Baseline, 0.001)
+}
+
+func TestDetectAnomalies_LowVolumeKey_ShouldNotScore(t *testing.T) {
+	day := time.Date(2025, 5, 7, 0, 0, 0, 0, time.UTC)
+	totals := []int{50, 50, 50, 50, 50, 50, 50}
+	airlines := make([]map[string]int, len(totals))
+	for i, total := range totals {
+		airlines[i] = map[string]int{"United": total, "JetBlue": i % 2}
+	}
+	history := trailingSummaries(day, totals, airlines)
+
+	summary := model.DailyFlightSummary{
+		Date:              model.ToMongoDateTime(day),
+		Airport:           "JFK",
+		TotalFlights:      50,
+		AirlineCounts:     map[string]int{"United": 50, "JetBlue": 4},
+		DestinationCounts: map[string]int{"LAX": 50},
+	}
+
+	tests := []struct {
+		name      string
+		minVolume int
+		expected  []string
+	}{
+		{name: "without min volume", minVolume: 0, expected: []string{"JetBlue"}},
+		{name: "with min volume", minVolume: 10, expected: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			summaries := mock.NewMockSummaryRepository(ctrl)
+			summaries.EXPECT().ListByDateRange(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+				Return(history, nil)
+
+			cfg := config.AnomalyConfig{Method: "mad", WindowDays: 28, MinSamples: 7, Threshold: 3.5, MinVolume: tt.minVolume}
+			detector, err := service.NewAnomalyDetector(cfg, summaries)
+			require.NoError(t, err)
+
+			anomalies, err := detector.DetectAnomalies(context.Background(), summary)
+			require.NoError(t, err)
+
+			flagged := make([]string, 0, len(anomalies))
+			for _, anomaly := range anomalies {
+				flagged = append(flagged, anomaly.Key)
+			}
+			require.Equal(t, tt.expected, flagged)
+		})
+	}
+}
+
+func TestDetectAnomalies_NotEnoughHistory_ShouldNotFlag(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	day := time.Date(2025, 5, 7, 0, 0, 0, 0, time.UTC)
+	history := trailingSummaries(day, []int{50, 50}, []map[string]int{{"United": 50}, {"United": 50}})
+	// A day processed twice is counted once
+	history = append(history, history[1])
+
+	summaries := mock.NewMockSummaryRepository(ctrl)
+	summaries.EXPECT().ListByDateRange(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(history, nil)
+
+	cfg := config.AnomalyConfig{Method: "zscore", WindowDays: 28, MinSamples: 3, Threshold: 3}
+	detector, err := service.NewAnomalyDetector(cfg, summaries)
+	require.NoError(t, err)
+
+	anomalies, err := detector.DetectAnomalies(context.Background(), model.DailyFlightSummary{
+		Date:         model.ToMongoDateTime(day),
+		Airport:      "JFK",
+		TotalFlights: 0,
+	})
+	require.NoError(t, err)
+	require.Empty(t, anomalies)
+}
+
+func TestDetectAnomalies_RepositoryError_ShouldError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	summaries := mock.NewMockSummaryRepository(ctrl)
+	summaries.EXPECT().ListByDateRange(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(nil, errors.New("test error"))
+
+	cfg := config.AnomalyConfig{Method: "mad", WindowDays: 28, MinSamples: 7, Threshold: 3.5}
+	detector, err := service.NewAnomalyDetector(cfg, summaries)
+	require.NoError(t, err)
+
+	anomalies, err := detector.DetectAnomalies(context.Background(), model.DailyFlightSummary{})
+	require.ErrorContains(t, err, "failed to list trailing summaries")
+	require.Nil(t, anomalies)
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ansoncht/flight-microservices/internal/processor/config"
+	"github.com/ansoncht/flight-microservices/pkg/model"
+)
+
+// deduplicator removes flight records describing the same physical flight, such as records resent by a reader
+// rerun or redelivered by the message queue.
+type deduplicator struct {
+	// window specifies the maximum difference in first seen time, in seconds, between records of the same flight.
+	window int
+	// codeshare specifies whether records of the same aircraft are collapsed whatever their flight numbers.
+	codeshare bool
+}
+
+// newDeduplicator creates a new deduplicator based on the provided configuration.
+// It returns nil when deduplication is disabled.
+func newDeduplicator(cfg config.DedupConfig) (*deduplicator, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+
+	if cfg.WindowSeconds < 0 {
+		return nil, fmt.Errorf("dedup window is invalid: %d", cfg.WindowSeconds)
+	}
+
+	return &deduplicator{
+		window:    cfg.WindowSeconds,
+		codeshare: cfg.Codeshare,
+	}, nil
+}
+
+// deduplicate returns the records without duplicates, in their original order, and the number of records removed.
+// Records of the same flight and origin first seen within the window of the earliest record are duplicates, and
+// only the earliest is kept.
+func (d *deduplicator) deduplicate(records []model.FlightRecord) ([]model.FlightRecord, int) {
+	groups := make(map[string][]int)
+	for i, record := range records {
+		identity := d.identity(record)
+		if identity == "" {
+			continue
+		}
+
+		key := identity + "|" + record.Origin
+		groups[key] = append(groups[key], i)
+	}
+
+	removed := make(map[int]bool)
+	for _, indices := range groups {
+		sort.SliceStable(indices, func(a, b int) bool {
+			return records[indices[a]].FirstSeen < records[indices[b]].FirstSeen
+		})
+
+		for start := 0; start < len(indices); {
+			end := start + 1
+			for end < len(indices) && records[indices[end]].FirstSeen-records[indices[start]].FirstSeen <= d.window {
+				end++
+			}
+
+			for _, i := range indices[start+1 : end] {
+				removed[i] = true
+			}
+
+			start = end
+		}
+	}
+
+	if len(removed) == 0 {
+		return records, 0
+	}
+
+	unique := make([]model.FlightRecord, 0, len(records)-len(removed))
+	for i, record := range records {
+		if !removed[i] {
+			unique = append(unique, record)
+		}
+	}
+
+	return unique, len(removed)
+}
+
+// identity returns the key identifying the physical flight of a record, or an empty string if it has none.
+// In codeshare mode the aircraft takes precedence over the flight number, so that the marketing flight numbers of
+// an aircraft are collapsed. The route API gives no operating flight number, so codeshares are collapsed by
+// icao24 only.
+func (d *deduplicator) identity(record model.FlightRecord) string {
+	flightNumber := strings.ToUpper(strings.TrimSpace(record.FlightNumber))
+	icao24 := strings.ToLower(strings.TrimSpace(record.Icao24))
+
+	if d.codeshare && icao24 != "" {
+		return "icao24:" + icao24
+	}
+
+	if flightNumber != "" {
+		return "flight:" + flightNumber
+	}
+
+	if icao24 != "" {
+		return "icao24:" + icao24
+	}
+
+	return ""
+}
@@ -0,0 +1,129 @@
+package service_test
+
+import (
+	"testing"
+
+	"github.com/ansoncht/flight-microservices/internal/processor/config"
+	"github.com/ansoncht/flight-microservices/internal/processor/service"
+	msg "github.com/ansoncht/flight-microservices/pkg/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSummarizer_InvalidDedupWindow_ShouldError(t *testing.T) {
+	summarizer, err := service.NewSummarizer(config.SummarizerConfig{
+		TopN:  5,
+		Dedup: config.DedupConfig{Enabled: true, WindowSeconds: -1},
+	})
+	require.ErrorContains(t, err, "dedup window is invalid")
+	require.Nil(t, summarizer)
+}
+
+func TestSummarizeFlights_Dedup_ShouldRemoveDuplicates(t *testing.T) {
+	testCases := []struct {
+		name       string
+		dedup      config.DedupConfig
+		flights    []msg.FlightRecord
+		total      int
+		duplicates int
+		airlines   map[string]int
+	}{
+		{
+			name:  "Disabled",
+			dedup: config.DedupConfig{},
+			flights: []msg.FlightRecord{
+				{FlightNumber: "CX500", Airline: "Cathay Pacific", Origin: "HKG", FirstSeen: 1000},
+				{FlightNumber: "CX500", Airline: "Cathay Pacific", Origin: "HKG", FirstSeen: 1000},
+			},
+			total:      2,
+			duplicates: 0,
+			airlines:   map[string]int{"Cathay Pacific": 2},
+		},
+		{
+			name:  "Redelivered Record",
+			dedup: config.DedupConfig{Enabled: true, WindowSeconds: 600},
+			flights: []msg.FlightRecord{
+				{FlightNumber: "CX500", Airline: "Cathay Pacific", Origin: "HKG", FirstSeen: 1000},
+				{FlightNumber: "UO800", Airline: "HK Express", Origin: "HKG", FirstSeen: 1100},
+				{FlightNumber: "cx500 ", Airline: "Cathay Pacific", Origin: "HKG", FirstSeen: 1300},
+			},
+			total:      2,
+			duplicates: 1,
+			airlines:   map[string]int{"Cathay Pacific": 1, "HK Express": 1},
+		},
+		{
+			name:  "Outside Window",
+			dedup: config.DedupConfig{Enabled: true, WindowSeconds: 600},
+			flights: []msg.FlightRecord{
+				{FlightNumber: "CX500", Airline: "Cathay Pacific", Origin: "HKG", FirstSeen: 1000},
+				{FlightNumber: "CX500", Airline: "Cathay Pacific", Origin: "HKG", FirstSeen: 1601},
+				{FlightNumber: "CX500", Airline: "Cathay Pacific", Origin: "TPE", FirstSeen: 1000},
+			},
+			total:      3,
+			duplicates: 0,
+			airlines:   map[string]int{"Cathay Pacific": 3},
+		},
+		{
+			name:  "Aircraft Without Flight Number",
+			dedup: config.DedupConfig{Enabled: true, WindowSeconds: 600},
+			flights: []msg.FlightRecord{
+				{Icao24: "780abc", Airline: "Cathay Pacific", Origin: "HKG", FirstSeen: 1000},
+				{Icao24: "780ABC", Airline: "Cathay Pacific", Origin: "HKG", FirstSeen: 1200},
+				{Airline: "Cathay Pacific", Origin: "HKG", FirstSeen: 1000},
+				{Airline: "Cathay Pacific", Origin: "HKG", FirstSeen: 1000},
+			},
+			total:      3,
+			duplicates: 1,
+			airlines:   map[string]int{"Cathay Pacific": 3},
+		},
+		{
+			name:  "Codeshare Disabled",
+			dedup: config.DedupConfig{Enabled: true, WindowSeconds: 600},
+			flights: []msg.FlightRecord{
+				{FlightNumber: "AA8900", Airline: "American", Origin: "HKG", Icao24: "780abc", FirstSeen: 1000},
+				{FlightNumber: "CX500", Airline: "Cathay Pacific", Origin: "HKG", Icao24: "780abc", FirstSeen: 1000},
+			},
+			total:      2,
+			duplicates: 0,
+			airlines:   map[string]int{"American": 1, "Cathay Pacific": 1},
+		},
+		{
+			name:  "Codeshare Same Aircraft",
+			dedup: config.DedupConfig{Enabled: true, WindowSeconds: 600, Codeshare: true},
+			flights: []msg.FlightRecord{
+				{FlightNumber: "AA8900", Airline: "American", Origin: "HKG", Icao24: "780abc", FirstSeen: 1000},
+				{FlightNumber: "JL7000", Airline: "Japan Airlines", Origin: "HKG", Icao24: "780abc", FirstSeen: 1060},
+			},
+			total:      1,
+			duplicates: 1,
+			airlines:   map[string]int{"American": 1},
+		},
+		{
+			name:  "Codeshare Without Aircraft",
+			dedup: config.DedupConfig{Enabled: true, WindowSeconds: 600, Codeshare: true},
+			flights: []msg.FlightRecord{
+				{FlightNumber: "AA8900", Airline: "American", Origin: "HKG", FirstSeen: 1000},
+				{FlightNumber: "CX500", Airline: "Cathay Pacific", Origin: "HKG", FirstSeen: 1030},
+			},
+			total:      2,
+			duplicates: 0,
+			airlines:   map[string]int{"American": 1, "Cathay Pacific": 1},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			summarizer, err := service.NewSummarizer(config.SummarizerConfig{
+				TopN:       5,
+				Statistics: []string{service.StatisticAirlines},
+				Dedup:      tc.dedup,
+			})
+			require.NoError(t, err)
+
+			summary, err := summarizer.SummarizeFlights(tc.flights, "2025-05-07", "VHHH")
+			require.NoError(t, err)
+			require.Equal(t, tc.total, summary.TotalFlights)
+			require.Equal(t, tc.duplicates, summary.DuplicatesRemoved)
+			require.Equal(t, tc.airlines, summary.AirlineCounts)
+		})
+	}
+}
@@ -0,0 +1,22 @@
+package service
+
+import (
+	"github.com/ansoncht/flight-microservices/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Periods of summaries labelled by the summary metric.
+const (
+	periodDaily   = "daily"
+	periodWeekly  = "weekly"
+	periodMonthly = "monthly"
+)
+
+// summariesWritten counts the flight summaries written to the repository by period.
+var summariesWritten = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: metrics.Namespace,
+	Subsystem: "processor",
+	Name:      "summaries_written_total",
+	Help:      "Number of flight summaries written to the repository by period, either daily, weekly or monthly.",
+}, []string{"period"})
@@ -5,33 +5,50 @@ import (
 	"encoding/json"
 	"fmt"
 	"log/slog"
+	"time"
 
 	msgQueue "github.com/ansoncht/flight-microservices/pkg/kafka"
 	"github.com/ansoncht/flight-microservices/pkg/model"
+	"github.com/ansoncht/flight-microservices/pkg/msgbus"
 	repo "github.com/ansoncht/flight-microservices/pkg/repository"
-	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/ansoncht/flight-microservices/pkg/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/sync/errgroup"
 )
 
+// tracer specifies the tracer of the processor service.
+var tracer = otel.Tracer("github.com/ansoncht/flight-microservices/internal/processor/service")
+
 // Processor holds dependencies for reading from Kafka, summarizing, storing, and publishing.
 type Processor struct {
 	// MessageWriter specifies the message writer to send messages to a message queue.
-	MessageWriter msgQueue.MessageWriter
+	MessageWriter msgbus.MessageWriter
 	// MessageReader specifies the message reader to read messages from a message queue.
-	MessageReader msgQueue.MessageReader
+	MessageReader msgbus.MessageReader
+	// DeadLetterWriter specifies the writer to set aside messages which cannot be decoded.
+	DeadLetterWriter msgQueue.DeadLetterWriter
 	// summarizer specifies the summarizer to gather flights statistic.
 	summarizer Summarizer
 	// repository  specifies the repository to interact with the db collection.
 	repository repo.SummaryRepository
+	// roller specifies the roller to aggregate daily summaries into weekly and monthly summaries.
+	roller Roller
+	// detector specifies the detector to flag unusual daily counts.
+	detector Detector
 }
 
 // NewProcessor creates a new Processor instance based on the
-// provided message writer, message reader, summarizer and repository.
+// provided message writer, message reader, summarizer, repository, roller, detector and dead letter writer.
 func NewProcessor(
-	messageWriter msgQueue.MessageWriter,
-	messageReader msgQueue.MessageReader,
+	messageWriter msgbus.MessageWriter,
+	messageReader msgbus.MessageReader,
 	summarizer Summarizer,
 	repository repo.SummaryRepository,
+	roller Roller,
+	detector Detector,
+	deadLetterWriter msgQueue.DeadLetterWriter,
 ) (*Processor, error) {
 	if messageWriter == nil {
 		return nil, fmt.Errorf("message writer is nil")
@@ -49,19 +66,41 @@ func NewProcessor(
 		return nil, fmt.Errorf("repository is nil")
 	}
 
+	if roller == nil {
+		return nil, fmt.Errorf("roller is nil")
+	}
+
+	if detector == nil {
+		return nil, fmt.Errorf("detector is nil")
+	}
+
+	if deadLetterWriter == nil {
+		return nil, fmt.Errorf("dead letter writer is nil")
+	}
+
 	return &Processor{
-		MessageWriter: messageWriter,
-		MessageReader: messageReader,
-		summarizer:    summarizer,
-		repository:    repository,
+		MessageWriter:    messageWriter,
+		MessageReader:    messageReader,
+		DeadLetterWriter: deadLetterWriter,
+		summarizer:       summarizer,
+		repository:       repository,
+		roller:           roller,
+		detector:         detector,
 	}, nil
 }
 
 func (p *Processor) Process(ctx context.Context) error {
 	flights := make([]model.FlightRecord, 0)
-	msgChan := make(chan kgo.Record)
+	msgChan := make(chan msgbus.Delivery, msgbus.DeliveryBufferSize)
 	airport := ""
 
+	// pending holds the messages of the current day, acknowledged once the day is finalized
+	pending := make([]msgbus.Delivery, 0)
+
+	// rejected holds the messages of the current day which cannot be decoded, set aside once the date of the day
+	// is known
+	rejected := make([]rejection, 0)
+
 	g, gCtx := errgroup.WithContext(ctx)
 
 	g.Go(func() error {
@@ -82,38 +121,54 @@ processingLoop:
 
 			switch key {
 			case "start_of_stream":
+				// A stream started before the last one ended was abandoned by its reader, so its flights are discarded
+				// rather than summarized as a partial day
+				if len(pending) > 0 {
+					slog.Warn("Discarding unfinished stream", "airport", airport, "flights", len(flights))
+
+					settle(pending, nil)
+					flights = flights[:0]
+					pending = pending[:0]
+					rejected = rejected[:0]
+				}
+
 				airport = string(msg.Value)
 				slog.Info("Started processing stream for airport", "airport", airport)
-			case "end_of_stream":
 
+				pending = append(pending, msg)
+			case "end_of_stream":
 				date := string(msg.Value)
 				slog.Info("Ended processing stream for airport", "date", date)
 
-				summary, err := p.summarizer.SummarizeFlights(flights, date, airport)
-				if err != nil {
-					return fmt.Errorf("failed to summarize flights: %w", err)
-				}
+				pending = append(pending, msg)
 
-				objectID, err := p.repository.Insert(ctx, *summary)
-				if err != nil {
-					return fmt.Errorf("failed to insert summary: %w", err)
+				if err := p.setAside(ctx, rejected, airport, date); err != nil {
+					settle(pending, err)
+					return err
 				}
 
-				if err := p.MessageWriter.WriteMessage(ctx, []byte("summary_id"), []byte(objectID)); err != nil {
-					return fmt.Errorf("failed to publish summary ObjectID: %w", err)
+				// Continue the trace of the reader which ended the stream
+				err := p.finalizeDay(msg.TraceContext(ctx), flights, date, airport)
+				settle(pending, err)
+				if err != nil {
+					return err
 				}
 
-				slog.Info("Published summary", "objectID", objectID)
-
 				flights = flights[:0]
+				pending = pending[:0]
+				rejected = rejected[:0]
 			default:
 				flight, err := p.decodeMessage(msg.Value)
 				if err != nil {
 					slog.Warn("Failed to decode flight record", "key", key, "error", err)
+
+					rejected = append(rejected, rejection{message: msg.Message, reason: err})
+					pending = append(pending, msg)
 					continue
 				}
 
 				flights = append(flights, *flight)
+				pending = append(pending, msg)
 			}
 		}
 	}
@@ -129,6 +184,177 @@ processingLoop:
 	return nil
 }
 
+// rejection holds a message which cannot be decoded and the reason why.
+type rejection struct {
+	message msgbus.Message
+	reason  error
+}
+
+// setAside writes the messages of a day stream which cannot be decoded to the dead-letter queue, along with the
+// airport and date of the stream so that replaying them reads the day again.
+func (p *Processor) setAside(ctx context.Context, rejected []rejection, airport string, date string) error {
+	for _, r := range rejected {
+		message := msgQueue.WithStream(r.message, airport, date)
+		if err := p.DeadLetterWriter.WriteDeadLetter(ctx, message, r.reason); err != nil {
+			return fmt.Errorf("failed to write dead letter: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// settle acknowledges the messages if their processing succeeded, or rejects them otherwise.
+func settle(deliveries []msgbus.Delivery, err error) {
+	for _, delivery := range deliveries {
+		if err != nil {
+			delivery.Nack(err)
+		} else {
+			delivery.Ack()
+		}
+	}
+}
+
+// finalizeDay summarizes the flights of a day, compares it with earlier days, stores and publishes the summary
+// and its anomalies, then publishes the weekly and monthly rollups the day closes.
+func (p *Processor) finalizeDay(ctx context.Context, flights []model.FlightRecord, date string, airport string) error {
+	ctx, span := tracer.Start(ctx, "FinalizeDay", trace.WithAttributes(
+		attribute.String("airport", airport),
+		attribute.String("date", date),
+	))
+	defer span.End()
+
+	_, summarizeSpan := tracer.Start(ctx, "SummarizeFlights", trace.WithAttributes(
+		attribute.Int("flights", len(flights)),
+	))
+	summary, err := p.summarizer.SummarizeFlights(flights, date, airport)
+	tracing.End(summarizeSpan, err)
+	if err != nil {
+		return fmt.Errorf("failed to summarize flights: %w", err)
+	}
+
+	if summary.DuplicatesRemoved > 0 {
+		slog.Info("Removed duplicate flights", "airport", airport, "date", date, "duplicates", summary.DuplicatesRemoved)
+	}
+
+	if err := p.compareWithHistory(ctx, summary); err != nil {
+		return err
+	}
+
+	anomalies, err := p.detector.DetectAnomalies(ctx, *summary)
+	if err != nil {
+		return fmt.Errorf("failed to detect anomalies: %w", err)
+	}
+	summary.Anomalies = anomalies
+
+	// Upsert so that a redelivered day replaces its summary instead of duplicating it
+	upsertCtx, upsertSpan := tracer.Start(ctx, "UpsertSummary")
+	objectID, err := p.repository.Upsert(upsertCtx, *summary)
+	tracing.End(upsertSpan, err)
+	if err != nil {
+		return fmt.Errorf("failed to upsert summary: %w", err)
+	}
+
+	summariesWritten.WithLabelValues(periodDaily).Inc()
+
+	if err := p.MessageWriter.WriteMessage(ctx, []byte("summary_id"), []byte(objectID)); err != nil {
+		return fmt.Errorf("failed to publish summary ObjectID: %w", err)
+	}
+
+	slog.Info("Published summary", "objectID", objectID)
+
+	if len(anomalies) > 0 {
+		if err := p.MessageWriter.WriteMessage(ctx, []byte("anomaly_detected"), []byte(objectID)); err != nil {
+			return fmt.Errorf("failed to publish anomaly event: %w", err)
+		}
+
+		slog.Info("Published anomaly event", "objectID", objectID, "anomalies", len(anomalies))
+	}
+
+	if err := p.publishRollups(ctx, airport, summary.Date.Time().UTC()); err != nil {
+		return err
+	}
+
+	// Make sure asynchronous writes are delivered before the day's messages are acknowledged
+	if err := p.MessageWriter.Flush(ctx); err != nil {
+		return fmt.Errorf("failed to flush published messages: %w", err)
+	}
+
+	return nil
+}
+
+// compareWithHistory sets the day-over-day and week-over-week changes of a summary against
+// the previous day and the same weekday last week, when those days were summarized.
+func (p *Processor) compareWithHistory(ctx context.Context, summary *model.DailyFlightSummary) error {
+	day := summary.Date.Time().UTC()
+	yesterday := day.AddDate(0, 0, -1)
+	lastWeek := day.AddDate(0, 0, -daysPerWeek)
+
+	history, err := p.repository.ListByDateRange(ctx, summary.Airport, lastWeek, yesterday)
+	if err != nil {
+		return fmt.Errorf("failed to list previous summaries: %w", err)
+	}
+
+	for _, previous := range latestSummaryPerDay(history) {
+		comparison := CompareSummaries(*summary, previous)
+
+		switch date := previous.Date.Time(); {
+		case date.Equal(yesterday):
+			summary.DayOverDay = &comparison
+		case date.Equal(lastWeek):
+			summary.WeekOverWeek = &comparison
+		}
+	}
+
+	return nil
+}
+
+// publishRollups rolls up and publishes the weekly summary when the day is a Sunday
+// and the monthly summary when the day is the last of its month. As the reader reads the day before yesterday by
+// default, the recap of a Monday to Sunday week is published on the Tuesday after it.
+func (p *Processor) publishRollups(ctx context.Context, airport string, day time.Time) error {
+	if day.Weekday() == time.Sunday {
+		objectID, err := p.roller.RollupWeek(ctx, airport, day)
+		if err != nil {
+			return fmt.Errorf("failed to roll up weekly summary: %w", err)
+		}
+
+		if objectID != "" {
+			if err := p.publishRollup(ctx, periodWeekly, objectID); err != nil {
+				return err
+			}
+		}
+	}
+
+	if day.AddDate(0, 0, 1).Day() == 1 {
+		objectID, err := p.roller.RollupMonth(ctx, airport, day)
+		if err != nil {
+			return fmt.Errorf("failed to roll up monthly summary: %w", err)
+		}
+
+		if objectID != "" {
+			if err := p.publishRollup(ctx, periodMonthly, objectID); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// publishRollup publishes the ObjectID of the weekly or monthly summary of the given period.
+func (p *Processor) publishRollup(ctx context.Context, period string, objectID string) error {
+	summariesWritten.WithLabelValues(period).Inc()
+
+	key := period + "_summary_id"
+	if err := p.MessageWriter.WriteMessage(ctx, []byte(key), []byte(objectID)); err != nil {
+		return fmt.Errorf("failed to publish %s summary ObjectID: %w", period, err)
+	}
+
+	slog.Info("Published rollup summary", "period", period, "objectID", objectID)
+
+	return nil
+}
+
 // decodeMessage decodes the Kafka message to a FlightRecord.
 func (p *Processor) decodeMessage(msg []byte) (*model.FlightRecord, error) {
 	var flight model.FlightRecord
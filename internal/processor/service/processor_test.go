@@ -11,9 +11,9 @@ import (
 	"github.com/ansoncht/flight-microservices/internal/test/mock"
 	"github.com/ansoncht/flight-microservices/pkg/kafka"
 	"github.com/ansoncht/flight-microservices/pkg/model"
+	"github.com/ansoncht/flight-microservices/pkg/msgbus"
 	"github.com/ansoncht/flight-microservices/pkg/repository"
 	"github.com/stretchr/testify/require"
-	"github.com/twmb/franz-go/pkg/kgo"
 	"go.uber.org/mock/gomock"
 )
 
@@ -25,8 +25,11 @@ func TestNewProcessor_ValidDependencies_ShouldSucceed(t *testing.T) {
 	reader := mock.NewMockMessageReader(ctrl)
 	sum := mock.NewMockSummarizer(ctrl)
 	repo := mock.NewMockSummaryRepository(ctrl)
+	roller := mock.NewMockRoller(ctrl)
+	detector := mock.NewMockDetector(ctrl)
+	deadLetters := mock.NewMockDeadLetterWriter(ctrl)
 
-	processor, err := service.NewProcessor(writer, reader, sum, repo)
+	processor, err := service.NewProcessor(writer, reader, sum, repo, roller, detector, deadLetters)
 	require.NoError(t, err)
 	require.NotNil(t, processor)
 	require.Equal(t, writer, processor.MessageWriter)
@@ -39,10 +42,13 @@ func TestNewProcessor_NilDependencies_ShouldReturnError(t *testing.T) {
 
 	tests := []struct {
 		name        string
-		writer      kafka.MessageWriter
-		reader      kafka.MessageReader
+		writer      msgbus.MessageWriter
+		reader      msgbus.MessageReader
 		summarizer  service.Summarizer
 		repository  repository.SummaryRepository
+		roller      service.Roller
+		detector    service.Detector
+		deadLetters kafka.DeadLetterWriter
 		expectedErr string
 	}{
 		{
@@ -51,6 +57,9 @@ func TestNewProcessor_NilDependencies_ShouldReturnError(t *testing.T) {
 			reader:      mock.NewMockMessageReader(ctrl),
 			summarizer:  mock.NewMockSummarizer(ctrl),
 			repository:  mock.NewMockSummaryRepository(ctrl),
+			roller:      mock.NewMockRoller(ctrl),
+			detector:    mock.NewMockDetector(ctrl),
+			deadLetters: mock.NewMockDeadLetterWriter(ctrl),
 			expectedErr: "message writer is nil",
 		},
 		{
@@ -59,6 +68,9 @@ func TestNewProcessor_NilDependencies_ShouldReturnError(t *testing.T) {
 			reader:      nil,
 			summarizer:  mock.NewMockSummarizer(ctrl),
 			repository:  mock.NewMockSummaryRepository(ctrl),
+			roller:      mock.NewMockRoller(ctrl),
+			detector:    mock.NewMockDetector(ctrl),
+			deadLetters: mock.NewMockDeadLetterWriter(ctrl),
 			expectedErr: "message reader is nil",
 		},
 		{
@@ -67,6 +79,9 @@ func TestNewProcessor_NilDependencies_ShouldReturnError(t *testing.T) {
 			reader:      mock.NewMockMessageReader(ctrl),
 			summarizer:  nil,
 			repository:  mock.NewMockSummaryRepository(ctrl),
+			roller:      mock.NewMockRoller(ctrl),
+			detector:    mock.NewMockDetector(ctrl),
+			deadLetters: mock.NewMockDeadLetterWriter(ctrl),
 			expectedErr: "summarizer is nil",
 		},
 		{
@@ -75,13 +90,57 @@ func TestNewProcessor_NilDependencies_ShouldReturnError(t *testing.T) {
 			reader:      mock.NewMockMessageReader(ctrl),
 			summarizer:  mock.NewMockSummarizer(ctrl),
 			repository:  nil,
+			roller:      mock.NewMockRoller(ctrl),
+			detector:    mock.NewMockDetector(ctrl),
+			deadLetters: mock.NewMockDeadLetterWriter(ctrl),
 			expectedErr: "repository is nil",
 		},
+		{
+			name:        "nil roller",
+			writer:      mock.NewMockMessageWriter(ctrl),
+			reader:      mock.NewMockMessageReader(ctrl),
+			summarizer:  mock.NewMockSummarizer(ctrl),
+			repository:  mock.NewMockSummaryRepository(ctrl),
+			roller:      nil,
+			detector:    mock.NewMockDetector(ctrl),
+			deadLetters: mock.NewMockDeadLetterWriter(ctrl),
+			expectedErr: "roller is nil",
+		},
+		{
+			name:        "nil detector",
+			writer:      mock.NewMockMessageWriter(ctrl),
+			reader:      mock.NewMockMessageReader(ctrl),
+			summarizer:  mock.NewMockSummarizer(ctrl),
+			repository:  mock.NewMockSummaryRepository(ctrl),
+			roller:      mock.NewMockRoller(ctrl),
+			detector:    nil,
+			deadLetters: mock.NewMockDeadLetterWriter(ctrl),
+			expectedErr: "detector is nil",
+		},
+		{
+			name:        "nil dead letter writer",
+			writer:      mock.NewMockMessageWriter(ctrl),
+			reader:      mock.NewMockMessageReader(ctrl),
+			summarizer:  mock.NewMockSummarizer(ctrl),
+			repository:  mock.NewMockSummaryRepository(ctrl),
+			roller:      mock.NewMockRoller(ctrl),
+			detector:    mock.NewMockDetector(ctrl),
+			deadLetters: nil,
+			expectedErr: "dead letter writer is nil",
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			processor, err := service.NewProcessor(tt.writer, tt.reader, tt.summarizer, tt.repository)
+			processor, err := service.NewProcessor(
+				tt.writer,
+				tt.reader,
+				tt.summarizer,
+				tt.repository,
+				tt.roller,
+				tt.detector,
+				tt.deadLetters,
+			)
 			require.ErrorContains(t, err, tt.expectedErr)
 			require.Nil(t, processor)
 		})
@@ -99,8 +158,12 @@ func TestProcess_ValidMessage_ShouldSuccess(t *testing.T) {
 	reader := mock.NewMockMessageReader(ctrl)
 	sum := mock.NewMockSummarizer(ctrl)
 	repo := mock.NewMockSummaryRepository(ctrl)
+	roller := mock.NewMockRoller(ctrl)
+	detector := mock.NewMockDetector(ctrl)
+	deadLetters := mock.NewMockDeadLetterWriter(ctrl)
+	acker := mock.NewMockAcknowledger(ctrl)
 
-	processor, err := service.NewProcessor(writer, reader, sum, repo)
+	processor, err := service.NewProcessor(writer, reader, sum, repo, roller, detector, deadLetters)
 	require.NoError(t, err)
 	require.NotNil(t, processor)
 
@@ -112,7 +175,7 @@ func TestProcess_ValidMessage_ShouldSuccess(t *testing.T) {
 	require.NoError(t, err)
 	require.NotNil(t, flight2)
 
-	messages := []kgo.Record{
+	messages := []msgbus.Message{
 		{Key: []byte("start_of_stream"), Value: []byte("JFK")},
 		{Key: []byte("flight"), Value: flight1},
 		{Key: []byte("flight"), Value: flight2},
@@ -120,10 +183,10 @@ func TestProcess_ValidMessage_ShouldSuccess(t *testing.T) {
 	}
 
 	reader.EXPECT().ReadMessages(gomock.Any(), gomock.Any()).DoAndReturn(
-		func(_ context.Context, msgChan chan<- kgo.Record) error {
+		func(_ context.Context, msgChan chan<- msgbus.Delivery) error {
 			defer close(msgChan)
 			for _, msg := range messages {
-				msgChan <- msg
+				msgChan <- msgbus.NewDelivery(msg, acker)
 			}
 			return nil
 		},
@@ -140,8 +203,74 @@ func TestProcess_ValidMessage_ShouldSuccess(t *testing.T) {
 	}
 
 	sum.EXPECT().SummarizeFlights(gomock.Any(), "2025-05-07", "JFK").Return(expectedSummary, nil)
-	repo.EXPECT().Insert(gomock.Any(), *expectedSummary).Return("test_id", nil)
+	repo.EXPECT().ListByDateRange(gomock.Any(), "JFK", gomock.Any(), gomock.Any()).Return(nil, nil)
+	detector.EXPECT().DetectAnomalies(gomock.Any(), gomock.Any()).Return(nil, nil)
+	repo.EXPECT().Upsert(gomock.Any(), *expectedSummary).Return("test_id", nil)
 	writer.EXPECT().WriteMessage(gomock.Any(), []byte("summary_id"), []byte("test_id")).Return(nil)
+	flushed := writer.EXPECT().Flush(gomock.Any()).Return(nil)
+
+	// Messages are only acknowledged once the summary is published and flushed
+	acker.EXPECT().Ack(gomock.Any()).Times(len(messages)).After(flushed)
+
+	err = processor.Process(ctx)
+	require.NoError(t, err)
+}
+
+func TestProcess_UnfinishedStream_ShouldBeDiscarded(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	writer := mock.NewMockMessageWriter(ctrl)
+	reader := mock.NewMockMessageReader(ctrl)
+	sum := mock.NewMockSummarizer(ctrl)
+	repo := mock.NewMockSummaryRepository(ctrl)
+	roller := mock.NewMockRoller(ctrl)
+	detector := mock.NewMockDetector(ctrl)
+	deadLetters := mock.NewMockDeadLetterWriter(ctrl)
+	acker := mock.NewMockAcknowledger(ctrl)
+
+	processor, err := service.NewProcessor(writer, reader, sum, repo, roller, detector, deadLetters)
+	require.NoError(t, err)
+	require.NotNil(t, processor)
+
+	abandoned, err := json.Marshal(&model.FlightRecord{Airline: "UA", FlightNumber: "123", Destination: "LAX"})
+	require.NoError(t, err)
+
+	flight, err := json.Marshal(&model.FlightRecord{Airline: "AA", FlightNumber: "456", Destination: "SFO"})
+	require.NoError(t, err)
+
+	// The first stream is abandoned by its reader without an end
+	messages := []msgbus.Message{
+		{Key: []byte("start_of_stream"), Value: []byte("JFK")},
+		{Key: []byte("flight"), Value: abandoned},
+		{Key: []byte("start_of_stream"), Value: []byte("JFK")},
+		{Key: []byte("flight"), Value: flight},
+		{Key: []byte("end_of_stream"), Value: []byte("2025-05-07")},
+	}
+
+	reader.EXPECT().ReadMessages(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, msgChan chan<- msgbus.Delivery) error {
+			defer close(msgChan)
+			for _, msg := range messages {
+				msgChan <- msgbus.NewDelivery(msg, acker)
+			}
+			return nil
+		},
+	)
+
+	summary := &model.DailyFlightSummary{Airport: "JFK", TotalFlights: 1}
+
+	sum.EXPECT().SummarizeFlights([]model.FlightRecord{{Airline: "AA", FlightNumber: "456", Destination: "SFO"}},
+		"2025-05-07", "JFK").Return(summary, nil)
+	repo.EXPECT().ListByDateRange(gomock.Any(), "JFK", gomock.Any(), gomock.Any()).Return(nil, nil)
+	detector.EXPECT().DetectAnomalies(gomock.Any(), gomock.Any()).Return(nil, nil)
+	repo.EXPECT().Upsert(gomock.Any(), *summary).Return("test_id", nil)
+	writer.EXPECT().WriteMessage(gomock.Any(), []byte("summary_id"), []byte("test_id")).Return(nil)
+	writer.EXPECT().Flush(gomock.Any()).Return(nil)
+	acker.EXPECT().Ack(gomock.Any()).Times(len(messages))
 
 	err = processor.Process(ctx)
 	require.NoError(t, err)
@@ -158,8 +287,11 @@ func TestProcess_MalformedMessage_ShouldSuccess(t *testing.T) {
 	reader := mock.NewMockMessageReader(ctrl)
 	sum := mock.NewMockSummarizer(ctrl)
 	repo := mock.NewMockSummaryRepository(ctrl)
+	roller := mock.NewMockRoller(ctrl)
+	detector := mock.NewMockDetector(ctrl)
+	deadLetters := mock.NewMockDeadLetterWriter(ctrl)
 
-	processor, err := service.NewProcessor(writer, reader, sum, repo)
+	processor, err := service.NewProcessor(writer, reader, sum, repo, roller, detector, deadLetters)
 	require.NoError(t, err)
 	require.NotNil(t, processor)
 
@@ -167,7 +299,7 @@ func TestProcess_MalformedMessage_ShouldSuccess(t *testing.T) {
 	require.NoError(t, err)
 	require.NotNil(t, flight1)
 
-	messages := []kgo.Record{
+	messages := []msgbus.Message{
 		{Key: []byte("start_of_stream"), Value: []byte("JFK")},
 		{Key: []byte("flight"), Value: flight1},
 		{Key: []byte("flight"), Value: []byte("malformed")},
@@ -175,10 +307,10 @@ func TestProcess_MalformedMessage_ShouldSuccess(t *testing.T) {
 	}
 
 	reader.EXPECT().ReadMessages(gomock.Any(), gomock.Any()).DoAndReturn(
-		func(_ context.Context, msgChan chan<- kgo.Record) error {
+		func(_ context.Context, msgChan chan<- msgbus.Delivery) error {
 			defer close(msgChan)
 			for _, msg := range messages {
-				msgChan <- msg
+				msgChan <- msgbus.NewDelivery(msg, nil)
 			}
 			return nil
 		},
@@ -194,14 +326,75 @@ func TestProcess_MalformedMessage_ShouldSuccess(t *testing.T) {
 		TopAirlines:       []string{"UA"},
 	}
 
+	// The malformed message is set aside along with the day stream it belongs to
+	deadLetters.EXPECT().WriteDeadLetter(
+		gomock.Any(),
+		kafka.WithStream(messages[2], "JFK", "2025-05-07"),
+		gomock.Any(),
+	).Return(nil)
 	sum.EXPECT().SummarizeFlights(gomock.Any(), "2025-05-07", "JFK").Return(expectedSummary, nil)
-	repo.EXPECT().Insert(gomock.Any(), *expectedSummary).Return("test_id", nil)
+	repo.EXPECT().ListByDateRange(gomock.Any(), "JFK", gomock.Any(), gomock.Any()).Return(nil, nil)
+	detector.EXPECT().DetectAnomalies(gomock.Any(), gomock.Any()).Return(nil, nil)
+	repo.EXPECT().Upsert(gomock.Any(), *expectedSummary).Return("test_id", nil)
 	writer.EXPECT().WriteMessage(gomock.Any(), []byte("summary_id"), []byte("test_id")).Return(nil)
+	writer.EXPECT().Flush(gomock.Any()).Return(nil)
 
 	err = processor.Process(ctx)
 	require.NoError(t, err)
 }
 
+func TestProcess_DeadLetterError_ShouldError(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	writer := mock.NewMockMessageWriter(ctrl)
+	reader := mock.NewMockMessageReader(ctrl)
+	sum := mock.NewMockSummarizer(ctrl)
+	repo := mock.NewMockSummaryRepository(ctrl)
+	roller := mock.NewMockRoller(ctrl)
+	detector := mock.NewMockDetector(ctrl)
+	deadLetters := mock.NewMockDeadLetterWriter(ctrl)
+
+	processor, err := service.NewProcessor(writer, reader, sum, repo, roller, detector, deadLetters)
+	require.NoError(t, err)
+	require.NotNil(t, processor)
+
+	malformed := msgbus.Message{Topic: "flights", Partition: 1, Offset: 42, Key: []byte("flight"), Value: []byte("{")}
+	messages := []msgbus.Message{
+		{Key: []byte("start_of_stream"), Value: []byte("JFK")},
+		malformed,
+		{Key: []byte("end_of_stream"), Value: []byte("2025-05-07")},
+	}
+
+	reader.EXPECT().ReadMessages(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, msgChan chan<- msgbus.Delivery) error {
+			defer close(msgChan)
+			for _, msg := range messages {
+				select {
+				case msgChan <- msgbus.NewDelivery(msg, nil):
+				case <-ctx.Done():
+					return nil
+				}
+			}
+			return nil
+		},
+	)
+
+	streamed := kafka.WithStream(malformed, "JFK", "2025-05-07")
+	deadLetters.EXPECT().WriteDeadLetter(gomock.Any(), streamed, gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ msgbus.Message, reason error) error {
+			require.ErrorContains(t, reason, "failed to parse flight record")
+			return errors.New("broker unavailable")
+		},
+	)
+
+	err = processor.Process(ctx)
+	require.ErrorContains(t, err, "failed to write dead letter")
+}
+
 func TestProcess_ContextCanceledWhenRead_ShouldError(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -213,19 +406,22 @@ func TestProcess_ContextCanceledWhenRead_ShouldError(t *testing.T) {
 	reader := mock.NewMockMessageReader(ctrl)
 	sum := mock.NewMockSummarizer(ctrl)
 	repo := mock.NewMockSummaryRepository(ctrl)
+	roller := mock.NewMockRoller(ctrl)
+	detector := mock.NewMockDetector(ctrl)
+	deadLetters := mock.NewMockDeadLetterWriter(ctrl)
 
 	flight, err := json.Marshal(&model.FlightRecord{Airline: "UA", FlightNumber: "123", Destination: "LAX"})
 	require.NoError(t, err)
 	require.NotNil(t, flight)
 
 	reader.EXPECT().ReadMessages(gomock.Any(), gomock.Any()).DoAndReturn(
-		func(ctx context.Context, msgChan chan<- kgo.Record) error {
+		func(ctx context.Context, msgChan chan<- msgbus.Delivery) error {
 			defer close(msgChan)
 
 			select {
 			case <-ctx.Done():
 				return ctx.Err()
-			case msgChan <- kgo.Record{Key: []byte("start_of_stream"), Value: []byte("JFK")}:
+			case msgChan <- msgbus.NewDelivery(msgbus.Message{Key: []byte("start_of_stream"), Value: []byte("JFK")}, nil):
 				cancel()
 				time.Sleep(10 * time.Millisecond)
 				return ctx.Err()
@@ -233,7 +429,7 @@ func TestProcess_ContextCanceledWhenRead_ShouldError(t *testing.T) {
 		},
 	)
 
-	processor, err := service.NewProcessor(writer, reader, sum, repo)
+	processor, err := service.NewProcessor(writer, reader, sum, repo, roller, detector, deadLetters)
 	require.NoError(t, err)
 	require.NotNil(t, processor)
 
@@ -252,19 +448,22 @@ func TestProcess_ContextCanceled_ShouldError(t *testing.T) {
 	reader := mock.NewMockMessageReader(ctrl)
 	sum := mock.NewMockSummarizer(ctrl)
 	repo := mock.NewMockSummaryRepository(ctrl)
+	roller := mock.NewMockRoller(ctrl)
+	detector := mock.NewMockDetector(ctrl)
+	deadLetters := mock.NewMockDeadLetterWriter(ctrl)
 
 	flight, err := json.Marshal(&model.FlightRecord{Airline: "UA", FlightNumber: "123", Destination: "LAX"})
 	require.NoError(t, err)
 	require.NotNil(t, flight)
 
 	reader.EXPECT().ReadMessages(gomock.Any(), gomock.Any()).DoAndReturn(
-		func(ctx context.Context, msgChan chan<- kgo.Record) error {
+		func(ctx context.Context, msgChan chan<- msgbus.Delivery) error {
 			defer close(msgChan)
 
 			select {
 			case <-ctx.Done():
 				return ctx.Err()
-			case msgChan <- kgo.Record{Key: []byte("start_of_stream"), Value: []byte("JFK")}:
+			case msgChan <- msgbus.NewDelivery(msgbus.Message{Key: []byte("start_of_stream"), Value: []byte("JFK")}, nil):
 				cancel()
 				time.Sleep(10 * time.Millisecond)
 			}
@@ -272,7 +471,7 @@ func TestProcess_ContextCanceled_ShouldError(t *testing.T) {
 		},
 	)
 
-	processor, err := service.NewProcessor(writer, reader, sum, repo)
+	processor, err := service.NewProcessor(writer, reader, sum, repo, roller, detector, deadLetters)
 	require.NoError(t, err)
 	require.NotNil(t, processor)
 
@@ -289,13 +488,16 @@ func TestProcess_ReaderError_ShouldError(t *testing.T) {
 	reader := mock.NewMockMessageReader(ctrl)
 	sum := mock.NewMockSummarizer(ctrl)
 	repo := mock.NewMockSummaryRepository(ctrl)
+	roller := mock.NewMockRoller(ctrl)
+	detector := mock.NewMockDetector(ctrl)
+	deadLetters := mock.NewMockDeadLetterWriter(ctrl)
 
-	processor, err := service.NewProcessor(writer, reader, sum, repo)
+	processor, err := service.NewProcessor(writer, reader, sum, repo, roller, detector, deadLetters)
 	require.NoError(t, err)
 	require.NotNil(t, processor)
 
 	reader.EXPECT().ReadMessages(gomock.Any(), gomock.Any()).DoAndReturn(
-		func(_ context.Context, msgChan chan<- kgo.Record) error {
+		func(_ context.Context, msgChan chan<- msgbus.Delivery) error {
 			defer close(msgChan)
 			return errors.New("test error")
 		},
@@ -314,8 +516,11 @@ func TestProcess_SummarizeFlightsError_ShouldError(t *testing.T) {
 	reader := mock.NewMockMessageReader(ctrl)
 	sum := mock.NewMockSummarizer(ctrl)
 	repo := mock.NewMockSummaryRepository(ctrl)
+	roller := mock.NewMockRoller(ctrl)
+	detector := mock.NewMockDetector(ctrl)
+	deadLetters := mock.NewMockDeadLetterWriter(ctrl)
 
-	processor, err := service.NewProcessor(writer, reader, sum, repo)
+	processor, err := service.NewProcessor(writer, reader, sum, repo, roller, detector, deadLetters)
 	require.NoError(t, err)
 	require.NotNil(t, processor)
 
@@ -323,17 +528,17 @@ func TestProcess_SummarizeFlightsError_ShouldError(t *testing.T) {
 	require.NoError(t, err)
 	require.NotNil(t, flight)
 
-	messages := []kgo.Record{
+	messages := []msgbus.Message{
 		{Key: []byte("start_of_stream"), Value: []byte("JFK")},
 		{Key: []byte("flight"), Value: flight},
 		{Key: []byte("end_of_stream"), Value: []byte("2025-05-07")},
 	}
 
 	reader.EXPECT().ReadMessages(gomock.Any(), gomock.Any()).DoAndReturn(
-		func(_ context.Context, msgChan chan<- kgo.Record) error {
+		func(_ context.Context, msgChan chan<- msgbus.Delivery) error {
 			defer close(msgChan)
 			for _, msg := range messages {
-				msgChan <- msg
+				msgChan <- msgbus.NewDelivery(msg, nil)
 			}
 			return nil
 		},
@@ -345,7 +550,7 @@ func TestProcess_SummarizeFlightsError_ShouldError(t *testing.T) {
 	require.ErrorContains(t, err, "failed to summarize flights")
 }
 
-func TestProcessor_Process_RepositoryInsertError(t *testing.T) {
+func TestProcessor_Process_RepositoryUpsertError(t *testing.T) {
 	ctx := context.Background()
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -354,8 +559,12 @@ func TestProcessor_Process_RepositoryInsertError(t *testing.T) {
 	reader := mock.NewMockMessageReader(ctrl)
 	sum := mock.NewMockSummarizer(ctrl)
 	repo := mock.NewMockSummaryRepository(ctrl)
+	roller := mock.NewMockRoller(ctrl)
+	detector := mock.NewMockDetector(ctrl)
+	deadLetters := mock.NewMockDeadLetterWriter(ctrl)
+	acker := mock.NewMockAcknowledger(ctrl)
 
-	processor, err := service.NewProcessor(writer, reader, sum, repo)
+	processor, err := service.NewProcessor(writer, reader, sum, repo, roller, detector, deadLetters)
 	require.NoError(t, err)
 	require.NotNil(t, processor)
 
@@ -363,17 +572,17 @@ func TestProcessor_Process_RepositoryInsertError(t *testing.T) {
 	require.NoError(t, err)
 	require.NotNil(t, flight)
 
-	messages := []kgo.Record{
+	messages := []msgbus.Message{
 		{Key: []byte("start_of_stream"), Value: []byte("JFK")},
 		{Key: []byte("flight"), Value: flight},
 		{Key: []byte("end_of_stream"), Value: []byte("2025-05-07")},
 	}
 
 	reader.EXPECT().ReadMessages(gomock.Any(), gomock.Any()).DoAndReturn(
-		func(_ context.Context, msgChan chan<- kgo.Record) error {
+		func(_ context.Context, msgChan chan<- msgbus.Delivery) error {
 			defer close(msgChan)
 			for _, msg := range messages {
-				msgChan <- msg
+				msgChan <- msgbus.NewDelivery(msg, acker)
 			}
 			return nil
 		},
@@ -390,10 +599,13 @@ func TestProcessor_Process_RepositoryInsertError(t *testing.T) {
 	}
 
 	sum.EXPECT().SummarizeFlights(gomock.Any(), "2025-05-07", "JFK").Return(expectedSummary, nil)
-	repo.EXPECT().Insert(gomock.Any(), *expectedSummary).Return("", errors.New("test error"))
+	repo.EXPECT().ListByDateRange(gomock.Any(), "JFK", gomock.Any(), gomock.Any()).Return(nil, nil)
+	detector.EXPECT().DetectAnomalies(gomock.Any(), gomock.Any()).Return(nil, nil)
+	repo.EXPECT().Upsert(gomock.Any(), *expectedSummary).Return("", errors.New("test error"))
+	acker.EXPECT().Nack(gomock.Any(), gomock.Any()).Times(len(messages))
 
 	err = processor.Process(ctx)
-	require.ErrorContains(t, err, "failed to insert summary")
+	require.ErrorContains(t, err, "failed to upsert summary")
 }
 
 func TestProcessor_Process_WriteMessageError(t *testing.T) {
@@ -405,8 +617,11 @@ func TestProcessor_Process_WriteMessageError(t *testing.T) {
 	reader := mock.NewMockMessageReader(ctrl)
 	sum := mock.NewMockSummarizer(ctrl)
 	repo := mock.NewMockSummaryRepository(ctrl)
+	roller := mock.NewMockRoller(ctrl)
+	detector := mock.NewMockDetector(ctrl)
+	deadLetters := mock.NewMockDeadLetterWriter(ctrl)
 
-	processor, err := service.NewProcessor(writer, reader, sum, repo)
+	processor, err := service.NewProcessor(writer, reader, sum, repo, roller, detector, deadLetters)
 	require.NoError(t, err)
 	require.NotNil(t, processor)
 
@@ -414,17 +629,17 @@ func TestProcessor_Process_WriteMessageError(t *testing.T) {
 	require.NoError(t, err)
 	require.NotNil(t, flight)
 
-	messages := []kgo.Record{
+	messages := []msgbus.Message{
 		{Key: []byte("start_of_stream"), Value: []byte("JFK")},
 		{Key: []byte("flight"), Value: flight},
 		{Key: []byte("end_of_stream"), Value: []byte("2025-05-07")},
 	}
 
 	reader.EXPECT().ReadMessages(gomock.Any(), gomock.Any()).DoAndReturn(
-		func(_ context.Context, msgChan chan<- kgo.Record) error {
+		func(_ context.Context, msgChan chan<- msgbus.Delivery) error {
 			defer close(msgChan)
 			for _, msg := range messages {
-				msgChan <- msg
+				msgChan <- msgbus.NewDelivery(msg, nil)
 			}
 			return nil
 		},
@@ -441,9 +656,493 @@ func TestProcessor_Process_WriteMessageError(t *testing.T) {
 	}
 
 	sum.EXPECT().SummarizeFlights(gomock.Any(), "2025-05-07", "JFK").Return(expectedSummary, nil)
-	repo.EXPECT().Insert(gomock.Any(), *expectedSummary).Return("test_id", nil)
+	repo.EXPECT().ListByDateRange(gomock.Any(), "JFK", gomock.Any(), gomock.Any()).Return(nil, nil)
+	detector.EXPECT().DetectAnomalies(gomock.Any(), gomock.Any()).Return(nil, nil)
+	repo.EXPECT().Upsert(gomock.Any(), *expectedSummary).Return("test_id", nil)
 	writer.EXPECT().WriteMessage(gomock.Any(), []byte("summary_id"), []byte("test_id")).Return(errors.New("test error"))
 
 	err = processor.Process(ctx)
 	require.ErrorContains(t, err, "failed to publish summary ObjectID")
 }
+
+func TestProcessor_Process_FlushError(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	writer := mock.NewMockMessageWriter(ctrl)
+	reader := mock.NewMockMessageReader(ctrl)
+	sum := mock.NewMockSummarizer(ctrl)
+	repo := mock.NewMockSummaryRepository(ctrl)
+	roller := mock.NewMockRoller(ctrl)
+	detector := mock.NewMockDetector(ctrl)
+	deadLetters := mock.NewMockDeadLetterWriter(ctrl)
+
+	processor, err := service.NewProcessor(writer, reader, sum, repo, roller, detector, deadLetters)
+	require.NoError(t, err)
+	require.NotNil(t, processor)
+
+	flight, err := json.Marshal(&model.FlightRecord{Airline: "UA", FlightNumber: "123", Destination: "LAX"})
+	require.NoError(t, err)
+	require.NotNil(t, flight)
+
+	messages := []msgbus.Message{
+		{Key: []byte("start_of_stream"), Value: []byte("JFK")},
+		{Key: []byte("flight"), Value: flight},
+		{Key: []byte("end_of_stream"), Value: []byte("2025-05-07")},
+	}
+
+	reader.EXPECT().ReadMessages(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, msgChan chan<- msgbus.Delivery) error {
+			defer close(msgChan)
+			for _, msg := range messages {
+				msgChan <- msgbus.NewDelivery(msg, nil)
+			}
+			return nil
+		},
+	)
+
+	expectedSummary := &model.DailyFlightSummary{
+		Date:              model.ToMongoDateTime(time.Date(2025, 5, 7, 0, 0, 0, 0, time.UTC)),
+		Airport:           "JFK",
+		TotalFlights:      1,
+		AirlineCounts:     map[string]int{"UA": 1},
+		DestinationCounts: map[string]int{"LAX": 1},
+		TopDestinations:   []string{"LAX"},
+		TopAirlines:       []string{"UA"},
+	}
+
+	sum.EXPECT().SummarizeFlights(gomock.Any(), "2025-05-07", "JFK").Return(expectedSummary, nil)
+	repo.EXPECT().ListByDateRange(gomock.Any(), "JFK", gomock.Any(), gomock.Any()).Return(nil, nil)
+	detector.EXPECT().DetectAnomalies(gomock.Any(), gomock.Any()).Return(nil, nil)
+	repo.EXPECT().Upsert(gomock.Any(), *expectedSummary).Return("test_id", nil)
+	writer.EXPECT().WriteMessage(gomock.Any(), []byte("summary_id"), []byte("test_id")).Return(nil)
+	writer.EXPECT().Flush(gomock.Any()).Return(errors.New("test error"))
+
+	err = processor.Process(ctx)
+	require.ErrorContains(t, err, "failed to flush published messages")
+}
+
+func TestProcess_PeriodEndDate_ShouldPublishRollups(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	writer := mock.NewMockMessageWriter(ctrl)
+	reader := mock.NewMockMessageReader(ctrl)
+	sum := mock.NewMockSummarizer(ctrl)
+	repo := mock.NewMockSummaryRepository(ctrl)
+	roller := mock.NewMockRoller(ctrl)
+	detector := mock.NewMockDetector(ctrl)
+	deadLetters := mock.NewMockDeadLetterWriter(ctrl)
+
+	processor, err := service.NewProcessor(writer, reader, sum, repo, roller, detector, deadLetters)
+	require.NoError(t, err)
+	require.NotNil(t, processor)
+
+	// 2025-08-31 is both a Sunday and the last day of the month
+	messages := []msgbus.Message{
+		{Key: []byte("start_of_stream"), Value: []byte("JFK")},
+		{Key: []byte("end_of_stream"), Value: []byte("2025-08-31")},
+	}
+
+	reader.EXPECT().ReadMessages(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, msgChan chan<- msgbus.Delivery) error {
+			defer close(msgChan)
+			for _, msg := range messages {
+				msgChan <- msgbus.NewDelivery(msg, nil)
+			}
+			return nil
+		},
+	)
+
+	day := time.Date(2025, 8, 31, 0, 0, 0, 0, time.UTC)
+	expectedSummary := &model.DailyFlightSummary{
+		Date:    model.ToMongoDateTime(day),
+		Airport: "JFK",
+	}
+
+	sum.EXPECT().SummarizeFlights(gomock.Any(), "2025-08-31", "JFK").Return(expectedSummary, nil)
+	repo.EXPECT().ListByDateRange(gomock.Any(), "JFK", gomock.Any(), gomock.Any()).Return(nil, nil)
+	detector.EXPECT().DetectAnomalies(gomock.Any(), gomock.Any()).Return(nil, nil)
+	repo.EXPECT().Upsert(gomock.Any(), *expectedSummary).Return("test_id", nil)
+	writer.EXPECT().WriteMessage(gomock.Any(), []byte("summary_id"), []byte("test_id")).Return(nil)
+	roller.EXPECT().RollupWeek(gomock.Any(), "JFK", day).Return("weekly_id", nil)
+	writer.EXPECT().WriteMessage(gomock.Any(), []byte("weekly_summary_id"), []byte("weekly_id")).Return(nil)
+	roller.EXPECT().RollupMonth(gomock.Any(), "JFK", day).Return("monthly_id", nil)
+	writer.EXPECT().WriteMessage(gomock.Any(), []byte("monthly_summary_id"), []byte("monthly_id")).Return(nil)
+	writer.EXPECT().Flush(gomock.Any()).Return(nil)
+
+	err = processor.Process(ctx)
+	require.NoError(t, err)
+}
+
+func TestProcess_MidWeekDate_ShouldNotPublishRollups(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	writer := mock.NewMockMessageWriter(ctrl)
+	reader := mock.NewMockMessageReader(ctrl)
+	sum := mock.NewMockSummarizer(ctrl)
+	repo := mock.NewMockSummaryRepository(ctrl)
+	roller := mock.NewMockRoller(ctrl)
+	detector := mock.NewMockDetector(ctrl)
+	deadLetters := mock.NewMockDeadLetterWriter(ctrl)
+
+	processor, err := service.NewProcessor(writer, reader, sum, repo, roller, detector, deadLetters)
+	require.NoError(t, err)
+	require.NotNil(t, processor)
+
+	// 2025-05-12 is the Monday after the week ending on 2025-05-11, which is only rolled up once that Sunday is read
+	messages := []msgbus.Message{
+		{Key: []byte("start_of_stream"), Value: []byte("JFK")},
+		{Key: []byte("end_of_stream"), Value: []byte("2025-05-12")},
+	}
+
+	reader.EXPECT().ReadMessages(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, msgChan chan<- msgbus.Delivery) error {
+			defer close(msgChan)
+			for _, msg := range messages {
+				msgChan <- msgbus.NewDelivery(msg, nil)
+			}
+			return nil
+		},
+	)
+
+	expectedSummary := &model.DailyFlightSummary{
+		Date:    model.ToMongoDateTime(time.Date(2025, 5, 12, 0, 0, 0, 0, time.UTC)),
+		Airport: "JFK",
+	}
+
+	sum.EXPECT().SummarizeFlights(gomock.Any(), "2025-05-12", "JFK").Return(expectedSummary, nil)
+	repo.EXPECT().ListByDateRange(gomock.Any(), "JFK", gomock.Any(), gomock.Any()).Return(nil, nil)
+	detector.EXPECT().DetectAnomalies(gomock.Any(), gomock.Any()).Return(nil, nil)
+	repo.EXPECT().Upsert(gomock.Any(), *expectedSummary).Return("test_id", nil)
+	writer.EXPECT().WriteMessage(gomock.Any(), []byte("summary_id"), []byte("test_id")).Return(nil)
+	writer.EXPECT().Flush(gomock.Any()).Return(nil)
+
+	err = processor.Process(ctx)
+	require.NoError(t, err)
+}
+
+func TestProcess_RollupError_ShouldError(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	writer := mock.NewMockMessageWriter(ctrl)
+	reader := mock.NewMockMessageReader(ctrl)
+	sum := mock.NewMockSummarizer(ctrl)
+	repo := mock.NewMockSummaryRepository(ctrl)
+	roller := mock.NewMockRoller(ctrl)
+	detector := mock.NewMockDetector(ctrl)
+	deadLetters := mock.NewMockDeadLetterWriter(ctrl)
+
+	processor, err := service.NewProcessor(writer, reader, sum, repo, roller, detector, deadLetters)
+	require.NoError(t, err)
+	require.NotNil(t, processor)
+
+	// 2025-05-11 is a Sunday
+	messages := []msgbus.Message{
+		{Key: []byte("start_of_stream"), Value: []byte("JFK")},
+		{Key: []byte("end_of_stream"), Value: []byte("2025-05-11")},
+	}
+
+	reader.EXPECT().ReadMessages(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, msgChan chan<- msgbus.Delivery) error {
+			defer close(msgChan)
+			for _, msg := range messages {
+				msgChan <- msgbus.NewDelivery(msg, nil)
+			}
+			return nil
+		},
+	)
+
+	day := time.Date(2025, 5, 11, 0, 0, 0, 0, time.UTC)
+	expectedSummary := &model.DailyFlightSummary{
+		Date:    model.ToMongoDateTime(day),
+		Airport: "JFK",
+	}
+
+	sum.EXPECT().SummarizeFlights(gomock.Any(), "2025-05-11", "JFK").Return(expectedSummary, nil)
+	repo.EXPECT().ListByDateRange(gomock.Any(), "JFK", gomock.Any(), gomock.Any()).Return(nil, nil)
+	detector.EXPECT().DetectAnomalies(gomock.Any(), gomock.Any()).Return(nil, nil)
+	repo.EXPECT().Upsert(gomock.Any(), *expectedSummary).Return("test_id", nil)
+	writer.EXPECT().WriteMessage(gomock.Any(), []byte("summary_id"), []byte("test_id")).Return(nil)
+	roller.EXPECT().RollupWeek(gomock.Any(), "JFK", day).Return("", errors.New("test error"))
+
+	err = processor.Process(ctx)
+	require.ErrorContains(t, err, "failed to roll up weekly summary")
+}
+
+func TestProcess_EmptyRollup_ShouldNotPublish(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	writer := mock.NewMockMessageWriter(ctrl)
+	reader := mock.NewMockMessageReader(ctrl)
+	sum := mock.NewMockSummarizer(ctrl)
+	repo := mock.NewMockSummaryRepository(ctrl)
+	roller := mock.NewMockRoller(ctrl)
+	detector := mock.NewMockDetector(ctrl)
+	deadLetters := mock.NewMockDeadLetterWriter(ctrl)
+
+	processor, err := service.NewProcessor(writer, reader, sum, repo, roller, detector, deadLetters)
+	require.NoError(t, err)
+	require.NotNil(t, processor)
+
+	// 2025-05-11 is a Sunday
+	messages := []msgbus.Message{
+		{Key: []byte("start_of_stream"), Value: []byte("JFK")},
+		{Key: []byte("end_of_stream"), Value: []byte("2025-05-11")},
+	}
+
+	reader.EXPECT().ReadMessages(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, msgChan chan<- msgbus.Delivery) error {
+			defer close(msgChan)
+			for _, msg := range messages {
+				msgChan <- msgbus.NewDelivery(msg, nil)
+			}
+			return nil
+		},
+	)
+
+	day := time.Date(2025, 5, 11, 0, 0, 0, 0, time.UTC)
+	expectedSummary := &model.DailyFlightSummary{
+		Date:    model.ToMongoDateTime(day),
+		Airport: "JFK",
+	}
+
+	sum.EXPECT().SummarizeFlights(gomock.Any(), "2025-05-11", "JFK").Return(expectedSummary, nil)
+	repo.EXPECT().ListByDateRange(gomock.Any(), "JFK", gomock.Any(), gomock.Any()).Return(nil, nil)
+	detector.EXPECT().DetectAnomalies(gomock.Any(), gomock.Any()).Return(nil, nil)
+	repo.EXPECT().Upsert(gomock.Any(), *expectedSummary).Return("test_id", nil)
+	writer.EXPECT().WriteMessage(gomock.Any(), []byte("summary_id"), []byte("test_id")).Return(nil)
+	roller.EXPECT().RollupWeek(gomock.Any(), "JFK", day).Return("", nil)
+	writer.EXPECT().Flush(gomock.Any()).Return(nil)
+
+	err = processor.Process(ctx)
+	require.NoError(t, err)
+}
+
+func TestProcess_PreviousSummaries_ShouldStoreTrends(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	writer := mock.NewMockMessageWriter(ctrl)
+	reader := mock.NewMockMessageReader(ctrl)
+	sum := mock.NewMockSummarizer(ctrl)
+	repo := mock.NewMockSummaryRepository(ctrl)
+	roller := mock.NewMockRoller(ctrl)
+	detector := mock.NewMockDetector(ctrl)
+	deadLetters := mock.NewMockDeadLetterWriter(ctrl)
+
+	processor, err := service.NewProcessor(writer, reader, sum, repo, roller, detector, deadLetters)
+	require.NoError(t, err)
+	require.NotNil(t, processor)
+
+	messages := []msgbus.Message{
+		{Key: []byte("start_of_stream"), Value: []byte("JFK")},
+		{Key: []byte("end_of_stream"), Value: []byte("2025-05-07")},
+	}
+
+	reader.EXPECT().ReadMessages(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, msgChan chan<- msgbus.Delivery) error {
+			defer close(msgChan)
+			for _, msg := range messages {
+				msgChan <- msgbus.NewDelivery(msg, nil)
+			}
+			return nil
+		},
+	)
+
+	day := time.Date(2025, 5, 7, 0, 0, 0, 0, time.UTC)
+	yesterday := day.AddDate(0, 0, -1)
+	lastWeek := day.AddDate(0, 0, -7)
+
+	summary := &model.DailyFlightSummary{
+		Date:          model.ToMongoDateTime(day),
+		Airport:       "JFK",
+		TotalFlights:  12,
+		AirlineCounts: map[string]int{"United": 12},
+	}
+	history := []model.DailyFlightSummary{
+		{Date: model.ToMongoDateTime(lastWeek), Airport: "JFK", TotalFlights: 10},
+		{Date: model.ToMongoDateTime(day.AddDate(0, 0, -3)), Airport: "JFK", TotalFlights: 99},
+		{Date: model.ToMongoDateTime(yesterday), Airport: "JFK", TotalFlights: 16},
+	}
+
+	sum.EXPECT().SummarizeFlights(gomock.Any(), "2025-05-07", "JFK").Return(summary, nil)
+	repo.EXPECT().ListByDateRange(gomock.Any(), "JFK", lastWeek, yesterday).Return(history, nil)
+	detector.EXPECT().DetectAnomalies(gomock.Any(), gomock.Any()).Return(nil, nil)
+	repo.EXPECT().Upsert(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, inserted model.DailyFlightSummary) (string, error) {
+			require.NotNil(t, inserted.DayOverDay)
+			require.Equal(t, model.ToMongoDateTime(yesterday), inserted.DayOverDay.BaselineDate)
+			require.Equal(t, -4, inserted.DayOverDay.FlightsChange)
+			require.InDelta(t, -25.0, inserted.DayOverDay.FlightsChangePercent, 0.001)
+
+			require.NotNil(t, inserted.WeekOverWeek)
+			require.Equal(t, model.ToMongoDateTime(lastWeek), inserted.WeekOverWeek.BaselineDate)
+			require.Equal(t, 2, inserted.WeekOverWeek.FlightsChange)
+			require.InDelta(t, 20.0, inserted.WeekOverWeek.FlightsChangePercent, 0.001)
+			require.Equal(t, []string{"United"}, inserted.WeekOverWeek.AirlinesGained)
+			return "test_id", nil
+		},
+	)
+	writer.EXPECT().WriteMessage(gomock.Any(), []byte("summary_id"), []byte("test_id")).Return(nil)
+	writer.EXPECT().Flush(gomock.Any()).Return(nil)
+
+	err = processor.Process(ctx)
+	require.NoError(t, err)
+}
+
+func TestProcess_ListPreviousSummariesError_ShouldError(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	writer := mock.NewMockMessageWriter(ctrl)
+	reader := mock.NewMockMessageReader(ctrl)
+	sum := mock.NewMockSummarizer(ctrl)
+	repo := mock.NewMockSummaryRepository(ctrl)
+	roller := mock.NewMockRoller(ctrl)
+	detector := mock.NewMockDetector(ctrl)
+	deadLetters := mock.NewMockDeadLetterWriter(ctrl)
+
+	processor, err := service.NewProcessor(writer, reader, sum, repo, roller, detector, deadLetters)
+	require.NoError(t, err)
+	require.NotNil(t, processor)
+
+	messages := []msgbus.Message{
+		{Key: []byte("start_of_stream"), Value: []byte("JFK")},
+		{Key: []byte("end_of_stream"), Value: []byte("2025-05-07")},
+	}
+
+	reader.EXPECT().ReadMessages(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, msgChan chan<- msgbus.Delivery) error {
+			defer close(msgChan)
+			for _, msg := range messages {
+				msgChan <- msgbus.NewDelivery(msg, nil)
+			}
+			return nil
+		},
+	)
+
+	summary := &model.DailyFlightSummary{
+		Date:    model.ToMongoDateTime(time.Date(2025, 5, 7, 0, 0, 0, 0, time.UTC)),
+		Airport: "JFK",
+	}
+
+	sum.EXPECT().SummarizeFlights(gomock.Any(), "2025-05-07", "JFK").Return(summary, nil)
+	repo.EXPECT().ListByDateRange(gomock.Any(), "JFK", gomock.Any(), gomock.Any()).
+		Return(nil, errors.New("test error"))
+
+	err = processor.Process(ctx)
+	require.ErrorContains(t, err, "failed to list previous summaries")
+}
+
+func TestProcess_AnomaliesDetected_ShouldPublishEvent(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	writer := mock.NewMockMessageWriter(ctrl)
+	reader := mock.NewMockMessageReader(ctrl)
+	sum := mock.NewMockSummarizer(ctrl)
+	repo := mock.NewMockSummaryRepository(ctrl)
+	roller := mock.NewMockRoller(ctrl)
+	detector := mock.NewMockDetector(ctrl)
+	deadLetters := mock.NewMockDeadLetterWriter(ctrl)
+
+	processor, err := service.NewProcessor(writer, reader, sum, repo, roller, detector, deadLetters)
+	require.NoError(t, err)
+	require.NotNil(t, processor)
+
+	messages := []msgbus.Message{
+		{Key: []byte("start_of_stream"), Value: []byte("JFK")},
+		{Key: []byte("end_of_stream"), Value: []byte("2025-05-07")},
+	}
+
+	reader.EXPECT().ReadMessages(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, msgChan chan<- msgbus.Delivery) error {
+			defer close(msgChan)
+			for _, msg := range messages {
+				msgChan <- msgbus.NewDelivery(msg, nil)
+			}
+			return nil
+		},
+	)
+
+	summary := &model.DailyFlightSummary{
+		Date:    model.ToMongoDateTime(time.Date(2025, 5, 7, 0, 0, 0, 0, time.UTC)),
+		Airport: "JFK",
+	}
+	anomalies := []model.Anomaly{
+		{Metric: model.AnomalyMetricTotalFlights, Value: 0, Baseline: 50, Score: -33.7, Method: "mad"},
+	}
+
+	sum.EXPECT().SummarizeFlights(gomock.Any(), "2025-05-07", "JFK").Return(summary, nil)
+	repo.EXPECT().ListByDateRange(gomock.Any(), "JFK", gomock.Any(), gomock.Any()).Return(nil, nil)
+	detector.EXPECT().DetectAnomalies(gomock.Any(), gomock.Any()).Return(anomalies, nil)
+	repo.EXPECT().Upsert(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, inserted model.DailyFlightSummary) (string, error) {
+			require.Equal(t, anomalies, inserted.Anomalies)
+			return "test_id", nil
+		},
+	)
+	writer.EXPECT().WriteMessage(gomock.Any(), []byte("summary_id"), []byte("test_id")).Return(nil)
+	writer.EXPECT().WriteMessage(gomock.Any(), []byte("anomaly_detected"), []byte("test_id")).Return(nil)
+	writer.EXPECT().Flush(gomock.Any()).Return(nil)
+
+	err = processor.Process(ctx)
+	require.NoError(t, err)
+}
+
+func TestProcess_DetectAnomaliesError_ShouldError(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	writer := mock.NewMockMessageWriter(ctrl)
+	reader := mock.NewMockMessageReader(ctrl)
+	sum := mock.NewMockSummarizer(ctrl)
+	repo := mock.NewMockSummaryRepository(ctrl)
+	roller := mock.NewMockRoller(ctrl)
+	detector := mock.NewMockDetector(ctrl)
+	deadLetters := mock.NewMockDeadLetterWriter(ctrl)
+
+	processor, err := service.NewProcessor(writer, reader, sum, repo, roller, detector, deadLetters)
+	require.NoError(t, err)
+	require.NotNil(t, processor)
+
+	messages := []msgbus.Message{
+		{Key: []byte("start_of_stream"), Value: []byte("JFK")},
+		{Key: []byte("end_of_stream"), Value: []byte("2025-05-07")},
+	}
+
+	reader.EXPECT().ReadMessages(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, msgChan chan<- msgbus.Delivery) error {
+			defer close(msgChan)
+			for _, msg := range messages {
+				msgChan <- msgbus.NewDelivery(msg, nil)
+			}
+			return nil
+		},
+	)
+
+	summary := &model.DailyFlightSummary{
+		Date:    model.ToMongoDateTime(time.Date(2025, 5, 7, 0, 0, 0, 0, time.UTC)),
+		Airport: "JFK",
+	}
+
+	sum.EXPECT().SummarizeFlights(gomock.Any(), "2025-05-07", "JFK").Return(summary, nil)
+	repo.EXPECT().ListByDateRange(gomock.Any(), "JFK", gomock.Any(), gomock.Any()).Return(nil, nil)
+	detector.EXPECT().DetectAnomalies(gomock.Any(), gomock.Any()).Return(nil, errors.New("test error"))
+
+	err = processor.Process(ctx)
+	require.ErrorContains(t, err, "failed to detect anomalies")
+}
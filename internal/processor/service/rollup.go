@@ -0,0 +1,187 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/ansoncht/flight-microservices/internal/processor/config"
+	"github.com/ansoncht/flight-microservices/pkg/model"
+	"github.com/ansoncht/flight-microservices/pkg/ranking"
+	repo "github.com/ansoncht/flight-microservices/pkg/repository"
+)
+
+// daysPerWeek specifies the number of days in a Monday to Sunday week.
+const daysPerWeek = 7
+
+// Roller defines the interface for rolling daily flight summaries up into longer periods.
+type Roller interface {
+	// RollupWeek aggregates the week ending on the given Sunday and stores the weekly summary.
+	// It returns an empty ID when the week has no daily summaries to roll up.
+	RollupWeek(ctx context.Context, airport string, end time.Time) (string, error)
+	// RollupMonth aggregates the month ending on the given day and stores the monthly summary.
+	// It returns an empty ID when the month has no daily summaries to roll up.
+	RollupMonth(ctx context.Context, airport string, end time.Time) (string, error)
+}
+
+// FlightRollup implements the Roller interface.
+type FlightRollup struct {
+	// rankOpts specifies how the top airlines and destinations are ranked.
+	rankOpts ranking.Options
+	// summaries specifies the repository to read daily summaries from.
+	summaries repo.SummaryRepository
+	// rollups specifies the repository to store weekly and monthly summaries in.
+	rollups repo.RollupRepository
+}
+
+// NewRollup creates a new FlightRollup instance based on the provided configuration and repositories.
+func NewRollup(
+	cfg config.SummarizerConfig,
+	summaries repo.SummaryRepository,
+	rollups repo.RollupRepository,
+) (*FlightRollup, error) {
+	if cfg.TopN <= 0 {
+		return nil, fmt.Errorf("topN is invalid: %d", cfg.TopN)
+	}
+
+	if err := rankingOptions(cfg).Validate(); err != nil {
+		return nil, fmt.Errorf("failed to validate ranking options: %w", err)
+	}
+
+	if summaries == nil {
+		return nil, fmt.Errorf("summary repository is nil")
+	}
+
+	if rollups == nil {
+		return nil, fmt.Errorf("rollup repository is nil")
+	}
+
+	return &FlightRollup{
+		rankOpts:  rankingOptions(cfg),
+		summaries: summaries,
+		rollups:   rollups,
+	}, nil
+}
+
+// RollupWeek aggregates the daily summaries from Monday to the given Sunday and stores the weekly summary,
+// replacing the one stored for the same week when the day is processed again.
+func (f *FlightRollup) RollupWeek(ctx context.Context, airport string, end time.Time) (string, error) {
+	start := end.AddDate(0, 0, -(daysPerWeek - 1))
+
+	rollup, err := f.aggregate(ctx, airport, start, end)
+	if err != nil || rollup == nil {
+		return "", err
+	}
+
+	id, err := f.rollups.UpsertWeekly(ctx, model.WeeklyFlightSummary{FlightSummaryRollup: *rollup})
+	if err != nil {
+		return "", fmt.Errorf("failed to upsert weekly summary: %w", err)
+	}
+
+	return id, nil
+}
+
+// RollupMonth aggregates the daily summaries from the first of the month to the given day and stores
+// the monthly summary, replacing the one stored for the same month when the day is processed again.
+func (f *FlightRollup) RollupMonth(ctx context.Context, airport string, end time.Time) (string, error) {
+	start := time.Date(end.Year(), end.Month(), 1, 0, 0, 0, 0, end.Location())
+
+	rollup, err := f.aggregate(ctx, airport, start, end)
+	if err != nil || rollup == nil {
+		return "", err
+	}
+
+	id, err := f.rollups.UpsertMonthly(ctx, model.MonthlyFlightSummary{FlightSummaryRollup: *rollup})
+	if err != nil {
+		return "", fmt.Errorf("failed to upsert monthly summary: %w", err)
+	}
+
+	return id, nil
+}
+
+// aggregate lists the daily summaries of an airport within [start, end] and merges them into a rollup.
+// It returns no rollup when there are no daily summaries, as an empty period has nothing to recap.
+func (f *FlightRollup) aggregate(
+	ctx context.Context,
+	airport string,
+	start time.Time,
+	end time.Time,
+) (*model.FlightSummaryRollup, error) {
+	summaries, err := f.summaries.ListByDateRange(ctx, airport, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list daily summaries: %w", err)
+	}
+
+	if len(summaries) == 0 {
+		slog.Warn(
+			"No daily summaries to roll up",
+			"airport", airport,
+			"start", start.Format(format),
+			"end", end.Format(format),
+		)
+		return nil, nil
+	}
+
+	rollup := mergeSummaries(latestSummaryPerDay(summaries), f.rankOpts)
+	rollup.Airport = airport
+	rollup.StartDate = model.ToMongoDateTime(start)
+	rollup.EndDate = model.ToMongoDateTime(end)
+
+	return &rollup, nil
+}
+
+// latestSummaryPerDay keeps only the last summary of each date from summaries ordered by date,
+// so that a day which was processed more than once is counted once.
+func latestSummaryPerDay(summaries []model.DailyFlightSummary) []model.DailyFlightSummary {
+	days := make([]model.DailyFlightSummary, 0, len(summaries))
+
+	for _, summary := range summaries {
+		if n := len(days); n > 0 && days[n-1].Date == summary.Date {
+			days[n-1] = summary
+			continue
+		}
+
+		days = append(days, summary)
+	}
+
+	return days
+}
+
+// mergeSummaries merges the counts of daily summaries ordered by date and recomputes the top N lists,
+// the daily average and the busiest day. Ties for the busiest day go to the earliest date.
+func mergeSummaries(days []model.DailyFlightSummary, rankOpts ranking.Options) model.FlightSummaryRollup {
+	rollup := model.FlightSummaryRollup{
+		Days:              len(days),
+		AirlineCounts:     make(map[string]int),
+		DestinationCounts: make(map[string]int),
+	}
+
+	for i, day := range days {
+		rollup.TotalFlights += day.TotalFlights
+
+		for airline, count := range day.AirlineCounts {
+			rollup.AirlineCounts[airline] += count
+		}
+
+		for destination, count := range day.DestinationCounts {
+			rollup.DestinationCounts[destination] += count
+		}
+
+		if i == 0 || day.TotalFlights > rollup.BusiestDayFlights {
+			rollup.BusiestDay = day.Date
+			rollup.BusiestDayFlights = day.TotalFlights
+		}
+	}
+
+	if rollup.Days > 0 {
+		rollup.DailyAverage = float64(rollup.TotalFlights) / float64(rollup.Days)
+	}
+
+	rollup.DestinationRanking = ranking.Rank(rollup.DestinationCounts, rankOpts)
+	rollup.TopDestinations = ranking.Keys(rollup.DestinationRanking)
+	rollup.AirlineRanking = ranking.Rank(rollup.AirlineCounts, rankOpts)
+	rollup.TopAirlines = ranking.Keys(rollup.AirlineRanking)
+
+	return rollup
+}
@@ -0,0 +1,236 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ansoncht/flight-microservices/internal/processor/config"
+	"github.com/ansoncht/flight-microservices/internal/processor/service"
+	"github.com/ansoncht/flight-microservices/internal/test/mock"
+	"github.com/ansoncht/flight-microservices/pkg/model"
+	"github.com/ansoncht/flight-microservices/pkg/repository"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestNewRollup_InvalidDependencies_ShouldError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	tests := []struct {
+		name        string
+		cfg         config.SummarizerConfig
+		summaries   repository.SummaryRepository
+		rollups     repository.RollupRepository
+		expectedErr string
+	}{
+		{
+			name:        "invalid topN",
+			cfg:         config.SummarizerConfig{TopN: 0},
+			summaries:   mock.NewMockSummaryRepository(ctrl),
+			rollups:     mock.NewMockRollupRepository(ctrl),
+			expectedErr: "topN is invalid",
+		},
+		{
+			name:        "nil summary repository",
+			cfg:         config.SummarizerConfig{TopN: 5},
+			summaries:   nil,
+			rollups:     mock.NewMockRollupRepository(ctrl),
+			expectedErr: "summary repository is nil",
+		},
+		{
+			name:        "nil rollup repository",
+			cfg:         config.SummarizerConfig{TopN: 5},
+			summaries:   mock.NewMockSummaryRepository(ctrl),
+			rollups:     nil,
+			expectedErr: "rollup repository is nil",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rollup, err := service.NewRollup(tt.cfg, tt.summaries, tt.rollups)
+			require.ErrorContains(t, err, tt.expectedErr)
+			require.Nil(t, rollup)
+		})
+	}
+}
+
+func TestRollupWeek_ValidSummaries_ShouldAggregate(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	summaries := mock.NewMockSummaryRepository(ctrl)
+	rollups := mock.NewMockRollupRepository(ctrl)
+
+	rollup, err := service.NewRollup(config.SummarizerConfig{TopN: 2}, summaries, rollups)
+	require.NoError(t, err)
+	require.NotNil(t, rollup)
+
+	monday := time.Date(2025, 5, 5, 0, 0, 0, 0, time.UTC)
+	sunday := time.Date(2025, 5, 11, 0, 0, 0, 0, time.UTC)
+	tuesday := monday.AddDate(0, 0, 1)
+
+	days := []model.DailyFlightSummary{
+		{
+			Date:              model.ToMongoDateTime(monday),
+			TotalFlights:      3,
+			AirlineCounts:     map[string]int{"United": 2, "Delta": 1},
+			DestinationCounts: map[string]int{"LAX": 3},
+		},
+		{
+			// Superseded by the reprocessed Tuesday summary below
+			Date:              model.ToMongoDateTime(tuesday),
+			TotalFlights:      100,
+			AirlineCounts:     map[string]int{"United": 100},
+			DestinationCounts: map[string]int{"SFO": 100},
+		},
+		{
+			Date:              model.ToMongoDateTime(tuesday),
+			TotalFlights:      5,
+			AirlineCounts:     map[string]int{"Delta": 4, "JetBlue": 1},
+			DestinationCounts: map[string]int{"SFO": 2, "LAX": 3},
+		},
+		{
+			Date:              model.ToMongoDateTime(sunday),
+			TotalFlights:      4,
+			AirlineCounts:     map[string]int{"United": 4},
+			DestinationCounts: map[string]int{"JFK": 4},
+		},
+	}
+
+	summaries.EXPECT().ListByDateRange(gomock.Any(), "JFK", monday, sunday).Return(days, nil)
+	rollups.EXPECT().UpsertWeekly(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, weekly model.WeeklyFlightSummary) (string, error) {
+			require.Equal(t, "JFK", weekly.Airport)
+			require.Equal(t, model.ToMongoDateTime(monday), weekly.StartDate)
+			require.Equal(t, model.ToMongoDateTime(sunday), weekly.EndDate)
+			require.Equal(t, 3, weekly.Days)
+			require.Equal(t, 12, weekly.TotalFlights)
+			require.InDelta(t, 4.0, weekly.DailyAverage, 0.001)
+			require.Equal(t, model.ToMongoDateTime(tuesday), weekly.BusiestDay)
+			require.Equal(t, 5, weekly.BusiestDayFlights)
+			require.Equal(t, map[string]int{"United": 6, "Delta": 5, "JetBlue": 1}, weekly.AirlineCounts)
+			require.Equal(t, map[string]int{"LAX": 6, "SFO": 2, "JFK": 4}, weekly.DestinationCounts)
+			require.Equal(t, []string{"United", "Delta"}, weekly.TopAirlines)
+			require.Equal(t, []string{"LAX", "JFK"}, weekly.TopDestinations)
+			require.Equal(t, []model.RankedEntry{
+				{Key: "United", Count: 6, Rank: 1, Share: 0.5},
+				{Key: "Delta", Count: 5, Rank: 2, Share: 5.0 / 12},
+			}, weekly.AirlineRanking)
+			require.Contains(t, weekly.FormatForSocialMedia(), "1️⃣ United (6, 50%)\n2️⃣ Delta (5, 42%)\n")
+			return "weekly_id", nil
+		},
+	)
+
+	id, err := rollup.RollupWeek(ctx, "JFK", sunday)
+	require.NoError(t, err)
+	require.Equal(t, "weekly_id", id)
+}
+
+func TestRollupWeek_NoSummaries_ShouldSkip(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	summaries := mock.NewMockSummaryRepository(ctrl)
+	rollups := mock.NewMockRollupRepository(ctrl)
+
+	rollup, err := service.NewRollup(config.SummarizerConfig{TopN: 5}, summaries, rollups)
+	require.NoError(t, err)
+
+	summaries.EXPECT().ListByDateRange(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return([]model.DailyFlightSummary{}, nil)
+
+	id, err := rollup.RollupWeek(ctx, "JFK", time.Date(2025, 5, 11, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.Empty(t, id)
+}
+
+func TestRollupMonth_ValidSummaries_ShouldAggregateFromFirstOfMonth(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	summaries := mock.NewMockSummaryRepository(ctrl)
+	rollups := mock.NewMockRollupRepository(ctrl)
+
+	rollup, err := service.NewRollup(config.SummarizerConfig{TopN: 5}, summaries, rollups)
+	require.NoError(t, err)
+	require.NotNil(t, rollup)
+
+	first := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+	last := time.Date(2025, 2, 28, 0, 0, 0, 0, time.UTC)
+
+	days := []model.DailyFlightSummary{
+		{Date: model.ToMongoDateTime(first), TotalFlights: 2, AirlineCounts: map[string]int{"Delta": 2}},
+		{Date: model.ToMongoDateTime(last), TotalFlights: 2, AirlineCounts: map[string]int{"United": 2}},
+	}
+
+	summaries.EXPECT().ListByDateRange(gomock.Any(), "JFK", first, last).Return(days, nil)
+	rollups.EXPECT().UpsertMonthly(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, monthly model.MonthlyFlightSummary) (string, error) {
+			require.Equal(t, model.ToMongoDateTime(first), monthly.StartDate)
+			require.Equal(t, model.ToMongoDateTime(last), monthly.EndDate)
+			require.Equal(t, 4, monthly.TotalFlights)
+			// Ties for the busiest day go to the earliest date
+			require.Equal(t, model.ToMongoDateTime(first), monthly.BusiestDay)
+			return "monthly_id", nil
+		},
+	)
+
+	id, err := rollup.RollupMonth(ctx, "JFK", last)
+	require.NoError(t, err)
+	require.Equal(t, "monthly_id", id)
+}
+
+func TestRollupWeek_RepositoryErrors_ShouldError(t *testing.T) {
+	ctx := context.Background()
+	sunday := time.Date(2025, 5, 11, 0, 0, 0, 0, time.UTC)
+	day := model.DailyFlightSummary{Date: model.ToMongoDateTime(sunday), TotalFlights: 1}
+
+	tests := []struct {
+		name        string
+		setup       func(summaries *mock.MockSummaryRepository, rollups *mock.MockRollupRepository)
+		expectedErr string
+	}{
+		{
+			name: "list error",
+			setup: func(summaries *mock.MockSummaryRepository, _ *mock.MockRollupRepository) {
+				summaries.EXPECT().ListByDateRange(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, errors.New("test error"))
+			},
+			expectedErr: "failed to list daily summaries",
+		},
+		{
+			name: "upsert error",
+			setup: func(summaries *mock.MockSummaryRepository, rollups *mock.MockRollupRepository) {
+				summaries.EXPECT().ListByDateRange(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return([]model.DailyFlightSummary{day}, nil)
+				rollups.EXPECT().UpsertWeekly(gomock.Any(), gomock.Any()).Return("", errors.New("test error"))
+			},
+			expectedErr: "failed to upsert weekly summary",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			summaries := mock.NewMockSummaryRepository(ctrl)
+			rollups := mock.NewMockRollupRepository(ctrl)
+			tt.setup(summaries, rollups)
+
+			rollup, err := service.NewRollup(config.SummarizerConfig{TopN: 5}, summaries, rollups)
+			require.NoError(t, err)
+
+			id, err := rollup.RollupWeek(ctx, "JFK", sunday)
+			require.ErrorContains(t, err, tt.expectedErr)
+			require.Empty(t, id)
+		})
+	}
+}
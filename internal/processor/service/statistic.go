@@ -0,0 +1,458 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/ansoncht/flight-microservices/internal/processor/config"
+	"github.com/ansoncht/flight-microservices/pkg/geo"
+	msg "github.com/ansoncht/flight-microservices/pkg/model"
+	"github.com/ansoncht/flight-microservices/pkg/ranking"
+)
+
+// Built-in statistic names.
+const (
+	StatisticAirlines     = "airlines"
+	StatisticDestinations = "destinations"
+	StatisticHourly       = "hourly"
+	StatisticCountries    = "countries"
+	StatisticRoutes       = "routes"
+	StatisticDurations    = "durations"
+)
+
+// hoursPerDay specifies the number of buckets in the hourly departure histogram.
+const hoursPerDay = 24
+
+// defaultStatistics specifies the statistics computed when none are configured.
+var defaultStatistics = []string{
+	StatisticAirlines,
+	StatisticDestinations,
+	StatisticHourly,
+	StatisticCountries,
+	StatisticRoutes,
+}
+
+// Statistic defines the interface for a plugin contributing a named section to a daily flight summary.
+type Statistic interface {
+	// Name returns the name the statistic is enabled by in the summarizer config.
+	Name() string
+	// Compute consumes the flight records of a day and contributes its section to the summary.
+	Compute(records []msg.FlightRecord, summary *msg.DailyFlightSummary)
+}
+
+// statisticFactories maps each statistic name to the constructor of its plugin.
+var statisticFactories = map[string]func(cfg config.SummarizerConfig) (Statistic, error){
+	StatisticAirlines:     newAirlineStatistic,
+	StatisticDestinations: newDestinationStatistic,
+	StatisticHourly:       newHourlyStatistic,
+	StatisticCountries:    newCountryStatistic,
+	StatisticRoutes:       newRouteStatistic,
+	StatisticDurations:    newDurationStatistic,
+}
+
+// newStatistics creates the statistics enabled in the summarizer config, in the configured order.
+func newStatistics(cfg config.SummarizerConfig) ([]Statistic, error) {
+	names := cfg.Statistics
+	if len(names) == 0 {
+		names = defaultStatistics
+	}
+
+	statistics := make([]Statistic, 0, len(names))
+	seen := make(map[string]bool, len(names))
+
+	for _, name := range names {
+		factory, ok := statisticFactories[name]
+		if !ok {
+			return nil, fmt.Errorf("statistic is unknown: %s", name)
+		}
+
+		if seen[name] {
+			return nil, fmt.Errorf("statistic is duplicated: %s", name)
+		}
+		seen[name] = true
+
+		statistic, err := factory(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create statistic %s: %w", name, err)
+		}
+
+		statistics = append(statistics, statistic)
+	}
+
+	return statistics, nil
+}
+
+// airlineStatistic counts the flights of each airline and ranks the top airlines.
+type airlineStatistic struct {
+	// rankOpts specifies how the top airlines are ranked.
+	rankOpts ranking.Options
+}
+
+func newAirlineStatistic(cfg config.SummarizerConfig) (Statistic, error) {
+	return &airlineStatistic{rankOpts: rankingOptions(cfg)}, nil
+}
+
+// Name returns the name of the airline statistic.
+func (s *airlineStatistic) Name() string {
+	return StatisticAirlines
+}
+
+// Compute sets the airline counts, ranking and top airlines of the summary.
+func (s *airlineStatistic) Compute(records []msg.FlightRecord, summary *msg.DailyFlightSummary) {
+	summary.AirlineCounts = make(map[string]int)
+	for _, flight := range records {
+		summary.AirlineCounts[flight.Airline]++
+	}
+
+	summary.AirlineRanking = ranking.Rank(summary.AirlineCounts, s.rankOpts)
+	summary.TopAirlines = ranking.Keys(summary.AirlineRanking)
+}
+
+// destinationStatistic counts the flights to each destination and ranks the top destinations.
+type destinationStatistic struct {
+	// rankOpts specifies how the top destinations are ranked.
+	rankOpts ranking.Options
+}
+
+func newDestinationStatistic(cfg config.SummarizerConfig) (Statistic, error) {
+	return &destinationStatistic{rankOpts: rankingOptions(cfg)}, nil
+}
+
+// Name returns the name of the destination statistic.
+func (s *destinationStatistic) Name() string {
+	return StatisticDestinations
+}
+
+// Compute sets the destination counts, ranking and top destinations of the summary.
+func (s *destinationStatistic) Compute(records []msg.FlightRecord, summary *msg.DailyFlightSummary) {
+	summary.DestinationCounts = make(map[string]int)
+	for _, flight := range records {
+		summary.DestinationCounts[flight.Destination]++
+	}
+
+	summary.DestinationRanking = ranking.Rank(summary.DestinationCounts, s.rankOpts)
+	summary.TopDestinations = ranking.Keys(summary.DestinationRanking)
+}
+
+// hourlyStatistic buckets departures by airport-local hour and finds the busiest and quietest hours.
+type hourlyStatistic struct {
+	// locations specifies the time zone of each upper-cased airport code.
+	locations map[string]*time.Location
+}
+
+func newHourlyStatistic(cfg config.SummarizerConfig) (Statistic, error) {
+	locations := make(map[string]*time.Location, len(cfg.Timezones))
+	for airport, timezone := range cfg.Timezones {
+		loc, err := time.LoadLocation(timezone)
+		if err != nil {
+			return nil, fmt.Errorf("timezone of airport %s is invalid: %w", airport, err)
+		}
+
+		// Config keys are case-insensitive, so airports are matched upper-cased
+		locations[strings.ToUpper(airport)] = loc
+	}
+
+	return &hourlyStatistic{locations: locations}, nil
+}
+
+// Name returns the name of the hourly statistic.
+func (s *hourlyStatistic) Name() string {
+	return StatisticHourly
+}
+
+// Compute sets the hourly departure histogram and the busiest and quietest hours of the summary.
+func (s *hourlyStatistic) Compute(records []msg.FlightRecord, summary *msg.DailyFlightSummary) {
+	hourly := hourlyHistogram(records, s.location(summary.Airport))
+	busiest, quietest := peakHours(hourly)
+
+	summary.HourlyDepartures = hourly
+	summary.BusiestHour = busiest
+	summary.BusiestHourFlights = hourly[busiest]
+	summary.QuietestHour = quietest
+	summary.QuietestHourFlights = hourly[quietest]
+}
+
+// location returns the time zone of an airport, falling back to UTC for airports without one configured.
+func (s *hourlyStatistic) location(airport string) *time.Location {
+	if loc, ok := s.locations[strings.ToUpper(airport)]; ok {
+		return loc
+	}
+
+	return time.UTC
+}
+
+// hourlyHistogram counts the departures of each local hour of the day from the first seen timestamps.
+// Records without a first seen timestamp are not counted.
+func hourlyHistogram(records []msg.FlightRecord, loc *time.Location) []int {
+	hourly := make([]int, hoursPerDay)
+
+	for _, flight := range records {
+		if flight.FirstSeen <= 0 {
+			continue
+		}
+
+		hourly[time.Unix(int64(flight.FirstSeen), 0).In(loc).Hour()]++
+	}
+
+	return hourly
+}
+
+// peakHours returns the hours with the most and the fewest departures. Ties go to the earliest hour.
+func peakHours(hourly []int) (int, int) {
+	busiest, quietest := 0, 0
+
+	for hour, count := range hourly {
+		if count > hourly[busiest] {
+			busiest = hour
+		}
+
+		if count < hourly[quietest] {
+			quietest = hour
+		}
+	}
+
+	return busiest, quietest
+}
+
+// Stat section names contributed by the country statistic besides its own.
+const (
+	statSectionTopCountries = "top_countries"
+	statSectionRegions      = "regions"
+)
+
+// countryStatistic counts the flights to each destination country and continent
+// and splits domestic from international flights.
+type countryStatistic struct{}
+
+func newCountryStatistic(_ config.SummarizerConfig) (Statistic, error) {
+	return &countryStatistic{}, nil
+}
+
+// Name returns the name of the country statistic.
+func (s *countryStatistic) Name() string {
+	return StatisticCountries
+}
+
+// Compute adds the number of destination countries with the domestic and international flights, the flights to
+// each country and the flights to each continent to the summary stats. Flights without a destination country are
+// not counted, flights without an origin country are neither domestic nor international, and nothing is added
+// when no flight has a destination country.
+func (s *countryStatistic) Compute(records []msg.FlightRecord, summary *msg.DailyFlightSummary) {
+	countries := make(map[string]int)
+	regions := make(map[string]int)
+	domestic, international := 0, 0
+
+	for _, flight := range records {
+		destination := strings.ToUpper(strings.TrimSpace(flight.DestinationCountry))
+		if destination == "" {
+			continue
+		}
+
+		countries[destination]++
+		regions[geo.Region(destination)]++
+
+		origin := strings.ToUpper(strings.TrimSpace(flight.OriginCountry))
+		switch {
+		case origin == "":
+			// Unknown origin country, so the flight cannot be classified
+		case origin == destination:
+			domestic++
+		default:
+			international++
+		}
+	}
+
+	if len(countries) == 0 {
+		return
+	}
+
+	summary.Stats = append(summary.Stats,
+		msg.StatSection{
+			Name:  StatisticCountries,
+			Title: "Destination Countries",
+			Values: []msg.StatValue{
+				{Label: "Countries", Value: float64(len(countries))},
+				{Label: "Domestic", Value: float64(domestic), Unit: "flights"},
+				{Label: "International", Value: float64(international), Unit: "flights"},
+			},
+		},
+		msg.StatSection{
+			Name:   statSectionTopCountries,
+			Title:  "Top Countries",
+			Values: countValues(countries, geo.CountryName),
+		},
+		msg.StatSection{
+			Name:   statSectionRegions,
+			Title:  "Regions",
+			Values: countValues(regions, func(region string) string { return region }),
+		},
+	)
+}
+
+// countValues returns every count as a stat value keyed by its key and labelled by the label function,
+// ordered by descending count.
+func countValues(counts map[string]int, label func(key string) string) []msg.StatValue {
+	entries := ranking.Rank(counts, ranking.Options{})
+
+	values := make([]msg.StatValue, 0, len(entries))
+	for _, entry := range entries {
+		values = append(values, msg.StatValue{Key: entry.Key, Label: label(entry.Key), Value: float64(entry.Count)})
+	}
+
+	return values
+}
+
+// Stat section names contributed by the route statistic besides the routes section.
+const (
+	statSectionAirlineRoutes       = "airline_routes"
+	statSectionMonopolyRoutes      = "monopoly_routes"
+	statSectionDestinationAirlines = "destination_airlines"
+)
+
+// routeStatistic counts the flights on each route and of each airline on each route,
+// then finds monopoly routes and competition per destination.
+type routeStatistic struct{}
+
+func newRouteStatistic(_ config.SummarizerConfig) (Statistic, error) {
+	return &routeStatistic{}, nil
+}
+
+// Name returns the name of the route statistic.
+func (s *routeStatistic) Name() string {
+	return StatisticRoutes
+}
+
+// airlineRoute identifies the flights of an airline on a route.
+type airlineRoute struct {
+	airline     string
+	origin      string
+	destination string
+}
+
+// Compute adds the flights on each route, the flights of each airline on each route, the flights on routes operated
+// by a single airline and the airlines competing for each destination to the summary stats. The routes section is
+// keyed by msg.RouteKey so that summaries can be listed by route. Flights without an origin or destination are not
+// counted, and nothing is added when no flight has both.
+func (s *routeStatistic) Compute(records []msg.FlightRecord, summary *msg.DailyFlightSummary) {
+	routes := make(map[string]int)
+	airlineRoutes := make(map[airlineRoute]int)
+
+	for _, flight := range records {
+		if flight.Origin == "" || flight.Destination == "" {
+			continue
+		}
+
+		routes[msg.RouteKey(flight.Origin, flight.Destination)]++
+		airlineRoutes[airlineRoute{
+			airline:     flight.Airline,
+			origin:      flight.Origin,
+			destination: flight.Destination,
+		}]++
+	}
+
+	if len(routes) == 0 {
+		return
+	}
+
+	routeAirlines := make(map[string]int, len(routes))
+	destinationAirlines := make(map[string]int)
+	airlineRouteCounts := make(map[string]int, len(airlineRoutes))
+	airlineRouteLabels := make(map[string]string, len(airlineRoutes))
+
+	for route, flights := range airlineRoutes {
+		key := msg.RouteKey(route.origin, route.destination)
+		routeAirlines[key]++
+		destinationAirlines[route.destination]++
+
+		airlineKey := route.airline + "|" + key
+		airlineRouteCounts[airlineKey] = flights
+		airlineRouteLabels[airlineKey] = route.airline + " " + routeLabel(key)
+	}
+
+	monopolies := make(map[string]int)
+	for route, airlines := range routeAirlines {
+		if airlines == 1 {
+			monopolies[route] = routes[route]
+		}
+	}
+
+	competition := countValues(destinationAirlines, func(destination string) string { return destination })
+	for i := range competition {
+		competition[i].Unit = "airlines"
+	}
+
+	summary.Stats = append(summary.Stats,
+		msg.StatSection{
+			Name:   msg.RouteStatName,
+			Title:  "Top Routes",
+			Values: countValues(routes, routeLabel),
+		},
+		msg.StatSection{
+			Name:  statSectionAirlineRoutes,
+			Title: "Top Airline Routes",
+			Values: countValues(airlineRouteCounts, func(key string) string {
+				return airlineRouteLabels[key]
+			}),
+		},
+		msg.StatSection{
+			Name:   statSectionMonopolyRoutes,
+			Title:  "Monopoly Routes",
+			Values: countValues(monopolies, routeLabel),
+		},
+		msg.StatSection{
+			Name:   statSectionDestinationAirlines,
+			Title:  "Airlines Per Destination",
+			Values: competition,
+		},
+	)
+}
+
+// routeLabel returns the display label of a route key, e.g. "HKG → NRT".
+func routeLabel(key string) string {
+	return strings.ReplaceAll(key, "-", " → ")
+}
+
+// durationStatistic measures how long flights were tracked between first and last seen.
+type durationStatistic struct{}
+
+func newDurationStatistic(_ config.SummarizerConfig) (Statistic, error) {
+	return &durationStatistic{}, nil
+}
+
+// Name returns the name of the duration statistic.
+func (s *durationStatistic) Name() string {
+	return StatisticDurations
+}
+
+// Compute adds the average and longest tracked flight durations to the summary stats.
+// Records without both timestamps are skipped, and nothing is added when none has them.
+func (s *durationStatistic) Compute(records []msg.FlightRecord, summary *msg.DailyFlightSummary) {
+	var total, longest float64
+	measured := 0
+
+	for _, flight := range records {
+		if flight.FirstSeen <= 0 || flight.LastSeen < flight.FirstSeen {
+			continue
+		}
+
+		minutes := (time.Duration(flight.LastSeen-flight.FirstSeen) * time.Second).Minutes()
+		total += minutes
+		longest = math.Max(longest, minutes)
+		measured++
+	}
+
+	if measured == 0 {
+		return
+	}
+
+	summary.Stats = append(summary.Stats, msg.StatSection{
+		Name:  StatisticDurations,
+		Title: "Flight Durations",
+		Values: []msg.StatValue{
+			{Label: "Average", Value: math.Round(total / float64(measured)), Unit: "min"},
+			{Label: "Longest", Value: math.Round(longest), Unit: "min"},
+		},
+	})
+}
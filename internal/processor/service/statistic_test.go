@@ -0,0 +1,241 @@
+package service_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ansoncht/flight-microservices/internal/processor/config"
+	"github.com/ansoncht/flight-microservices/internal/processor/service"
+	msg "github.com/ansoncht/flight-microservices/pkg/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSummarizer_InvalidStatistics_ShouldError(t *testing.T) {
+	testCases := []struct {
+		name        string
+		statistics  []string
+		expectedErr string
+	}{
+		{
+			name:        "Unknown Statistic",
+			statistics:  []string{"airlines", "weather"},
+			expectedErr: "statistic is unknown: weather",
+		},
+		{
+			name:        "Duplicated Statistic",
+			statistics:  []string{"airlines", "airlines"},
+			expectedErr: "statistic is duplicated: airlines",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			summarizer, err := service.NewSummarizer(config.SummarizerConfig{TopN: 5, Statistics: tc.statistics})
+			require.ErrorContains(t, err, tc.expectedErr)
+			require.Nil(t, summarizer)
+		})
+	}
+}
+
+func TestSummarizeFlights_EnabledStatistics_ShouldOnlyComputeEnabled(t *testing.T) {
+	summarizer, err := service.NewSummarizer(config.SummarizerConfig{
+		TopN:       5,
+		Statistics: []string{service.StatisticDestinations, service.StatisticDurations},
+	})
+	require.NoError(t, err)
+	require.NotNil(t, summarizer)
+
+	departure := time.Date(2025, 5, 7, 8, 0, 0, 0, time.UTC)
+	flights := []msg.FlightRecord{
+		{
+			Airline:     "Cathay Pacific",
+			Destination: "NRT",
+			FirstSeen:   int(departure.Unix()),
+			LastSeen:    int(departure.Add(4 * time.Hour).Unix()),
+		},
+		{
+			Airline:     "HK Express",
+			Destination: "TPE",
+			FirstSeen:   int(departure.Unix()),
+			LastSeen:    int(departure.Add(90 * time.Minute).Unix()),
+		},
+		// Records without timestamps are not measured
+		{Airline: "HK Express", Destination: "TPE"},
+	}
+
+	summary, err := summarizer.SummarizeFlights(flights, "2025-05-07", "VHHH")
+	require.NoError(t, err)
+	require.NotNil(t, summary)
+	require.Equal(t, 3, summary.TotalFlights)
+	require.Equal(t, map[string]int{"NRT": 1, "TPE": 2}, summary.DestinationCounts)
+	require.Nil(t, summary.AirlineCounts)
+	require.Nil(t, summary.HourlyDepartures)
+	require.Equal(t, []msg.StatSection{
+		{
+			Name:  service.StatisticDurations,
+			Title: "Flight Durations",
+			Values: []msg.StatValue{
+				{Label: "Average", Value: 165, Unit: "min"},
+				{Label: "Longest", Value: 240, Unit: "min"},
+			},
+		},
+	}, summary.Stats)
+	require.Contains(t, summary.FormatForSocialMedia(), "📊 **Flight Durations**: Average 165 min, Longest 240 min\n")
+}
+
+func TestSummarizeFlights_NoMeasurableDurations_ShouldOmitSection(t *testing.T) {
+	summarizer, err := service.NewSummarizer(config.SummarizerConfig{
+		TopN:       5,
+		Statistics: []string{service.StatisticDurations},
+	})
+	require.NoError(t, err)
+
+	summary, err := summarizer.SummarizeFlights([]msg.FlightRecord{{Airline: "United"}}, "2025-05-07", "SFO")
+	require.NoError(t, err)
+	require.Empty(t, summary.Stats)
+	require.NotContains(t, summary.FormatForSocialMedia(), "📊")
+}
+
+func TestSummarizeFlights_Countries_ShouldBreakDownDestinations(t *testing.T) {
+	summarizer, err := service.NewSummarizer(config.SummarizerConfig{
+		TopN:       2,
+		Statistics: []string{service.StatisticCountries},
+	})
+	require.NoError(t, err)
+
+	flights := []msg.FlightRecord{
+		{Destination: "NRT", OriginCountry: "HK", DestinationCountry: "JP"},
+		{Destination: "KIX", OriginCountry: "HK", DestinationCountry: "jp"},
+		{Destination: "TPE", OriginCountry: "HK", DestinationCountry: "TW"},
+		{Destination: "LHR", OriginCountry: "HK", DestinationCountry: "GB"},
+		{Destination: "HKG", OriginCountry: "HK", DestinationCountry: "HK"},
+		{Destination: "XXX", OriginCountry: "", DestinationCountry: "ZZ"},
+		{Destination: "YYY", OriginCountry: "HK", DestinationCountry: ""},
+	}
+
+	summary, err := summarizer.SummarizeFlights(flights, "2025-05-07", "VHHH")
+	require.NoError(t, err)
+	require.Equal(t, []msg.StatValue{
+		{Label: "Countries", Value: 5},
+		{Label: "Domestic", Value: 1, Unit: "flights"},
+		{Label: "International", Value: 4, Unit: "flights"},
+	}, summary.Stat(service.StatisticCountries).Values)
+
+	countries := summary.Stat("top_countries").Values
+	require.Len(t, countries, 5)
+	require.Equal(t, msg.StatValue{Key: "JP", Label: "Japan", Value: 2}, countries[0])
+
+	require.Equal(t, []msg.StatValue{
+		{Key: "Asia", Label: "Asia", Value: 4},
+		{Key: "Europe", Label: "Europe", Value: 1},
+		{Key: "Unknown", Label: "Unknown", Value: 1},
+	}, summary.Stat("regions").Values)
+
+	content := summary.FormatForSocialMedia()
+	require.Contains(
+		t,
+		content,
+		"📊 **Destination Countries**: Countries 5, Domestic 1 flights, International 4 flights\n",
+	)
+	require.Contains(t, content, "📊 **Top Countries**: Japan 2, ")
+}
+
+func TestSummarizeFlights_NoDestinationCountries_ShouldOmitSections(t *testing.T) {
+	summarizer, err := service.NewSummarizer(config.SummarizerConfig{
+		TopN:       5,
+		Statistics: []string{service.StatisticCountries},
+	})
+	require.NoError(t, err)
+
+	summary, err := summarizer.SummarizeFlights([]msg.FlightRecord{{Destination: "NRT"}}, "2025-05-07", "VHHH")
+	require.NoError(t, err)
+	require.Empty(t, summary.Stats)
+}
+
+func TestSummarizeFlights_Routes_ShouldAggregateRoutePairs(t *testing.T) {
+	summarizer, err := service.NewSummarizer(config.SummarizerConfig{
+		TopN:       1,
+		Statistics: []string{service.StatisticRoutes},
+	})
+	require.NoError(t, err)
+
+	flights := []msg.FlightRecord{
+		{Airline: "Cathay Pacific", Origin: "HKG", Destination: "NRT"},
+		{Airline: "Cathay Pacific", Origin: "HKG", Destination: "NRT"},
+		{Airline: "HK Express", Origin: "HKG", Destination: "NRT"},
+		{Airline: "HK Express", Origin: "HKG", Destination: "TPE"},
+		{Airline: "Cathay Pacific", Origin: "HKG", Destination: "LHR"},
+		{Airline: "United", Origin: "", Destination: "SFO"},
+	}
+
+	summary, err := summarizer.SummarizeFlights(flights, "2025-05-07", "VHHH")
+	require.NoError(t, err)
+	require.Equal(t, []msg.StatValue{
+		{Key: "HKG-NRT", Label: "HKG → NRT", Value: 3},
+		{Key: "HKG-LHR", Label: "HKG → LHR", Value: 1},
+		{Key: "HKG-TPE", Label: "HKG → TPE", Value: 1},
+	}, summary.Stat(msg.RouteStatName).Values)
+	require.Equal(t, []msg.StatValue{
+		{Key: "Cathay Pacific|HKG-NRT", Label: "Cathay Pacific HKG → NRT", Value: 2},
+		{Key: "Cathay Pacific|HKG-LHR", Label: "Cathay Pacific HKG → LHR", Value: 1},
+		{Key: "HK Express|HKG-NRT", Label: "HK Express HKG → NRT", Value: 1},
+		{Key: "HK Express|HKG-TPE", Label: "HK Express HKG → TPE", Value: 1},
+	}, summary.Stat("airline_routes").Values)
+	require.Equal(t, []msg.StatValue{
+		{Key: "HKG-LHR", Label: "HKG → LHR", Value: 1},
+		{Key: "HKG-TPE", Label: "HKG → TPE", Value: 1},
+	}, summary.Stat("monopoly_routes").Values)
+	require.Equal(t, []msg.StatValue{
+		{Key: "NRT", Label: "NRT", Value: 2, Unit: "airlines"},
+		{Key: "LHR", Label: "LHR", Value: 1, Unit: "airlines"},
+		{Key: "TPE", Label: "TPE", Value: 1, Unit: "airlines"},
+	}, summary.Stat("destination_airlines").Values)
+
+	content := summary.FormatForSocialMedia()
+	require.Contains(t, content, "📊 **Top Routes**: HKG → NRT 3, HKG → LHR 1, HKG → TPE 1\n")
+	require.Contains(t, content, "📊 **Monopoly Routes**: HKG → LHR 1, HKG → TPE 1\n")
+}
+
+func TestSummarizeFlights_NoRoutes_ShouldOmitSections(t *testing.T) {
+	summarizer, err := service.NewSummarizer(config.SummarizerConfig{
+		TopN:       5,
+		Statistics: []string{service.StatisticRoutes},
+	})
+	require.NoError(t, err)
+
+	summary, err := summarizer.SummarizeFlights([]msg.FlightRecord{{Destination: "NRT"}}, "2025-05-07", "VHHH")
+	require.NoError(t, err)
+	require.Empty(t, summary.Stats)
+}
+
+func TestSummarizeFlights_Ranking_ShouldBreakTiesAndAggregateOthers(t *testing.T) {
+	summarizer, err := service.NewSummarizer(config.SummarizerConfig{
+		TopN:          2,
+		RankingMethod: "dense",
+		RankOthers:    true,
+		Statistics:    []string{service.StatisticAirlines},
+	})
+	require.NoError(t, err)
+
+	flights := []msg.FlightRecord{
+		{Airline: "United"}, {Airline: "United"}, {Airline: "United"},
+		{Airline: "Delta"}, {Airline: "American"}, {Airline: "JetBlue"},
+	}
+
+	summary, err := summarizer.SummarizeFlights(flights, "2025-05-07", "SFO")
+	require.NoError(t, err)
+	require.Equal(t, []string{"United", "American"}, summary.TopAirlines)
+	require.Equal(t, []msg.RankedEntry{
+		{Key: "United", Count: 3, Rank: 1, Share: 0.5},
+		{Key: "American", Count: 1, Rank: 2, Share: 1.0 / 6},
+		{Key: "Others", Count: 2, Share: 2.0 / 6},
+	}, summary.AirlineRanking)
+	require.Contains(t, summary.FormatForSocialMedia(),
+		"1️⃣ United (3, 50%)\n2️⃣ American (1, 17%)\n➕ Others (2, 33%)\n")
+}
+
+func TestNewSummarizer_InvalidRankingMethod_ShouldError(t *testing.T) {
+	summarizer, err := service.NewSummarizer(config.SummarizerConfig{TopN: 5, RankingMethod: "olympic"})
+	require.ErrorContains(t, err, "ranking method is invalid")
+	require.Nil(t, summarizer)
+}
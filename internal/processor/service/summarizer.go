@@ -6,6 +6,7 @@ import (
 
 	"github.com/ansoncht/flight-microservices/internal/processor/config"
 	msg "github.com/ansoncht/flight-microservices/pkg/model"
+	"github.com/ansoncht/flight-microservices/pkg/ranking"
 )
 
 const (
@@ -20,18 +21,35 @@ type Summarizer interface {
 
 // FlightSummarizer implements the Summarizer interface.
 type FlightSummarizer struct {
-	// topN specifies the number of top airlines and destinations to return.
-	topN int
+	// statistics specifies the statistics contributing sections to each summary, in order.
+	statistics []Statistic
+	// dedup specifies the deduplicator removing duplicate records before summarizing, or nil if disabled.
+	dedup *deduplicator
 }
 
-// NewSummarizer creates a new Summarizer instance.
+// NewSummarizer creates a new Summarizer instance with the statistics enabled in the configuration.
 func NewSummarizer(cfg config.SummarizerConfig) (*FlightSummarizer, error) {
 	if cfg.TopN <= 0 {
 		return nil, fmt.Errorf("topN is invalid: %d", cfg.TopN)
 	}
 
+	if err := rankingOptions(cfg).Validate(); err != nil {
+		return nil, fmt.Errorf("failed to validate ranking options: %w", err)
+	}
+
+	statistics, err := newStatistics(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	dedup, err := newDeduplicator(cfg.Dedup)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create deduplicator: %w", err)
+	}
+
 	return &FlightSummarizer{
-		topN: cfg.TopN,
+		statistics: statistics,
+		dedup:      dedup,
 	}, nil
 }
 
@@ -45,57 +63,32 @@ func (f *FlightSummarizer) SummarizeFlights(
 		return nil, fmt.Errorf("failed to parse date for transaction: %w", err)
 	}
 
-	airlineCounts := make(map[string]int)
-	destCounts := make(map[string]int)
-	totalFlights := 0
-
-	for _, flight := range records {
-		airlineCounts[flight.Airline]++
-		destCounts[flight.Destination]++
-		totalFlights++
+	duplicates := 0
+	if f.dedup != nil {
+		records, duplicates = f.dedup.deduplicate(records)
 	}
 
-	// Get top n destinations and airlines
-	topDestinations := topNKeysByValue(destCounts, f.topN)
-	topAirlines := topNKeysByValue(airlineCounts, f.topN)
-
-	return &msg.DailyFlightSummary{
+	summary := &msg.DailyFlightSummary{
 		Date:              msg.ToMongoDateTime(dt),
 		Airport:           airport,
-		TotalFlights:      totalFlights,
-		AirlineCounts:     airlineCounts,
-		DestinationCounts: destCounts,
-		TopDestinations:   topDestinations,
-		TopAirlines:       topAirlines,
-	}, nil
-}
-
-// topNKeysByValue returns the top N keys from a map[string]int by descending value.
-func topNKeysByValue(m map[string]int, n int) []string {
-	// get the maximum frequency in the map.
-	var maxFreq int
-	for _, val := range m {
-		if val > maxFreq {
-			maxFreq = val
-		}
+		TotalFlights:      len(records),
+		DuplicatesRemoved: duplicates,
 	}
 
-	buckets := make([][]string, maxFreq+1)
-	for key, val := range m {
-		buckets[val] = append(buckets[val], key)
+	for _, statistic := range f.statistics {
+		statistic.Compute(records, summary)
 	}
 
-	result := make([]string, 0, n)
-	for i := maxFreq; i >= 0; i-- {
-		for _, value := range buckets[i] {
-			result = append(result, value)
-			if len(result) == n {
-				return result
-			}
-		}
-	}
+	return summary, nil
+}
 
-	return result
+// rankingOptions returns the options ranking the top N lists as set by the summarizer config.
+func rankingOptions(cfg config.SummarizerConfig) ranking.Options {
+	return ranking.Options{
+		Method: cfg.RankingMethod,
+		Limit:  cfg.TopN,
+		Others: cfg.RankOthers,
+	}
 }
 
 // parseDate parses the date string into a time.Time object.
@@ -1,6 +1,7 @@
 package service_test
 
 import (
+	"fmt"
 	"testing"
 	"time"
 
@@ -30,6 +31,17 @@ func TestNewSummarizer_InValidConfig_ShouldError(t *testing.T) {
 	require.Nil(t, summarizer)
 }
 
+func TestNewSummarizer_InvalidTimezone_ShouldError(t *testing.T) {
+	cfg := config.SummarizerConfig{
+		TopN:      5,
+		Timezones: map[string]string{"VHHH": "Mars/Olympus_Mons"},
+	}
+
+	summarizer, err := service.NewSummarizer(cfg)
+	require.ErrorContains(t, err, "timezone of airport VHHH is invalid")
+	require.Nil(t, summarizer)
+}
+
 func TestSummarizeFlights_ValidAndEmptyData_ShouldSucceed(t *testing.T) {
 	testCases := []struct {
 		name            string
@@ -134,3 +146,79 @@ func TestSummarizeFlights_InvalidData_ShouldError(t *testing.T) {
 		})
 	}
 }
+
+func TestSummarizeFlights_FirstSeen_ShouldBucketByLocalHour(t *testing.T) {
+	cfg := config.SummarizerConfig{
+		TopN: 5,
+		// Keys are lower-cased when loaded from the config file
+		Timezones: map[string]string{"vhhh": "Asia/Hong_Kong"},
+	}
+	summarizer, err := service.NewSummarizer(cfg)
+	require.NoError(t, err)
+	require.NotNil(t, summarizer)
+
+	// 01:xx UTC is 09:xx in Hong Kong
+	at := func(hour int, minute int) int {
+		return int(time.Date(2025, 5, 7, hour, minute, 0, 0, time.UTC).Unix())
+	}
+
+	flights := []msg.FlightRecord{
+		{Airline: "Cathay Pacific", Destination: "NRT", FirstSeen: at(1, 5)},
+		{Airline: "Cathay Pacific", Destination: "NRT", FirstSeen: at(1, 40)},
+		{Airline: "HK Express", Destination: "TPE", FirstSeen: at(1, 59)},
+		{Airline: "HK Express", Destination: "TPE", FirstSeen: at(14, 0)},
+		{Airline: "HK Express", Destination: "TPE", FirstSeen: 0},
+	}
+
+	testCases := []struct {
+		name             string
+		airport          string
+		expectedBusiest  int
+		expectedQuietest int
+	}{
+		{name: "Configured Airport", airport: "VHHH", expectedBusiest: 9, expectedQuietest: 0},
+		{name: "Unconfigured Airport Uses UTC", airport: "RJTT", expectedBusiest: 1, expectedQuietest: 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			summary, err := summarizer.SummarizeFlights(flights, "2025-05-07", tc.airport)
+			require.NoError(t, err)
+			require.NotNil(t, summary)
+			require.Len(t, summary.HourlyDepartures, 24)
+			require.Equal(t, 5, summary.TotalFlights)
+			require.Equal(t, tc.expectedBusiest, summary.BusiestHour)
+			require.Equal(t, 3, summary.BusiestHourFlights)
+			require.Equal(t, tc.expectedQuietest, summary.QuietestHour)
+			require.Equal(t, 0, summary.QuietestHourFlights)
+			require.Contains(t, summary.FormatForSocialMedia(),
+				fmt.Sprintf("Peak hour was %02d:00 with 3 departures", tc.expectedBusiest))
+		})
+	}
+}
+
+func TestSummarizeFlights_AllHoursBusy_ShouldPickEarliestTies(t *testing.T) {
+	cfg := config.SummarizerConfig{
+		TopN: 5,
+	}
+	summarizer, err := service.NewSummarizer(cfg)
+	require.NoError(t, err)
+	require.NotNil(t, summarizer)
+
+	flights := make([]msg.FlightRecord, 0, 25)
+	for hour := range 24 {
+		flights = append(flights, msg.FlightRecord{
+			FirstSeen: int(time.Date(2025, 5, 7, hour, 30, 0, 0, time.UTC).Unix()),
+		})
+	}
+	flights = append(flights, msg.FlightRecord{
+		FirstSeen: int(time.Date(2025, 5, 7, 18, 0, 0, 0, time.UTC).Unix()),
+	})
+
+	summary, err := summarizer.SummarizeFlights(flights, "2025-05-07", "SFO")
+	require.NoError(t, err)
+	require.Equal(t, 18, summary.BusiestHour)
+	require.Equal(t, 2, summary.BusiestHourFlights)
+	require.Equal(t, 0, summary.QuietestHour)
+	require.Equal(t, 1, summary.QuietestHourFlights)
+}
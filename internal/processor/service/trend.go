@@ -0,0 +1,44 @@
+package service
+
+import (
+	"sort"
+
+	"github.com/ansoncht/flight-microservices/pkg/model"
+)
+
+// percent specifies the multiplier converting a ratio to a percentage.
+const percent = 100
+
+// CompareSummaries compares a daily flight summary against an earlier baseline summary.
+// Names in the resulting lists are sorted so that the comparison is deterministic.
+func CompareSummaries(current model.DailyFlightSummary, baseline model.DailyFlightSummary) model.SummaryComparison {
+	comparison := model.SummaryComparison{
+		BaselineDate:    baseline.Date,
+		BaselineFlights: baseline.TotalFlights,
+		FlightsChange:   current.TotalFlights - baseline.TotalFlights,
+		AirlinesGained:  missingKeys(current.AirlineCounts, baseline.AirlineCounts),
+		AirlinesLost:    missingKeys(baseline.AirlineCounts, current.AirlineCounts),
+		NewDestinations: missingKeys(current.DestinationCounts, baseline.DestinationCounts),
+	}
+
+	if baseline.TotalFlights > 0 {
+		comparison.FlightsChangePercent = float64(comparison.FlightsChange) / float64(baseline.TotalFlights) * percent
+	}
+
+	return comparison
+}
+
+// missingKeys returns the sorted keys with a positive count in m which have no positive count in other.
+func missingKeys(m map[string]int, other map[string]int) []string {
+	keys := make([]string, 0)
+
+	for key, count := range m {
+		if count > 0 && other[key] <= 0 {
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
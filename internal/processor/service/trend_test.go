@@ -0,0 +1,120 @@
+package service_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ansoncht/flight-microservices/internal/processor/service"
+	"github.com/ansoncht/flight-microservices/pkg/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompareSummaries_ValidSummaries_ShouldSucceed(t *testing.T) {
+	baselineDate := model.ToMongoDateTime(time.Date(2025, 4, 29, 0, 0, 0, 0, time.UTC))
+
+	testCases := []struct {
+		name       string
+		current    model.DailyFlightSummary
+		baseline   model.DailyFlightSummary
+		expected   model.SummaryComparison
+		hasPercent bool
+	}{
+		{
+			name: "Growth With Changed Airlines And Destinations",
+			current: model.DailyFlightSummary{
+				TotalFlights:      56,
+				AirlineCounts:     map[string]int{"United": 30, "JetBlue": 20, "Alaska": 6},
+				DestinationCounts: map[string]int{"LAX": 40, "SFO": 10, "BOS": 6},
+			},
+			baseline: model.DailyFlightSummary{
+				Date:              baselineDate,
+				TotalFlights:      50,
+				AirlineCounts:     map[string]int{"United": 30, "Delta": 15, "Spirit": 5},
+				DestinationCounts: map[string]int{"LAX": 50},
+			},
+			expected: model.SummaryComparison{
+				BaselineDate:         baselineDate,
+				BaselineFlights:      50,
+				FlightsChange:        6,
+				FlightsChangePercent: 12,
+				AirlinesGained:       []string{"Alaska", "JetBlue"},
+				AirlinesLost:         []string{"Delta", "Spirit"},
+				NewDestinations:      []string{"BOS", "SFO"},
+			},
+		},
+		{
+			name: "Decline Ignores Zero Counts",
+			current: model.DailyFlightSummary{
+				TotalFlights:      3,
+				AirlineCounts:     map[string]int{"United": 3, "Delta": 0},
+				DestinationCounts: map[string]int{"LAX": 3},
+			},
+			baseline: model.DailyFlightSummary{
+				Date:              baselineDate,
+				TotalFlights:      4,
+				AirlineCounts:     map[string]int{"United": 4},
+				DestinationCounts: map[string]int{"LAX": 4, "SFO": 0},
+			},
+			expected: model.SummaryComparison{
+				BaselineDate:         baselineDate,
+				BaselineFlights:      4,
+				FlightsChange:        -1,
+				FlightsChangePercent: -25,
+				AirlinesGained:       []string{},
+				AirlinesLost:         []string{},
+				NewDestinations:      []string{},
+			},
+		},
+		{
+			name: "Empty Baseline",
+			current: model.DailyFlightSummary{
+				TotalFlights:      2,
+				AirlineCounts:     map[string]int{"United": 2},
+				DestinationCounts: map[string]int{"LAX": 2},
+			},
+			baseline: model.DailyFlightSummary{Date: baselineDate},
+			expected: model.SummaryComparison{
+				BaselineDate:    baselineDate,
+				FlightsChange:   2,
+				AirlinesGained:  []string{"United"},
+				AirlinesLost:    []string{},
+				NewDestinations: []string{"LAX"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			comparison := service.CompareSummaries(tc.current, tc.baseline)
+			require.Equal(t, tc.expected, comparison)
+
+			// Map iteration order must not leak into the result
+			for range 10 {
+				require.Equal(t, comparison, service.CompareSummaries(tc.current, tc.baseline))
+			}
+		})
+	}
+}
+
+func TestFormatForSocialMedia_Trends_ShouldIncludeChanges(t *testing.T) {
+	summary := model.DailyFlightSummary{
+		// 2025-05-06 is a Tuesday
+		Date:         model.ToMongoDateTime(time.Date(2025, 5, 6, 0, 0, 0, 0, time.UTC)),
+		Airport:      "JFK",
+		TotalFlights: 56,
+	}
+
+	require.NotContains(t, summary.FormatForSocialMedia(), "Trend")
+
+	summary.DayOverDay = &model.SummaryComparison{BaselineFlights: 0, FlightsChange: 56}
+	summary.WeekOverWeek = &model.SummaryComparison{
+		BaselineFlights:      50,
+		FlightsChangePercent: 12,
+		NewDestinations:      []string{"BOS", "SFO"},
+	}
+
+	content := summary.FormatForSocialMedia()
+	require.Contains(t, content, "📈 **Trend**: +12% vs last Tuesday\n")
+	require.Contains(t, content, "🆕 **New Destinations**: BOS, SFO\n")
+	require.NotContains(t, content, "vs yesterday")
+}
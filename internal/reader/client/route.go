@@ -3,6 +3,7 @@ package client
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
@@ -13,6 +14,10 @@ import (
 	"github.com/ansoncht/flight-microservices/internal/reader/model"
 )
 
+// ErrRouteUnavailable is wrapped by the errors of route lookups which may succeed when looked up again, such as
+// transport errors and server errors of the route API, as opposed to callsigns the route API has no route for.
+var ErrRouteUnavailable = errors.New("route api is unavailable")
+
 // Route defines the interface for fetching flight route.
 type Route interface {
 	// FetchRoute retrieves the flight route for a given callsign from external API.
@@ -66,10 +71,14 @@ func (c *RouteAPI) FetchRoute(ctx context.Context, callsign string) (*model.Rout
 
 	resp, err := c.client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch route: %w", err)
+		return nil, fmt.Errorf("failed to fetch route: %w: %w", ErrRouteUnavailable, err)
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests {
+		return nil, fmt.Errorf("%w: unexpected status code: %d", ErrRouteUnavailable, resp.StatusCode)
+	}
+
 	if resp.StatusCode != http.StatusOK {
 		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
 	}
@@ -95,26 +95,46 @@ func TestFetchRoute_InvalidArgs_ShouldError(t *testing.T) {
 	cfg := config.RouteAPIConfig{
 		URL: server.URL,
 	}
-	client, err := client.NewRouteAPI(cfg, server.Client())
+	routeAPI, err := client.NewRouteAPI(cfg, server.Client())
 	require.NoError(t, err)
-	require.NotNil(t, client)
+	require.NotNil(t, routeAPI)
 
-	route, err := client.FetchRoute(context.Background(), "ABC123")
+	route, err := routeAPI.FetchRoute(context.Background(), "ABC123")
 	require.ErrorContains(t, err, "unexpected status code")
+	require.NotErrorIs(t, err, client.ErrRouteUnavailable)
 	require.Nil(t, route)
 }
 
+func TestFetchRoute_ServerError_ShouldBeUnavailable(t *testing.T) {
+	for _, status := range []int{http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway} {
+		t.Run(http.StatusText(status), func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				http.Error(w, http.StatusText(status), status)
+			}))
+			defer server.Close()
+
+			routeAPI, err := client.NewRouteAPI(config.RouteAPIConfig{URL: server.URL}, server.Client())
+			require.NoError(t, err)
+
+			route, err := routeAPI.FetchRoute(context.Background(), "CRK452")
+			require.ErrorIs(t, err, client.ErrRouteUnavailable)
+			require.Nil(t, route)
+		})
+	}
+}
+
 func TestFetchRoute_HTTPClientError_ShouldError(t *testing.T) {
 	cfg := config.RouteAPIConfig{
 		URL: "not a valid url",
 	}
 
-	client, err := client.NewRouteAPI(cfg, &http.Client{})
+	routeAPI, err := client.NewRouteAPI(cfg, &http.Client{})
 	require.NoError(t, err)
-	require.NotNil(t, client)
+	require.NotNil(t, routeAPI)
 
-	route, err := client.FetchRoute(context.Background(), "CRK452")
+	route, err := routeAPI.FetchRoute(context.Background(), "CRK452")
 	require.ErrorContains(t, err, "failed to fetch route")
+	require.ErrorIs(t, err, client.ErrRouteUnavailable)
 	require.Nil(t, route)
 }
 
@@ -4,9 +4,13 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/ansoncht/flight-microservices/pkg/bus"
 	"github.com/ansoncht/flight-microservices/pkg/http"
 	"github.com/ansoncht/flight-microservices/pkg/kafka"
 	"github.com/ansoncht/flight-microservices/pkg/logger"
+	"github.com/ansoncht/flight-microservices/pkg/nats"
+	"github.com/ansoncht/flight-microservices/pkg/redis"
+	"github.com/ansoncht/flight-microservices/pkg/tracing"
 	"github.com/spf13/viper"
 )
 
@@ -16,7 +20,12 @@ type FlightReaderConfig struct {
 	HTTPClientConfig      http.ClientConfig  `mapstructure:"http_client"`
 	FlightAPIClientConfig FlightAPIConfig    `mapstructure:"flight_api"`
 	RouteAPIClientConfig  RouteAPIConfig     `mapstructure:"route_api"`
+	BusConfig             bus.Config         `mapstructure:"bus"`
 	KafkaWriterConfig     kafka.WriterConfig `mapstructure:"kafka_writer"`
+	NATSWriterConfig      nats.WriterConfig  `mapstructure:"nats_writer"`
+	RedisWriterConfig     redis.WriterConfig `mapstructure:"redis_writer"`
+	DLQConfig             kafka.DLQConfig    `mapstructure:"dlq"`
+	TracingConfig         tracing.Config     `mapstructure:"tracing"`
 	LoggerConfig          logger.Config      `mapstructure:"logger"`
 }
 
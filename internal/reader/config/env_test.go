@@ -15,18 +15,48 @@ func TestLoadConfig_ValidConfigFile_ShouldSucceed(t *testing.T) {
 	os.Setenv("FLIGHT_READER_ROUTE_API_URL", "test")
 	os.Setenv("FLIGHT_READER_KAFKA_WRITER_ADDRESS", "test")
 	os.Setenv("FLIGHT_READER_KAFKA_WRITER_TOPIC", "test")
+	os.Setenv("FLIGHT_READER_KAFKA_WRITER_SASL_PASSWORD", "secret")
+	os.Setenv("FLIGHT_READER_KAFKA_WRITER_SASL_TOKEN_FILE", "/var/run/secrets/kafka/token")
+	os.Setenv("FLIGHT_READER_DLQ_TOPIC", "flights-reader-dlq")
 
 	cfg, err := config.LoadConfig()
 	require.NoError(t, err)
 	require.NotNil(t, cfg)
 	require.Equal(t, "8080", cfg.HTTPServerConfig.Port)
 	require.Equal(t, 75, cfg.HTTPServerConfig.Timeout)
+	require.True(t, cfg.HTTPServerConfig.Middleware.AccessLog)
+	require.True(t, cfg.HTTPServerConfig.Middleware.Recover)
+	require.True(t, cfg.HTTPServerConfig.Middleware.RequestID)
+	require.True(t, cfg.HTTPServerConfig.Middleware.Gzip)
+	require.False(t, cfg.HTTPServerConfig.Middleware.CORS.Enabled)
+	require.Empty(t, cfg.HTTPServerConfig.Middleware.CORS.AllowedOrigins)
+	require.Equal(t, []string{"GET", "OPTIONS"}, cfg.HTTPServerConfig.Middleware.CORS.AllowedMethods)
+	require.Equal(t, 600, cfg.HTTPServerConfig.Middleware.CORS.MaxAge)
+	require.Empty(t, cfg.HTTPServerConfig.Middleware.Timeouts)
 	require.Equal(t, 70, cfg.HTTPClientConfig.Timeout)
 	require.Equal(t, "test", cfg.FlightAPIClientConfig.URL)
 	require.Equal(t, "test", cfg.FlightAPIClientConfig.User)
 	require.Equal(t, "test", cfg.FlightAPIClientConfig.Pass)
 	require.Equal(t, "test", cfg.KafkaWriterConfig.Address)
 	require.Equal(t, "test", cfg.KafkaWriterConfig.Topic)
+	require.False(t, cfg.KafkaWriterConfig.Async)
+	require.Equal(t, 10, cfg.KafkaWriterConfig.LingerMs)
+	require.Equal(t, int32(1048576), cfg.KafkaWriterConfig.BatchMaxBytes)
+	require.Equal(t, "snappy", cfg.KafkaWriterConfig.Compression)
+	require.False(t, cfg.KafkaWriterConfig.TLS.Enabled)
+	require.Empty(t, cfg.KafkaWriterConfig.SASL.Mechanism)
+	require.Equal(t, "secret", cfg.KafkaWriterConfig.SASL.Password)
+	require.Equal(t, "/var/run/secrets/kafka/token", cfg.KafkaWriterConfig.SASL.TokenFile)
+	require.Equal(t, "kafka", cfg.BusConfig.Driver)
+	require.False(t, cfg.NATSWriterConfig.Async)
+	require.Equal(t, int64(0), cfg.RedisWriterConfig.MaxLen)
+	require.False(t, cfg.DLQConfig.Enabled)
+	require.Equal(t, "flights-reader-dlq", cfg.DLQConfig.Topic)
+	require.False(t, cfg.TracingConfig.Enabled)
+	require.Equal(t, "otlp", cfg.TracingConfig.Exporter)
+	require.Empty(t, cfg.TracingConfig.Endpoint)
+	require.True(t, cfg.TracingConfig.Insecure)
+	require.InDelta(t, 1.0, cfg.TracingConfig.SampleRatio, 0.001)
 	require.True(t, cfg.LoggerConfig.JSON)
 	require.Equal(t, "info", cfg.LoggerConfig.Level)
 }
@@ -88,6 +118,8 @@ func TestLoadConfig_EnvOverride_ShouldSucceed(t *testing.T) {
 	os.Setenv("FLIGHT_READER_KAFKA_WRITER_TOPIC", "test")
 	t.Setenv("FLIGHT_READER_LOGGER_LEVEL", "debug")
 	t.Setenv("FLIGHT_READER_LOGGER_JSON", "false")
+	t.Setenv("FLIGHT_READER_HTTP_SERVER_MIDDLEWARE_CORS_ENABLED", "true")
+	t.Setenv("FLIGHT_READER_HTTP_SERVER_MIDDLEWARE_CORS_ALLOWED_ORIGINS", "https://example.com,https://example.org")
 
 	cfg, err := config.LoadConfig()
 	require.NoError(t, err)
@@ -100,6 +132,11 @@ func TestLoadConfig_EnvOverride_ShouldSucceed(t *testing.T) {
 	require.Equal(t, "test", cfg.FlightAPIClientConfig.Pass)
 	require.Equal(t, "test", cfg.KafkaWriterConfig.Address)
 	require.Equal(t, "test", cfg.KafkaWriterConfig.Topic)
+	require.True(t, cfg.HTTPServerConfig.Middleware.CORS.Enabled)
+	require.Equal(t,
+		[]string{"https://example.com", "https://example.org"},
+		cfg.HTTPServerConfig.Middleware.CORS.AllowedOrigins,
+	)
 	require.False(t, cfg.LoggerConfig.JSON)
 	require.Equal(t, "debug", cfg.LoggerConfig.Level)
 }
@@ -0,0 +1,34 @@
+package service
+
+import (
+	"github.com/ansoncht/flight-microservices/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// metricsSubsystem specifies the subsystem of the reader metrics.
+const metricsSubsystem = "reader"
+
+// Results of route lookups labelled by the route metric.
+const (
+	routeResolved   = "resolved"
+	routeUnresolved = "unresolved"
+	routeFailed     = "failed"
+)
+
+var (
+	// flightsFetched counts the flights fetched from the flight API.
+	flightsFetched = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: metricsSubsystem,
+		Name:      "flights_fetched_total",
+		Help:      "Number of flights fetched from the flight API by airport.",
+	}, []string{"airport"})
+	// routesLookedUp counts the route lookups of flights by result.
+	routesLookedUp = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: metricsSubsystem,
+		Name:      "routes_total",
+		Help:      "Number of flight routes looked up from the route API by result: resolved, unresolved or failed.",
+	}, []string{"result"})
+)
@@ -8,29 +8,42 @@ import (
 	"log/slog"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/ansoncht/flight-microservices/internal/reader/client"
 	"github.com/ansoncht/flight-microservices/internal/reader/model"
+	appHTTP "github.com/ansoncht/flight-microservices/pkg/http"
 	"github.com/ansoncht/flight-microservices/pkg/kafka"
 	msg "github.com/ansoncht/flight-microservices/pkg/model"
+	"github.com/ansoncht/flight-microservices/pkg/msgbus"
+	"github.com/ansoncht/flight-microservices/pkg/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/sync/errgroup"
 )
 
+// tracer specifies the tracer of the reader service.
+var tracer = otel.Tracer("github.com/ansoncht/flight-microservices/internal/reader/service")
+
 type Reader struct {
 	// flightsClient specifies the shared HTTP client to submit requests to the external flight API.
 	flightsClient client.Flight
 	// routesClient specifies the shared HTTP client to submit requests to the external route API.
 	routeClient client.Route
 	// messageWriter specifies the message writer to send messages to a message queue.
-	messageWriter kafka.MessageWriter
+	messageWriter msgbus.MessageWriter
+	// deadLetterWriter specifies the writer to set aside flights which cannot be sent.
+	deadLetterWriter kafka.DeadLetterWriter
 }
 
-// NewReader creates a new Reader instance based on the provided api clients and message writer.
+// NewReader creates a new Reader instance based on the provided api clients, message writer and dead letter writer.
 func NewReader(
 	flightClient client.Flight,
 	routeClient client.Route,
-	messageWriter kafka.MessageWriter,
+	messageWriter msgbus.MessageWriter,
+	deadLetterWriter kafka.DeadLetterWriter,
 ) (*Reader, error) {
 	if flightClient == nil {
 		return nil, fmt.Errorf("flight client is nil")
@@ -44,18 +57,42 @@ func NewReader(
 		return nil, fmt.Errorf("message writer is nil")
 	}
 
+	if deadLetterWriter == nil {
+		return nil, fmt.Errorf("dead letter writer is nil")
+	}
+
 	return &Reader{
-		flightsClient: flightClient,
-		routeClient:   routeClient,
-		messageWriter: messageWriter,
+		flightsClient:    flightClient,
+		routeClient:      routeClient,
+		messageWriter:    messageWriter,
+		deadLetterWriter: deadLetterWriter,
 	}, nil
 }
 
+// Check returns an error if the message queue of the reader service cannot be reached.
+func (r *Reader) Check(ctx context.Context) error {
+	return r.messageWriter.Check(ctx) //nolint:wrapcheck // the message writer describes its own errors
+}
+
 // Close closes the reader service.
 func (r *Reader) Close() {
 	r.messageWriter.Close()
+	r.deadLetterWriter.Close()
+}
+
+// Resummarize reads the flights of the airport on the given date again, streaming the day as a whole.
+// It implements the kafka.Resummarizer interface to replay the flights set aside from a day stream.
+func (r *Reader) Resummarize(ctx context.Context, airport string, date string) error {
+	day, err := parseDay(date)
+	if err != nil {
+		return err
+	}
+
+	return r.processFlights(ctx, airport, day)
 }
 
+// HTTPHandler reads the flights of the airport given by the airport parameter. It reads the day given by the
+// optional date parameter, formatted as YYYY-MM-DD, or the day before yesterday otherwise.
 func (r *Reader) HTTPHandler(w http.ResponseWriter, req *http.Request) {
 	airport := req.URL.Query().Get("airport")
 	if airport == "" {
@@ -63,8 +100,15 @@ func (r *Reader) HTTPHandler(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	err := r.processFlights(req.Context(), airport)
+	day, err := parseDay(req.URL.Query().Get("date"))
+	if err != nil {
+		http.Error(w, "invalid date parameter", http.StatusBadRequest)
+		return
+	}
+
+	err = r.processFlights(req.Context(), airport, day)
 	if err != nil {
+		appHTTP.Logger(req.Context()).Error("Failed to process flights", "airport", airport, "error", err)
 		http.Error(w, fmt.Sprintf("failed to process flights: %v", err), http.StatusInternalServerError)
 		return
 	}
@@ -76,28 +120,35 @@ func (r *Reader) HTTPHandler(w http.ResponseWriter, req *http.Request) {
 	// Encode a success message as JSON
 	response := map[string]string{"message": "flights processed successfully"}
 	if err := json.NewEncoder(w).Encode(response); err != nil {
-		slog.Error("Failed to write response", "error", err)
+		appHTTP.Logger(req.Context()).Error("Failed to write response", "error", err)
 		http.Error(w, "failed to send response", http.StatusInternalServerError)
 		return
 	}
 }
 
-// processFlights fetches flight data for a specified airport, processes
+// processFlights fetches flight data for a specified airport and day, processes
 // the data to retrieve route information, and sends the flight details
 // to a kafka topic.
 func (r *Reader) processFlights(
 	ctx context.Context,
 	airport string,
+	day time.Time,
 ) error {
-	// Get previous day in Unix timestamp
-	begin, end, date := getPreviousDayTime()
+	ctx, span := tracer.Start(ctx, "ProcessFlights", trace.WithAttributes(attribute.String("airport", airport)))
+	defer span.End()
+
+	// Get the day in Unix timestamp
+	begin, end, date := getDayTime(day)
 
-	flights, err := r.flightsClient.FetchFlights(ctx, airport, begin, end)
+	fetchCtx, fetchSpan := tracer.Start(ctx, "FetchFlights", trace.WithAttributes(attribute.String("airport", airport)))
+	flights, err := r.flightsClient.FetchFlights(fetchCtx, airport, begin, end)
+	tracing.End(fetchSpan, err)
 	if err != nil {
 		return fmt.Errorf("failed to process flights: %w", err)
 	}
 
 	slog.Info("Fetched flights successfully", "airport", airport, "flights_count", len(flights))
+	flightsFetched.WithLabelValues(airport).Add(float64(len(flights)))
 
 	if err := r.processRoute(ctx, flights, airport, date); err != nil {
 		return fmt.Errorf("failed to process routes: %w", err)
@@ -114,6 +165,9 @@ func (r *Reader) processRoute(ctx context.Context, flights []model.Flight, airpo
 	// Use errgroup with shared context to process routes concurrently
 	g, gCtx := errgroup.WithContext(ctx)
 
+	var mu sync.Mutex
+	messages := make([]msgbus.Message, 0, len(flights))
+
 	// For each flight entry, process its route concurrently
 	for _, f := range flights {
 		flight := f
@@ -125,26 +179,44 @@ func (r *Reader) processRoute(ctx context.Context, flights []model.Flight, airpo
 					return nil
 				}
 
-				route, err := r.routeClient.FetchRoute(gCtx, callsign)
+				routeCtx, routeSpan := tracer.Start(
+					gCtx,
+					"FetchRoute",
+					trace.WithAttributes(attribute.String("callsign", callsign)),
+				)
+				route, err := r.routeClient.FetchRoute(routeCtx, callsign)
+				tracing.End(routeSpan, err)
 				if err != nil {
 					if errors.Is(err, context.Canceled) {
 						return fmt.Errorf("context canceled while processing route: %w", gCtx.Err())
 					}
 
+					// Only lookups which may succeed later are set aside, as replaying the day would look up a
+					// callsign without a route again and set it aside once more
+					if !errors.Is(err, client.ErrRouteUnavailable) {
+						slog.Warn("Dropping flight without route", "callsign", callsign, "error", err)
+						routesLookedUp.WithLabelValues(routeUnresolved).Inc()
+						return nil
+					}
+
 					slog.Warn("Failed to fetch route", "callsign", callsign, "error", err)
-					return nil
+					routesLookedUp.WithLabelValues(routeFailed).Inc()
+					return r.setAside(gCtx, flight, airport, date, err)
 				}
 
-				// Send the flight and route data to a message queue
-				if err := r.sendFlightAndRouteMessage(gCtx, flight, *route); err != nil {
-					if errors.Is(err, context.Canceled) {
-						return fmt.Errorf("context canceled while sending flight and route: %w", gCtx.Err())
-					}
-
-					slog.Warn("Failed to send flight and route message", "callsign", callsign, "error", err)
+				message, err := newFlightAndRouteMessage(flight, *route)
+				if err != nil {
+					slog.Warn("Dropping flight without usable route", "callsign", callsign, "error", err)
+					routesLookedUp.WithLabelValues(routeUnresolved).Inc()
 					return nil
 				}
 
+				routesLookedUp.WithLabelValues(routeResolved).Inc()
+
+				mu.Lock()
+				messages = append(messages, *message)
+				mu.Unlock()
+
 				return nil
 			})
 		}
@@ -155,36 +227,76 @@ func (r *Reader) processRoute(ctx context.Context, flights []model.Flight, airpo
 		return fmt.Errorf("failed to process at least one route: %w", err)
 	}
 
+	// Send the flight and route data to a message queue in batches rather than one round trip per flight.
+	// The stream is left without its end when the batch fails, so that the processor never summarizes a partial day.
+	if len(messages) > 0 {
+		if err := r.messageWriter.WriteMessages(ctx, messages); err != nil {
+			if errors.Is(err, context.Canceled) {
+				return fmt.Errorf("context canceled while sending flights and routes: %w", err)
+			}
+
+			return fmt.Errorf("failed to send %d flight and route messages: %w", len(messages), err)
+		}
+	}
+
 	if err := r.sendStreamControlMessage(ctx, "end_of_stream", date); err != nil {
 		return fmt.Errorf("failed to send end_of_stream message: %w", err)
 	}
 
+	if err := r.messageWriter.Flush(ctx); err != nil {
+		return fmt.Errorf("failed to flush messages to the message queue: %w", err)
+	}
+
 	return nil
 }
 
-func (r *Reader) sendFlightAndRouteMessage(
-	ctx context.Context,
-	flight model.Flight,
-	route model.Route,
-) error {
+// newFlightAndRouteMessage creates the message carrying the flight record of a flight and its route.
+// It returns an error if the message cannot be written, such as when the route has no IATA callsign to key it by.
+func newFlightAndRouteMessage(flight model.Flight, route model.Route) (*msgbus.Message, error) {
 	record := &msg.FlightRecord{
-		FlightNumber: route.Response.FlightRoute.CallSignIATA,
-		Airline:      route.Response.FlightRoute.Airline.Name,
-		Origin:       route.Response.FlightRoute.Origin.IATACode,
-		Destination:  route.Response.FlightRoute.Destination.IATACode,
-		FirstSeen:    flight.FirstSeen,
-		LastSeen:     flight.LastSeen,
+		FlightNumber:       route.Response.FlightRoute.CallSignIATA,
+		Airline:            route.Response.FlightRoute.Airline.Name,
+		Origin:             route.Response.FlightRoute.Origin.IATACode,
+		Destination:        route.Response.FlightRoute.Destination.IATACode,
+		FirstSeen:          flight.FirstSeen,
+		LastSeen:           flight.LastSeen,
+		OriginCountry:      route.Response.FlightRoute.Origin.CountryISOName,
+		DestinationCountry: route.Response.FlightRoute.Destination.CountryISOName,
+		Icao24:             strings.ToLower(strings.TrimSpace(flight.Icao24)),
 	}
 
 	value, err := json.Marshal(record)
 	if err != nil {
-		return fmt.Errorf("failed to marshal flight record: %w", err)
+		return nil, fmt.Errorf("failed to marshal flight record: %w", err)
 	}
 
-	key := []byte(route.Response.FlightRoute.CallSignIATA)
+	message := &msgbus.Message{
+		Key:   []byte(route.Response.FlightRoute.CallSignIATA),
+		Value: value,
+	}
 
-	if err := r.messageWriter.WriteMessage(ctx, key, value); err != nil {
-		return fmt.Errorf("failed to write message to the message queue: %w", err)
+	if err := message.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid flight and route message: %w", err)
+	}
+
+	return message, nil
+}
+
+// setAside writes a flight which cannot be sent to the dead-letter queue, along with the airport and date of its
+// day stream so that replaying it reads the day again.
+func (r *Reader) setAside(ctx context.Context, flight model.Flight, airport string, date string, reason error) error {
+	value, err := json.Marshal(flight)
+	if err != nil {
+		return fmt.Errorf("failed to marshal flight: %w", err)
+	}
+
+	message := kafka.WithStream(msgbus.Message{
+		Key:   []byte(strings.TrimSpace(flight.Callsign)),
+		Value: value,
+	}, airport, date)
+
+	if err := r.deadLetterWriter.WriteDeadLetter(ctx, message, reason); err != nil {
+		return fmt.Errorf("failed to write dead letter: %w", err)
 	}
 
 	return nil
@@ -198,23 +310,34 @@ func (r *Reader) sendStreamControlMessage(ctx context.Context, key, message stri
 	return nil
 }
 
-// getPreviousDayTime calculates the start and end Unix timestamps for the previous day.
-func getPreviousDayTime() (string, string, string) {
-	now := time.Now()
+// parseDay parses a date formatted as YYYY-MM-DD in local time, defaulting to the day before yesterday when it is
+// empty so that the flight API has recorded the whole day.
+func parseDay(date string) (time.Time, error) {
+	if date == "" {
+		return time.Now().AddDate(0, 0, -2), nil
+	}
 
-	yesterday := now.AddDate(0, 0, -2)
+	day, err := time.ParseInLocation("2006-01-02", date, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to parse date: %w", err)
+	}
+
+	return day, nil
+}
 
-	// Mark the start of yesterday (12:00:00 AM)
-	startOfYesterday := time.Date(yesterday.Year(), yesterday.Month(), yesterday.Day(), 0, 0, 0, 0, now.Location())
+// getDayTime calculates the start and end Unix timestamps for the day.
+func getDayTime(day time.Time) (string, string, string) {
+	// Mark the start of the day (12:00:00 AM)
+	startOfDay := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, day.Location())
 
-	// Mark the end of yesterday (11:59:59 PM)
-	endOfYesterday := time.Date(yesterday.Year(), yesterday.Month(), yesterday.Day(), 23, 59, 59, 0, now.Location())
+	// Mark the end of the day (11:59:59 PM)
+	endOfDay := time.Date(day.Year(), day.Month(), day.Day(), 23, 59, 59, 0, day.Location())
 
 	// Convert to Unix epoch timestamps
-	startEpoch := startOfYesterday.Unix()
-	endEpoch := endOfYesterday.Unix()
+	startEpoch := startOfDay.Unix()
+	endEpoch := endOfDay.Unix()
 
-	date := yesterday.Format("2006-01-02")
+	date := day.Format("2006-01-02")
 
 	return fmt.Sprintf("%d", startEpoch), fmt.Sprintf("%d", endEpoch), date
 }
@@ -2,22 +2,33 @@ package service_test
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/ansoncht/flight-microservices/internal/reader/client"
 	"github.com/ansoncht/flight-microservices/internal/reader/model"
 	"github.com/ansoncht/flight-microservices/internal/reader/service"
 	"github.com/ansoncht/flight-microservices/internal/test/mock"
 	"github.com/ansoncht/flight-microservices/pkg/kafka"
+	msg "github.com/ansoncht/flight-microservices/pkg/model"
+	"github.com/ansoncht/flight-microservices/pkg/msgbus"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 )
 
+// route is a route with an IATA callsign to key its flight record by.
+var route = &model.Route{Response: model.Response{FlightRoute: model.FlightRoute{CallSignIATA: "UO452"}}}
+
+// unavailable is an error of a route lookup which may succeed when looked up again.
+var unavailable = fmt.Errorf("%w: unexpected status code: 503", client.ErrRouteUnavailable)
+
 func TestNewReader_NonNilClients_ShouldSucceed(t *testing.T) {
-	reader, err := service.NewReader(&client.FlightAPI{}, &client.RouteAPI{}, &kafka.Writer{})
+	reader, err := service.NewReader(&client.FlightAPI{}, &client.RouteAPI{}, &kafka.Writer{}, &kafka.NopDeadLetterWriter{})
 	require.NoError(t, err)
 	require.NotNil(t, reader)
 }
@@ -27,7 +38,8 @@ func TestNewReader_NilClient_ShouldError(t *testing.T) {
 		name          string
 		flightClient  client.Flight
 		routeClient   client.Route
-		messageWriter kafka.MessageWriter
+		messageWriter msgbus.MessageWriter
+		deadLetters   kafka.DeadLetterWriter
 		wantErr       string
 	}{
 		{
@@ -35,6 +47,7 @@ func TestNewReader_NilClient_ShouldError(t *testing.T) {
 			flightClient:  nil,
 			routeClient:   &client.RouteAPI{},
 			messageWriter: &kafka.Writer{},
+			deadLetters:   &kafka.NopDeadLetterWriter{},
 			wantErr:       "flight client is nil",
 		},
 		{
@@ -42,6 +55,7 @@ func TestNewReader_NilClient_ShouldError(t *testing.T) {
 			flightClient:  &client.FlightAPI{},
 			routeClient:   nil,
 			messageWriter: &kafka.Writer{},
+			deadLetters:   &kafka.NopDeadLetterWriter{},
 			wantErr:       "route client is nil",
 		},
 		{
@@ -50,13 +64,22 @@ func TestNewReader_NilClient_ShouldError(t *testing.T) {
 			flightClient:  &client.FlightAPI{},
 			routeClient:   &client.RouteAPI{},
 			messageWriter: nil,
+			deadLetters:   &kafka.NopDeadLetterWriter{},
 			wantErr:       "message writer is nil",
 		},
+		{
+			name:          "Nil Dead Letter Writer",
+			flightClient:  &client.FlightAPI{},
+			routeClient:   &client.RouteAPI{},
+			messageWriter: &kafka.Writer{},
+			deadLetters:   nil,
+			wantErr:       "dead letter writer is nil",
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			reader, err := service.NewReader(tt.flightClient, tt.routeClient, tt.messageWriter)
+			reader, err := service.NewReader(tt.flightClient, tt.routeClient, tt.messageWriter, tt.deadLetters)
 			require.Nil(t, reader)
 			require.ErrorContains(t, err, tt.wantErr)
 		})
@@ -64,7 +87,7 @@ func TestNewReader_NilClient_ShouldError(t *testing.T) {
 }
 
 func TestHTTPHandler_MissingAirport_ShouldError(t *testing.T) {
-	reader, err := service.NewReader(&client.FlightAPI{}, &client.RouteAPI{}, &kafka.Writer{})
+	reader, err := service.NewReader(&client.FlightAPI{}, &client.RouteAPI{}, &kafka.Writer{}, &kafka.NopDeadLetterWriter{})
 	require.NoError(t, err)
 	require.NotNil(t, reader)
 
@@ -81,6 +104,24 @@ func TestHTTPHandler_MissingAirport_ShouldError(t *testing.T) {
 	require.Equal(t, "missing airport parameter\n", w.Body.String())
 }
 
+func TestHTTPHandler_InvalidDate_ShouldError(t *testing.T) {
+	reader, err := service.NewReader(&client.FlightAPI{}, &client.RouteAPI{}, &kafka.Writer{}, &kafka.NopDeadLetterWriter{})
+	require.NoError(t, err)
+	require.NotNil(t, reader)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/fetch?airport=VHHH&date=07-05-2025", nil)
+	w := httptest.NewRecorder()
+	reader.HTTPHandler(w, req)
+
+	resp := w.Result()
+	defer func() {
+		err := resp.Body.Close()
+		require.NoError(t, err)
+	}()
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	require.Equal(t, "invalid date parameter\n", w.Body.String())
+}
+
 func TestHTTPHandler_WorkingComponents_ShouldSucceed(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -88,18 +129,20 @@ func TestHTTPHandler_WorkingComponents_ShouldSucceed(t *testing.T) {
 	mRoutes := mock.NewMockRoute(ctrl)
 	mFlights := mock.NewMockFlight(ctrl)
 	mKafka := mock.NewMockMessageWriter(ctrl)
+	mDeadLetters := mock.NewMockDeadLetterWriter(ctrl)
 
 	flights := []model.Flight{
 		{Origin: "VHHH", Destination: "RJTT", Callsign: "CRK452", FirstSeen: 1, LastSeen: 2},
 	}
 
 	mFlights.EXPECT().FetchFlights(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(flights, nil)
-	mRoutes.EXPECT().FetchRoute(gomock.Any(), gomock.Any()).Return(&model.Route{}, nil)
-	mKafka.EXPECT().WriteMessage(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+	mRoutes.EXPECT().FetchRoute(gomock.Any(), gomock.Any()).Return(route, nil)
 	mKafka.EXPECT().WriteMessage(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+	mKafka.EXPECT().WriteMessages(gomock.Any(), gomock.Any()).Return(nil)
 	mKafka.EXPECT().WriteMessage(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+	mKafka.EXPECT().Flush(gomock.Any()).Return(nil)
 
-	reader, err := service.NewReader(mFlights, mRoutes, mKafka)
+	reader, err := service.NewReader(mFlights, mRoutes, mKafka, mDeadLetters)
 	require.NoError(t, err)
 	require.NotNil(t, reader)
 
@@ -116,6 +159,39 @@ func TestHTTPHandler_WorkingComponents_ShouldSucceed(t *testing.T) {
 	require.Contains(t, w.Body.String(), "flights processed successfully")
 }
 
+func TestHTTPHandler_NoDate_ShouldReadDayBeforeYesterday(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mRoutes := mock.NewMockRoute(ctrl)
+	mFlights := mock.NewMockFlight(ctrl)
+	mKafka := mock.NewMockMessageWriter(ctrl)
+	mDeadLetters := mock.NewMockDeadLetterWriter(ctrl)
+
+	// A Sunday is therefore summarized, and its week rolled up, on the Tuesday after it
+	date := time.Now().AddDate(0, 0, -2).Format("2006-01-02")
+
+	mFlights.EXPECT().FetchFlights(gomock.Any(), "VHHH", gomock.Any(), gomock.Any()).Return(nil, nil)
+	mKafka.EXPECT().WriteMessage(gomock.Any(), []byte("start_of_stream"), []byte("VHHH")).Return(nil)
+	mKafka.EXPECT().WriteMessage(gomock.Any(), []byte("end_of_stream"), []byte(date)).Return(nil)
+	mKafka.EXPECT().Flush(gomock.Any()).Return(nil)
+
+	reader, err := service.NewReader(mFlights, mRoutes, mKafka, mDeadLetters)
+	require.NoError(t, err)
+	require.NotNil(t, reader)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/fetch?airport=VHHH", nil)
+	w := httptest.NewRecorder()
+	reader.HTTPHandler(w, req)
+
+	resp := w.Result()
+	defer func() {
+		err := resp.Body.Close()
+		require.NoError(t, err)
+	}()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
 func TestHTTPHandler_FlightsClientError_ShouldError(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -124,7 +200,7 @@ func TestHTTPHandler_FlightsClientError_ShouldError(t *testing.T) {
 
 	mFlights.EXPECT().FetchFlights(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("error"))
 
-	reader, err := service.NewReader(mFlights, &client.RouteAPI{}, &kafka.Writer{})
+	reader, err := service.NewReader(mFlights, &client.RouteAPI{}, &kafka.Writer{}, &kafka.NopDeadLetterWriter{})
 	require.NoError(t, err)
 	require.NotNil(t, reader)
 
@@ -148,6 +224,7 @@ func TestHTTPHandler_EmptyCallSign_ShouldSucceed(t *testing.T) {
 	mRoutes := mock.NewMockRoute(ctrl)
 	mFlights := mock.NewMockFlight(ctrl)
 	mKafka := mock.NewMockMessageWriter(ctrl)
+	mDeadLetters := mock.NewMockDeadLetterWriter(ctrl)
 
 	flights := []model.Flight{
 		{Origin: "VHHH", Destination: "RJTT", Callsign: "", FirstSeen: 1, LastSeen: 2},
@@ -156,8 +233,9 @@ func TestHTTPHandler_EmptyCallSign_ShouldSucceed(t *testing.T) {
 	mFlights.EXPECT().FetchFlights(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(flights, nil)
 	mKafka.EXPECT().WriteMessage(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
 	mKafka.EXPECT().WriteMessage(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+	mKafka.EXPECT().Flush(gomock.Any()).Return(nil)
 
-	reader, err := service.NewReader(mFlights, mRoutes, mKafka)
+	reader, err := service.NewReader(mFlights, mRoutes, mKafka, mDeadLetters)
 	require.NoError(t, err)
 	require.NotNil(t, reader)
 
@@ -181,21 +259,34 @@ func TestHTTPHandler_RoutesClientError_ShouldSucceed(t *testing.T) {
 	mRoutes := mock.NewMockRoute(ctrl)
 	mFlights := mock.NewMockFlight(ctrl)
 	mKafka := mock.NewMockMessageWriter(ctrl)
+	mDeadLetters := mock.NewMockDeadLetterWriter(ctrl)
 
 	flights := []model.Flight{
 		{Origin: "VHHH", Destination: "RJTT", Callsign: "CRK452", FirstSeen: 1, LastSeen: 2},
 	}
 
 	mFlights.EXPECT().FetchFlights(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(flights, nil)
-	mRoutes.EXPECT().FetchRoute(gomock.Any(), gomock.Any()).Return(nil, errors.New("error"))
+	mRoutes.EXPECT().FetchRoute(gomock.Any(), gomock.Any()).Return(nil, unavailable)
+	mDeadLetters.EXPECT().WriteDeadLetter(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, message msgbus.Message, _ error) error {
+			// The flight is set aside along with its day stream
+			require.Equal(t, "CRK452", string(message.Key))
+			require.Equal(t, kafka.HeaderStreamAirport, message.Headers[0].Key)
+			require.Equal(t, "VHHH", string(message.Headers[0].Value))
+			require.Equal(t, kafka.HeaderStreamDate, message.Headers[1].Key)
+			require.Equal(t, "2025-05-07", string(message.Headers[1].Value))
+			return nil
+		},
+	)
 	mKafka.EXPECT().WriteMessage(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
 	mKafka.EXPECT().WriteMessage(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+	mKafka.EXPECT().Flush(gomock.Any()).Return(nil)
 
-	reader, err := service.NewReader(mFlights, mRoutes, mKafka)
+	reader, err := service.NewReader(mFlights, mRoutes, mKafka, mDeadLetters)
 	require.NoError(t, err)
 	require.NotNil(t, reader)
 
-	req := httptest.NewRequest(http.MethodGet, "/api/v1/fetch?airport=VHHH", nil)
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/fetch?airport=VHHH&date=2025-05-07", nil)
 	w := httptest.NewRecorder()
 	reader.HTTPHandler(w, req)
 
@@ -208,25 +299,132 @@ func TestHTTPHandler_RoutesClientError_ShouldSucceed(t *testing.T) {
 	require.Contains(t, w.Body.String(), "flights processed successfully")
 }
 
-func TestHTTPHandler_MessageWriterError_ShouldSucceed(t *testing.T) {
+func TestHTTPHandler_UnknownCallSign_ShouldDropFlight(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mRoutes := mock.NewMockRoute(ctrl)
 	mFlights := mock.NewMockFlight(ctrl)
 	mKafka := mock.NewMockMessageWriter(ctrl)
+	mDeadLetters := mock.NewMockDeadLetterWriter(ctrl)
 
 	flights := []model.Flight{
 		{Origin: "VHHH", Destination: "RJTT", Callsign: "CRK452", FirstSeen: 1, LastSeen: 2},
 	}
 
+	// The flight is not set aside, as replaying its day would not find its route either
 	mFlights.EXPECT().FetchFlights(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(flights, nil)
-	mRoutes.EXPECT().FetchRoute(gomock.Any(), gomock.Any()).Return(&model.Route{}, nil)
+	mRoutes.EXPECT().FetchRoute(gomock.Any(), gomock.Any()).Return(nil, errors.New("unexpected status code: 404"))
+	mKafka.EXPECT().WriteMessage(gomock.Any(), []byte("start_of_stream"), gomock.Any()).Return(nil)
+	mKafka.EXPECT().WriteMessage(gomock.Any(), []byte("end_of_stream"), gomock.Any()).Return(nil)
+	mKafka.EXPECT().Flush(gomock.Any()).Return(nil)
+
+	reader, err := service.NewReader(mFlights, mRoutes, mKafka, mDeadLetters)
+	require.NoError(t, err)
+	require.NotNil(t, reader)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/fetch?airport=VHHH", nil)
+	w := httptest.NewRecorder()
+	reader.HTTPHandler(w, req)
+
+	resp := w.Result()
+	defer func() {
+		err := resp.Body.Close()
+		require.NoError(t, err)
+	}()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestHTTPHandler_DeadLetterWriterError_ShouldError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mRoutes := mock.NewMockRoute(ctrl)
+	mFlights := mock.NewMockFlight(ctrl)
+	mKafka := mock.NewMockMessageWriter(ctrl)
+	mDeadLetters := mock.NewMockDeadLetterWriter(ctrl)
+
+	flights := []model.Flight{
+		{Origin: "VHHH", Destination: "RJTT", Callsign: "CRK452", FirstSeen: 1, LastSeen: 2},
+	}
+
+	mFlights.EXPECT().FetchFlights(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(flights, nil)
+	mRoutes.EXPECT().FetchRoute(gomock.Any(), gomock.Any()).Return(nil, unavailable)
+	mKafka.EXPECT().WriteMessage(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+	mDeadLetters.EXPECT().WriteDeadLetter(gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("error"))
+
+	reader, err := service.NewReader(mFlights, mRoutes, mKafka, mDeadLetters)
+	require.NoError(t, err)
+	require.NotNil(t, reader)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/fetch?airport=VHHH", nil)
+	w := httptest.NewRecorder()
+	reader.HTTPHandler(w, req)
+
+	resp := w.Result()
+	defer func() {
+		err := resp.Body.Close()
+		require.NoError(t, err)
+	}()
+	require.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+	require.Contains(t, w.Body.String(), "failed to write dead letter")
+}
+
+func TestHTTPHandler_MessageWriterError_ShouldError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mRoutes := mock.NewMockRoute(ctrl)
+	mFlights := mock.NewMockFlight(ctrl)
+	mKafka := mock.NewMockMessageWriter(ctrl)
+	mDeadLetters := mock.NewMockDeadLetterWriter(ctrl)
+
+	flights := []model.Flight{
+		{Origin: "VHHH", Destination: "RJTT", Callsign: "CRK452", FirstSeen: 1, LastSeen: 2},
+	}
+
+	mFlights.EXPECT().FetchFlights(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(flights, nil)
+	mRoutes.EXPECT().FetchRoute(gomock.Any(), gomock.Any()).Return(route, nil)
 	mKafka.EXPECT().WriteMessage(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
-	mKafka.EXPECT().WriteMessage(gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("error"))
+	mKafka.EXPECT().WriteMessages(gomock.Any(), gomock.Any()).Return(errors.New("error"))
+
+	reader, err := service.NewReader(mFlights, mRoutes, mKafka, mDeadLetters)
+	require.NoError(t, err)
+	require.NotNil(t, reader)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/fetch?airport=VHHH", nil)
+	w := httptest.NewRecorder()
+	reader.HTTPHandler(w, req)
+
+	resp := w.Result()
+	defer func() {
+		err := resp.Body.Close()
+		require.NoError(t, err)
+	}()
+	require.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+	require.Contains(t, w.Body.String(), "failed to process flights")
+}
+
+func TestHTTPHandler_EmptyRouteCallSign_ShouldSucceed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mRoutes := mock.NewMockRoute(ctrl)
+	mFlights := mock.NewMockFlight(ctrl)
+	mKafka := mock.NewMockMessageWriter(ctrl)
+	mDeadLetters := mock.NewMockDeadLetterWriter(ctrl)
+
+	flights := []model.Flight{
+		{Origin: "VHHH", Destination: "RJTT", Callsign: "CRK452", FirstSeen: 1, LastSeen: 2},
+	}
+
+	mFlights.EXPECT().FetchFlights(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(flights, nil)
+	mRoutes.EXPECT().FetchRoute(gomock.Any(), gomock.Any()).Return(&model.Route{}, nil)
 	mKafka.EXPECT().WriteMessage(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+	mKafka.EXPECT().WriteMessage(gomock.Any(), []byte("end_of_stream"), gomock.Any()).Return(nil)
+	mKafka.EXPECT().Flush(gomock.Any()).Return(nil)
 
-	reader, err := service.NewReader(mFlights, mRoutes, mKafka)
+	reader, err := service.NewReader(mFlights, mRoutes, mKafka, mDeadLetters)
 	require.NoError(t, err)
 	require.NotNil(t, reader)
 
@@ -243,6 +441,43 @@ func TestHTTPHandler_MessageWriterError_ShouldSucceed(t *testing.T) {
 	require.Contains(t, w.Body.String(), "flights processed successfully")
 }
 
+func TestHTTPHandler_MessageWriterFlushError_ShouldError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mRoutes := mock.NewMockRoute(ctrl)
+	mFlights := mock.NewMockFlight(ctrl)
+	mKafka := mock.NewMockMessageWriter(ctrl)
+	mDeadLetters := mock.NewMockDeadLetterWriter(ctrl)
+
+	flights := []model.Flight{
+		{Origin: "VHHH", Destination: "RJTT", Callsign: "CRK452", FirstSeen: 1, LastSeen: 2},
+	}
+
+	mFlights.EXPECT().FetchFlights(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(flights, nil)
+	mRoutes.EXPECT().FetchRoute(gomock.Any(), gomock.Any()).Return(route, nil)
+	mKafka.EXPECT().WriteMessage(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+	mKafka.EXPECT().WriteMessages(gomock.Any(), gomock.Any()).Return(nil)
+	mKafka.EXPECT().WriteMessage(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+	mKafka.EXPECT().Flush(gomock.Any()).Return(errors.New("error"))
+
+	reader, err := service.NewReader(mFlights, mRoutes, mKafka, mDeadLetters)
+	require.NoError(t, err)
+	require.NotNil(t, reader)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/fetch?airport=VHHH", nil)
+	w := httptest.NewRecorder()
+	reader.HTTPHandler(w, req)
+
+	resp := w.Result()
+	defer func() {
+		err := resp.Body.Close()
+		require.NoError(t, err)
+	}()
+	require.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+	require.Contains(t, w.Body.String(), "failed to flush messages to the message queue")
+}
+
 func TestHTTPHandler_RouteProcessContextCancellation_ShouldError(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -250,6 +485,7 @@ func TestHTTPHandler_RouteProcessContextCancellation_ShouldError(t *testing.T) {
 	mRoutes := mock.NewMockRoute(ctrl)
 	mFlights := mock.NewMockFlight(ctrl)
 	mKafka := mock.NewMockMessageWriter(ctrl)
+	mDeadLetters := mock.NewMockDeadLetterWriter(ctrl)
 
 	flights := []model.Flight{
 		{Origin: "VHHH", Destination: "RJTT", Callsign: "CRK452", FirstSeen: 1, LastSeen: 2},
@@ -259,7 +495,7 @@ func TestHTTPHandler_RouteProcessContextCancellation_ShouldError(t *testing.T) {
 	mRoutes.EXPECT().FetchRoute(gomock.Any(), gomock.Any()).Return(nil, context.Canceled)
 	mKafka.EXPECT().WriteMessage(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
 
-	reader, err := service.NewReader(mFlights, mRoutes, mKafka)
+	reader, err := service.NewReader(mFlights, mRoutes, mKafka, mDeadLetters)
 	require.NoError(t, err)
 	require.NotNil(t, reader)
 
@@ -283,17 +519,18 @@ func TestHTTPHandler_MessageProcessContextCancellation_ShouldError(t *testing.T)
 	mRoutes := mock.NewMockRoute(ctrl)
 	mFlights := mock.NewMockFlight(ctrl)
 	mKafka := mock.NewMockMessageWriter(ctrl)
+	mDeadLetters := mock.NewMockDeadLetterWriter(ctrl)
 
 	flights := []model.Flight{
 		{Origin: "VHHH", Destination: "RJTT", Callsign: "CRK452", FirstSeen: 1, LastSeen: 2},
 	}
 
 	mFlights.EXPECT().FetchFlights(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(flights, nil)
-	mRoutes.EXPECT().FetchRoute(gomock.Any(), gomock.Any()).Return(&model.Route{}, nil)
+	mRoutes.EXPECT().FetchRoute(gomock.Any(), gomock.Any()).Return(route, nil)
 	mKafka.EXPECT().WriteMessage(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
-	mKafka.EXPECT().WriteMessage(gomock.Any(), gomock.Any(), gomock.Any()).Return(context.Canceled)
+	mKafka.EXPECT().WriteMessages(gomock.Any(), gomock.Any()).Return(context.Canceled)
 
-	reader, err := service.NewReader(mFlights, mRoutes, mKafka)
+	reader, err := service.NewReader(mFlights, mRoutes, mKafka, mDeadLetters)
 	require.NoError(t, err)
 	require.NotNil(t, reader)
 
@@ -307,7 +544,7 @@ func TestHTTPHandler_MessageProcessContextCancellation_ShouldError(t *testing.T)
 		require.NoError(t, err)
 	}()
 	require.Equal(t, http.StatusInternalServerError, resp.StatusCode)
-	require.Contains(t, w.Body.String(), "failed to process at least one route")
+	require.Contains(t, w.Body.String(), "context canceled while sending flights and routes")
 }
 
 func TestClose_ValidAction_ShouldSucceed(t *testing.T) {
@@ -315,11 +552,64 @@ func TestClose_ValidAction_ShouldSucceed(t *testing.T) {
 	defer ctrl.Finish()
 
 	mKafka := mock.NewMockMessageWriter(ctrl)
+	mDeadLetters := mock.NewMockDeadLetterWriter(ctrl)
 
 	mKafka.EXPECT().Close().Return()
+	mDeadLetters.EXPECT().Close().Return()
 
-	reader, err := service.NewReader(&client.FlightAPI{}, &client.RouteAPI{}, mKafka)
+	reader, err := service.NewReader(&client.FlightAPI{}, &client.RouteAPI{}, mKafka, mDeadLetters)
 	require.NoError(t, err)
 	require.NotNil(t, reader)
 	defer reader.Close()
 }
+
+func TestHTTPHandler_RouteCountries_ShouldBeSent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mRoutes := mock.NewMockRoute(ctrl)
+	mFlights := mock.NewMockFlight(ctrl)
+	mKafka := mock.NewMockMessageWriter(ctrl)
+	mDeadLetters := mock.NewMockDeadLetterWriter(ctrl)
+
+	flights := []model.Flight{
+		{Origin: "VHHH", Destination: "RJTT", Callsign: "CRK452", FirstSeen: 1, LastSeen: 2},
+	}
+	route := &model.Route{Response: model.Response{FlightRoute: model.FlightRoute{
+		CallSignIATA: "UO452",
+		Origin:       model.Airport{IATACode: "HKG", CountryISOName: "HK"},
+		Destination:  model.Airport{IATACode: "HND", CountryISOName: "JP"},
+	}}}
+
+	mFlights.EXPECT().FetchFlights(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(flights, nil)
+	mRoutes.EXPECT().FetchRoute(gomock.Any(), gomock.Any()).Return(route, nil)
+	mKafka.EXPECT().WriteMessage(gomock.Any(), []byte("start_of_stream"), gomock.Any()).Return(nil)
+	mKafka.EXPECT().WriteMessages(gomock.Any(), gomock.Len(1)).DoAndReturn(
+		func(_ context.Context, msgs []msgbus.Message) error {
+			require.Equal(t, "UO452", string(msgs[0].Key))
+
+			var record msg.FlightRecord
+			require.NoError(t, json.Unmarshal(msgs[0].Value, &record))
+			require.Equal(t, "HK", record.OriginCountry)
+			require.Equal(t, "JP", record.DestinationCountry)
+			return nil
+		},
+	)
+	mKafka.EXPECT().WriteMessage(gomock.Any(), []byte("end_of_stream"), gomock.Any()).Return(nil)
+	mKafka.EXPECT().Flush(gomock.Any()).Return(nil)
+
+	reader, err := service.NewReader(mFlights, mRoutes, mKafka, mDeadLetters)
+	require.NoError(t, err)
+	require.NotNil(t, reader)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/fetch?airport=VHHH", nil)
+	w := httptest.NewRecorder()
+	reader.HTTPHandler(w, req)
+
+	resp := w.Result()
+	defer func() {
+		err := resp.Body.Close()
+		require.NoError(t, err)
+	}()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+}
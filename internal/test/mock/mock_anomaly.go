@@ -0,0 +1,57 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/processor/service/anomaly.go
+//
+// Generated by this command:
+//
+//	mockgen -source internal/processor/service/anomaly.go -destination=internal/test/mock/mock_anomaly.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	model "github.com/ansoncht/flight-microservices/pkg/model"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockDetector is a mock of Detector interface.
+type MockDetector struct {
+	ctrl     *gomock.Controller
+	recorder *MockDetectorMockRecorder
+	isgomock struct{}
+}
+
+// MockDetectorMockRecorder is the mock recorder for MockDetector.
+type MockDetectorMockRecorder struct {
+	mock *MockDetector
+}
+
+// NewMockDetector creates a new mock instance.
+func NewMockDetector(ctrl *gomock.Controller) *MockDetector {
+	mock := &MockDetector{ctrl: ctrl}
+	mock.recorder = &MockDetectorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDetector) EXPECT() *MockDetectorMockRecorder {
+	return m.recorder
+}
+
+// DetectAnomalies mocks base method.
+func (m *MockDetector) DetectAnomalies(ctx context.Context, summary model.DailyFlightSummary) ([]model.Anomaly, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DetectAnomalies", ctx, summary)
+	ret0, _ := ret[0].([]model.Anomaly)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DetectAnomalies indicates an expected call of DetectAnomalies.
+func (mr *MockDetectorMockRecorder) DetectAnomalies(ctx, summary any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DetectAnomalies", reflect.TypeOf((*MockDetector)(nil).DetectAnomalies), ctx, summary)
+}
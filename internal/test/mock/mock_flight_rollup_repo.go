@@ -0,0 +1,102 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: pkg/repository/flight_rollup.go
+//
+// Generated by this command:
+//
+//	mockgen -source pkg/repository/flight_rollup.go -destination=internal/test/mock/mock_flight_rollup_repo.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	model "github.com/ansoncht/flight-microservices/pkg/model"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRollupRepository is a mock of RollupRepository interface.
+type MockRollupRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRollupRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockRollupRepositoryMockRecorder is the mock recorder for MockRollupRepository.
+type MockRollupRepositoryMockRecorder struct {
+	mock *MockRollupRepository
+}
+
+// NewMockRollupRepository creates a new mock instance.
+func NewMockRollupRepository(ctrl *gomock.Controller) *MockRollupRepository {
+	mock := &MockRollupRepository{ctrl: ctrl}
+	mock.recorder = &MockRollupRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRollupRepository) EXPECT() *MockRollupRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetMonthly mocks base method.
+func (m *MockRollupRepository) GetMonthly(ctx context.Context, id string) (*model.MonthlyFlightSummary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMonthly", ctx, id)
+	ret0, _ := ret[0].(*model.MonthlyFlightSummary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMonthly indicates an expected call of GetMonthly.
+func (mr *MockRollupRepositoryMockRecorder) GetMonthly(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMonthly", reflect.TypeOf((*MockRollupRepository)(nil).GetMonthly), ctx, id)
+}
+
+// GetWeekly mocks base method.
+func (m *MockRollupRepository) GetWeekly(ctx context.Context, id string) (*model.WeeklyFlightSummary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWeekly", ctx, id)
+	ret0, _ := ret[0].(*model.WeeklyFlightSummary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWeekly indicates an expected call of GetWeekly.
+func (mr *MockRollupRepositoryMockRecorder) GetWeekly(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWeekly", reflect.TypeOf((*MockRollupRepository)(nil).GetWeekly), ctx, id)
+}
+
+// UpsertMonthly mocks base method.
+func (m *MockRollupRepository) UpsertMonthly(ctx context.Context, summary model.MonthlyFlightSummary) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertMonthly", ctx, summary)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertMonthly indicates an expected call of UpsertMonthly.
+func (mr *MockRollupRepositoryMockRecorder) UpsertMonthly(ctx, summary any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertMonthly", reflect.TypeOf((*MockRollupRepository)(nil).UpsertMonthly), ctx, summary)
+}
+
+// UpsertWeekly mocks base method.
+func (m *MockRollupRepository) UpsertWeekly(ctx context.Context, summary model.WeeklyFlightSummary) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertWeekly", ctx, summary)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertWeekly indicates an expected call of UpsertWeekly.
+func (mr *MockRollupRepositoryMockRecorder) UpsertWeekly(ctx, summary any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertWeekly", reflect.TypeOf((*MockRollupRepository)(nil).UpsertWeekly), ctx, summary)
+}
@@ -12,6 +12,7 @@ package mock
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	model "github.com/ansoncht/flight-microservices/pkg/model"
 	gomock "go.uber.org/mock/gomock"
@@ -70,3 +71,48 @@ func (mr *MockSummaryRepositoryMockRecorder) Insert(ctx, summary any) *gomock.Ca
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Insert", reflect.TypeOf((*MockSummaryRepository)(nil).Insert), ctx, summary)
 }
+
+// ListByDateRange mocks base method.
+func (m *MockSummaryRepository) ListByDateRange(ctx context.Context, airport string, from, to time.Time) ([]model.DailyFlightSummary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByDateRange", ctx, airport, from, to)
+	ret0, _ := ret[0].([]model.DailyFlightSummary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByDateRange indicates an expected call of ListByDateRange.
+func (mr *MockSummaryRepositoryMockRecorder) ListByDateRange(ctx, airport, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByDateRange", reflect.TypeOf((*MockSummaryRepository)(nil).ListByDateRange), ctx, airport, from, to)
+}
+
+// ListByRoute mocks base method.
+func (m *MockSummaryRepository) ListByRoute(ctx context.Context, airport, route string, from, to time.Time) ([]model.DailyFlightSummary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByRoute", ctx, airport, route, from, to)
+	ret0, _ := ret[0].([]model.DailyFlightSummary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByRoute indicates an expected call of ListByRoute.
+func (mr *MockSummaryRepositoryMockRecorder) ListByRoute(ctx, airport, route, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByRoute", reflect.TypeOf((*MockSummaryRepository)(nil).ListByRoute), ctx, airport, route, from, to)
+}
+
+// Upsert mocks base method.
+func (m *MockSummaryRepository) Upsert(ctx context.Context, summary model.DailyFlightSummary) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Upsert", ctx, summary)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Upsert indicates an expected call of Upsert.
+func (mr *MockSummaryRepositoryMockRecorder) Upsert(ctx, summary any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upsert", reflect.TypeOf((*MockSummaryRepository)(nil).Upsert), ctx, summary)
+}
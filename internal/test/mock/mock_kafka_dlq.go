@@ -0,0 +1,106 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: pkg/kafka/dlq.go
+//
+// Generated by this command:
+//
+//	mockgen -source pkg/kafka/dlq.go -destination=internal/test/mock/mock_kafka_dlq.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	msgbus "github.com/ansoncht/flight-microservices/pkg/msgbus"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockDeadLetterWriter is a mock of DeadLetterWriter interface.
+type MockDeadLetterWriter struct {
+	ctrl     *gomock.Controller
+	recorder *MockDeadLetterWriterMockRecorder
+	isgomock struct{}
+}
+
+// MockDeadLetterWriterMockRecorder is the mock recorder for MockDeadLetterWriter.
+type MockDeadLetterWriterMockRecorder struct {
+	mock *MockDeadLetterWriter
+}
+
+// NewMockDeadLetterWriter creates a new mock instance.
+func NewMockDeadLetterWriter(ctrl *gomock.Controller) *MockDeadLetterWriter {
+	mock := &MockDeadLetterWriter{ctrl: ctrl}
+	mock.recorder = &MockDeadLetterWriterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDeadLetterWriter) EXPECT() *MockDeadLetterWriterMockRecorder {
+	return m.recorder
+}
+
+// Close mocks base method.
+func (m *MockDeadLetterWriter) Close() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Close")
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockDeadLetterWriterMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockDeadLetterWriter)(nil).Close))
+}
+
+// WriteDeadLetter mocks base method.
+func (m *MockDeadLetterWriter) WriteDeadLetter(ctx context.Context, msg msgbus.Message, reason error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WriteDeadLetter", ctx, msg, reason)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WriteDeadLetter indicates an expected call of WriteDeadLetter.
+func (mr *MockDeadLetterWriterMockRecorder) WriteDeadLetter(ctx, msg, reason any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteDeadLetter", reflect.TypeOf((*MockDeadLetterWriter)(nil).WriteDeadLetter), ctx, msg, reason)
+}
+
+// MockResummarizer is a mock of Resummarizer interface.
+type MockResummarizer struct {
+	ctrl     *gomock.Controller
+	recorder *MockResummarizerMockRecorder
+	isgomock struct{}
+}
+
+// MockResummarizerMockRecorder is the mock recorder for MockResummarizer.
+type MockResummarizerMockRecorder struct {
+	mock *MockResummarizer
+}
+
+// NewMockResummarizer creates a new mock instance.
+func NewMockResummarizer(ctrl *gomock.Controller) *MockResummarizer {
+	mock := &MockResummarizer{ctrl: ctrl}
+	mock.recorder = &MockResummarizerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockResummarizer) EXPECT() *MockResummarizerMockRecorder {
+	return m.recorder
+}
+
+// Resummarize mocks base method.
+func (m *MockResummarizer) Resummarize(ctx context.Context, airport, date string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Resummarize", ctx, airport, date)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Resummarize indicates an expected call of Resummarize.
+func (mr *MockResummarizerMockRecorder) Resummarize(ctx, airport, date any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Resummarize", reflect.TypeOf((*MockResummarizer)(nil).Resummarize), ctx, airport, date)
+}
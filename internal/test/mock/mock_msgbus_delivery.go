@@ -0,0 +1,65 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: pkg/msgbus/delivery.go
+//
+// Generated by this command:
+//
+//	mockgen -source pkg/msgbus/delivery.go -destination=internal/test/mock/mock_msgbus_delivery.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	reflect "reflect"
+
+	msgbus "github.com/ansoncht/flight-microservices/pkg/msgbus"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockAcknowledger is a mock of Acknowledger interface.
+type MockAcknowledger struct {
+	ctrl     *gomock.Controller
+	recorder *MockAcknowledgerMockRecorder
+	isgomock struct{}
+}
+
+// MockAcknowledgerMockRecorder is the mock recorder for MockAcknowledger.
+type MockAcknowledgerMockRecorder struct {
+	mock *MockAcknowledger
+}
+
+// NewMockAcknowledger creates a new mock instance.
+func NewMockAcknowledger(ctrl *gomock.Controller) *MockAcknowledger {
+	mock := &MockAcknowledger{ctrl: ctrl}
+	mock.recorder = &MockAcknowledgerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAcknowledger) EXPECT() *MockAcknowledgerMockRecorder {
+	return m.recorder
+}
+
+// Ack mocks base method.
+func (m *MockAcknowledger) Ack(msg msgbus.Message) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Ack", msg)
+}
+
+// Ack indicates an expected call of Ack.
+func (mr *MockAcknowledgerMockRecorder) Ack(msg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ack", reflect.TypeOf((*MockAcknowledger)(nil).Ack), msg)
+}
+
+// Nack mocks base method.
+func (m *MockAcknowledger) Nack(msg msgbus.Message, err error) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Nack", msg, err)
+}
+
+// Nack indicates an expected call of Nack.
+func (mr *MockAcknowledgerMockRecorder) Nack(msg, err any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Nack", reflect.TypeOf((*MockAcknowledger)(nil).Nack), msg, err)
+}
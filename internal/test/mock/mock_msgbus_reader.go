@@ -1,9 +1,9 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: pkg/kafka/reader.go
+// Source: pkg/msgbus/reader.go
 //
 // Generated by this command:
 //
-//	mockgen -source pkg/kafka/reader.go -destination=internal/test/mock/mock_kafka_reader.go -package=mock
+//	mockgen -source pkg/msgbus/reader.go -destination=internal/test/mock/mock_msgbus_reader.go -package=mock
 //
 
 // Package mock is a generated GoMock package.
@@ -13,7 +13,7 @@ import (
 	context "context"
 	reflect "reflect"
 
-	kgo "github.com/twmb/franz-go/pkg/kgo"
+	msgbus "github.com/ansoncht/flight-microservices/pkg/msgbus"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -41,6 +41,20 @@ func (m *MockMessageReader) EXPECT() *MockMessageReaderMockRecorder {
 	return m.recorder
 }
 
+// Check mocks base method.
+func (m *MockMessageReader) Check(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Check", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Check indicates an expected call of Check.
+func (mr *MockMessageReaderMockRecorder) Check(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Check", reflect.TypeOf((*MockMessageReader)(nil).Check), ctx)
+}
+
 // Close mocks base method.
 func (m *MockMessageReader) Close() {
 	m.ctrl.T.Helper()
@@ -54,7 +68,7 @@ func (mr *MockMessageReaderMockRecorder) Close() *gomock.Call {
 }
 
 // ReadMessages mocks base method.
-func (m *MockMessageReader) ReadMessages(ctx context.Context, msgChan chan<- kgo.Record) error {
+func (m *MockMessageReader) ReadMessages(ctx context.Context, msgChan chan<- msgbus.Delivery) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "ReadMessages", ctx, msgChan)
 	ret0, _ := ret[0].(error)
@@ -1,9 +1,9 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: pkg/kafka/writer.go
+// Source: pkg/msgbus/writer.go
 //
 // Generated by this command:
 //
-//	mockgen -source pkg/kafka/writer.go -destination=internal/test/mock/mock_kafka_writer.go -package=mock
+//	mockgen -source pkg/msgbus/writer.go -destination=internal/test/mock/mock_msgbus_writer.go -package=mock
 //
 
 // Package mock is a generated GoMock package.
@@ -13,6 +13,7 @@ import (
 	context "context"
 	reflect "reflect"
 
+	msgbus "github.com/ansoncht/flight-microservices/pkg/msgbus"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -40,6 +41,20 @@ func (m *MockMessageWriter) EXPECT() *MockMessageWriterMockRecorder {
 	return m.recorder
 }
 
+// Check mocks base method.
+func (m *MockMessageWriter) Check(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Check", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Check indicates an expected call of Check.
+func (mr *MockMessageWriterMockRecorder) Check(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Check", reflect.TypeOf((*MockMessageWriter)(nil).Check), ctx)
+}
+
 // Close mocks base method.
 func (m *MockMessageWriter) Close() {
 	m.ctrl.T.Helper()
@@ -52,6 +67,20 @@ func (mr *MockMessageWriterMockRecorder) Close() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockMessageWriter)(nil).Close))
 }
 
+// Flush mocks base method.
+func (m *MockMessageWriter) Flush(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Flush", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Flush indicates an expected call of Flush.
+func (mr *MockMessageWriterMockRecorder) Flush(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Flush", reflect.TypeOf((*MockMessageWriter)(nil).Flush), ctx)
+}
+
 // WriteMessage mocks base method.
 func (m *MockMessageWriter) WriteMessage(ctx context.Context, key, value []byte) error {
 	m.ctrl.T.Helper()
@@ -65,3 +94,17 @@ func (mr *MockMessageWriterMockRecorder) WriteMessage(ctx, key, value any) *gomo
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteMessage", reflect.TypeOf((*MockMessageWriter)(nil).WriteMessage), ctx, key, value)
 }
+
+// WriteMessages mocks base method.
+func (m *MockMessageWriter) WriteMessages(ctx context.Context, msgs []msgbus.Message) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WriteMessages", ctx, msgs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WriteMessages indicates an expected call of WriteMessages.
+func (mr *MockMessageWriterMockRecorder) WriteMessages(ctx, msgs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteMessages", reflect.TypeOf((*MockMessageWriter)(nil).WriteMessages), ctx, msgs)
+}
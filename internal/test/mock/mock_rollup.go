@@ -0,0 +1,72 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/processor/service/rollup.go
+//
+// Generated by this command:
+//
+//	mockgen -source internal/processor/service/rollup.go -destination=internal/test/mock/mock_rollup.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRoller is a mock of Roller interface.
+type MockRoller struct {
+	ctrl     *gomock.Controller
+	recorder *MockRollerMockRecorder
+	isgomock struct{}
+}
+
+// MockRollerMockRecorder is the mock recorder for MockRoller.
+type MockRollerMockRecorder struct {
+	mock *MockRoller
+}
+
+// NewMockRoller creates a new mock instance.
+func NewMockRoller(ctrl *gomock.Controller) *MockRoller {
+	mock := &MockRoller{ctrl: ctrl}
+	mock.recorder = &MockRollerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRoller) EXPECT() *MockRollerMockRecorder {
+	return m.recorder
+}
+
+// RollupMonth mocks base method.
+func (m *MockRoller) RollupMonth(ctx context.Context, airport string, end time.Time) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RollupMonth", ctx, airport, end)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RollupMonth indicates an expected call of RollupMonth.
+func (mr *MockRollerMockRecorder) RollupMonth(ctx, airport, end any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RollupMonth", reflect.TypeOf((*MockRoller)(nil).RollupMonth), ctx, airport, end)
+}
+
+// RollupWeek mocks base method.
+func (m *MockRoller) RollupWeek(ctx context.Context, airport string, end time.Time) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RollupWeek", ctx, airport, end)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RollupWeek indicates an expected call of RollupWeek.
+func (mr *MockRollerMockRecorder) RollupWeek(ctx, airport, end any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RollupWeek", reflect.TypeOf((*MockRoller)(nil).RollupWeek), ctx, airport, end)
+}
@@ -0,0 +1,146 @@
+package bus
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ansoncht/flight-microservices/pkg/kafka"
+	"github.com/ansoncht/flight-microservices/pkg/msgbus"
+	"github.com/ansoncht/flight-microservices/pkg/nats"
+	"github.com/ansoncht/flight-microservices/pkg/redis"
+)
+
+const (
+	// DriverKafka selects the Kafka message bus.
+	DriverKafka = "kafka"
+	// DriverNATS selects the NATS JetStream message bus.
+	DriverNATS = "nats"
+	// DriverRedis selects the Redis Streams message bus.
+	DriverRedis = "redis"
+)
+
+// Config holds configuration settings for the message bus.
+type Config struct {
+	// Driver specifies the message bus backend, either "kafka", "nats" or "redis".
+	Driver string `mapstructure:"driver"`
+}
+
+// ReaderConfigs holds the reader configurations of each message bus driver.
+type ReaderConfigs struct {
+	// Kafka specifies the Kafka reader configuration.
+	Kafka kafka.ReaderConfig
+	// NATS specifies the NATS JetStream reader configuration.
+	NATS nats.ReaderConfig
+	// Redis specifies the Redis Streams reader configuration.
+	Redis redis.ReaderConfig
+}
+
+// WriterConfigs holds the writer configurations of each message bus driver.
+type WriterConfigs struct {
+	// Kafka specifies the Kafka writer configuration.
+	Kafka kafka.WriterConfig
+	// NATS specifies the NATS JetStream writer configuration.
+	NATS nats.WriterConfig
+	// Redis specifies the Redis Streams writer configuration.
+	Redis redis.WriterConfig
+}
+
+// NewMessageReader creates the message reader of the configured driver.
+func NewMessageReader(cfg Config, readerCfgs ReaderConfigs) (msgbus.MessageReader, error) {
+	switch cfg.Driver {
+	case DriverKafka:
+		reader, err := kafka.NewKafkaReader(readerCfgs.Kafka)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create kafka reader: %w", err)
+		}
+
+		return reader, nil
+	case DriverNATS:
+		reader, err := nats.NewJetStreamReader(readerCfgs.NATS)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create nats reader: %w", err)
+		}
+
+		return reader, nil
+	case DriverRedis:
+		reader, err := redis.NewStreamReader(readerCfgs.Redis)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create redis reader: %w", err)
+		}
+
+		return reader, nil
+	default:
+		return nil, fmt.Errorf("message bus driver is invalid: %s", cfg.Driver)
+	}
+}
+
+// NewMessageWriter creates the message writer of the configured driver.
+func NewMessageWriter(cfg Config, writerCfgs WriterConfigs) (msgbus.MessageWriter, error) {
+	switch cfg.Driver {
+	case DriverKafka:
+		writer, err := kafka.NewKafkaWriter(writerCfgs.Kafka)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create kafka writer: %w", err)
+		}
+
+		return writer, nil
+	case DriverNATS:
+		writer, err := nats.NewJetStreamWriter(writerCfgs.NATS)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create nats writer: %w", err)
+		}
+
+		return writer, nil
+	case DriverRedis:
+		writer, err := redis.NewStreamWriter(writerCfgs.Redis)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create redis writer: %w", err)
+		}
+
+		return writer, nil
+	default:
+		return nil, fmt.Errorf("message bus driver is invalid: %s", cfg.Driver)
+	}
+}
+
+// NewDeadLetterWriter creates the dead-letter writer of the configured driver. Failed records are dropped when the
+// dead-letter queue is disabled, so that a service on a driver other than Kafka needs no Kafka broker.
+func NewDeadLetterWriter(cfg Config, dlqCfg kafka.DLQConfig) (kafka.DeadLetterWriter, error) {
+	if !dlqCfg.Enabled {
+		return &kafka.NopDeadLetterWriter{}, nil
+	}
+
+	if cfg.Driver != DriverKafka {
+		return nil, fmt.Errorf("dead-letter queue requires the kafka message bus driver: %s", cfg.Driver)
+	}
+
+	writer, err := kafka.NewDLQWriter(dlqCfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kafka dead-letter writer: %w", err)
+	}
+
+	return writer, nil
+}
+
+// ReplayDeadLetters replays the dead letters of the configured driver and returns how many were replayed.
+func ReplayDeadLetters(
+	ctx context.Context,
+	cfg Config,
+	dlqCfg kafka.DLQConfig,
+	resummarizer kafka.Resummarizer,
+) (int, error) {
+	if !dlqCfg.Enabled {
+		return 0, fmt.Errorf("dead-letter queue is disabled")
+	}
+
+	if cfg.Driver != DriverKafka {
+		return 0, fmt.Errorf("dead-letter queue requires the kafka message bus driver: %s", cfg.Driver)
+	}
+
+	replayed, err := kafka.ReplayDeadLetters(ctx, dlqCfg, resummarizer)
+	if err != nil {
+		return replayed, fmt.Errorf("failed to replay kafka dead letters: %w", err)
+	}
+
+	return replayed, nil
+}
@@ -0,0 +1,133 @@
+package bus_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ansoncht/flight-microservices/pkg/bus"
+	"github.com/ansoncht/flight-microservices/pkg/kafka"
+	"github.com/ansoncht/flight-microservices/pkg/msgbus"
+	"github.com/ansoncht/flight-microservices/pkg/nats"
+	"github.com/ansoncht/flight-microservices/pkg/redis"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	readerCfgs = bus.ReaderConfigs{
+		Kafka: kafka.ReaderConfig{Address: "localhost:9092", Topic: "flights", GroupID: "group"},
+		NATS: nats.ReaderConfig{
+			URL:      "nats://localhost:4222",
+			Stream:   "FLIGHTS",
+			Subject:  "flights",
+			Consumer: "consumer",
+		},
+		Redis: redis.ReaderConfig{Address: "localhost:6379", Stream: "flights", Group: "group", Consumer: "consumer"},
+	}
+	writerCfgs = bus.WriterConfigs{
+		Kafka: kafka.WriterConfig{Address: "localhost:9092", Topic: "flights"},
+		NATS:  nats.WriterConfig{URL: "nats://localhost:4222", Stream: "FLIGHTS", Subject: "flights"},
+		Redis: redis.WriterConfig{Address: "localhost:6379", Stream: "flights"},
+	}
+)
+
+func TestNewMessageReader_Driver_ShouldSelectImplementation(t *testing.T) {
+	tests := []struct {
+		driver string
+		want   msgbus.MessageReader
+	}{
+		{driver: bus.DriverKafka, want: &kafka.Reader{}},
+		{driver: bus.DriverNATS, want: &nats.Reader{}},
+		{driver: bus.DriverRedis, want: &redis.Reader{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.driver, func(t *testing.T) {
+			reader, err := bus.NewMessageReader(bus.Config{Driver: tt.driver}, readerCfgs)
+			require.NoError(t, err)
+			require.IsType(t, tt.want, reader)
+			reader.Close()
+		})
+	}
+}
+
+func TestNewMessageWriter_Driver_ShouldSelectImplementation(t *testing.T) {
+	tests := []struct {
+		driver string
+		want   msgbus.MessageWriter
+	}{
+		{driver: bus.DriverKafka, want: &kafka.Writer{}},
+		{driver: bus.DriverNATS, want: &nats.Writer{}},
+		{driver: bus.DriverRedis, want: &redis.Writer{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.driver, func(t *testing.T) {
+			writer, err := bus.NewMessageWriter(bus.Config{Driver: tt.driver}, writerCfgs)
+			require.NoError(t, err)
+			require.IsType(t, tt.want, writer)
+			writer.Close()
+		})
+	}
+}
+
+func TestNewMessageBus_InvalidConfig_ShouldError(t *testing.T) {
+	t.Run("Invalid Reader Driver", func(t *testing.T) {
+		reader, err := bus.NewMessageReader(bus.Config{Driver: "rabbitmq"}, readerCfgs)
+		require.ErrorContains(t, err, "message bus driver is invalid: rabbitmq")
+		require.Nil(t, reader)
+	})
+
+	t.Run("Invalid Writer Driver", func(t *testing.T) {
+		writer, err := bus.NewMessageWriter(bus.Config{Driver: ""}, writerCfgs)
+		require.ErrorContains(t, err, "message bus driver is invalid")
+		require.Nil(t, writer)
+	})
+
+	t.Run("Invalid Driver Config", func(t *testing.T) {
+		cfgs := readerCfgs
+		cfgs.Redis.Stream = ""
+		reader, err := bus.NewMessageReader(bus.Config{Driver: bus.DriverRedis}, cfgs)
+		require.ErrorContains(t, err, "failed to create redis reader: redis stream is empty")
+		require.Nil(t, reader)
+	})
+}
+
+func TestNewDeadLetterWriter_Driver_ShouldSelectImplementation(t *testing.T) {
+	dlqCfg := kafka.DLQConfig{Enabled: true, Address: "localhost:9092", Topic: "flights-dlq"}
+
+	t.Run("Kafka Driver", func(t *testing.T) {
+		writer, err := bus.NewDeadLetterWriter(bus.Config{Driver: bus.DriverKafka}, dlqCfg)
+		require.NoError(t, err)
+		require.IsType(t, &kafka.DLQWriter{}, writer)
+		writer.Close()
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		writer, err := bus.NewDeadLetterWriter(bus.Config{Driver: bus.DriverNATS}, kafka.DLQConfig{})
+		require.NoError(t, err)
+		require.IsType(t, &kafka.NopDeadLetterWriter{}, writer)
+	})
+
+	t.Run("Enabled Without Kafka Driver", func(t *testing.T) {
+		writer, err := bus.NewDeadLetterWriter(bus.Config{Driver: bus.DriverRedis}, dlqCfg)
+		require.ErrorContains(t, err, "dead-letter queue requires the kafka message bus driver: redis")
+		require.Nil(t, writer)
+	})
+}
+
+func TestReplayDeadLetters_InvalidConfig_ShouldError(t *testing.T) {
+	t.Run("Disabled", func(t *testing.T) {
+		replayed, err := bus.ReplayDeadLetters(
+			context.Background(), bus.Config{Driver: bus.DriverKafka}, kafka.DLQConfig{}, nil,
+		)
+		require.ErrorContains(t, err, "dead-letter queue is disabled")
+		require.Zero(t, replayed)
+	})
+
+	t.Run("Enabled Without Kafka Driver", func(t *testing.T) {
+		dlqCfg := kafka.DLQConfig{Enabled: true, Address: "localhost:9092", Topic: "flights-dlq"}
+		replayed, err := bus.ReplayDeadLetters(context.Background(), bus.Config{Driver: bus.DriverNATS}, dlqCfg, nil)
+		require.ErrorContains(t, err, "dead-letter queue requires the kafka message bus driver: nats")
+		require.Zero(t, replayed)
+	})
+}
@@ -0,0 +1,86 @@
+package geo
+
+import (
+	"bytes"
+	_ "embed" // embed the country table
+	"encoding/csv"
+	"fmt"
+	"strings"
+)
+
+// UnknownRegion specifies the region of countries missing from the country table.
+const UnknownRegion = "Unknown"
+
+//go:embed countries.csv
+var countriesCSV []byte
+
+// Country holds the name and region of an ISO 3166-1 alpha-2 country code.
+type Country struct {
+	// ISO specifies the ISO 3166-1 alpha-2 code.
+	ISO string
+	// Name specifies the common English name.
+	Name string
+	// Region specifies the continent, e.g. "Asia" or "North America".
+	Region string
+}
+
+// countries maps each upper-cased ISO code to its country, parsed once from the embedded table.
+var countries = mustParseCountries(countriesCSV)
+
+// LookupCountry returns the country of an ISO 3166-1 alpha-2 code, reporting whether it is known.
+func LookupCountry(iso string) (Country, bool) {
+	country, ok := countries[strings.ToUpper(strings.TrimSpace(iso))]
+	return country, ok
+}
+
+// Region returns the continent of an ISO 3166-1 alpha-2 code, or UnknownRegion when it is not known.
+func Region(iso string) string {
+	if country, ok := LookupCountry(iso); ok {
+		return country.Region
+	}
+
+	return UnknownRegion
+}
+
+// CountryName returns the name of an ISO 3166-1 alpha-2 code, or the code itself when it is not known.
+func CountryName(iso string) string {
+	if country, ok := LookupCountry(iso); ok {
+		return country.Name
+	}
+
+	return iso
+}
+
+// mustParseCountries parses the country table and panics if the embedded file is malformed.
+func mustParseCountries(data []byte) map[string]Country {
+	parsed, err := parseCountries(data)
+	if err != nil {
+		panic(err)
+	}
+
+	return parsed
+}
+
+// parseCountries parses a CSV table with an iso,name,region header into countries keyed by ISO code.
+func parseCountries(data []byte) (map[string]Country, error) {
+	rows, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read country table: %w", err)
+	}
+
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("country table is empty")
+	}
+
+	parsed := make(map[string]Country, len(rows)-1)
+	for _, row := range rows[1:] {
+		country := Country{ISO: row[0], Name: row[1], Region: row[2]}
+		if _, ok := parsed[country.ISO]; ok {
+			return nil, fmt.Errorf("country %s is duplicated", country.ISO)
+		}
+
+		parsed[country.ISO] = country
+	}
+
+	return parsed, nil
+}
@@ -0,0 +1,41 @@
+package geo_test
+
+import (
+	"testing"
+
+	"github.com/ansoncht/flight-microservices/pkg/geo"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLookupCountry_KnownCode_ShouldSucceed(t *testing.T) {
+	testCases := []struct {
+		iso      string
+		expected geo.Country
+	}{
+		{iso: "HK", expected: geo.Country{ISO: "HK", Name: "Hong Kong", Region: "Asia"}},
+		{iso: "jp", expected: geo.Country{ISO: "JP", Name: "Japan", Region: "Asia"}},
+		{iso: " US ", expected: geo.Country{ISO: "US", Name: "United States", Region: "North America"}},
+		{iso: "BR", expected: geo.Country{ISO: "BR", Name: "Brazil", Region: "South America"}},
+		{iso: "FR", expected: geo.Country{ISO: "FR", Name: "France", Region: "Europe"}},
+		{iso: "KE", expected: geo.Country{ISO: "KE", Name: "Kenya", Region: "Africa"}},
+		{iso: "NZ", expected: geo.Country{ISO: "NZ", Name: "New Zealand", Region: "Oceania"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.iso, func(t *testing.T) {
+			country, ok := geo.LookupCountry(tc.iso)
+			require.True(t, ok)
+			require.Equal(t, tc.expected, country)
+			require.Equal(t, tc.expected.Region, geo.Region(tc.iso))
+			require.Equal(t, tc.expected.Name, geo.CountryName(tc.iso))
+		})
+	}
+}
+
+func TestLookupCountry_UnknownCode_ShouldFallBack(t *testing.T) {
+	country, ok := geo.LookupCountry("ZZ")
+	require.False(t, ok)
+	require.Empty(t, country)
+	require.Equal(t, geo.UnknownRegion, geo.Region("ZZ"))
+	require.Equal(t, "ZZ", geo.CountryName("ZZ"))
+}
@@ -5,6 +5,8 @@ import (
 	"log/slog"
 	"net/http"
 	"time"
+
+	"github.com/ansoncht/flight-microservices/pkg/metrics"
 )
 
 // ClientConfig holds configuration settings for the HTTP client.
@@ -14,6 +16,7 @@ type ClientConfig struct {
 }
 
 // NewClient creates a new http client based on the provided configuration.
+// The client observes the latency and response status of its requests to upstream APIs.
 func NewClient(cfg ClientConfig) (*http.Client, error) {
 	slog.Info("Initializing HTTP client for the service", "timeout", cfg.Timeout)
 
@@ -23,6 +26,7 @@ func NewClient(cfg ClientConfig) (*http.Client, error) {
 	}
 
 	return &http.Client{
-		Timeout: time.Duration(cfg.Timeout) * time.Second,
+		Timeout:   time.Duration(cfg.Timeout) * time.Second,
+		Transport: metrics.NewTransport(http.DefaultTransport),
 	}, nil
 }
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/ansoncht/flight-microservices/pkg/http"
+	"github.com/ansoncht/flight-microservices/pkg/metrics"
 	"github.com/stretchr/testify/require"
 )
 
@@ -14,6 +15,7 @@ func TestNewHTTPClient_ValidTimeout_ShouldSucceed(t *testing.T) {
 	require.NoError(t, err)
 	require.NotNil(t, client)
 	require.Equal(t, time.Duration(cfg.Timeout)*time.Second, client.Timeout)
+	require.IsType(t, &metrics.Transport{}, client.Transport)
 }
 
 func TestNewHTTPClient_InvalidTimeout_ShouldError(t *testing.T) {
@@ -0,0 +1,200 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// HealthPath specifies the path of the liveness probe.
+	HealthPath = "/healthz"
+	// ReadyPath specifies the path of the readiness probe.
+	ReadyPath = "/readyz"
+)
+
+// Statuses reported by the health probes.
+const (
+	StatusOK          = "ok"
+	StatusUnavailable = "unavailable"
+)
+
+// Checker defines the interface for checking whether a dependency of the service is available.
+type Checker interface {
+	// Check returns an error if the dependency is unavailable.
+	Check(ctx context.Context) error
+}
+
+// CheckerFunc adapts an ordinary function to the Checker interface.
+type CheckerFunc func(ctx context.Context) error
+
+// Check calls f(ctx).
+func (f CheckerFunc) Check(ctx context.Context) error {
+	return f(ctx)
+}
+
+// CheckResult holds the status of a dependency.
+type CheckResult struct {
+	// Status specifies whether the dependency is available.
+	Status string `json:"status"`
+	// Error specifies why the dependency is unavailable.
+	Error string `json:"error,omitempty"`
+}
+
+// HealthReport holds the status of the service and of each of its dependencies.
+type HealthReport struct {
+	// Status specifies whether the service is available.
+	Status string `json:"status"`
+	// Checks specifies the status of each dependency by name.
+	Checks map[string]CheckResult `json:"checks,omitempty"`
+}
+
+// Health holds the checkers of the dependencies of the service.
+// It serves the liveness probe, which reports the process is up, and the readiness probe, which reports whether
+// every dependency is available.
+type Health struct {
+	// checkers specifies the checker of each dependency by name.
+	checkers map[string]Checker
+	// timeout specifies how long the readiness probe waits for the checkers.
+	timeout time.Duration
+}
+
+// NewHealth creates a new Health instance based on the provided checkers and timeout in seconds.
+func NewHealth(checkers map[string]Checker, timeout int) (*Health, error) {
+	if timeout <= 0 {
+		return nil, fmt.Errorf("health check timeout is invalid: %d", timeout)
+	}
+
+	for name, checker := range checkers {
+		if checker == nil {
+			return nil, fmt.Errorf("checker of %s is nil", name)
+		}
+	}
+
+	return &Health{
+		checkers: checkers,
+		timeout:  time.Duration(timeout) * time.Second,
+	}, nil
+}
+
+// Register serves the liveness and readiness probes on the provided mux.
+func (h *Health) Register(mux *http.ServeMux) {
+	mux.HandleFunc(HealthPath, h.LivenessHandler)
+	mux.HandleFunc(ReadyPath, h.ReadinessHandler)
+}
+
+// LivenessHandler reports the service is up.
+func (h *Health) LivenessHandler(w http.ResponseWriter, _ *http.Request) {
+	writeReport(w, http.StatusOK, HealthReport{Status: StatusOK})
+}
+
+// ReadinessHandler reports the status of each dependency, responding with 503 if any of them is unavailable.
+func (h *Health) ReadinessHandler(w http.ResponseWriter, req *http.Request) {
+	report := h.Check(req.Context())
+
+	code := http.StatusOK
+	if report.Status != StatusOK {
+		code = http.StatusServiceUnavailable
+	}
+
+	writeReport(w, code, report)
+}
+
+// Check runs the checkers concurrently and reports the status of each dependency.
+func (h *Health) Check(ctx context.Context) HealthReport {
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	report := HealthReport{
+		Status: StatusOK,
+		Checks: make(map[string]CheckResult, len(h.checkers)),
+	}
+
+	for name, checker := range h.checkers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			result := CheckResult{Status: StatusOK}
+			if err := checker.Check(ctx); err != nil {
+				slog.Warn("Dependency is unavailable", "dependency", name, "error", err)
+				result = CheckResult{Status: StatusUnavailable, Error: err.Error()}
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			report.Checks[name] = result
+			if result.Status != StatusOK {
+				report.Status = StatusUnavailable
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	return report
+}
+
+// writeReport writes the health report as JSON with the given status code.
+func writeReport(w http.ResponseWriter, code int, report HealthReport) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+
+	if err := json.NewEncoder(w).Encode(report); err != nil {
+		slog.Error("Failed to write health report", "error", err)
+	}
+}
+
+// EndpointChecker holds an HTTP client checking whether an upstream API is reachable.
+// It implements the Checker interface, treating any response below 500 as reachable since upstream APIs may
+// reject requests without credentials or parameters.
+type EndpointChecker struct {
+	// client specifies the HTTP client sending the requests.
+	client *http.Client
+	// url specifies the URL of the upstream API.
+	url string
+}
+
+// NewEndpointChecker creates a new EndpointChecker instance based on the provided HTTP client and URL.
+func NewEndpointChecker(client *http.Client, url string) (*EndpointChecker, error) {
+	if client == nil {
+		return nil, fmt.Errorf("http client is nil")
+	}
+
+	if url == "" {
+		return nil, fmt.Errorf("endpoint url is empty")
+	}
+
+	return &EndpointChecker{
+		client: client,
+		url:    url,
+	}, nil
+}
+
+// Check sends a HEAD request to the upstream API and returns an error if it cannot be reached or fails.
+func (c *EndpointChecker) Check(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, c.url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach upstream: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("upstream %s responded with status %d", req.URL.Host, resp.StatusCode)
+	}
+
+	return nil
+}
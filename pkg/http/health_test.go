@@ -0,0 +1,190 @@
+package http_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	server "github.com/ansoncht/flight-microservices/pkg/http"
+	"github.com/stretchr/testify/require"
+)
+
+// available is a checker of a dependency which is always available.
+var available = server.CheckerFunc(func(context.Context) error {
+	return nil
+})
+
+// probe serves the request on a mux with the health probes of the checkers registered and decodes the report.
+func probe(t *testing.T, checkers map[string]server.Checker, path string) (int, server.HealthReport) {
+	t.Helper()
+
+	health, err := server.NewHealth(checkers, 1)
+	require.NoError(t, err)
+
+	mux := http.NewServeMux()
+	health.Register(mux)
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	require.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+	var report server.HealthReport
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&report))
+
+	return rec.Code, report
+}
+
+func TestNewHealth_InvalidConfig_ShouldError(t *testing.T) {
+	tests := []struct {
+		name     string
+		checkers map[string]server.Checker
+		timeout  int
+		wantErr  string
+	}{
+		{
+			name:     "Zero Timeout",
+			checkers: map[string]server.Checker{"kafka": available},
+			timeout:  0,
+			wantErr:  "health check timeout is invalid",
+		},
+		{
+			name:     "Nil Checker",
+			checkers: map[string]server.Checker{"kafka": nil},
+			timeout:  1,
+			wantErr:  "checker of kafka is nil",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			health, err := server.NewHealth(tt.checkers, tt.timeout)
+			require.ErrorContains(t, err, tt.wantErr)
+			require.Nil(t, health)
+		})
+	}
+}
+
+func TestLivenessHandler_UnavailableDependency_ShouldReportOK(t *testing.T) {
+	code, report := probe(t, map[string]server.Checker{
+		"mongo": server.CheckerFunc(func(context.Context) error {
+			return errors.New("connection refused")
+		}),
+	}, server.HealthPath)
+
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, server.StatusOK, report.Status)
+	require.Empty(t, report.Checks)
+}
+
+func TestReadinessHandler_AvailableDependencies_ShouldReportOK(t *testing.T) {
+	code, report := probe(t, map[string]server.Checker{
+		"kafka": available,
+		"mongo": available,
+	}, server.ReadyPath)
+
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, server.HealthReport{
+		Status: server.StatusOK,
+		Checks: map[string]server.CheckResult{
+			"kafka": {Status: server.StatusOK},
+			"mongo": {Status: server.StatusOK},
+		},
+	}, report)
+}
+
+func TestReadinessHandler_UnavailableDependency_ShouldReportUnavailable(t *testing.T) {
+	code, report := probe(t, map[string]server.Checker{
+		"kafka": available,
+		"mongo": server.CheckerFunc(func(context.Context) error {
+			return errors.New("connection refused")
+		}),
+	}, server.ReadyPath)
+
+	require.Equal(t, http.StatusServiceUnavailable, code)
+	require.Equal(t, server.HealthReport{
+		Status: server.StatusUnavailable,
+		Checks: map[string]server.CheckResult{
+			"kafka": {Status: server.StatusOK},
+			"mongo": {Status: server.StatusUnavailable, Error: "connection refused"},
+		},
+	}, report)
+}
+
+func TestReadinessHandler_SlowDependency_ShouldTimeOut(t *testing.T) {
+	code, report := probe(t, map[string]server.Checker{
+		"flight_api": server.CheckerFunc(func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}),
+	}, server.ReadyPath)
+
+	require.Equal(t, http.StatusServiceUnavailable, code)
+	require.Equal(t, server.StatusUnavailable, report.Checks["flight_api"].Status)
+	require.Contains(t, report.Checks["flight_api"].Error, context.DeadlineExceeded.Error())
+}
+
+func TestNewEndpointChecker_InvalidConfig_ShouldError(t *testing.T) {
+	checker, err := server.NewEndpointChecker(nil, "http://localhost")
+	require.ErrorContains(t, err, "http client is nil")
+	require.Nil(t, checker)
+
+	checker, err = server.NewEndpointChecker(http.DefaultClient, "")
+	require.ErrorContains(t, err, "endpoint url is empty")
+	require.Nil(t, checker)
+}
+
+func TestEndpointChecker_Check(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		wantErr string
+	}{
+		{
+			name:   "OK",
+			status: http.StatusOK,
+		},
+		{
+			name:   "Unauthorized Is Reachable",
+			status: http.StatusUnauthorized,
+		},
+		{
+			name:    "Server Error",
+			status:  http.StatusBadGateway,
+			wantErr: "responded with status 502",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				require.Equal(t, http.MethodHead, req.Method)
+				w.WriteHeader(tt.status)
+			}))
+			defer upstream.Close()
+
+			checker, err := server.NewEndpointChecker(upstream.Client(), upstream.URL)
+			require.NoError(t, err)
+
+			err = checker.Check(context.Background())
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestEndpointChecker_Unreachable_ShouldError(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(testHandler))
+	url := upstream.URL
+	upstream.Close()
+
+	checker, err := server.NewEndpointChecker(http.DefaultClient, url)
+	require.NoError(t, err)
+	require.ErrorContains(t, checker.Check(context.Background()), "failed to reach")
+}
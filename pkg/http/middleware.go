@@ -0,0 +1,434 @@
+package http
+
+import (
+	"compress/gzip"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// RequestIDHeader specifies the header carrying the ID of a request.
+	RequestIDHeader = "X-Request-ID"
+	// requestIDBytes specifies the number of random bytes of a generated request ID.
+	requestIDBytes = 16
+	// maxRequestIDLength specifies the longest request ID accepted from a client.
+	maxRequestIDLength = 128
+)
+
+// MiddlewareConfig holds configuration settings for the middleware of the HTTP server.
+type MiddlewareConfig struct {
+	// AccessLog specifies whether each request is logged along with its response status and duration.
+	AccessLog bool `mapstructure:"access_log"`
+	// Recover specifies whether panics in handlers are recovered and answered with 500.
+	Recover bool `mapstructure:"recover"`
+	// RequestID specifies whether each request is given an ID, taken from the X-Request-ID header if set.
+	RequestID bool `mapstructure:"request_id"`
+	// Gzip specifies whether responses are compressed for clients accepting gzip.
+	Gzip bool `mapstructure:"gzip"`
+	// CORS specifies the cross-origin resource sharing policy.
+	CORS CORSConfig `mapstructure:"cors"`
+	// Timeouts specifies the timeout in seconds of the routes by path.
+	Timeouts map[string]int `mapstructure:"timeouts"`
+}
+
+// CORSConfig holds configuration settings for cross-origin resource sharing.
+type CORSConfig struct {
+	// Enabled specifies whether cross-origin requests are allowed.
+	Enabled bool `mapstructure:"enabled"`
+	// AllowedOrigins specifies the origins allowed to send requests, or "*" for any origin.
+	AllowedOrigins []string `mapstructure:"allowed_origins"`
+	// AllowedMethods specifies the methods allowed in cross-origin requests.
+	AllowedMethods []string `mapstructure:"allowed_methods"`
+	// AllowedHeaders specifies the headers allowed in cross-origin requests.
+	AllowedHeaders []string `mapstructure:"allowed_headers"`
+	// MaxAge specifies how long in seconds the result of a preflight request may be cached.
+	MaxAge int `mapstructure:"max_age"`
+}
+
+// Middleware wraps an HTTP handler with additional behavior.
+type Middleware func(next http.Handler) http.Handler
+
+// Chain wraps the handler with the middleware, the first being the outermost.
+func Chain(handler http.Handler, middleware ...Middleware) http.Handler {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		handler = middleware[i](handler)
+	}
+
+	return handler
+}
+
+// NewMiddleware creates the middleware enabled in the provided configuration, outermost first.
+func NewMiddleware(cfg MiddlewareConfig) ([]Middleware, error) {
+	middleware := make([]Middleware, 0)
+
+	if cfg.RequestID {
+		middleware = append(middleware, RequestID())
+	}
+
+	if cfg.AccessLog {
+		middleware = append(middleware, AccessLog())
+	}
+
+	if cfg.Recover {
+		middleware = append(middleware, Recover())
+	}
+
+	if cfg.CORS.Enabled {
+		if len(cfg.CORS.AllowedOrigins) == 0 {
+			return nil, fmt.Errorf("cors allowed origins are empty")
+		}
+
+		if cfg.CORS.MaxAge < 0 {
+			return nil, fmt.Errorf("cors max age is invalid: %d", cfg.CORS.MaxAge)
+		}
+
+		middleware = append(middleware, CORS(cfg.CORS))
+	}
+
+	if len(cfg.Timeouts) > 0 {
+		timeouts := make(map[string]time.Duration, len(cfg.Timeouts))
+		for path, timeout := range cfg.Timeouts {
+			if timeout <= 0 {
+				return nil, fmt.Errorf("route timeout of %s is invalid: %d", path, timeout)
+			}
+
+			timeouts[path] = time.Duration(timeout) * time.Second
+		}
+
+		middleware = append(middleware, Timeouts(timeouts))
+	}
+
+	if cfg.Gzip {
+		middleware = append(middleware, Gzip())
+	}
+
+	return middleware, nil
+}
+
+// requestIDKey is the context key of the request ID.
+type requestIDKey struct{}
+
+// RequestIDFromContext returns the ID of the request carried by the context, or an empty string if it has none.
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+
+	return id
+}
+
+// Logger returns the default logger annotated with the ID of the request carried by the context, if any.
+func Logger(ctx context.Context) *slog.Logger {
+	if id := RequestIDFromContext(ctx); id != "" {
+		return slog.Default().With("request_id", id)
+	}
+
+	return slog.Default()
+}
+
+// RequestID gives each request an ID, taken from its X-Request-ID header or generated when it has none.
+// The ID is set in the request context and echoed in the response header.
+func RequestID() Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			id := req.Header.Get(RequestIDHeader)
+			if id == "" || len(id) > maxRequestIDLength {
+				id = newRequestID()
+			}
+
+			w.Header().Set(RequestIDHeader, id)
+			next.ServeHTTP(w, req.WithContext(context.WithValue(req.Context(), requestIDKey{}, id)))
+		})
+	}
+}
+
+// newRequestID generates a random request ID.
+func newRequestID() string {
+	b := make([]byte, requestIDBytes)
+	_, _ = rand.Read(b) // never returns an error
+
+	return hex.EncodeToString(b)
+}
+
+// statusRecorder records the status code and size of a response.
+type statusRecorder struct {
+	http.ResponseWriter
+	// status specifies the status code of the response.
+	status int
+	// bytes specifies the number of bytes of the response body written.
+	bytes int
+}
+
+// WriteHeader records the status code and writes it.
+func (r *statusRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// Write records the size of the body and writes it.
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+
+	n, err := r.ResponseWriter.Write(b)
+	r.bytes += n
+
+	return n, err //nolint:wrapcheck // the server expects the errors of its response writer as is
+}
+
+// Unwrap returns the underlying response writer.
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// AccessLog logs each request along with its response status, size and duration.
+func AccessLog() Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			start := time.Now()
+			recorder := &statusRecorder{ResponseWriter: w}
+
+			next.ServeHTTP(recorder, req)
+
+			if recorder.status == 0 {
+				recorder.status = http.StatusOK
+			}
+
+			Logger(req.Context()).Info(
+				"Served HTTP request",
+				"method", req.Method,
+				"path", req.URL.Path,
+				"status", recorder.status,
+				"bytes", recorder.bytes,
+				"duration", time.Since(start),
+				"remote_addr", req.RemoteAddr,
+			)
+		})
+	}
+}
+
+// Recover recovers from panics in handlers, logging them and responding with 500.
+func Recover() Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			defer func() {
+				recovered := recover()
+				if recovered == nil {
+					return
+				}
+
+				// Let the server abort the response as the handler intended
+				if recovered == http.ErrAbortHandler { //nolint:errorlint // the sentinel is panicked as is
+					panic(recovered)
+				}
+
+				Logger(req.Context()).Error(
+					"Recovered from panic in HTTP handler",
+					"panic", recovered,
+					"stack", string(debug.Stack()),
+				)
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			}()
+
+			next.ServeHTTP(w, req)
+		})
+	}
+}
+
+// Timeouts cancels the requests to the given paths once their timeout elapses, responding with 503.
+// Requests to other paths are not limited.
+func Timeouts(timeouts map[string]time.Duration) Middleware {
+	return func(next http.Handler) http.Handler {
+		handlers := make(map[string]http.Handler, len(timeouts))
+		for path, timeout := range timeouts {
+			handlers[path] = http.TimeoutHandler(next, timeout, "request timed out")
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			if handler, ok := handlers[req.URL.Path]; ok {
+				handler.ServeHTTP(w, req)
+				return
+			}
+
+			next.ServeHTTP(w, req)
+		})
+	}
+}
+
+// CORS allows cross-origin requests from the configured origins and answers their preflight requests.
+func CORS(cfg CORSConfig) Middleware {
+	anyOrigin := slices.Contains(cfg.AllowedOrigins, "*")
+	methods := strings.Join(cfg.AllowedMethods, ", ")
+	headers := strings.Join(cfg.AllowedHeaders, ", ")
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			origin := req.Header.Get("Origin")
+			if origin == "" {
+				next.ServeHTTP(w, req)
+				return
+			}
+
+			w.Header().Add("Vary", "Origin")
+			if !anyOrigin && !slices.Contains(cfg.AllowedOrigins, origin) {
+				next.ServeHTTP(w, req)
+				return
+			}
+
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+
+			// Answer preflight requests without passing them to the handler
+			if req.Method == http.MethodOptions && req.Header.Get("Access-Control-Request-Method") != "" {
+				if methods != "" {
+					w.Header().Set("Access-Control-Allow-Methods", methods)
+				}
+
+				if headers != "" {
+					w.Header().Set("Access-Control-Allow-Headers", headers)
+				}
+
+				if cfg.MaxAge > 0 {
+					w.Header().Set("Access-Control-Max-Age", strconv.Itoa(cfg.MaxAge))
+				}
+
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+
+			next.ServeHTTP(w, req)
+		})
+	}
+}
+
+// gzipResponseWriter compresses the body of a response once its status code allows one.
+type gzipResponseWriter struct {
+	http.ResponseWriter
+	// writer specifies the gzip writer compressing the body, nil unless the body is compressed.
+	writer *gzip.Writer
+	// compress specifies whether the body is compressed.
+	compress bool
+	// wroteHeader specifies whether the status code has been written.
+	wroteHeader bool
+	// head specifies whether the response answers a HEAD request, which has no body.
+	head bool
+}
+
+// WriteHeader decides whether the body is compressed and writes the status code.
+func (w *gzipResponseWriter) WriteHeader(status int) {
+	if w.wroteHeader {
+		return
+	}
+
+	w.wroteHeader = true
+
+	// Leave responses without a body, or already encoded by the handler, as is.
+	// The gzip writer is created along with the header, so that an empty body is still a valid gzip stream.
+	header := w.Header()
+	if !w.head && bodyAllowed(status) && header.Get("Content-Encoding") == "" {
+		w.compress = true
+		w.writer = gzip.NewWriter(w.ResponseWriter)
+		header.Set("Content-Encoding", "gzip")
+		header.Del("Content-Length")
+	}
+
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Write compresses the body if allowed and writes it.
+func (w *gzipResponseWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		if w.Header().Get("Content-Type") == "" {
+			w.Header().Set("Content-Type", http.DetectContentType(b))
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}
+
+	if !w.compress {
+		return w.ResponseWriter.Write(b) //nolint:wrapcheck // the server expects the errors of its response writer as is
+	}
+
+	return w.writer.Write(b) //nolint:wrapcheck // the server expects the errors of its response writer as is
+}
+
+// Unwrap returns the underlying response writer.
+func (w *gzipResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// Close flushes the compressed body.
+func (w *gzipResponseWriter) Close() error {
+	if w.writer == nil {
+		return nil
+	}
+
+	if err := w.writer.Close(); err != nil {
+		return fmt.Errorf("failed to close gzip writer: %w", err)
+	}
+
+	return nil
+}
+
+// bodyAllowed reports whether a response with the status code may have a body.
+func bodyAllowed(status int) bool {
+	switch {
+	case status >= http.StatusContinue && status < http.StatusOK:
+		return false
+	case status == http.StatusNoContent, status == http.StatusNotModified:
+		return false
+	default:
+		return true
+	}
+}
+
+// Gzip compresses the responses to clients accepting gzip.
+func Gzip() Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			w.Header().Add("Vary", "Accept-Encoding")
+
+			if !acceptsGzip(req) {
+				next.ServeHTTP(w, req)
+				return
+			}
+
+			// Keep the handler from compressing the response a second time
+			req.Header.Del("Accept-Encoding")
+
+			writer := &gzipResponseWriter{ResponseWriter: w, head: req.Method == http.MethodHead}
+			defer func() {
+				if err := writer.Close(); err != nil {
+					slog.Warn("Failed to compress HTTP response", "error", err)
+				}
+			}()
+
+			next.ServeHTTP(writer, req)
+		})
+	}
+}
+
+// acceptsGzip reports whether the client accepts gzip-encoded responses.
+func acceptsGzip(req *http.Request) bool {
+	for _, encoding := range strings.Split(req.Header.Get("Accept-Encoding"), ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(encoding), ";")
+		if strings.TrimSpace(name) != "gzip" {
+			continue
+		}
+
+		// Treat gzip;q=0 as refused
+		return strings.ReplaceAll(strings.TrimSpace(params), " ", "") != "q=0"
+	}
+
+	return false
+}
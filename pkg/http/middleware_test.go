@@ -0,0 +1,369 @@
+package http_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	server "github.com/ansoncht/flight-microservices/pkg/http"
+	"github.com/stretchr/testify/require"
+)
+
+// captureLogs sends the default logs to a buffer until the test ends.
+func captureLogs(t *testing.T) *bytes.Buffer {
+	t.Helper()
+
+	var buf bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&buf, nil)))
+	t.Cleanup(func() {
+		slog.SetDefault(previous)
+	})
+
+	return &buf
+}
+
+// serve serves the request with the handler wrapped with the middleware.
+func serve(handler http.Handler, req *http.Request, middleware ...server.Middleware) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	server.Chain(handler, middleware...).ServeHTTP(rec, req)
+
+	return rec
+}
+
+func TestChain_ShouldWrapFirstOutermost(t *testing.T) {
+	var order []string
+	trace := func(name string) server.Middleware {
+		return func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				order = append(order, name)
+				next.ServeHTTP(w, req)
+			})
+		}
+	}
+
+	serve(http.HandlerFunc(testHandler), httptest.NewRequest(http.MethodGet, "/", nil), trace("first"), trace("second"))
+	require.Equal(t, []string{"first", "second"}, order)
+}
+
+func TestNewMiddleware_InvalidConfig_ShouldError(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     server.MiddlewareConfig
+		wantErr string
+	}{
+		{
+			name:    "Empty CORS Origins",
+			cfg:     server.MiddlewareConfig{CORS: server.CORSConfig{Enabled: true}},
+			wantErr: "cors allowed origins are empty",
+		},
+		{
+			name: "Negative CORS Max Age",
+			cfg: server.MiddlewareConfig{
+				CORS: server.CORSConfig{Enabled: true, AllowedOrigins: []string{"*"}, MaxAge: -1},
+			},
+			wantErr: "cors max age is invalid",
+		},
+		{
+			name:    "Zero Route Timeout",
+			cfg:     server.MiddlewareConfig{Timeouts: map[string]int{"/api/v1/fetch": 0}},
+			wantErr: "route timeout of /api/v1/fetch is invalid",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			middleware, err := server.NewMiddleware(tt.cfg)
+			require.ErrorContains(t, err, tt.wantErr)
+			require.Nil(t, middleware)
+
+			httpServer, err := server.NewServer(
+				server.ServerConfig{Port: "8080", Timeout: 5, Middleware: tt.cfg},
+				http.HandlerFunc(testHandler),
+			)
+			require.ErrorContains(t, err, tt.wantErr)
+			require.Nil(t, httpServer)
+		})
+	}
+}
+
+func TestNewMiddleware_ShouldEnableConfigured(t *testing.T) {
+	middleware, err := server.NewMiddleware(server.MiddlewareConfig{})
+	require.NoError(t, err)
+	require.Empty(t, middleware)
+
+	middleware, err = server.NewMiddleware(server.MiddlewareConfig{
+		AccessLog: true,
+		Recover:   true,
+		RequestID: true,
+		Gzip:      true,
+		CORS:      server.CORSConfig{Enabled: true, AllowedOrigins: []string{"*"}},
+		Timeouts:  map[string]int{"/api/v1/fetch": 30},
+	})
+	require.NoError(t, err)
+	require.Len(t, middleware, 6)
+}
+
+func TestRequestID_NoHeader_ShouldGenerate(t *testing.T) {
+	var id string
+	handler := http.HandlerFunc(func(_ http.ResponseWriter, req *http.Request) {
+		id = server.RequestIDFromContext(req.Context())
+	})
+
+	rec := serve(handler, httptest.NewRequest(http.MethodGet, "/", nil), server.RequestID())
+
+	require.Len(t, id, 32)
+	require.Equal(t, id, rec.Header().Get(server.RequestIDHeader))
+}
+
+func TestRequestID_Header_ShouldPropagate(t *testing.T) {
+	logs := captureLogs(t)
+
+	var id string
+	handler := http.HandlerFunc(func(_ http.ResponseWriter, req *http.Request) {
+		id = server.RequestIDFromContext(req.Context())
+		server.Logger(req.Context()).Info("Handling request")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(server.RequestIDHeader, "abc-123")
+	rec := serve(handler, req, server.RequestID())
+
+	require.Equal(t, "abc-123", id)
+	require.Equal(t, "abc-123", rec.Header().Get(server.RequestIDHeader))
+	require.Contains(t, logs.String(), `"request_id":"abc-123"`)
+}
+
+func TestRequestID_HeaderTooLong_ShouldGenerate(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(server.RequestIDHeader, strings.Repeat("a", 129))
+
+	rec := serve(http.HandlerFunc(testHandler), req, server.RequestID())
+	require.Len(t, rec.Header().Get(server.RequestIDHeader), 32)
+}
+
+func TestAccessLog_ShouldLogRequest(t *testing.T) {
+	logs := captureLogs(t)
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+		_, _ = w.Write([]byte("short and stout"))
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/fetch", nil)
+	req.Header.Set(server.RequestIDHeader, "abc-123")
+	serve(handler, req, server.RequestID(), server.AccessLog())
+
+	require.Contains(t, logs.String(), `"msg":"Served HTTP request"`)
+	require.Contains(t, logs.String(), `"method":"POST"`)
+	require.Contains(t, logs.String(), `"path":"/api/v1/fetch"`)
+	require.Contains(t, logs.String(), `"status":418`)
+	require.Contains(t, logs.String(), `"bytes":15`)
+	require.Contains(t, logs.String(), `"request_id":"abc-123"`)
+}
+
+func TestRecover_Panic_ShouldRespondInternalServerError(t *testing.T) {
+	logs := captureLogs(t)
+
+	handler := http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		panic("nil map")
+	})
+
+	rec := serve(handler, httptest.NewRequest(http.MethodGet, "/", nil), server.AccessLog(), server.Recover())
+
+	require.Equal(t, http.StatusInternalServerError, rec.Code)
+	require.Contains(t, logs.String(), `"panic":"nil map"`)
+	require.Contains(t, logs.String(), `"status":500`)
+}
+
+func TestRecover_AbortHandler_ShouldRepanic(t *testing.T) {
+	handler := http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		panic(http.ErrAbortHandler)
+	})
+
+	require.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		serve(handler, httptest.NewRequest(http.MethodGet, "/", nil), server.Recover())
+	})
+}
+
+func TestTimeouts_ShouldLimitConfiguredRoutes(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		select {
+		case <-req.Context().Done():
+		case <-time.After(50 * time.Millisecond):
+			w.WriteHeader(http.StatusOK)
+		}
+	})
+	timeouts := server.Timeouts(map[string]time.Duration{"/slow": time.Millisecond})
+
+	rec := serve(handler, httptest.NewRequest(http.MethodGet, "/slow", nil), timeouts)
+	require.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	require.Equal(t, "request timed out", rec.Body.String())
+
+	rec = serve(handler, httptest.NewRequest(http.MethodGet, "/other", nil), timeouts)
+	require.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestCORS(t *testing.T) {
+	cors := server.CORS(server.CORSConfig{
+		Enabled:        true,
+		AllowedOrigins: []string{"https://example.com"},
+		AllowedMethods: []string{http.MethodGet, http.MethodOptions},
+		AllowedHeaders: []string{"Content-Type", server.RequestIDHeader},
+		MaxAge:         600,
+	})
+
+	tests := []struct {
+		name        string
+		method      string
+		origin      string
+		preflight   bool
+		wantCode    int
+		wantOrigin  string
+		wantMethods string
+	}{
+		{
+			name:     "Same Origin",
+			method:   http.MethodGet,
+			wantCode: http.StatusOK,
+		},
+		{
+			name:       "Allowed Origin",
+			method:     http.MethodGet,
+			origin:     "https://example.com",
+			wantCode:   http.StatusOK,
+			wantOrigin: "https://example.com",
+		},
+		{
+			name:     "Disallowed Origin",
+			method:   http.MethodGet,
+			origin:   "https://example.org",
+			wantCode: http.StatusOK,
+		},
+		{
+			name:        "Preflight",
+			method:      http.MethodOptions,
+			origin:      "https://example.com",
+			preflight:   true,
+			wantCode:    http.StatusNoContent,
+			wantOrigin:  "https://example.com",
+			wantMethods: "GET, OPTIONS",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/", nil)
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+
+			if tt.preflight {
+				req.Header.Set("Access-Control-Request-Method", http.MethodGet)
+			}
+
+			rec := serve(http.HandlerFunc(testHandler), req, cors)
+
+			require.Equal(t, tt.wantCode, rec.Code)
+			require.Equal(t, tt.wantOrigin, rec.Header().Get("Access-Control-Allow-Origin"))
+			require.Equal(t, tt.wantMethods, rec.Header().Get("Access-Control-Allow-Methods"))
+		})
+	}
+}
+
+func TestGzip_AcceptsGzip_ShouldCompress(t *testing.T) {
+	body := strings.Repeat(`{"message":"flights processed successfully"}`, 10)
+	handler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		require.Empty(t, req.Header.Get("Accept-Encoding"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(body))
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Encoding", "br, gzip")
+	rec := serve(handler, req, server.Gzip())
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, "gzip", rec.Header().Get("Content-Encoding"))
+	require.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+	reader, err := gzip.NewReader(rec.Body)
+	require.NoError(t, err)
+	decompressed, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	require.Equal(t, body, string(decompressed))
+}
+
+func TestGzip_ShouldNotCompress(t *testing.T) {
+	tests := []struct {
+		name     string
+		encoding string
+		status   int
+	}{
+		{
+			name:   "Gzip Not Accepted",
+			status: http.StatusOK,
+		},
+		{
+			name:     "Gzip Refused",
+			encoding: "gzip;q=0",
+			status:   http.StatusOK,
+		},
+		{
+			name:     "No Content",
+			encoding: "gzip",
+			status:   http.StatusNoContent,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(tt.status)
+				if tt.status == http.StatusOK {
+					_, _ = w.Write([]byte("plain"))
+				}
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.encoding != "" {
+				req.Header.Set("Accept-Encoding", tt.encoding)
+			}
+
+			rec := serve(handler, req, server.Gzip())
+
+			require.Equal(t, tt.status, rec.Code)
+			require.Empty(t, rec.Header().Get("Content-Encoding"))
+			if tt.status == http.StatusOK {
+				require.Equal(t, "plain", rec.Body.String())
+			} else {
+				require.Empty(t, rec.Body.Bytes())
+			}
+		})
+	}
+}
+
+func TestGzip_EmptyBody_ShouldWriteValidStream(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rec := serve(handler, req, server.Gzip())
+
+	require.Equal(t, http.StatusAccepted, rec.Code)
+	require.Equal(t, "gzip", rec.Header().Get("Content-Encoding"))
+
+	reader, err := gzip.NewReader(rec.Body)
+	require.NoError(t, err)
+	decompressed, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	require.Empty(t, decompressed)
+}
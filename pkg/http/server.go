@@ -15,6 +15,8 @@ type ServerConfig struct {
 	Port string `mapstructure:"port"`
 	// Timeout specifies the timeout for reading HTTP headers in seconds.
 	Timeout int `mapstructure:"timeout"`
+	// Middleware specifies the middleware wrapping the handler of the HTTP server.
+	Middleware MiddlewareConfig `mapstructure:"middleware"`
 }
 
 // HTTP holds the HTTP server instance and its dependencies.
@@ -23,6 +25,7 @@ type HTTP struct {
 }
 
 // NewServer creates a new HTTP server instance based on the provided configuration.
+// The handler is wrapped with the middleware enabled in the configuration.
 func NewServer(cfg ServerConfig, handler http.Handler) (*HTTP, error) {
 	slog.Info("Initializing HTTP server for the service", "port", cfg.Port, "timeout", cfg.Timeout)
 
@@ -47,9 +50,14 @@ func NewServer(cfg ServerConfig, handler http.Handler) (*HTTP, error) {
 		return nil, fmt.Errorf("port number must be greater than 0")
 	}
 
+	middleware, err := NewMiddleware(cfg.Middleware)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create middleware: %w", err)
+	}
+
 	server := &http.Server{
 		Addr:              ":" + cfg.Port,
-		Handler:           handler,
+		Handler:           Chain(handler, middleware...),
 		ReadHeaderTimeout: time.Duration(cfg.Timeout) * time.Second,
 	}
 
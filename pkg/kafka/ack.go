@@ -0,0 +1,150 @@
+package kafka
+
+import (
+	"log/slog"
+	"sync"
+
+	"github.com/ansoncht/flight-microservices/pkg/msgbus"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+// topicPartition identifies a partition of a topic.
+type topicPartition struct {
+	topic     string
+	partition int32
+}
+
+// partitionOffsets holds the delivered records of a partition which have not been marked for commit.
+type partitionOffsets struct {
+	// pending holds the delivered records not yet marked for commit, in offset order.
+	pending []*kgo.Record
+	// acked holds the offsets of the pending records which have been acknowledged.
+	acked map[int64]bool
+	// held specifies whether a record of the partition has been rejected, so that no later record is committed.
+	held bool
+	// heldAt specifies the offset of the earliest rejected record of the partition.
+	heldAt int64
+}
+
+// OffsetTracker implements the msgbus.Acknowledger interface.
+// It marks a record for commit only once it and every record delivered before it from the same partition have
+// been acknowledged, so that a rejected or unsettled record blocks the commits of its partition. Records delivered
+// after a rejected one are no longer tracked until the partition is revoked, as they cannot be committed anyway.
+type OffsetTracker struct {
+	// mu protects partitions, as records are settled concurrently.
+	mu sync.Mutex
+	// partitions specifies the delivered records of each partition which have not been marked for commit.
+	partitions map[topicPartition]*partitionOffsets
+	// mark specifies the function marking records for commit.
+	mark func(records ...*kgo.Record)
+}
+
+// NewOffsetTracker creates a new OffsetTracker instance marking records for commit with the provided function.
+func NewOffsetTracker(mark func(records ...*kgo.Record)) *OffsetTracker {
+	return &OffsetTracker{
+		partitions: make(map[topicPartition]*partitionOffsets),
+		mark:       mark,
+	}
+}
+
+// Track registers a record which is about to be delivered.
+func (t *OffsetTracker) Track(record *kgo.Record) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	key := topicPartition{topic: record.Topic, partition: record.Partition}
+
+	offsets, ok := t.partitions[key]
+	if !ok {
+		offsets = &partitionOffsets{acked: make(map[int64]bool)}
+		t.partitions[key] = offsets
+	}
+
+	if offsets.held {
+		return
+	}
+
+	offsets.pending = append(offsets.pending, record)
+}
+
+// Ack acknowledges a message and marks the longest acknowledged run of its partition for commit.
+func (t *OffsetTracker) Ack(msg msgbus.Message) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	offsets, ok := t.partitions[topicPartition{topic: msg.Topic, partition: msg.Partition}]
+	if !ok {
+		// The partition has been revoked since the message was delivered
+		return
+	}
+
+	if offsets.held && msg.Offset >= offsets.heldAt {
+		return
+	}
+
+	offsets.acked[msg.Offset] = true
+
+	var last *kgo.Record
+	for len(offsets.pending) > 0 && offsets.acked[offsets.pending[0].Offset] {
+		last = offsets.pending[0]
+		delete(offsets.acked, last.Offset)
+		offsets.pending = offsets.pending[1:]
+	}
+
+	if last != nil {
+		t.mark(last)
+	}
+}
+
+// Nack rejects a message, leaving it and every later record of its partition uncommitted until the partition is
+// revoked, and stops tracking them.
+func (t *OffsetTracker) Nack(msg msgbus.Message, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	offsets, ok := t.partitions[topicPartition{topic: msg.Topic, partition: msg.Partition}]
+	if !ok {
+		// The partition has been revoked since the message was delivered
+		return
+	}
+
+	if offsets.held && msg.Offset >= offsets.heldAt {
+		return
+	}
+
+	offsets.held = true
+	offsets.heldAt = msg.Offset
+
+	// Forget the records from the rejected one on, which can no longer be committed
+	for i, record := range offsets.pending {
+		if record.Offset >= msg.Offset {
+			for _, forgotten := range offsets.pending[i:] {
+				delete(offsets.acked, forgotten.Offset)
+			}
+
+			offsets.pending = offsets.pending[:i]
+
+			break
+		}
+	}
+
+	slog.Warn(
+		"Rejected message, holding back commits of its partition",
+		"topic", msg.Topic,
+		"partition", msg.Partition,
+		"offset", msg.Offset,
+		"error", err,
+	)
+}
+
+// Revoke forgets the records of partitions which are no longer assigned to the reader.
+func (t *OffsetTracker) Revoke(revoked map[string][]int32) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for topic, partitions := range revoked {
+		for _, partition := range partitions {
+			delete(t.partitions, topicPartition{topic: topic, partition: partition})
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package kafka_test
+
+import (
+	"errors"
+	"testing"
+
+	msgQueue "github.com/ansoncht/flight-microservices/pkg/kafka"
+	"github.com/ansoncht/flight-microservices/pkg/msgbus"
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+// trackRecords tracks records of a partition at the given offsets and returns them.
+func trackRecords(tracker *msgQueue.OffsetTracker, partition int32, offsets ...int64) []*kgo.Record {
+	records := make([]*kgo.Record, 0, len(offsets))
+	for _, offset := range offsets {
+		record := &kgo.Record{Topic: testTopic, Partition: partition, Offset: offset}
+		tracker.Track(record)
+		records = append(records, record)
+	}
+
+	return records
+}
+
+// messageOf returns the message delivered for a tracked record.
+func messageOf(record *kgo.Record) msgbus.Message {
+	return msgbus.Message{Topic: record.Topic, Partition: record.Partition, Offset: record.Offset}
+}
+
+func TestOffsetTracker_InOrderAcks_ShouldMarkEachRecord(t *testing.T) {
+	var marked []int64
+	tracker := msgQueue.NewOffsetTracker(func(records ...*kgo.Record) {
+		for _, record := range records {
+			marked = append(marked, record.Offset)
+		}
+	})
+
+	records := trackRecords(tracker, 0, 10, 11, 12)
+	for _, record := range records {
+		msgbus.NewDelivery(messageOf(record), tracker).Ack()
+	}
+
+	require.Equal(t, []int64{10, 11, 12}, marked)
+}
+
+func TestOffsetTracker_OutOfOrderAcks_ShouldWaitForEarlierRecords(t *testing.T) {
+	var marked []int64
+	tracker := msgQueue.NewOffsetTracker(func(records ...*kgo.Record) {
+		for _, record := range records {
+			marked = append(marked, record.Offset)
+		}
+	})
+
+	records := trackRecords(tracker, 0, 10, 11, 12)
+	tracker.Ack(messageOf(records[2]))
+	tracker.Ack(messageOf(records[1]))
+	require.Empty(t, marked)
+
+	tracker.Ack(messageOf(records[0]))
+	require.Equal(t, []int64{12}, marked)
+}
+
+func TestOffsetTracker_Nack_ShouldHoldBackPartition(t *testing.T) {
+	var marked []*kgo.Record
+	tracker := msgQueue.NewOffsetTracker(func(records ...*kgo.Record) {
+		marked = append(marked, records...)
+	})
+
+	first := trackRecords(tracker, 0, 10, 11)
+	other := trackRecords(tracker, 1, 20)
+
+	tracker.Ack(messageOf(first[0]))
+	msgbus.NewDelivery(messageOf(first[1]), tracker).Nack(errors.New("failed to post"))
+	tracker.Ack(messageOf(other[0]))
+
+	// Only the record before the rejected one and the other partition are committed
+	require.Equal(t, []*kgo.Record{first[0], other[0]}, marked)
+
+	later := trackRecords(tracker, 0, 12)
+	tracker.Ack(messageOf(later[0]))
+	require.Len(t, marked, 2)
+}
+
+func TestOffsetTracker_AckAfterNack_ShouldOnlyMarkEarlierRecords(t *testing.T) {
+	var marked []int64
+	tracker := msgQueue.NewOffsetTracker(func(records ...*kgo.Record) {
+		for _, record := range records {
+			marked = append(marked, record.Offset)
+		}
+	})
+
+	records := trackRecords(tracker, 0, 10, 11, 12)
+	tracker.Nack(messageOf(records[1]), errors.New("failed to post"))
+	tracker.Ack(messageOf(records[2]))
+	require.Empty(t, marked)
+
+	// The record before the rejected one can still be committed
+	tracker.Ack(messageOf(records[0]))
+	require.Equal(t, []int64{10}, marked)
+
+	later := trackRecords(tracker, 0, 13)
+	tracker.Ack(messageOf(later[0]))
+	tracker.Ack(messageOf(records[1]))
+	require.Equal(t, []int64{10}, marked)
+
+	// Once the partition is reassigned its records are committed again
+	tracker.Revoke(map[string][]int32{testTopic: {0}})
+	reassigned := trackRecords(tracker, 0, 11)
+	tracker.Ack(messageOf(reassigned[0]))
+	require.Equal(t, []int64{10, 11}, marked)
+}
+
+func TestOffsetTracker_Revoke_ShouldIgnoreLateAcks(t *testing.T) {
+	var marked []*kgo.Record
+	tracker := msgQueue.NewOffsetTracker(func(records ...*kgo.Record) {
+		marked = append(marked, records...)
+	})
+
+	records := trackRecords(tracker, 0, 10)
+	tracker.Revoke(map[string][]int32{testTopic: {0}})
+	tracker.Ack(messageOf(records[0]))
+	require.Empty(t, marked)
+}
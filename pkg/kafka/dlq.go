@@ -0,0 +1,378 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ansoncht/flight-microservices/pkg/msgbus"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+// Headers describing where a dead letter was originally consumed from and why it failed.
+const (
+	HeaderOriginalTopic     = "dlq-original-topic"
+	HeaderOriginalPartition = "dlq-original-partition"
+	HeaderOriginalOffset    = "dlq-original-offset"
+	HeaderError             = "dlq-error"
+)
+
+// Headers describing the day stream a dead letter was set aside from.
+const (
+	HeaderStreamAirport = "dlq-stream-airport"
+	HeaderStreamDate    = "dlq-stream-date"
+)
+
+const (
+	// replayIdleTimeout specifies how long a replay waits for more dead letters before it is done.
+	replayIdleTimeout = 10 * time.Second
+)
+
+// DLQConfig holds configuration settings for the dead-letter queue of a service.
+type DLQConfig struct {
+	// Enabled specifies whether failed records are written to the dead-letter topic instead of being dropped.
+	Enabled bool `mapstructure:"enabled"`
+	// Address specifies the Kafka broker address.
+	Address string `mapstructure:"address"`
+	// Topic specifies the dead-letter topic.
+	Topic string `mapstructure:"topic"`
+	// ReplayGroupID specifies the consumer group tracking which dead letters have been replayed.
+	ReplayGroupID string `mapstructure:"replay_group_id"`
+	// TLS specifies the TLS settings for connecting to the brokers.
+	TLS TLSConfig `mapstructure:"tls"`
+	// SASL specifies the SASL settings for authenticating to the brokers.
+	SASL SASLConfig `mapstructure:"sasl"`
+}
+
+// DeadLetterWriter defines the interface for setting aside records which cannot be processed.
+type DeadLetterWriter interface {
+	// WriteDeadLetter writes a failed message along with the reason it failed.
+	WriteDeadLetter(ctx context.Context, msg msgbus.Message, reason error) error
+	// Close closes the dead letter writer.
+	Close()
+}
+
+// Resummarizer defines the interface for reading the flights of a day again.
+// Dead letters set aside from a day stream are replayed through it, so that the day is streamed and summarized again
+// as a whole instead of its dead letters being re-injected into whichever stream is open.
+type Resummarizer interface {
+	// Resummarize reads the flights of the airport on the given date again.
+	Resummarize(ctx context.Context, airport string, date string) error
+}
+
+// streamDay holds the airport and date of a day stream.
+type streamDay struct {
+	airport string
+	date    string
+}
+
+// DLQWriter holds the Kafka dead-letter writer instance.
+// It implements the DeadLetterWriter interface to republish failed records to the dead-letter topic.
+type DLQWriter struct {
+	// Client specifies the kafka client instance.
+	Client *kgo.Client
+}
+
+// NopDeadLetterWriter implements the DeadLetterWriter interface by logging and dropping failed records.
+type NopDeadLetterWriter struct{}
+
+// NewDeadLetterWriter creates a DLQWriter if the dead-letter queue is enabled, or a NopDeadLetterWriter otherwise.
+func NewDeadLetterWriter(cfg DLQConfig) (DeadLetterWriter, error) {
+	if !cfg.Enabled {
+		return &NopDeadLetterWriter{}, nil
+	}
+
+	writer, err := NewDLQWriter(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return writer, nil
+}
+
+// NewDLQWriter creates a new DLQWriter instance based on the provided configuration.
+func NewDLQWriter(cfg DLQConfig) (*DLQWriter, error) {
+	slog.Info("Initializing Kafka dead-letter writer for the service", "address", cfg.Address, "topic", cfg.Topic)
+
+	if cfg.Address == "" {
+		return nil, fmt.Errorf("kafka broker address is empty")
+	}
+
+	if cfg.Topic == "" {
+		return nil, fmt.Errorf("kafka dead-letter topic is empty")
+	}
+
+	securityOpts, err := securityOptions(cfg.TLS, cfg.SASL)
+	if err != nil {
+		return nil, err
+	}
+
+	addresses := strings.Split(cfg.Address, ",")
+	opts := []kgo.Opt{
+		kgo.SeedBrokers(addresses...),
+		kgo.DefaultProduceTopic(cfg.Topic),
+		kgo.WithHooks(newClientMetrics(""), newClientTracing("")),
+	}
+	opts = append(opts, securityOpts...)
+
+	client, err := kgo.NewClient(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Kafka client: %w", err)
+	}
+
+	return &DLQWriter{
+		Client: client,
+	}, nil
+}
+
+// Close closes the Kafka dead-letter writer.
+func (w *DLQWriter) Close() {
+	w.Client.Close()
+}
+
+// WriteDeadLetter writes a failed message to the dead-letter topic.
+func (w *DLQWriter) WriteDeadLetter(ctx context.Context, msg msgbus.Message, reason error) error {
+	slog.Warn(
+		"Writing record to dead-letter topic",
+		"topic", msg.Topic,
+		"partition", msg.Partition,
+		"offset", msg.Offset,
+		"error", reason,
+	)
+
+	if w == nil {
+		return fmt.Errorf("kafka dead-letter writer is nil")
+	}
+
+	if err := w.Client.ProduceSync(ctx, NewDeadLetterRecord(msg, reason)).FirstErr(); err != nil {
+		return fmt.Errorf("failed to produce dead letter: %w", err)
+	}
+
+	return nil
+}
+
+// Close does nothing.
+func (w *NopDeadLetterWriter) Close() {}
+
+// WriteDeadLetter logs and drops a failed message.
+func (w *NopDeadLetterWriter) WriteDeadLetter(_ context.Context, msg msgbus.Message, reason error) error {
+	slog.Warn(
+		"Dropping record without dead-letter topic",
+		"topic", msg.Topic,
+		"partition", msg.Partition,
+		"offset", msg.Offset,
+		"error", reason,
+	)
+
+	return nil
+}
+
+// NewDeadLetterRecord creates the dead letter of a failed message, keeping its key, value and headers and adding
+// headers with its original topic, partition, offset and the reason it failed.
+func NewDeadLetterRecord(msg msgbus.Message, reason error) *kgo.Record {
+	message := "unknown error"
+	if reason != nil {
+		message = reason.Error()
+	}
+
+	headers := make([]kgo.RecordHeader, 0, len(msg.Headers)+4) //nolint:mnd // the four dead-letter headers
+	for _, header := range msg.Headers {
+		headers = append(headers, kgo.RecordHeader{Key: header.Key, Value: header.Value})
+	}
+
+	headers = append(headers,
+		kgo.RecordHeader{Key: HeaderOriginalTopic, Value: []byte(msg.Topic)},
+		kgo.RecordHeader{Key: HeaderOriginalPartition, Value: []byte(strconv.FormatInt(int64(msg.Partition), 10))},
+		kgo.RecordHeader{Key: HeaderOriginalOffset, Value: []byte(strconv.FormatInt(msg.Offset, 10))},
+		kgo.RecordHeader{Key: HeaderError, Value: []byte(message)},
+	)
+
+	return &kgo.Record{
+		Key:       msg.Key,
+		Value:     msg.Value,
+		Headers:   headers,
+		Timestamp: msg.Timestamp,
+	}
+}
+
+// WithStream returns a copy of a message with headers of the airport and date of the day stream it belongs to.
+func WithStream(msg msgbus.Message, airport string, date string) msgbus.Message {
+	headers := make([]msgbus.Header, 0, len(msg.Headers)+2) //nolint:mnd // the two stream headers
+	headers = append(headers, msg.Headers...)
+	headers = append(headers,
+		msgbus.Header{Key: HeaderStreamAirport, Value: []byte(airport)},
+		msgbus.Header{Key: HeaderStreamDate, Value: []byte(date)},
+	)
+
+	msg.Headers = headers
+
+	return msg
+}
+
+// deadLetterStream returns the day stream a dead letter was set aside from, if any.
+func deadLetterStream(deadLetter kgo.Record) (streamDay, bool) {
+	var day streamDay
+
+	for _, header := range deadLetter.Headers {
+		switch header.Key {
+		case HeaderStreamAirport:
+			day.airport = string(header.Value)
+		case HeaderStreamDate:
+			day.date = string(header.Value)
+		}
+	}
+
+	return day, day.airport != "" && day.date != ""
+}
+
+// NewReplayRecord creates the record re-injecting a dead letter into its original topic, without the
+// dead-letter headers.
+func NewReplayRecord(deadLetter kgo.Record) (*kgo.Record, error) {
+	topic := ""
+	headers := make([]kgo.RecordHeader, 0, len(deadLetter.Headers))
+
+	for _, header := range deadLetter.Headers {
+		switch header.Key {
+		case HeaderOriginalTopic:
+			topic = string(header.Value)
+		case HeaderOriginalPartition, HeaderOriginalOffset, HeaderError, HeaderStreamAirport, HeaderStreamDate:
+		default:
+			headers = append(headers, header)
+		}
+	}
+
+	if topic == "" {
+		return nil, fmt.Errorf("dead letter has no original topic")
+	}
+
+	return &kgo.Record{
+		Topic:   topic,
+		Key:     deadLetter.Key,
+		Value:   deadLetter.Value,
+		Headers: headers,
+	}, nil
+}
+
+// ReplayDeadLetters replays the dead letters not yet replayed and returns how many were replayed. It stops once no
+// dead letter arrives for a while.
+// Dead letters set aside from a day stream are replayed by resummarizing their day once, since re-injecting them
+// alone would add them to whichever day stream is open. Other dead letters are re-injected into their original
+// topics. The resummarizer may be nil if the service sets aside no dead letters from day streams.
+func ReplayDeadLetters(ctx context.Context, cfg DLQConfig, resummarizer Resummarizer) (int, error) {
+	slog.Info("Replaying dead letters", "address", cfg.Address, "topic", cfg.Topic)
+
+	if cfg.Address == "" {
+		return 0, fmt.Errorf("kafka broker address is empty")
+	}
+
+	if cfg.Topic == "" {
+		return 0, fmt.Errorf("kafka dead-letter topic is empty")
+	}
+
+	if cfg.ReplayGroupID == "" {
+		return 0, fmt.Errorf("kafka dead-letter replay group ID is empty")
+	}
+
+	securityOpts, err := securityOptions(cfg.TLS, cfg.SASL)
+	if err != nil {
+		return 0, err
+	}
+
+	addresses := strings.Split(cfg.Address, ",")
+	opts := []kgo.Opt{
+		kgo.SeedBrokers(addresses...),
+		kgo.ConsumerGroup(cfg.ReplayGroupID),
+		kgo.ConsumeTopics(cfg.Topic),
+		kgo.ConsumeResetOffset(kgo.NewOffset().AtStart()),
+		kgo.DisableAutoCommit(),
+	}
+	opts = append(opts, securityOpts...)
+
+	client, err := kgo.NewClient(opts...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create Kafka client: %w", err)
+	}
+	defer client.Close()
+
+	replayed := 0
+	resummarized := make(map[streamDay]struct{})
+
+	for {
+		pollCtx, cancel := context.WithTimeout(ctx, replayIdleTimeout)
+		fetches := client.PollFetches(pollCtx)
+		cancel()
+
+		if ctx.Err() != nil {
+			return replayed, fmt.Errorf("context canceled while replaying dead letters: %w", ctx.Err())
+		}
+
+		for _, fetchErr := range fetches.Errors() {
+			if errors.Is(fetchErr.Err, context.DeadlineExceeded) {
+				continue
+			}
+
+			return replayed, fmt.Errorf("failed to fetch dead letters: %w", fetchErr.Err)
+		}
+
+		if fetches.NumRecords() == 0 {
+			return replayed, nil
+		}
+
+		for _, deadLetter := range fetches.Records() {
+			if day, ok := deadLetterStream(*deadLetter); ok {
+				if err := resummarize(ctx, resummarizer, resummarized, day); err != nil {
+					return replayed, err
+				}
+
+				replayed++
+				continue
+			}
+
+			record, err := NewReplayRecord(*deadLetter)
+			if err != nil {
+				slog.Warn("Skipping dead letter", "offset", deadLetter.Offset, "error", err)
+				continue
+			}
+
+			if err := client.ProduceSync(ctx, record).FirstErr(); err != nil {
+				return replayed, fmt.Errorf("failed to replay dead letter: %w", err)
+			}
+
+			replayed++
+		}
+
+		if err := client.CommitUncommittedOffsets(ctx); err != nil {
+			return replayed, fmt.Errorf("failed to commit offsets: %w", err)
+		}
+	}
+}
+
+// resummarize reads the day stream of a dead letter again, unless it has already been read during the replay.
+func resummarize(
+	ctx context.Context,
+	resummarizer Resummarizer,
+	resummarized map[streamDay]struct{},
+	day streamDay,
+) error {
+	if _, ok := resummarized[day]; ok {
+		return nil
+	}
+
+	if resummarizer == nil {
+		return fmt.Errorf("no resummarizer to replay the day stream of %s on %s", day.airport, day.date)
+	}
+
+	slog.Info("Resummarizing day stream of dead letters", "airport", day.airport, "date", day.date)
+
+	if err := resummarizer.Resummarize(ctx, day.airport, day.date); err != nil {
+		return fmt.Errorf("failed to resummarize day stream: %w", err)
+	}
+
+	resummarized[day] = struct{}{}
+
+	return nil
+}
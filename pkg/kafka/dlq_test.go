@@ -0,0 +1,220 @@
+package kafka_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	msgQueue "github.com/ansoncht/flight-microservices/pkg/kafka"
+	"github.com/ansoncht/flight-microservices/pkg/msgbus"
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kfake"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+const testDLQTopic = testTopic + "-dlq"
+
+// resummarizer records the day streams it is asked to read again.
+type resummarizer struct {
+	days []string
+}
+
+func (r *resummarizer) Resummarize(_ context.Context, airport string, date string) error {
+	r.days = append(r.days, airport+" "+date)
+	return nil
+}
+
+func TestNewDeadLetterWriter_Disabled_ShouldDrop(t *testing.T) {
+	writer, err := msgQueue.NewDeadLetterWriter(msgQueue.DLQConfig{})
+	require.NoError(t, err)
+	require.IsType(t, &msgQueue.NopDeadLetterWriter{}, writer)
+	require.NoError(t, writer.WriteDeadLetter(t.Context(), msgbus.Message{}, errors.New("bad record")))
+	writer.Close()
+}
+
+func TestNewDLQWriter_InvalidConfig_ShouldError(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     msgQueue.DLQConfig
+		wantErr string
+	}{
+		{
+			name:    "Missing Address",
+			cfg:     msgQueue.DLQConfig{Enabled: true, Topic: "flights-dlq"},
+			wantErr: "kafka broker address is empty",
+		},
+		{
+			name:    "Missing Topic",
+			cfg:     msgQueue.DLQConfig{Enabled: true, Address: "localhost:9092"},
+			wantErr: "kafka dead-letter topic is empty",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writer, err := msgQueue.NewDeadLetterWriter(tt.cfg)
+			require.ErrorContains(t, err, tt.wantErr)
+			require.Nil(t, writer)
+		})
+	}
+}
+
+func TestNewDeadLetterRecord_ShouldAddOriginHeaders(t *testing.T) {
+	timestamp := time.Date(2025, 5, 7, 0, 0, 0, 0, time.UTC)
+	msg := msgbus.Message{
+		Topic:     "flights",
+		Partition: 2,
+		Offset:    42,
+		Key:       []byte(testMessageKey),
+		Value:     []byte(testMessageValue),
+		Headers:   []msgbus.Header{{Key: "trace", Value: []byte("abc")}},
+		Timestamp: timestamp,
+	}
+
+	deadLetter := msgQueue.NewDeadLetterRecord(msg, errors.New("failed to parse flight record"))
+	require.Empty(t, deadLetter.Topic)
+	require.Equal(t, msg.Key, deadLetter.Key)
+	require.Equal(t, msg.Value, deadLetter.Value)
+	require.Equal(t, timestamp, deadLetter.Timestamp)
+	require.Equal(t, []kgo.RecordHeader{
+		{Key: "trace", Value: []byte("abc")},
+		{Key: msgQueue.HeaderOriginalTopic, Value: []byte("flights")},
+		{Key: msgQueue.HeaderOriginalPartition, Value: []byte("2")},
+		{Key: msgQueue.HeaderOriginalOffset, Value: []byte("42")},
+		{Key: msgQueue.HeaderError, Value: []byte("failed to parse flight record")},
+	}, deadLetter.Headers)
+}
+
+func TestNewReplayRecord_ShouldRestoreOriginalRecord(t *testing.T) {
+	msg := msgbus.Message{
+		Topic:   "flights",
+		Key:     []byte(testMessageKey),
+		Value:   []byte(testMessageValue),
+		Headers: []msgbus.Header{{Key: "trace", Value: []byte("abc")}},
+	}
+
+	replay, err := msgQueue.NewReplayRecord(*msgQueue.NewDeadLetterRecord(msg, nil))
+	require.NoError(t, err)
+	require.Equal(t, &kgo.Record{
+		Topic:   "flights",
+		Key:     []byte(testMessageKey),
+		Value:   []byte(testMessageValue),
+		Headers: []kgo.RecordHeader{{Key: "trace", Value: []byte("abc")}},
+	}, replay)
+}
+
+func TestWithStream_ShouldAddStreamHeaders(t *testing.T) {
+	msg := msgbus.Message{
+		Key:     []byte(testMessageKey),
+		Headers: []msgbus.Header{{Key: "trace", Value: []byte("abc")}},
+	}
+
+	streamed := msgQueue.WithStream(msg, "VHHH", "2025-05-07")
+	require.Equal(t, []msgbus.Header{
+		{Key: "trace", Value: []byte("abc")},
+		{Key: msgQueue.HeaderStreamAirport, Value: []byte("VHHH")},
+		{Key: msgQueue.HeaderStreamDate, Value: []byte("2025-05-07")},
+	}, streamed.Headers)
+	require.Len(t, msg.Headers, 1)
+
+	// Stream headers are not carried over when a dead letter is re-injected
+	replay, err := msgQueue.NewReplayRecord(*msgQueue.NewDeadLetterRecord(msgQueue.WithStream(msgbus.Message{
+		Topic: testTopic,
+		Key:   []byte(testMessageKey),
+	}, "VHHH", "2025-05-07"), nil))
+	require.NoError(t, err)
+	require.Empty(t, replay.Headers)
+}
+
+func TestNewReplayRecord_MissingTopic_ShouldError(t *testing.T) {
+	replay, err := msgQueue.NewReplayRecord(kgo.Record{Key: []byte(testMessageKey)})
+	require.ErrorContains(t, err, "dead letter has no original topic")
+	require.Nil(t, replay)
+}
+
+func TestReplayDeadLetters_InvalidConfig_ShouldError(t *testing.T) {
+	replayed, err := msgQueue.ReplayDeadLetters(t.Context(), msgQueue.DLQConfig{
+		Address: "localhost:9092",
+		Topic:   "flights-dlq",
+	}, nil)
+	require.ErrorContains(t, err, "kafka dead-letter replay group ID is empty")
+	require.Zero(t, replayed)
+}
+
+func TestReplayDeadLetters_ShouldResummarizeDayStreams(t *testing.T) {
+	cluster, err := kfake.NewCluster(kfake.NumBrokers(1), kfake.SeedTopics(1, testTopic, testDLQTopic))
+	require.NoError(t, err)
+	defer cluster.Close()
+
+	cfg := msgQueue.DLQConfig{
+		Enabled:       true,
+		Address:       cluster.ListenAddrs()[0],
+		Topic:         testDLQTopic,
+		ReplayGroupID: testGroupID + "-replay",
+	}
+
+	writer, err := msgQueue.NewDLQWriter(cfg)
+	require.NoError(t, err)
+	defer writer.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*timeout)
+	defer cancel()
+
+	// Two records of the same day stream and one record outside of any day stream
+	flight := msgbus.Message{Topic: testTopic, Key: []byte("flight"), Value: []byte("{")}
+	require.NoError(t, writer.WriteDeadLetter(ctx, msgQueue.WithStream(flight, "VHHH", "2025-05-07"), nil))
+	require.NoError(t, writer.WriteDeadLetter(ctx, msgQueue.WithStream(flight, "VHHH", "2025-05-07"), nil))
+	require.NoError(t, writer.WriteDeadLetter(ctx, msgbus.Message{
+		Topic: testTopic,
+		Key:   []byte("summary_id"),
+		Value: []byte("test_id"),
+	}, nil))
+
+	days := &resummarizer{}
+	replayed, err := msgQueue.ReplayDeadLetters(ctx, cfg, days)
+	require.NoError(t, err)
+	require.Equal(t, 3, replayed)
+	require.Equal(t, []string{"VHHH 2025-05-07"}, days.days)
+
+	// Only the record outside of any day stream is re-injected into its original topic
+	client, err := kgo.NewClient(
+		kgo.SeedBrokers(cfg.Address),
+		kgo.ConsumeTopics(testTopic),
+		kgo.ConsumeResetOffset(kgo.NewOffset().AtStart()),
+	)
+	require.NoError(t, err)
+	defer client.Close()
+
+	fetches := client.PollFetches(ctx)
+	require.NoError(t, fetches.Err())
+	require.Len(t, fetches.Records(), 1)
+	require.Equal(t, "summary_id", string(fetches.Records()[0].Key))
+}
+
+func TestReplayDeadLetters_DayStreamWithoutResummarizer_ShouldError(t *testing.T) {
+	cluster, err := kfake.NewCluster(kfake.NumBrokers(1), kfake.SeedTopics(1, testTopic, testDLQTopic))
+	require.NoError(t, err)
+	defer cluster.Close()
+
+	cfg := msgQueue.DLQConfig{
+		Enabled:       true,
+		Address:       cluster.ListenAddrs()[0],
+		Topic:         testDLQTopic,
+		ReplayGroupID: testGroupID + "-replay",
+	}
+
+	writer, err := msgQueue.NewDLQWriter(cfg)
+	require.NoError(t, err)
+	defer writer.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*timeout)
+	defer cancel()
+
+	flight := msgbus.Message{Topic: testTopic, Key: []byte("flight"), Value: []byte("{")}
+	require.NoError(t, writer.WriteDeadLetter(ctx, msgQueue.WithStream(flight, "VHHH", "2025-05-07"), nil))
+
+	replayed, err := msgQueue.ReplayDeadLetters(ctx, cfg, nil)
+	require.ErrorContains(t, err, "no resummarizer to replay the day stream of VHHH on 2025-05-07")
+	require.Zero(t, replayed)
+}
@@ -0,0 +1,62 @@
+package kafka
+
+import (
+	"fmt"
+
+	"github.com/ansoncht/flight-microservices/pkg/msgbus"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+// newRecords creates the records of a batch of messages.
+func newRecords(msgs []msgbus.Message) ([]*kgo.Record, error) {
+	records := make([]*kgo.Record, 0, len(msgs))
+	for i, msg := range msgs {
+		if err := msg.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid message %d: %w", i, err)
+		}
+
+		records = append(records, newRecord(msg))
+	}
+
+	return records, nil
+}
+
+// newMessage creates the message of a record read from Kafka.
+func newMessage(record *kgo.Record) msgbus.Message {
+	var headers []msgbus.Header
+	if len(record.Headers) > 0 {
+		headers = make([]msgbus.Header, 0, len(record.Headers))
+		for _, header := range record.Headers {
+			headers = append(headers, msgbus.Header{Key: header.Key, Value: header.Value})
+		}
+	}
+
+	return msgbus.Message{
+		Topic:     record.Topic,
+		Partition: record.Partition,
+		Offset:    record.Offset,
+		Key:       record.Key,
+		Value:     record.Value,
+		Headers:   headers,
+		Timestamp: record.Timestamp,
+	}
+}
+
+// newRecord creates the record of a message to write.
+func newRecord(msg msgbus.Message) *kgo.Record {
+	var headers []kgo.RecordHeader
+	if len(msg.Headers) > 0 {
+		headers = make([]kgo.RecordHeader, 0, len(msg.Headers))
+		for _, header := range msg.Headers {
+			headers = append(headers, kgo.RecordHeader{Key: header.Key, Value: header.Value})
+		}
+	}
+
+	return &kgo.Record{
+		Topic:     msg.Topic,
+		Key:       msg.Key,
+		Value:     msg.Value,
+		Headers:   headers,
+		Timestamp: msg.Timestamp,
+	}
+}
@@ -0,0 +1,149 @@
+package kafka
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ansoncht/flight-microservices/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+// metricsSubsystem specifies the subsystem of the Kafka metrics.
+const metricsSubsystem = "kafka"
+
+// Rebalance events counted by the rebalance metric.
+const (
+	rebalanceAssigned = "assigned"
+	rebalanceRevoked  = "revoked"
+	rebalanceLost     = "lost"
+)
+
+// The collectors are registered with the Prometheus default registry, so that a service serves them alongside its
+// own metrics.
+var (
+	consumerLag = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: metricsSubsystem,
+		Name:      "consumer_lag",
+		Help:      "Number of records between the last record polled and the high watermark of a partition.",
+	}, []string{"group", "topic", "partition"})
+	recordsConsumed = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: metricsSubsystem,
+		Name:      "records_consumed_total",
+		Help:      "Number of records polled by consumers.",
+	}, []string{"group", "topic"})
+	recordsProduced = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: metricsSubsystem,
+		Name:      "records_produced_total",
+		Help:      "Number of records acknowledged by the brokers.",
+	}, []string{"topic"})
+	produceErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: metricsSubsystem,
+		Name:      "produce_errors_total",
+		Help:      "Number of records which failed to be produced.",
+	}, []string{"topic"})
+	produceLatency = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: metricsSubsystem,
+		Name:      "produce_latency_seconds",
+		Help:      "Time from buffering a record until the brokers acknowledge it.",
+		Buckets:   metrics.LatencyBuckets,
+	}, []string{"topic"})
+	rebalances = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: metricsSubsystem,
+		Name:      "rebalances_total",
+		Help:      "Number of partition assignments, revocations and losses of consumer groups.",
+	}, []string{"group", "event"})
+)
+
+// clientMetrics records the metrics of a Kafka client.
+// It implements the franz-go hooks for consumed and produced records, and provides the partition callbacks of a
+// consumer group.
+type clientMetrics struct {
+	// group specifies the consumer group of the client, empty for producers.
+	group string
+	// buffered holds the time each record in flight was buffered.
+	buffered sync.Map
+}
+
+// newClientMetrics creates a new clientMetrics instance for a client of the given consumer group.
+func newClientMetrics(group string) *clientMetrics {
+	return &clientMetrics{
+		group: group,
+	}
+}
+
+// OnFetchRecordUnbuffered counts a record once it has been polled.
+func (m *clientMetrics) OnFetchRecordUnbuffered(record *kgo.Record, polled bool) {
+	if polled {
+		recordsConsumed.WithLabelValues(m.group, record.Topic).Inc()
+	}
+}
+
+// OnProduceRecordBuffered notes when a record was buffered to measure its produce latency.
+func (m *clientMetrics) OnProduceRecordBuffered(record *kgo.Record) {
+	m.buffered.Store(record, time.Now())
+}
+
+// OnProduceRecordUnbuffered counts a record once it has been produced or failed, and observes its produce latency.
+func (m *clientMetrics) OnProduceRecordUnbuffered(record *kgo.Record, err error) {
+	buffered, _ := m.buffered.LoadAndDelete(record)
+
+	if err != nil {
+		produceErrors.WithLabelValues(record.Topic).Inc()
+		return
+	}
+
+	recordsProduced.WithLabelValues(record.Topic).Inc()
+
+	if start, ok := buffered.(time.Time); ok {
+		produceLatency.WithLabelValues(record.Topic).Observe(time.Since(start).Seconds())
+	}
+}
+
+// observeLag sets the lag of each partition with records in the fetches from its high watermark.
+func (m *clientMetrics) observeLag(fetches kgo.Fetches) {
+	fetches.EachPartition(func(p kgo.FetchTopicPartition) {
+		if len(p.Records) == 0 {
+			return
+		}
+
+		last := p.Records[len(p.Records)-1]
+		lag := max(p.HighWatermark-last.Offset-1, 0)
+		consumerLag.WithLabelValues(m.group, p.Topic, strconv.Itoa(int(p.Partition))).Set(float64(lag))
+	})
+}
+
+// onPartitionsAssigned counts the assignment of partitions to the consumer group member.
+func (m *clientMetrics) onPartitionsAssigned(_ context.Context, _ *kgo.Client, _ map[string][]int32) {
+	rebalances.WithLabelValues(m.group, rebalanceAssigned).Inc()
+}
+
+// onPartitionsRevoked counts the revocation of partitions and stops reporting their lag.
+func (m *clientMetrics) onPartitionsRevoked(_ context.Context, _ *kgo.Client, revoked map[string][]int32) {
+	rebalances.WithLabelValues(m.group, rebalanceRevoked).Inc()
+	m.forget(revoked)
+}
+
+// onPartitionsLost counts the loss of partitions and stops reporting their lag.
+func (m *clientMetrics) onPartitionsLost(_ context.Context, _ *kgo.Client, lost map[string][]int32) {
+	rebalances.WithLabelValues(m.group, rebalanceLost).Inc()
+	m.forget(lost)
+}
+
+// forget removes the lag of partitions no longer consumed by the consumer group member.
+func (m *clientMetrics) forget(partitions map[string][]int32) {
+	for topic, ids := range partitions {
+		for _, id := range ids {
+			consumerLag.DeleteLabelValues(m.group, topic, strconv.Itoa(int(id)))
+		}
+	}
+}
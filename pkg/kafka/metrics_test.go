@@ -0,0 +1,146 @@
+package kafka_test
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	msgQueue "github.com/ansoncht/flight-microservices/pkg/kafka"
+	"github.com/ansoncht/flight-microservices/pkg/msgbus"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/require"
+)
+
+// metricValue returns the value of the metric with the given labels from the default registry, or zero if it has
+// not been recorded.
+func metricValue(t *testing.T, name string, labels map[string]string) float64 {
+	t.Helper()
+
+	value, _ := lookupMetric(t, name, labels)
+
+	return value
+}
+
+// lookupMetric returns the value of the metric with the given labels from the default registry and whether it has
+// been recorded.
+func lookupMetric(t *testing.T, name string, labels map[string]string) (float64, bool) {
+	t.Helper()
+
+	families, err := prometheus.DefaultGatherer.Gather()
+	require.NoError(t, err)
+
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+
+		for _, metric := range family.GetMetric() {
+			if !hasLabels(metric, labels) {
+				continue
+			}
+
+			switch {
+			case metric.GetCounter() != nil:
+				return metric.GetCounter().GetValue(), true
+			case metric.GetGauge() != nil:
+				return metric.GetGauge().GetValue(), true
+			case metric.GetHistogram() != nil:
+				return float64(metric.GetHistogram().GetSampleCount()), true
+			}
+		}
+	}
+
+	return 0, false
+}
+
+// hasLabels reports whether the metric has each of the given labels.
+func hasLabels(metric *dto.Metric, labels map[string]string) bool {
+	matched := 0
+	for _, pair := range metric.GetLabel() {
+		if value, ok := labels[pair.GetName()]; ok && value == pair.GetValue() {
+			matched++
+		}
+	}
+
+	return matched == len(labels)
+}
+
+func TestWriteMessages_ShouldRecordProduceMetrics(t *testing.T) {
+	address := setupFakeKafka(t, 0)
+
+	writer, err := msgQueue.NewKafkaWriter(msgQueue.WriterConfig{Address: address, Topic: testTopic})
+	require.NoError(t, err)
+	defer writer.Close()
+
+	topic := map[string]string{"topic": testTopic}
+	produced := metricValue(t, "flight_kafka_records_produced_total", topic)
+	observed := metricValue(t, "flight_kafka_produce_latency_seconds", topic)
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	err = writer.WriteMessages(ctx, []msgbus.Message{
+		{Key: []byte(testMessageKey), Value: []byte(testMessageValue)},
+		{Key: []byte(testMessageKey), Value: []byte(testMessageValue)},
+		{Key: []byte(testMessageKey), Value: []byte(testMessageValue)},
+	})
+	require.NoError(t, err)
+
+	require.InDelta(t, produced+3, metricValue(t, "flight_kafka_records_produced_total", topic), 0)
+	require.InDelta(t, observed+3, metricValue(t, "flight_kafka_produce_latency_seconds", topic), 0)
+}
+
+func TestReadMessages_ShouldRecordConsumerMetrics(t *testing.T) {
+	const records = 30
+
+	address := setupFakeKafka(t, records)
+	groupID := testGroupID + "-metrics"
+
+	reader, err := msgQueue.NewKafkaReader(msgQueue.ReaderConfig{
+		Address: address,
+		Topic:   testTopic,
+		GroupID: groupID,
+	})
+	require.NoError(t, err)
+
+	readAll(t, reader, make(chan msgbus.Delivery, records), records)
+
+	consumed := metricValue(t, "flight_kafka_records_consumed_total", map[string]string{
+		"group": groupID,
+		"topic": testTopic,
+	})
+	require.InDelta(t, records, consumed, 0)
+	require.GreaterOrEqual(t, metricValue(t, "flight_kafka_rebalances_total", map[string]string{
+		"group": groupID,
+		"event": "assigned",
+	}), 1.0)
+
+	// Every record has been read, so no partition with records lags behind
+	reported := 0
+	for partition := range benchPartitions {
+		lag, ok := lookupMetric(t, "flight_kafka_consumer_lag", lagLabels(groupID, partition))
+		if ok {
+			reported++
+			require.Zero(t, lag)
+		}
+	}
+	require.Positive(t, reported)
+
+	// Leaving the group revokes the partitions, so their lag is no longer reported
+	reader.Close()
+	require.GreaterOrEqual(t, metricValue(t, "flight_kafka_rebalances_total", map[string]string{
+		"group": groupID,
+		"event": "revoked",
+	}), 1.0)
+
+	for partition := range benchPartitions {
+		_, ok := lookupMetric(t, "flight_kafka_consumer_lag", lagLabels(groupID, partition))
+		require.False(t, ok)
+	}
+}
+
+// lagLabels returns the labels of the consumer lag of a partition of the test topic.
+func lagLabels(groupID string, partition int) map[string]string {
+	return map[string]string{"group": groupID, "topic": testTopic, "partition": strconv.Itoa(partition)}
+}
@@ -8,11 +8,17 @@ import (
 	"strings"
 	"time"
 
+	"github.com/ansoncht/flight-microservices/pkg/msgbus"
 	"github.com/twmb/franz-go/pkg/kgo"
 )
 
 const (
-	pollInterval = 5 * time.Second
+	// defaultMaxPollRecords specifies the maximum number of records handled per poll when unset.
+	defaultMaxPollRecords = 500
+	// idleTimeout specifies how long a poll waits for records before the topic is considered idle.
+	idleTimeout = 5 * time.Second
+	// retryDelay specifies how long the reader waits before polling again after a fetch error.
+	retryDelay = time.Second
 )
 
 // ReaderConfig holds configuration settings for the Kafka reader.
@@ -23,20 +29,32 @@ type ReaderConfig struct {
 	Topic string `mapstructure:"topic"`
 	// GroupID specifies the consumer group ID.
 	GroupID string `mapstructure:"group_id"`
-}
-
-// MessageReader defines the interface for reading messages from a message queue.
-type MessageReader interface {
-	// ReadMessages reads messages from the message queue.
-	ReadMessages(ctx context.Context, msgChan chan<- kgo.Record) error
-	// Close closes the message queue reader.
-	Close()
+	// MaxPollRecords specifies the maximum number of records handled per poll. Defaults to 500.
+	MaxPollRecords int `mapstructure:"max_poll_records"`
+	// FetchMaxBytes specifies the maximum bytes a fetch response returns. Defaults to the franz-go default.
+	FetchMaxBytes int32 `mapstructure:"fetch_max_bytes"`
+	// FetchMaxPartitionBytes specifies the maximum bytes a fetch response returns for a partition.
+	// Defaults to the franz-go default.
+	FetchMaxPartitionBytes int32 `mapstructure:"fetch_max_partition_bytes"`
+	// FetchMaxWaitMs specifies the milliseconds a broker waits for records before answering a fetch.
+	// Defaults to the franz-go default.
+	FetchMaxWaitMs int `mapstructure:"fetch_max_wait_ms"`
+	// TLS specifies the TLS settings for connecting to the brokers.
+	TLS TLSConfig `mapstructure:"tls"`
+	// SASL specifies the SASL settings for authenticating to the brokers.
+	SASL SASLConfig `mapstructure:"sasl"`
 }
 
 // Reader holds the Kafka reader instance.
 type Reader struct {
 	// Client specifies the kafka client instance.
 	Client *kgo.Client
+	// tracker specifies the tracker marking acknowledged records for commit.
+	tracker *OffsetTracker
+	// metrics specifies the metrics of the consumer group member.
+	metrics *clientMetrics
+	// maxPollRecords specifies the maximum number of records handled per poll.
+	maxPollRecords int
 }
 
 // NewKafkaReader creates a new Reader instance based on the provided configuration.
@@ -60,20 +78,59 @@ func NewKafkaReader(cfg ReaderConfig) (*Reader, error) {
 		return nil, fmt.Errorf("kafka group ID is empty")
 	}
 
+	fetchOpts, err := fetchOptions(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	securityOpts, err := securityOptions(cfg.TLS, cfg.SASL)
+	if err != nil {
+		return nil, err
+	}
+
+	reader := &Reader{
+		metrics:        newClientMetrics(cfg.GroupID),
+		maxPollRecords: defaultMaxPollRecords,
+	}
+	if cfg.MaxPollRecords > 0 {
+		reader.maxPollRecords = cfg.MaxPollRecords
+	}
+
 	addresses := strings.Split(cfg.Address, ",")
 	opts := []kgo.Opt{
 		kgo.SeedBrokers(addresses...),
 		kgo.ConsumerGroup(cfg.GroupID),
 		kgo.ConsumeTopics(cfg.Topic),
+		// Skip messages of aborted transactions
+		kgo.FetchIsolationLevel(kgo.ReadCommitted()),
+		// Only commit offsets of acknowledged records
+		kgo.AutoCommitMarks(),
+		kgo.OnPartitionsAssigned(reader.metrics.onPartitionsAssigned),
+		kgo.OnPartitionsRevoked(reader.onPartitionsRevoked),
+		kgo.OnPartitionsLost(reader.onPartitionsLost),
+		kgo.WithHooks(reader.metrics, newClientTracing(cfg.GroupID)),
 	}
+	opts = append(opts, fetchOpts...)
+	opts = append(opts, securityOpts...)
+
 	client, err := kgo.NewClient(opts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create Kafka client: %w", err)
 	}
 
-	return &Reader{
-		Client: client,
-	}, nil
+	reader.Client = client
+	reader.tracker = NewOffsetTracker(client.MarkCommitRecords)
+
+	return reader, nil
+}
+
+// Check pings the Kafka brokers.
+func (r *Reader) Check(ctx context.Context) error {
+	if err := r.Client.Ping(ctx); err != nil {
+		return fmt.Errorf("failed to ping Kafka brokers: %w", err)
+	}
+
+	return nil
 }
 
 // Close closes the Kafka reader.
@@ -82,7 +139,12 @@ func (r *Reader) Close() {
 }
 
 // ReadMessages reads messages from the Kafka topic and sends them to the provided channel.
-func (r *Reader) ReadMessages(ctx context.Context, msgChan chan<- kgo.Record) error {
+// Records are polled continuously in batches of up to the configured maximum, and the partitions of a batch are
+// paused while the channel is full so that no more records are fetched until the consumer catches up. The channel
+// should be buffered, as a send on an unbuffered channel blocks until the consumer is ready and pauses almost
+// every batch. Polling waits a moment after a fetch error, so that an unreachable broker is not polled continuously.
+// The offset of a message is committed once it and the messages before it on its partition have been acknowledged.
+func (r *Reader) ReadMessages(ctx context.Context, msgChan chan<- msgbus.Delivery) error {
 	slog.Info("Reading message from Kafka topic")
 
 	defer close(msgChan)
@@ -91,45 +153,40 @@ func (r *Reader) ReadMessages(ctx context.Context, msgChan chan<- kgo.Record) er
 		return fmt.Errorf("kafka reader is nil")
 	}
 
-	// Poll every 5 seconds
-	pollTicker := time.NewTicker(pollInterval)
-	defer pollTicker.Stop()
-
 readingLoop:
 	for {
-		select {
-		case <-ctx.Done():
+		fetches := r.Client.PollRecords(ctx, r.maxPollRecords)
+		if fetches.IsClientClosed() {
 			break readingLoop
-		case <-pollTicker.C:
-			fetches := r.Client.PollFetches(ctx)
-
-			if errs := fetches.Errors(); len(errs) > 0 {
-				for _, err := range errs {
-					if errors.Is(err.Err, context.Canceled) {
-						break readingLoop
-					}
-
-					if err.Topic != "" || err.Partition != -1 || err.Err != nil {
-						slog.Error("Failed to fetch message from Kafka", "errors", err)
-					}
-				}
+		}
+
+		failed := false
+		for _, err := range fetches.Errors() {
+			if errors.Is(err.Err, context.Canceled) || errors.Is(err.Err, context.DeadlineExceeded) {
+				break readingLoop
 			}
 
-			// Process fetched messages
-			fetches.EachPartition(func(p kgo.FetchTopicPartition) {
-				for _, record := range p.Records {
-					select {
-					case <-ctx.Done():
-						return
-					case msgChan <- *record:
-						r.Client.MarkCommitRecords(record)
-					}
-				}
-			})
+			slog.Error("Failed to fetch message from Kafka", "errors", err)
+			failed = true
+		}
+
+		r.metrics.observeLag(fetches)
+
+		if !r.deliver(ctx, fetches, msgChan) {
+			break readingLoop
+		}
+
+		// Wait before polling again so that an unreachable broker is not polled in a tight loop
+		if failed {
+			select {
+			case <-ctx.Done():
+				break readingLoop
+			case <-time.After(retryDelay):
+			}
 		}
 	}
 
-	if err := r.Client.CommitUncommittedOffsets(ctx); err != nil {
+	if err := r.Client.CommitMarkedOffsets(context.WithoutCancel(ctx)); err != nil {
 		return fmt.Errorf("failed to commit offsets: %w", err)
 	}
 
@@ -139,3 +196,101 @@ readingLoop:
 
 	return nil
 }
+
+// deliver sends the records of a batch to the channel. Once the channel is full, it pauses fetching the
+// partitions of the batch until the batch has been delivered. It returns false if the context is done.
+func (r *Reader) deliver(ctx context.Context, fetches kgo.Fetches, msgChan chan<- msgbus.Delivery) bool {
+	paused := false
+	defer func() {
+		if paused {
+			r.Client.ResumeFetchPartitions(batchPartitions(fetches))
+		}
+	}()
+
+	for iter := fetches.RecordIter(); !iter.Done(); {
+		record := iter.Next()
+		r.tracker.Track(record)
+		delivery := msgbus.NewDelivery(newMessage(record), r.tracker)
+
+		select {
+		case msgChan <- delivery:
+			continue
+		default:
+		}
+
+		if !paused {
+			r.Client.PauseFetchPartitions(batchPartitions(fetches))
+			paused = true
+		}
+
+		select {
+		case <-ctx.Done():
+			return false
+		case msgChan <- delivery:
+		}
+	}
+
+	return true
+}
+
+// batchPartitions returns the partitions of each topic with records in the fetches.
+func batchPartitions(fetches kgo.Fetches) map[string][]int32 {
+	partitions := make(map[string][]int32)
+	fetches.EachPartition(func(p kgo.FetchTopicPartition) {
+		if len(p.Records) > 0 {
+			partitions[p.Topic] = append(partitions[p.Topic], p.Partition)
+		}
+	})
+
+	return partitions
+}
+
+// fetchOptions returns the franz-go options for the fetch sizes set in the configuration.
+func fetchOptions(cfg ReaderConfig) ([]kgo.Opt, error) {
+	if cfg.MaxPollRecords < 0 {
+		return nil, fmt.Errorf("kafka max poll records is invalid: %d", cfg.MaxPollRecords)
+	}
+
+	if cfg.FetchMaxBytes < 0 {
+		return nil, fmt.Errorf("kafka fetch max bytes is invalid: %d", cfg.FetchMaxBytes)
+	}
+
+	if cfg.FetchMaxPartitionBytes < 0 || (cfg.FetchMaxBytes > 0 && cfg.FetchMaxPartitionBytes > cfg.FetchMaxBytes) {
+		return nil, fmt.Errorf("kafka fetch max partition bytes is invalid: %d", cfg.FetchMaxPartitionBytes)
+	}
+
+	if cfg.FetchMaxWaitMs < 0 {
+		return nil, fmt.Errorf("kafka fetch max wait is invalid: %d", cfg.FetchMaxWaitMs)
+	}
+
+	opts := make([]kgo.Opt, 0)
+	if cfg.FetchMaxBytes > 0 {
+		opts = append(opts, kgo.FetchMaxBytes(cfg.FetchMaxBytes))
+	}
+
+	if cfg.FetchMaxPartitionBytes > 0 {
+		opts = append(opts, kgo.FetchMaxPartitionBytes(cfg.FetchMaxPartitionBytes))
+	}
+
+	if cfg.FetchMaxWaitMs > 0 {
+		opts = append(opts, kgo.FetchMaxWait(time.Duration(cfg.FetchMaxWaitMs)*time.Millisecond))
+	}
+
+	return opts, nil
+}
+
+// onPartitionsRevoked commits the acknowledged records of revoked partitions and stops tracking them.
+func (r *Reader) onPartitionsRevoked(ctx context.Context, client *kgo.Client, revoked map[string][]int32) {
+	if err := client.CommitMarkedOffsets(ctx); err != nil {
+		slog.Error("Failed to commit offsets of revoked partitions", "error", err)
+	}
+
+	r.tracker.Revoke(revoked)
+	r.metrics.onPartitionsRevoked(ctx, client, revoked)
+}
+
+// onPartitionsLost stops tracking partitions which were lost without a chance to commit.
+func (r *Reader) onPartitionsLost(ctx context.Context, client *kgo.Client, lost map[string][]int32) {
+	r.tracker.Revoke(lost)
+	r.metrics.onPartitionsLost(ctx, client, lost)
+}
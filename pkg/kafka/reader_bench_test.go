@@ -0,0 +1,144 @@
+package kafka_test
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+
+	msgQueue "github.com/ansoncht/flight-microservices/pkg/kafka"
+	"github.com/ansoncht/flight-microservices/pkg/msgbus"
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kfake"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+const (
+	benchPartitions = 3
+	benchRecords    = 10000
+)
+
+// setupFakeKafka starts an in-process Kafka cluster with the test topic seeded with the given number of records.
+func setupFakeKafka(tb testing.TB, records int) string {
+	tb.Helper()
+
+	cluster, err := kfake.NewCluster(
+		kfake.NumBrokers(1),
+		kfake.SeedTopics(benchPartitions, testTopic),
+	)
+	require.NoError(tb, err)
+	tb.Cleanup(cluster.Close)
+
+	address := cluster.ListenAddrs()[0]
+	if records == 0 {
+		return address
+	}
+
+	client, err := kgo.NewClient(
+		kgo.SeedBrokers(address),
+		kgo.DefaultProduceTopic(testTopic),
+	)
+	require.NoError(tb, err)
+	defer client.Close()
+
+	batch := make([]*kgo.Record, 0, records)
+	for i := range records {
+		batch = append(batch, &kgo.Record{
+			Key:   []byte(strconv.Itoa(i)),
+			Value: []byte(testMessageValue),
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	require.NoError(tb, client.ProduceSync(ctx, batch...).FirstErr())
+
+	return address
+}
+
+// readAll reads the given number of messages from the reader, acknowledging each, and stops the reader.
+func readAll(tb testing.TB, reader *msgQueue.Reader, msgChan chan msgbus.Delivery, records int) []msgbus.Delivery {
+	tb.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	readErrChan := make(chan error, 1)
+	go func() {
+		readErrChan <- reader.ReadMessages(ctx, msgChan)
+	}()
+
+	deliveries := make([]msgbus.Delivery, 0, records)
+	for len(deliveries) < records {
+		select {
+		case msg := <-msgChan:
+			msg.Ack()
+			deliveries = append(deliveries, msg)
+		case <-time.After(timeout):
+			tb.Fatalf("timed out after reading %d of %d messages", len(deliveries), records)
+		}
+	}
+
+	cancel()
+	require.ErrorIs(tb, <-readErrChan, context.Canceled)
+
+	return deliveries
+}
+
+func TestReadMessages_FullChannel_ShouldDeliverAllInOrder(t *testing.T) {
+	const records = 1000
+
+	address := setupFakeKafka(t, records)
+
+	reader, err := msgQueue.NewKafkaReader(msgQueue.ReaderConfig{
+		Address:        address,
+		Topic:          testTopic,
+		GroupID:        testGroupID + "-backpressure",
+		MaxPollRecords: 100,
+	})
+	require.NoError(t, err)
+	defer reader.Close()
+
+	// An unbuffered channel keeps the reader blocked on each send, pausing its partitions
+	deliveries := readAll(t, reader, make(chan msgbus.Delivery), records)
+
+	seen := make(map[string]bool, records)
+	lastOffsets := make(map[int32]int64, benchPartitions)
+	for _, delivery := range deliveries {
+		key := string(delivery.Key)
+		require.False(t, seen[key], "message %s delivered twice", key)
+		seen[key] = true
+
+		if last, ok := lastOffsets[delivery.Partition]; ok {
+			require.Greater(t, delivery.Offset, last)
+		}
+		lastOffsets[delivery.Partition] = delivery.Offset
+	}
+	require.Len(t, seen, records)
+}
+
+func BenchmarkReadMessages(b *testing.B) {
+	for _, maxPollRecords := range []int{1, 100, 500} {
+		b.Run(fmt.Sprintf("MaxPollRecords=%d", maxPollRecords), func(b *testing.B) {
+			address := setupFakeKafka(b, benchRecords)
+
+			b.ResetTimer()
+			for i := range b.N {
+				reader, err := msgQueue.NewKafkaReader(msgQueue.ReaderConfig{
+					Address:        address,
+					Topic:          testTopic,
+					GroupID:        fmt.Sprintf("%s-bench-%d-%d", testGroupID, maxPollRecords, i),
+					MaxPollRecords: maxPollRecords,
+				})
+				require.NoError(b, err)
+
+				readAll(b, reader, make(chan msgbus.Delivery, maxPollRecords), benchRecords)
+				reader.Close()
+			}
+
+			b.ReportMetric(float64(benchRecords*b.N)/b.Elapsed().Seconds(), "records/s")
+		})
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	msgQueue "github.com/ansoncht/flight-microservices/pkg/kafka"
+	"github.com/ansoncht/flight-microservices/pkg/msgbus"
 	"github.com/stretchr/testify/require"
 	"github.com/testcontainers/testcontainers-go/modules/kafka"
 	"github.com/twmb/franz-go/pkg/kadm"
@@ -111,6 +112,43 @@ func TestNewKafkaReader_InvalidConfig_ShouldError(t *testing.T) {
 			}(),
 			wantErr: "kafka group ID is empty",
 		},
+		{
+			name: "Negative Max Poll Records",
+			cfg: func() msgQueue.ReaderConfig {
+				c := cfg
+				c.MaxPollRecords = -1
+				return c
+			}(),
+			wantErr: "kafka max poll records is invalid",
+		},
+		{
+			name: "Negative Fetch Max Bytes",
+			cfg: func() msgQueue.ReaderConfig {
+				c := cfg
+				c.FetchMaxBytes = -1
+				return c
+			}(),
+			wantErr: "kafka fetch max bytes is invalid",
+		},
+		{
+			name: "Partition Bytes Above Fetch Max Bytes",
+			cfg: func() msgQueue.ReaderConfig {
+				c := cfg
+				c.FetchMaxBytes = 1024
+				c.FetchMaxPartitionBytes = 2048
+				return c
+			}(),
+			wantErr: "kafka fetch max partition bytes is invalid",
+		},
+		{
+			name: "Negative Fetch Max Wait",
+			cfg: func() msgQueue.ReaderConfig {
+				c := cfg
+				c.FetchMaxWaitMs = -1
+				return c
+			}(),
+			wantErr: "kafka fetch max wait is invalid",
+		},
 	}
 
 	for _, tt := range tests {
@@ -159,7 +197,7 @@ func TestReadMessages_Integration(t *testing.T) {
 	defer reader.Close()
 
 	t.Run("Successful ReadMessages", func(t *testing.T) {
-		msgChan := make(chan kgo.Record, 1)
+		msgChan := make(chan msgbus.Delivery, 1)
 		readErrChan := make(chan error, 1)
 		readCtx, readCancel := context.WithCancel(ctx)
 		defer readCancel()
@@ -172,6 +210,7 @@ func TestReadMessages_Integration(t *testing.T) {
 		case msg := <-msgChan:
 			require.Equal(t, testMessageKey, string(msg.Key))
 			require.Equal(t, testMessageValue, string(msg.Value))
+			msg.Ack()
 		case err := <-readErrChan:
 			require.NoError(t, err)
 		case <-time.After(timeout):
@@ -190,7 +229,7 @@ func TestReadMessages_Integration(t *testing.T) {
 	})
 
 	t.Run("Nil Reader", func(t *testing.T) {
-		msgChan := make(chan kgo.Record, 1)
+		msgChan := make(chan msgbus.Delivery, 1)
 		readCtx, readCancel := context.WithCancel(ctx)
 		defer readCancel()
 
@@ -203,7 +242,7 @@ func TestReadMessages_Integration(t *testing.T) {
 		cancelCtx, cancel := context.WithCancel(context.Background())
 		cancel()
 
-		msgChan := make(chan kgo.Record)
+		msgChan := make(chan msgbus.Delivery)
 		err = reader.ReadMessages(cancelCtx, msgChan)
 		require.ErrorIs(t, err, context.Canceled)
 	})
@@ -0,0 +1,187 @@
+package kafka
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/sasl/oauth"
+	"github.com/twmb/franz-go/pkg/sasl/plain"
+	"github.com/twmb/franz-go/pkg/sasl/scram"
+)
+
+// Supported SASL mechanisms.
+const (
+	SASLPlain       = "plain"
+	SASLScramSHA256 = "scram-sha-256"
+	SASLScramSHA512 = "scram-sha-512"
+	SASLOAuthBearer = "oauthbearer"
+)
+
+// TLSConfig holds configuration settings for connecting to Kafka brokers over TLS.
+type TLSConfig struct {
+	// Enabled specifies whether connections to the brokers use TLS.
+	Enabled bool `mapstructure:"enabled"`
+	// CAFile specifies the PEM file of the certificate authorities trusted to sign broker certificates.
+	// Defaults to the system certificate pool.
+	CAFile string `mapstructure:"ca_file"`
+	// CertFile specifies the PEM file of the client certificate for mutual TLS.
+	CertFile string `mapstructure:"cert_file"`
+	// KeyFile specifies the PEM file of the client private key for mutual TLS.
+	KeyFile string `mapstructure:"key_file"`
+	// InsecureSkipVerify specifies whether broker certificates are accepted without verification.
+	InsecureSkipVerify bool `mapstructure:"insecure_skip_verify"`
+}
+
+// SASLConfig holds configuration settings for authenticating to Kafka brokers with SASL.
+type SASLConfig struct {
+	// Mechanism specifies the SASL mechanism: plain, scram-sha-256, scram-sha-512 or oauthbearer.
+	// Defaults to no authentication.
+	Mechanism string `mapstructure:"mechanism"`
+	// Username specifies the username for the plain and scram mechanisms.
+	Username string `mapstructure:"username"`
+	// Password specifies the password for the plain and scram mechanisms.
+	Password string `mapstructure:"password"`
+	// Token specifies a static bearer token for the oauthbearer mechanism.
+	Token string `mapstructure:"token"`
+	// TokenFile specifies the file holding the bearer token for the oauthbearer mechanism instead of Token.
+	// It is read again on each authentication, so that a token refreshed by another process is picked up when
+	// the brokers ask the client to reauthenticate.
+	TokenFile string `mapstructure:"token_file"`
+}
+
+// securityOptions returns the franz-go options for the TLS and SASL settings, validating them.
+func securityOptions(tlsCfg TLSConfig, saslCfg SASLConfig) ([]kgo.Opt, error) {
+	opts := make([]kgo.Opt, 0)
+
+	if tlsCfg.Enabled {
+		config, err := newTLSConfig(tlsCfg)
+		if err != nil {
+			return nil, err
+		}
+
+		opts = append(opts, kgo.DialTLSConfig(config))
+	} else if tlsCfg.CAFile != "" || tlsCfg.CertFile != "" || tlsCfg.KeyFile != "" {
+		return nil, fmt.Errorf("kafka tls files are set but tls is disabled")
+	}
+
+	mechanism := strings.ToLower(saslCfg.Mechanism)
+
+	switch mechanism {
+	case "":
+		return opts, nil
+	case SASLPlain, SASLScramSHA256, SASLScramSHA512:
+		if saslCfg.Username == "" || saslCfg.Password == "" {
+			return nil, fmt.Errorf("kafka sasl username or password is empty")
+		}
+	case SASLOAuthBearer:
+		if saslCfg.Token != "" && saslCfg.TokenFile != "" {
+			return nil, fmt.Errorf("kafka sasl oauth token and token file are both set")
+		}
+
+		if saslCfg.Token == "" && saslCfg.TokenFile == "" {
+			return nil, fmt.Errorf("kafka sasl oauth token is empty")
+		}
+
+		// Fail on startup rather than on the first connection when the token file cannot be read
+		if saslCfg.TokenFile != "" {
+			if _, err := readTokenFile(saslCfg.TokenFile); err != nil {
+				return nil, err
+			}
+		}
+	default:
+		return nil, fmt.Errorf("kafka sasl mechanism is unsupported: %s", saslCfg.Mechanism)
+	}
+
+	if !tlsCfg.Enabled {
+		slog.Warn("Authenticating to Kafka with SASL without TLS", "mechanism", mechanism)
+	}
+
+	switch mechanism {
+	case SASLPlain:
+		opts = append(opts, kgo.SASL(plain.Auth{User: saslCfg.Username, Pass: saslCfg.Password}.AsMechanism()))
+	case SASLScramSHA256:
+		opts = append(opts, kgo.SASL(scram.Auth{User: saslCfg.Username, Pass: saslCfg.Password}.AsSha256Mechanism()))
+	case SASLScramSHA512:
+		opts = append(opts, kgo.SASL(scram.Auth{User: saslCfg.Username, Pass: saslCfg.Password}.AsSha512Mechanism()))
+	case SASLOAuthBearer:
+		if saslCfg.TokenFile != "" {
+			opts = append(opts, kgo.SASL(oauth.Oauth(TokenFromFile(saslCfg.TokenFile))))
+		} else {
+			opts = append(opts, kgo.SASL(oauth.Auth{Token: saslCfg.Token}.AsMechanism()))
+		}
+	}
+
+	return opts, nil
+}
+
+// TokenFromFile returns the oauthbearer authentication function reading the bearer token from the file on each
+// authentication.
+func TokenFromFile(path string) func(context.Context) (oauth.Auth, error) {
+	return func(context.Context) (oauth.Auth, error) {
+		token, err := readTokenFile(path)
+		if err != nil {
+			return oauth.Auth{}, err
+		}
+
+		return oauth.Auth{Token: token}, nil
+	}
+}
+
+// readTokenFile reads a bearer token from a file, ignoring surrounding whitespace.
+func readTokenFile(path string) (string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read kafka sasl oauth token file: %w", err)
+	}
+
+	token := strings.TrimSpace(string(content))
+	if token == "" {
+		return "", fmt.Errorf("kafka sasl oauth token file is empty: %s", path)
+	}
+
+	return token, nil
+}
+
+// newTLSConfig creates the TLS configuration trusting the configured certificate authorities and presenting the
+// configured client certificate.
+func newTLSConfig(cfg TLSConfig) (*tls.Config, error) {
+	config := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: cfg.InsecureSkipVerify, //nolint:gosec // opted into explicitly for test clusters
+	}
+
+	if cfg.CAFile != "" {
+		ca, err := os.ReadFile(cfg.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read kafka tls CA file: %w", err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(ca) {
+			return nil, fmt.Errorf("kafka tls CA file has no valid certificates: %s", cfg.CAFile)
+		}
+
+		config.RootCAs = pool
+	}
+
+	if (cfg.CertFile == "") != (cfg.KeyFile == "") {
+		return nil, fmt.Errorf("kafka tls client certificate and key must be set together")
+	}
+
+	if cfg.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load kafka tls client certificate: %w", err)
+		}
+
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	return config, nil
+}
@@ -0,0 +1,220 @@
+package kafka_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	msgQueue "github.com/ansoncht/flight-microservices/pkg/kafka"
+	"github.com/stretchr/testify/require"
+)
+
+// writeTestCertificate writes a self-signed certificate and its key to PEM files and returns their paths.
+func writeTestCertificate(t *testing.T) (certFile string, keyFile string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "kafka-test"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	certFile = filepath.Join(dir, "cert.pem")
+	keyFile = filepath.Join(dir, "key.pem")
+
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+
+	return certFile, keyFile
+}
+
+// writeTestToken writes a bearer token to a file and returns its path.
+func writeTestToken(t *testing.T, token string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(path, []byte(token), 0o600))
+
+	return path
+}
+
+func TestTokenFromFile_ShouldReadRefreshedToken(t *testing.T) {
+	path := writeTestToken(t, "first\n")
+	source := msgQueue.TokenFromFile(path)
+
+	auth, err := source(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "first", auth.Token)
+
+	require.NoError(t, os.WriteFile(path, []byte("second\n"), 0o600))
+
+	auth, err = source(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "second", auth.Token)
+
+	require.NoError(t, os.Remove(path))
+
+	_, err = source(context.Background())
+	require.ErrorContains(t, err, "failed to read kafka sasl oauth token file")
+}
+
+func TestNewKafkaWriter_ValidSecurityConfig_ShouldSucceed(t *testing.T) {
+	certFile, keyFile := writeTestCertificate(t)
+
+	tests := []struct {
+		name string
+		tls  msgQueue.TLSConfig
+		sasl msgQueue.SASLConfig
+	}{
+		{
+			name: "TLS With System Pool",
+			tls:  msgQueue.TLSConfig{Enabled: true},
+		},
+		{
+			name: "Mutual TLS",
+			tls:  msgQueue.TLSConfig{Enabled: true, CAFile: certFile, CertFile: certFile, KeyFile: keyFile},
+		},
+		{
+			name: "SASL Plain",
+			tls:  msgQueue.TLSConfig{Enabled: true},
+			sasl: msgQueue.SASLConfig{Mechanism: "plain", Username: "user", Password: "pass"},
+		},
+		{
+			name: "SASL SCRAM-SHA-256",
+			tls:  msgQueue.TLSConfig{Enabled: true},
+			sasl: msgQueue.SASLConfig{Mechanism: "SCRAM-SHA-256", Username: "user", Password: "pass"},
+		},
+		{
+			name: "SASL SCRAM-SHA-512",
+			tls:  msgQueue.TLSConfig{Enabled: true, CAFile: certFile},
+			sasl: msgQueue.SASLConfig{Mechanism: "scram-sha-512", Username: "user", Password: "pass"},
+		},
+		{
+			name: "SASL OAUTHBEARER",
+			tls:  msgQueue.TLSConfig{Enabled: true, InsecureSkipVerify: true},
+			sasl: msgQueue.SASLConfig{Mechanism: "oauthbearer", Token: "token"},
+		},
+		{
+			name: "SASL OAUTHBEARER Token File",
+			tls:  msgQueue.TLSConfig{Enabled: true},
+			sasl: msgQueue.SASLConfig{Mechanism: "oauthbearer", TokenFile: writeTestToken(t, "token")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writer, err := msgQueue.NewKafkaWriter(msgQueue.WriterConfig{
+				Address: "localhost:9092",
+				Topic:   testTopic,
+				TLS:     tt.tls,
+				SASL:    tt.sasl,
+			})
+			require.NoError(t, err)
+			require.NotNil(t, writer)
+			writer.Close()
+		})
+	}
+}
+
+func TestNewKafkaReader_InvalidSecurityConfig_ShouldError(t *testing.T) {
+	certFile, keyFile := writeTestCertificate(t)
+
+	tests := []struct {
+		name    string
+		tls     msgQueue.TLSConfig
+		sasl    msgQueue.SASLConfig
+		wantErr string
+	}{
+		{
+			name:    "TLS Files Without TLS",
+			tls:     msgQueue.TLSConfig{CAFile: certFile},
+			wantErr: "kafka tls files are set but tls is disabled",
+		},
+		{
+			name:    "Missing CA File",
+			tls:     msgQueue.TLSConfig{Enabled: true, CAFile: filepath.Join(t.TempDir(), "missing.pem")},
+			wantErr: "failed to read kafka tls CA file",
+		},
+		{
+			name:    "Invalid CA File",
+			tls:     msgQueue.TLSConfig{Enabled: true, CAFile: keyFile},
+			wantErr: "kafka tls CA file has no valid certificates",
+		},
+		{
+			name:    "Certificate Without Key",
+			tls:     msgQueue.TLSConfig{Enabled: true, CertFile: certFile},
+			wantErr: "kafka tls client certificate and key must be set together",
+		},
+		{
+			name:    "Mismatched Certificate And Key",
+			tls:     msgQueue.TLSConfig{Enabled: true, CertFile: keyFile, KeyFile: certFile},
+			wantErr: "failed to load kafka tls client certificate",
+		},
+		{
+			name:    "Unsupported Mechanism",
+			sasl:    msgQueue.SASLConfig{Mechanism: "gssapi"},
+			wantErr: "kafka sasl mechanism is unsupported",
+		},
+		{
+			name:    "SCRAM Without Password",
+			sasl:    msgQueue.SASLConfig{Mechanism: "scram-sha-512", Username: "user"},
+			wantErr: "kafka sasl username or password is empty",
+		},
+		{
+			name:    "OAUTHBEARER Without Token",
+			sasl:    msgQueue.SASLConfig{Mechanism: "oauthbearer"},
+			wantErr: "kafka sasl oauth token is empty",
+		},
+		{
+			name:    "OAUTHBEARER With Token And Token File",
+			sasl:    msgQueue.SASLConfig{Mechanism: "oauthbearer", Token: "token", TokenFile: writeTestToken(t, "token")},
+			wantErr: "kafka sasl oauth token and token file are both set",
+		},
+		{
+			name:    "OAUTHBEARER Missing Token File",
+			sasl:    msgQueue.SASLConfig{Mechanism: "oauthbearer", TokenFile: filepath.Join(t.TempDir(), "missing")},
+			wantErr: "failed to read kafka sasl oauth token file",
+		},
+		{
+			name:    "OAUTHBEARER Empty Token File",
+			sasl:    msgQueue.SASLConfig{Mechanism: "oauthbearer", TokenFile: writeTestToken(t, " \n")},
+			wantErr: "kafka sasl oauth token file is empty",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader, err := msgQueue.NewKafkaReader(msgQueue.ReaderConfig{
+				Address: "localhost:9092",
+				Topic:   testTopic,
+				GroupID: testGroupID,
+				TLS:     tt.tls,
+				SASL:    tt.sasl,
+			})
+			require.ErrorContains(t, err, tt.wantErr)
+			require.Nil(t, reader)
+		})
+	}
+}
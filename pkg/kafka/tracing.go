@@ -0,0 +1,130 @@
+package kafka
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/ansoncht/flight-microservices/pkg/tracing"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"go.opentelemetry.io/otel"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracer specifies the tracer of the Kafka clients.
+var tracer = otel.Tracer("github.com/ansoncht/flight-microservices/pkg/kafka")
+
+// recordCarrier adapts the headers of a record to an OpenTelemetry text map carrier.
+type recordCarrier struct {
+	// record specifies the record carrying the headers.
+	record *kgo.Record
+}
+
+// Get returns the value of the header with the given key.
+func (c recordCarrier) Get(key string) string {
+	for _, header := range c.record.Headers {
+		if header.Key == key {
+			return string(header.Value)
+		}
+	}
+
+	return ""
+}
+
+// Set sets the value of the header with the given key, replacing any existing value.
+func (c recordCarrier) Set(key string, value string) {
+	for i, header := range c.record.Headers {
+		if header.Key == key {
+			c.record.Headers[i].Value = []byte(value)
+			return
+		}
+	}
+
+	c.record.Headers = append(c.record.Headers, kgo.RecordHeader{Key: key, Value: []byte(value)})
+}
+
+// Keys returns the keys of the headers.
+func (c recordCarrier) Keys() []string {
+	keys := make([]string, 0, len(c.record.Headers))
+	for _, header := range c.record.Headers {
+		keys = append(keys, header.Key)
+	}
+
+	return keys
+}
+
+// clientTracing traces the records of a Kafka client.
+// It implements the franz-go hooks for produced and consumed records, starting a span for each record and
+// propagating its trace context in the record headers.
+type clientTracing struct {
+	// group specifies the consumer group of the client, empty for producers.
+	group string
+}
+
+// newClientTracing creates a new clientTracing instance for a client of the given consumer group.
+func newClientTracing(group string) *clientTracing {
+	return &clientTracing{
+		group: group,
+	}
+}
+
+// OnProduceRecordBuffered starts the publish span of a record as a child of the context it was produced with, and
+// injects the span into the record headers.
+func (t *clientTracing) OnProduceRecordBuffered(record *kgo.Record) {
+	parent := record.Context
+	if parent == nil {
+		parent = context.Background()
+	}
+
+	ctx, _ := tracer.Start(
+		parent,
+		record.Topic+" publish",
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			semconv.MessagingSystemKafka,
+			semconv.MessagingOperationTypePublish,
+			semconv.MessagingDestinationName(record.Topic),
+		),
+	)
+
+	otel.GetTextMapPropagator().Inject(ctx, recordCarrier{record: record})
+	record.Context = ctx
+}
+
+// OnProduceRecordUnbuffered ends the publish span of a record once it has been produced or failed.
+func (t *clientTracing) OnProduceRecordUnbuffered(record *kgo.Record, err error) {
+	span := trace.SpanFromContext(record.Context)
+	span.SetAttributes(semconv.MessagingDestinationPartitionID(strconv.Itoa(int(record.Partition))))
+	tracing.End(span, err)
+}
+
+// OnFetchRecordBuffered starts the receive span of a fetched record as a child of the trace context in its headers.
+func (t *clientTracing) OnFetchRecordBuffered(record *kgo.Record) {
+	parent := record.Context
+	if parent == nil {
+		parent = context.Background()
+	}
+
+	parent = otel.GetTextMapPropagator().Extract(parent, recordCarrier{record: record})
+
+	ctx, _ := tracer.Start(
+		parent,
+		record.Topic+" receive",
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			semconv.MessagingSystemKafka,
+			semconv.MessagingOperationTypeReceive,
+			semconv.MessagingDestinationName(record.Topic),
+			semconv.MessagingDestinationPartitionID(strconv.Itoa(int(record.Partition))),
+			semconv.MessagingKafkaMessageOffset(int(record.Offset)),
+			semconv.MessagingKafkaConsumerGroup(t.group),
+		),
+	)
+
+	record.Context = ctx
+}
+
+// OnFetchRecordUnbuffered ends the receive span of a record once it has been polled or discarded.
+func (t *clientTracing) OnFetchRecordUnbuffered(record *kgo.Record, _ bool) {
+	trace.SpanFromContext(record.Context).End()
+}
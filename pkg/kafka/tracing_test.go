@@ -0,0 +1,89 @@
+package kafka_test
+
+import (
+	"context"
+	"testing"
+
+	msgQueue "github.com/ansoncht/flight-microservices/pkg/kafka"
+	"github.com/ansoncht/flight-microservices/pkg/msgbus"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// setupTracing records the spans of the test and propagates their trace context until the test ends.
+func setupTracing(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	// Stop propagating trace context so that other tests write records without headers
+	t.Cleanup(func() {
+		otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator())
+	})
+
+	return recorder
+}
+
+// findSpan returns the ended span with the given name.
+func findSpan(t *testing.T, recorder *tracetest.SpanRecorder, name string) sdktrace.ReadOnlySpan {
+	t.Helper()
+
+	for _, span := range recorder.Ended() {
+		if span.Name() == name {
+			return span
+		}
+	}
+
+	require.Failf(t, "span not found", "no ended span named %s", name)
+
+	return nil
+}
+
+func TestWriteAndReadMessages_ShouldPropagateTraceContext(t *testing.T) {
+	recorder := setupTracing(t)
+	address := setupFakeKafka(t, 0)
+	groupID := testGroupID + "-tracing"
+
+	writer, err := msgQueue.NewKafkaWriter(msgQueue.WriterConfig{Address: address, Topic: testTopic})
+	require.NoError(t, err)
+	defer writer.Close()
+
+	reader, err := msgQueue.NewKafkaReader(msgQueue.ReaderConfig{
+		Address: address,
+		Topic:   testTopic,
+		GroupID: groupID,
+	})
+	require.NoError(t, err)
+	defer reader.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	ctx, parent := otel.Tracer("test").Start(ctx, "parent")
+	err = writer.WriteMessage(ctx, []byte(testMessageKey), []byte(testMessageValue))
+	parent.End()
+	require.NoError(t, err)
+
+	deliveries := readAll(t, reader, make(chan msgbus.Delivery, 1), 1)
+
+	// The message continues the trace of the writer
+	traceID := parent.SpanContext().TraceID()
+	spanContext := trace.SpanContextFromContext(deliveries[0].TraceContext(context.Background()))
+	require.Equal(t, traceID, spanContext.TraceID())
+
+	publish := findSpan(t, recorder, testTopic+" publish")
+	require.Equal(t, trace.SpanKindProducer, publish.SpanKind())
+	require.Equal(t, parent.SpanContext().SpanID(), publish.Parent().SpanID())
+	require.Equal(t, publish.SpanContext().SpanID(), spanContext.SpanID())
+
+	receive := findSpan(t, recorder, testTopic+" receive")
+	require.Equal(t, trace.SpanKindConsumer, receive.SpanKind())
+	require.Equal(t, traceID, receive.SpanContext().TraceID())
+	require.Equal(t, publish.SpanContext().SpanID(), receive.Parent().SpanID())
+}
@@ -0,0 +1,401 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ansoncht/flight-microservices/pkg/msgbus"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+// streamEndKey specifies the key of the message ending a day stream, whose transaction commits once the stream is
+// settled.
+const streamEndKey = "end_of_stream"
+
+// TransactConfig holds configuration settings for reading and writing messages in Kafka transactions.
+type TransactConfig struct {
+	// Enabled specifies whether consumed offsets and produced messages are committed in transactions.
+	Enabled bool `mapstructure:"enabled"`
+	// TransactionalID specifies the transactional ID, unique to each instance of a service.
+	TransactionalID string `mapstructure:"transactional_id"`
+	// Timeout specifies the seconds a transaction may stay open before the broker aborts it.
+	// A transaction spans a whole day stream, read one message at a time, and the finalizing of its day,
+	// so the timeout must cover the largest day of any airport. It must not exceed the transaction.max.timeout.ms
+	// of the brokers.
+	Timeout int `mapstructure:"timeout"`
+}
+
+// TransactSession holds the Kafka transactional session instance.
+// It implements the msgbus.MessageReader, msgbus.MessageWriter and msgbus.Acknowledger interfaces so that the
+// offsets of consumed messages and the messages written while handling them are committed atomically.
+//
+// A transaction begins with the first message read and commits once every message read has been acknowledged.
+// A rejected message aborts the transaction, so that its messages are read again and the messages written while
+// handling them are discarded.
+//
+// As a day stream is only acknowledged once it ends, a transaction stays open for a whole day stream. Reading
+// waits at the end of each stream until the stream is settled, so that its transaction ends before the next
+// stream is read. A stream outlasting the transaction timeout is aborted by the broker and read again, so the
+// timeout bounds the size of the days which can be processed.
+type TransactSession struct {
+	// Session specifies the franz-go group transact session instance.
+	Session *kgo.GroupTransactSession
+	// metrics specifies the metrics of the consumer group member.
+	metrics *clientMetrics
+	// mu protects the transaction state, as messages are settled concurrently.
+	mu sync.Mutex
+	// inTransaction specifies whether a transaction has begun.
+	inTransaction bool
+	// unsettled specifies the number of messages read in the transaction which have not been settled.
+	unsettled int
+	// rejected specifies whether a message read in the transaction has been rejected.
+	rejected bool
+	// settled signals that every message read in the transaction has been settled.
+	settled chan struct{}
+}
+
+// NewTransactSession creates a new TransactSession instance consuming the reader topic and producing to the
+// writer topic, based on the provided configurations.
+func NewTransactSession(readerCfg ReaderConfig, writerCfg WriterConfig, cfg TransactConfig) (*TransactSession, error) {
+	slog.Info(
+		"Initializing Kafka transactional session for the service",
+		"address", readerCfg.Address,
+		"read_topic", readerCfg.Topic,
+		"write_topic", writerCfg.Topic,
+		"transactional_id", cfg.TransactionalID,
+	)
+
+	if readerCfg.Address == "" || writerCfg.Address == "" {
+		return nil, fmt.Errorf("kafka broker address is empty")
+	}
+
+	if readerCfg.Address != writerCfg.Address {
+		return nil, fmt.Errorf("kafka transactions must read and write on the same brokers")
+	}
+
+	if readerCfg.Topic == "" || writerCfg.Topic == "" {
+		return nil, fmt.Errorf("kafka topic is empty")
+	}
+
+	if readerCfg.GroupID == "" {
+		return nil, fmt.Errorf("kafka group ID is empty")
+	}
+
+	if cfg.TransactionalID == "" {
+		return nil, fmt.Errorf("kafka transactional ID is empty")
+	}
+
+	if cfg.Timeout <= 0 {
+		return nil, fmt.Errorf("kafka transaction timeout is invalid: %d", cfg.Timeout)
+	}
+
+	produceOpts, err := produceOptions(writerCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// The reader and writer share the brokers, so the reader's security settings apply to both
+	securityOpts, err := securityOptions(readerCfg.TLS, readerCfg.SASL)
+	if err != nil {
+		return nil, err
+	}
+
+	metrics := newClientMetrics(readerCfg.GroupID)
+
+	addresses := strings.Split(readerCfg.Address, ",")
+	opts := []kgo.Opt{
+		kgo.SeedBrokers(addresses...),
+		kgo.ConsumerGroup(readerCfg.GroupID),
+		kgo.ConsumeTopics(readerCfg.Topic),
+		kgo.DefaultProduceTopic(writerCfg.Topic),
+		kgo.TransactionalID(cfg.TransactionalID),
+		kgo.TransactionTimeout(time.Duration(cfg.Timeout) * time.Second),
+		kgo.FetchIsolationLevel(kgo.ReadCommitted()),
+		kgo.RequireStableFetchOffsets(),
+		kgo.OnPartitionsAssigned(metrics.onPartitionsAssigned),
+		kgo.OnPartitionsRevoked(metrics.onPartitionsRevoked),
+		kgo.OnPartitionsLost(metrics.onPartitionsLost),
+		kgo.WithHooks(metrics, newClientTracing(readerCfg.GroupID)),
+	}
+	opts = append(opts, produceOpts...)
+	opts = append(opts, securityOpts...)
+
+	session, err := kgo.NewGroupTransactSession(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Kafka transactional session: %w", err)
+	}
+
+	return &TransactSession{
+		Session: session,
+		metrics: metrics,
+		settled: make(chan struct{}, 1),
+	}, nil
+}
+
+// Check pings the Kafka brokers.
+func (s *TransactSession) Check(ctx context.Context) error {
+	if err := s.Session.Client().Ping(ctx); err != nil {
+		return fmt.Errorf("failed to ping Kafka brokers: %w", err)
+	}
+
+	return nil
+}
+
+// Close closes the Kafka transactional session.
+func (s *TransactSession) Close() {
+	s.Session.Close()
+}
+
+// ReadMessages reads messages from the Kafka topic and sends them to the provided channel.
+// Messages are read one at a time, so that a commit never includes the offset of a message not yet sent.
+// A day stream is therefore handed over one poll per message, all within a single transaction, which ends once
+// the stream is settled and must end before the transaction timeout.
+func (s *TransactSession) ReadMessages(ctx context.Context, msgChan chan<- msgbus.Delivery) error {
+	slog.Info("Reading message from Kafka topic in transactions")
+
+	defer close(msgChan)
+
+	if s == nil {
+		return fmt.Errorf("kafka transactional session is nil")
+	}
+
+readingLoop:
+	for {
+		// End the transaction of the messages read so far once they are all settled
+		if err := s.endIfSettled(ctx); err != nil {
+			return err
+		}
+
+		pollCtx, cancel := context.WithTimeout(ctx, idleTimeout)
+		fetches := s.Session.PollRecords(pollCtx, 1)
+		cancel()
+
+		// A closed session cannot end its transaction, which the broker aborts once it times out
+		if fetches.IsClientClosed() {
+			return nil
+		}
+
+		if ctx.Err() != nil {
+			break readingLoop
+		}
+
+		for _, err := range fetches.Errors() {
+			if errors.Is(err.Err, context.DeadlineExceeded) || errors.Is(err.Err, context.Canceled) {
+				continue
+			}
+
+			slog.Error("Failed to fetch message from Kafka", "errors", err)
+		}
+
+		s.metrics.observeLag(fetches)
+
+		for _, record := range fetches.Records() {
+			if err := s.track(); err != nil {
+				return err
+			}
+
+			select {
+			case <-ctx.Done():
+				break readingLoop
+			case msgChan <- msgbus.NewDelivery(newMessage(record), s):
+			}
+
+			// Wait for the stream to be settled, so that the next stream is read in a transaction of its own
+			if string(record.Key) == streamEndKey && !s.awaitSettled(ctx) {
+				break readingLoop
+			}
+		}
+	}
+
+	// Abort the transaction of messages which were not settled before shutting down
+	if err := s.end(context.WithoutCancel(ctx)); err != nil {
+		return err
+	}
+
+	return fmt.Errorf("context canceled while fetching kafka messages: %w", ctx.Err())
+}
+
+// WriteMessage writes a message to the Kafka topic in the current transaction.
+func (s *TransactSession) WriteMessage(ctx context.Context, key []byte, value []byte) error {
+	slog.Info("Writing message to Kafka topic in transaction", "key", string(key), "message", string(value))
+
+	if s == nil {
+		return fmt.Errorf("kafka transactional session is nil")
+	}
+
+	if err := (msgbus.Message{Key: key, Value: value}).Validate(); err != nil {
+		return err
+	}
+
+	if err := s.Session.ProduceSync(ctx, &kgo.Record{Key: key, Value: value}).FirstErr(); err != nil {
+		return fmt.Errorf("failed to produce message in transaction: %w", err)
+	}
+
+	return nil
+}
+
+// WriteMessages writes a batch of messages to their topics in the current transaction, defaulting to the Kafka
+// topic of the session.
+// Writes in a transaction are always synchronous, as the transaction must not commit before they are delivered.
+func (s *TransactSession) WriteMessages(ctx context.Context, msgs []msgbus.Message) error {
+	slog.Info("Writing messages to Kafka topic in transaction", "count", len(msgs))
+
+	if s == nil {
+		return fmt.Errorf("kafka transactional session is nil")
+	}
+
+	records, err := newRecords(msgs)
+	if err != nil {
+		return err
+	}
+
+	if len(records) == 0 {
+		return nil
+	}
+
+	if err := s.Session.ProduceSync(ctx, records...).FirstErr(); err != nil {
+		return fmt.Errorf("failed to produce messages in transaction: %w", err)
+	}
+
+	return nil
+}
+
+// Flush waits until the messages written in the current transaction have been delivered.
+func (s *TransactSession) Flush(ctx context.Context) error {
+	if s == nil {
+		return fmt.Errorf("kafka transactional session is nil")
+	}
+
+	if err := s.Session.Client().Flush(ctx); err != nil {
+		return fmt.Errorf("failed to flush messages in transaction: %w", err)
+	}
+
+	return nil
+}
+
+// Ack settles a message whose side effects succeeded.
+func (s *TransactSession) Ack(_ msgbus.Message) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.unsettled--
+	s.signalIfSettled()
+}
+
+// Nack settles a message whose side effects failed, aborting the current transaction once it ends.
+func (s *TransactSession) Nack(msg msgbus.Message, err error) {
+	slog.Warn(
+		"Rejected message, aborting its transaction",
+		"topic", msg.Topic,
+		"partition", msg.Partition,
+		"offset", msg.Offset,
+		"error", err,
+	)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.unsettled--
+	s.rejected = true
+	s.signalIfSettled()
+}
+
+// signalIfSettled signals that the messages read in the transaction are settled if none is left unsettled.
+// It must be called with the lock held.
+func (s *TransactSession) signalIfSettled() {
+	if s.unsettled > 0 {
+		return
+	}
+
+	select {
+	case s.settled <- struct{}{}:
+	default:
+	}
+}
+
+// awaitSettled waits until every message read in the transaction has been settled. It returns false if the context
+// is done first.
+func (s *TransactSession) awaitSettled(ctx context.Context) bool {
+	for {
+		s.mu.Lock()
+		settled := s.unsettled == 0
+		s.mu.Unlock()
+
+		if settled {
+			return true
+		}
+
+		select {
+		case <-ctx.Done():
+			return false
+		case <-s.settled:
+		}
+	}
+}
+
+// track begins a transaction if none has begun and counts a message about to be sent.
+func (s *TransactSession) track() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.inTransaction {
+		if err := s.Session.Begin(); err != nil {
+			return fmt.Errorf("failed to begin transaction: %w", err)
+		}
+
+		s.inTransaction = true
+	}
+
+	s.unsettled++
+
+	return nil
+}
+
+// endIfSettled commits or aborts the current transaction if every message read in it has been settled.
+func (s *TransactSession) endIfSettled(ctx context.Context) error {
+	s.mu.Lock()
+	settled := s.unsettled == 0
+	s.mu.Unlock()
+
+	if !settled {
+		return nil
+	}
+
+	return s.end(ctx)
+}
+
+// end commits the current transaction if every message read in it was acknowledged, or aborts it otherwise.
+func (s *TransactSession) end(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.inTransaction {
+		return nil
+	}
+
+	commit := kgo.TryCommit
+	if s.rejected || s.unsettled > 0 {
+		commit = kgo.TryAbort
+	}
+
+	committed, err := s.Session.End(ctx, commit)
+
+	s.inTransaction = false
+	s.unsettled = 0
+	s.rejected = false
+
+	if err != nil {
+		return fmt.Errorf("failed to end transaction: %w", err)
+	}
+
+	if !committed {
+		slog.Warn("Aborted transaction, its messages will be read again")
+	}
+
+	return nil
+}
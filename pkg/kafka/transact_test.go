@@ -0,0 +1,236 @@
+package kafka_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	msgQueue "github.com/ansoncht/flight-microservices/pkg/kafka"
+	"github.com/ansoncht/flight-microservices/pkg/msgbus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTransactSession_InvalidConfig_ShouldError(t *testing.T) {
+	readerCfg := msgQueue.ReaderConfig{Address: "localhost:9092", Topic: testTopic, GroupID: testGroupID}
+	writerCfg := msgQueue.WriterConfig{Address: "localhost:9092", Topic: testTopic + "-out"}
+	cfg := msgQueue.TransactConfig{Enabled: true, TransactionalID: "processor-0", Timeout: 60}
+
+	tests := []struct {
+		name      string
+		readerCfg msgQueue.ReaderConfig
+		writerCfg msgQueue.WriterConfig
+		cfg       msgQueue.TransactConfig
+		wantErr   string
+	}{
+		{
+			name:      "Missing Address",
+			readerCfg: msgQueue.ReaderConfig{Topic: testTopic, GroupID: testGroupID},
+			writerCfg: writerCfg,
+			cfg:       cfg,
+			wantErr:   "kafka broker address is empty",
+		},
+		{
+			name:      "Different Brokers",
+			readerCfg: readerCfg,
+			writerCfg: msgQueue.WriterConfig{Address: "localhost:9093", Topic: testTopic + "-out"},
+			cfg:       cfg,
+			wantErr:   "kafka transactions must read and write on the same brokers",
+		},
+		{
+			name:      "Missing Topic",
+			readerCfg: readerCfg,
+			writerCfg: msgQueue.WriterConfig{Address: "localhost:9092"},
+			cfg:       cfg,
+			wantErr:   "kafka topic is empty",
+		},
+		{
+			name:      "Missing GroupID",
+			readerCfg: msgQueue.ReaderConfig{Address: "localhost:9092", Topic: testTopic},
+			writerCfg: writerCfg,
+			cfg:       cfg,
+			wantErr:   "kafka group ID is empty",
+		},
+		{
+			name:      "Missing TransactionalID",
+			readerCfg: readerCfg,
+			writerCfg: writerCfg,
+			cfg:       msgQueue.TransactConfig{Enabled: true, Timeout: 60},
+			wantErr:   "kafka transactional ID is empty",
+		},
+		{
+			name:      "Invalid Timeout",
+			readerCfg: readerCfg,
+			writerCfg: writerCfg,
+			cfg:       msgQueue.TransactConfig{Enabled: true, TransactionalID: "processor-0"},
+			wantErr:   "kafka transaction timeout is invalid",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session, err := msgQueue.NewTransactSession(tt.readerCfg, tt.writerCfg, tt.cfg)
+			require.ErrorContains(t, err, tt.wantErr)
+			require.Nil(t, session)
+		})
+	}
+}
+
+func TestTransactSession_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	brokers, cleanup := setupKafkaTest(ctx, t)
+	defer cleanup()
+
+	brokerAddress := brokers[0]
+	outputTopic := testTopic + "-summaries"
+
+	writer, err := msgQueue.NewKafkaWriter(msgQueue.WriterConfig{Address: brokerAddress, Topic: testTopic})
+	require.NoError(t, err)
+	defer writer.Close()
+
+	writeCtx, writeCancel := context.WithTimeout(ctx, timeout)
+	defer writeCancel()
+
+	err = writer.WriteMessage(writeCtx, []byte(testMessageKey), []byte(testMessageValue))
+	require.NoError(t, err)
+
+	session, err := msgQueue.NewTransactSession(
+		msgQueue.ReaderConfig{Address: brokerAddress, Topic: testTopic, GroupID: testGroupID + "-transact"},
+		msgQueue.WriterConfig{Address: brokerAddress, Topic: outputTopic},
+		msgQueue.TransactConfig{Enabled: true, TransactionalID: "transact-test", Timeout: 60},
+	)
+	require.NoError(t, err)
+	defer session.Close()
+
+	msgChan := make(chan msgbus.Delivery, 1)
+	readCtx, readCancel := context.WithCancel(ctx)
+	defer readCancel()
+
+	go func() {
+		_ = session.ReadMessages(readCtx, msgChan)
+	}()
+
+	select {
+	case msg := <-msgChan:
+		require.Equal(t, testMessageKey, string(msg.Key))
+		require.NoError(t, session.WriteMessage(readCtx, []byte("summary_id"), []byte("test_id")))
+		msg.Ack()
+	case <-time.After(timeout):
+		t.Fatal("timed out waiting for message from Kafka")
+	}
+
+	// The written message is only visible to committed readers once the transaction commits
+	reader, err := msgQueue.NewKafkaReader(msgQueue.ReaderConfig{
+		Address: brokerAddress,
+		Topic:   outputTopic,
+		GroupID: testGroupID + "-transact-output",
+	})
+	require.NoError(t, err)
+	defer reader.Close()
+
+	outChan := make(chan msgbus.Delivery, 1)
+	outCtx, outCancel := context.WithTimeout(ctx, 3*timeout)
+	defer outCancel()
+
+	go func() {
+		_ = reader.ReadMessages(outCtx, outChan)
+	}()
+
+	select {
+	case msg := <-outChan:
+		require.Equal(t, "summary_id", string(msg.Key))
+		require.Equal(t, "test_id", string(msg.Value))
+	case <-outCtx.Done():
+		t.Fatal("timed out waiting for committed message from Kafka")
+	}
+}
+
+func TestTransactSession_BackToBackStreams_ShouldCommitEachStream_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	brokers, cleanup := setupKafkaTest(ctx, t)
+	defer cleanup()
+
+	brokerAddress := brokers[0]
+	outputTopic := testTopic + "-streams"
+
+	writer, err := msgQueue.NewKafkaWriter(msgQueue.WriterConfig{Address: brokerAddress, Topic: testTopic})
+	require.NoError(t, err)
+	defer writer.Close()
+
+	writeCtx, writeCancel := context.WithTimeout(ctx, timeout)
+	defer writeCancel()
+
+	err = writer.WriteMessages(writeCtx, []msgbus.Message{
+		{Key: []byte("start_of_stream"), Value: []byte("HKG")},
+		{Key: []byte("end_of_stream"), Value: []byte("2025-05-12")},
+		{Key: []byte("start_of_stream"), Value: []byte("HKG")},
+	})
+	require.NoError(t, err)
+
+	session, err := msgQueue.NewTransactSession(
+		msgQueue.ReaderConfig{Address: brokerAddress, Topic: testTopic, GroupID: testGroupID + "-streams"},
+		msgQueue.WriterConfig{Address: brokerAddress, Topic: outputTopic},
+		msgQueue.TransactConfig{Enabled: true, TransactionalID: "transact-streams-test", Timeout: 60},
+	)
+	require.NoError(t, err)
+	defer session.Close()
+
+	msgChan := make(chan msgbus.Delivery)
+	readCtx, readCancel := context.WithCancel(ctx)
+	defer readCancel()
+
+	go func() {
+		_ = session.ReadMessages(readCtx, msgChan)
+	}()
+
+	receive := func() msgbus.Delivery {
+		select {
+		case msg := <-msgChan:
+			return msg
+		case <-time.After(timeout):
+			t.Fatal("timed out waiting for message from Kafka")
+			return msgbus.Delivery{}
+		}
+	}
+
+	start := receive()
+	end := receive()
+	require.Equal(t, "end_of_stream", string(end.Key))
+	require.NoError(t, session.WriteMessage(readCtx, []byte("summary_id"), []byte("test_id")))
+	start.Ack()
+	end.Ack()
+
+	// The next stream stays unsettled, so the summary is only visible if the first stream committed on its own
+	next := receive()
+	require.Equal(t, "start_of_stream", string(next.Key))
+
+	reader, err := msgQueue.NewKafkaReader(msgQueue.ReaderConfig{
+		Address: brokerAddress,
+		Topic:   outputTopic,
+		GroupID: testGroupID + "-streams-output",
+	})
+	require.NoError(t, err)
+	defer reader.Close()
+
+	outChan := make(chan msgbus.Delivery, 1)
+	outCtx, outCancel := context.WithTimeout(ctx, 3*timeout)
+	defer outCancel()
+
+	go func() {
+		_ = reader.ReadMessages(outCtx, outChan)
+	}()
+
+	select {
+	case msg := <-outChan:
+		require.Equal(t, "summary_id", string(msg.Key))
+	case <-outCtx.Done():
+		t.Fatal("timed out waiting for committed message from Kafka")
+	}
+}
@@ -2,34 +2,56 @@ package kafka
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/ansoncht/flight-microservices/pkg/msgbus"
 	"github.com/twmb/franz-go/pkg/kgo"
 )
 
+// Supported batch compression codecs.
+const (
+	CompressionNone   = "none"
+	CompressionSnappy = "snappy"
+	CompressionZstd   = "zstd"
+)
+
 // WriterConfig holds configuration settings for the Kafka writer.
 type WriterConfig struct {
 	// Address specifies the Kafka broker address.
 	Address string `mapstructure:"address"`
 	// Topic specifies the Kafka topic to write to.
 	Topic string `mapstructure:"topic"`
-}
-
-// MessageWriter defines the interface for writing messages to a message queue.
-type MessageWriter interface {
-	// WriteMessage writes a message to the message queue.
-	WriteMessage(ctx context.Context, key []byte, value []byte) error
-	// Close closes the message queue writer.
-	Close()
+	// Async specifies whether writes return once messages are buffered, leaving their errors to Flush.
+	Async bool `mapstructure:"async"`
+	// LingerMs specifies the milliseconds a batch waits for more messages before it is sent. Defaults to none.
+	LingerMs int `mapstructure:"linger_ms"`
+	// BatchMaxBytes specifies the maximum bytes of a batch of messages. Defaults to the franz-go default.
+	BatchMaxBytes int32 `mapstructure:"batch_max_bytes"`
+	// Compression specifies the codec compressing batches: none, snappy or zstd.
+	// Defaults to the franz-go default.
+	Compression string `mapstructure:"compression"`
+	// TLS specifies the TLS settings for connecting to the brokers.
+	TLS TLSConfig `mapstructure:"tls"`
+	// SASL specifies the SASL settings for authenticating to the brokers.
+	SASL SASLConfig `mapstructure:"sasl"`
 }
 
 // Writer holds the Kafka writer instance.
-// It implements the MessageWriter interface to provide methods for writing messages to Kafka.
+// It implements the msgbus.MessageWriter interface to provide methods for writing messages to Kafka.
 type Writer struct {
 	// Client specifies the kafka reader instance.
 	Client *kgo.Client
+	// async specifies whether writes return without waiting for their messages to be delivered.
+	async bool
+	// mu protects errs, as delivery callbacks run concurrently.
+	mu sync.Mutex
+	// errs holds the errors of asynchronous writes since the last flush.
+	errs []error
 }
 
 // NewKafkaWriter creates a new Writer instance with the provided configuration.
@@ -44,11 +66,25 @@ func NewKafkaWriter(cfg WriterConfig) (*Writer, error) {
 		return nil, fmt.Errorf("kafka topic is empty")
 	}
 
+	produceOpts, err := produceOptions(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	securityOpts, err := securityOptions(cfg.TLS, cfg.SASL)
+	if err != nil {
+		return nil, err
+	}
+
 	addresses := strings.Split(cfg.Address, ",")
 	opts := []kgo.Opt{
 		kgo.SeedBrokers(addresses...),
 		kgo.DefaultProduceTopic(cfg.Topic),
+		kgo.WithHooks(newClientMetrics(""), newClientTracing("")),
 	}
+	opts = append(opts, produceOpts...)
+	opts = append(opts, securityOpts...)
+
 	client, err := kgo.NewClient(opts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create Kafka client: %w", err)
@@ -56,9 +92,19 @@ func NewKafkaWriter(cfg WriterConfig) (*Writer, error) {
 
 	return &Writer{
 		Client: client,
+		async:  cfg.Async,
 	}, nil
 }
 
+// Check pings the Kafka brokers.
+func (w *Writer) Check(ctx context.Context) error {
+	if err := w.Client.Ping(ctx); err != nil {
+		return fmt.Errorf("failed to ping Kafka brokers: %w", err)
+	}
+
+	return nil
+}
+
 // Close closes the Kafka writer.
 func (w *Writer) Close() {
 	w.Client.Close()
@@ -72,35 +118,138 @@ func (w *Writer) WriteMessage(ctx context.Context, key []byte, value []byte) err
 		return fmt.Errorf("kafka writer is nil")
 	}
 
-	if len(key) == 0 {
-		return fmt.Errorf("message key is nil or empty")
+	if err := (msgbus.Message{Key: key, Value: value}).Validate(); err != nil {
+		return err
 	}
 
-	if len(value) == 0 {
-		return fmt.Errorf("message value is nil or empty")
+	return w.produce(ctx, []*kgo.Record{{Key: key, Value: value}})
+}
+
+// WriteMessages writes a batch of messages to their topics, defaulting to the Kafka topic of the writer, letting
+// the client group them into as few requests as the batch size allows.
+func (w *Writer) WriteMessages(ctx context.Context, msgs []msgbus.Message) error {
+	slog.Info("Writing messages to Kafka topic", "count", len(msgs))
+
+	if w == nil {
+		return fmt.Errorf("kafka writer is nil")
+	}
+
+	records, err := newRecords(msgs)
+	if err != nil {
+		return err
+	}
+
+	return w.produce(ctx, records)
+}
+
+// Flush waits until the messages written have been delivered and returns the errors of asynchronous writes
+// since the last flush.
+func (w *Writer) Flush(ctx context.Context) error {
+	if w == nil {
+		return fmt.Errorf("kafka writer is nil")
 	}
 
-	record := &kgo.Record{
-		Key:   key,
-		Value: value,
+	if err := w.Client.Flush(ctx); err != nil {
+		return fmt.Errorf("failed to flush messages: %w", err)
 	}
 
-	errChan := make(chan error, 1)
+	w.mu.Lock()
+	errs := w.errs
+	w.errs = nil
+	w.mu.Unlock()
 
-	w.Client.Produce(ctx, record, func(_ *kgo.Record, err error) {
-		if err != nil {
-			errChan <- err
-		} else {
-			errChan <- nil
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to produce %d messages: %w", len(errs), errors.Join(errs...))
+	}
+
+	return nil
+}
+
+// produce sends the records to the Kafka topic. In async mode it returns once the records are buffered and
+// collects their errors for Flush, otherwise it waits until every record has been delivered.
+func (w *Writer) produce(ctx context.Context, records []*kgo.Record) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	if w.async {
+		for _, record := range records {
+			w.Client.Produce(ctx, record, w.collect)
 		}
 
-		close(errChan)
-	})
+		return nil
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(records))
+
+	wg.Add(len(records))
+	for i, record := range records {
+		w.Client.Produce(ctx, record, func(_ *kgo.Record, err error) {
+			errs[i] = err
+			wg.Done()
+		})
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
 
 	select {
-	case err := <-errChan:
-		return err
+	case <-done:
+		return errors.Join(errs...)
 	case <-ctx.Done():
 		return fmt.Errorf("context canceled while producing message: %w", ctx.Err())
 	}
 }
+
+// collect records the error of an asynchronous write.
+func (w *Writer) collect(record *kgo.Record, err error) {
+	if err == nil {
+		return
+	}
+
+	slog.Warn("Failed to produce message", "key", string(record.Key), "error", err)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.errs = append(w.errs, err)
+}
+
+// produceOptions returns the franz-go options for the batching and compression set in the configuration.
+func produceOptions(cfg WriterConfig) ([]kgo.Opt, error) {
+	if cfg.LingerMs < 0 {
+		return nil, fmt.Errorf("kafka linger is invalid: %d", cfg.LingerMs)
+	}
+
+	if cfg.BatchMaxBytes < 0 {
+		return nil, fmt.Errorf("kafka batch max bytes is invalid: %d", cfg.BatchMaxBytes)
+	}
+
+	opts := make([]kgo.Opt, 0)
+
+	switch strings.ToLower(cfg.Compression) {
+	case "":
+	case CompressionNone:
+		opts = append(opts, kgo.ProducerBatchCompression(kgo.NoCompression()))
+	case CompressionSnappy:
+		opts = append(opts, kgo.ProducerBatchCompression(kgo.SnappyCompression()))
+	case CompressionZstd:
+		opts = append(opts, kgo.ProducerBatchCompression(kgo.ZstdCompression()))
+	default:
+		return nil, fmt.Errorf("kafka compression is unsupported: %s", cfg.Compression)
+	}
+
+	if cfg.LingerMs > 0 {
+		opts = append(opts, kgo.ProducerLinger(time.Duration(cfg.LingerMs)*time.Millisecond))
+	}
+
+	if cfg.BatchMaxBytes > 0 {
+		opts = append(opts, kgo.ProducerBatchMaxBytes(cfg.BatchMaxBytes))
+	}
+
+	return opts, nil
+}
@@ -2,11 +2,15 @@ package kafka_test
 
 import (
 	"context"
+	"fmt"
+	"strconv"
 	"testing"
 	"time"
 
 	msgQueue "github.com/ansoncht/flight-microservices/pkg/kafka"
+	"github.com/ansoncht/flight-microservices/pkg/msgbus"
 	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kfake"
 	"github.com/twmb/franz-go/pkg/kgo"
 )
 
@@ -49,6 +53,33 @@ func TestNewKafkaWriter_InvalidConfig_ShouldError(t *testing.T) {
 			}(),
 			wantErr: "kafka topic is empty",
 		},
+		{
+			name: "Unsupported Compression",
+			cfg: func() msgQueue.WriterConfig {
+				c := cfg
+				c.Compression = "brotli"
+				return c
+			}(),
+			wantErr: "kafka compression is unsupported",
+		},
+		{
+			name: "Negative Linger",
+			cfg: func() msgQueue.WriterConfig {
+				c := cfg
+				c.LingerMs = -1
+				return c
+			}(),
+			wantErr: "kafka linger is invalid",
+		},
+		{
+			name: "Negative Batch Max Bytes",
+			cfg: func() msgQueue.WriterConfig {
+				c := cfg
+				c.BatchMaxBytes = -1
+				return c
+			}(),
+			wantErr: "kafka batch max bytes is invalid",
+		},
 	}
 
 	for _, tt := range tests {
@@ -97,7 +128,7 @@ func TestWriteMessage_Integration(t *testing.T) {
 		require.NotNil(t, reader)
 		defer reader.Close()
 
-		msgChan := make(chan kgo.Record, 1)
+		msgChan := make(chan msgbus.Delivery, 1)
 		readErrChan := make(chan error, 1)
 		readCtx, readCancel := context.WithTimeout(ctx, timeout)
 		defer readCancel()
@@ -141,3 +172,131 @@ func TestWriteMessage_Integration(t *testing.T) {
 		require.ErrorIs(t, err, context.Canceled)
 	})
 }
+
+func TestWriteMessages_Batch_ShouldDeliverAll(t *testing.T) {
+	const records = 2000
+
+	for _, cfg := range []msgQueue.WriterConfig{
+		{Compression: msgQueue.CompressionZstd, LingerMs: 10},
+		{Compression: msgQueue.CompressionSnappy, Async: true},
+	} {
+		t.Run(fmt.Sprintf("Compression=%s/Async=%t", cfg.Compression, cfg.Async), func(t *testing.T) {
+			cfg.Address = setupFakeKafka(t, 0)
+			cfg.Topic = testTopic
+
+			writer, err := msgQueue.NewKafkaWriter(cfg)
+			require.NoError(t, err)
+			defer writer.Close()
+
+			msgs := make([]msgbus.Message, 0, records)
+			for i := range records {
+				msgs = append(msgs, msgbus.Message{Key: []byte(strconv.Itoa(i)), Value: []byte(testMessageValue)})
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+
+			require.NoError(t, writer.WriteMessages(ctx, msgs))
+			require.NoError(t, writer.Flush(ctx))
+
+			reader, err := msgQueue.NewKafkaReader(msgQueue.ReaderConfig{
+				Address: cfg.Address,
+				Topic:   testTopic,
+				GroupID: testGroupID + "-batch",
+			})
+			require.NoError(t, err)
+			defer reader.Close()
+
+			deliveries := readAll(t, reader, make(chan msgbus.Delivery, records), records)
+			require.Len(t, deliveries, records)
+		})
+	}
+}
+
+func TestWriteMessages_InvalidMessage_ShouldError(t *testing.T) {
+	writer, err := msgQueue.NewKafkaWriter(msgQueue.WriterConfig{Address: "localhost:9092", Topic: testTopic})
+	require.NoError(t, err)
+	defer writer.Close()
+
+	err = writer.WriteMessages(context.Background(), []msgbus.Message{
+		{Key: []byte(testMessageKey), Value: []byte(testMessageValue)},
+		{Key: []byte(testMessageKey)},
+	})
+	require.ErrorContains(t, err, "invalid message 1: message value is nil or empty")
+}
+
+func TestWriteMessages_MessageFields_ShouldBeKept(t *testing.T) {
+	const otherTopic = "test-other-topic"
+
+	cluster, err := kfake.NewCluster(kfake.NumBrokers(1), kfake.SeedTopics(1, testTopic, otherTopic))
+	require.NoError(t, err)
+	defer cluster.Close()
+
+	address := cluster.ListenAddrs()[0]
+
+	writer, err := msgQueue.NewKafkaWriter(msgQueue.WriterConfig{Address: address, Topic: testTopic})
+	require.NoError(t, err)
+	defer writer.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	timestamp := time.Date(2025, 5, 7, 12, 0, 0, 0, time.UTC)
+	err = writer.WriteMessages(ctx, []msgbus.Message{
+		{Key: []byte("default"), Value: []byte(testMessageValue)},
+		{
+			Topic:     otherTopic,
+			Key:       []byte("override"),
+			Value:     []byte(testMessageValue),
+			Headers:   []msgbus.Header{{Key: "correlation-id", Value: []byte("abc")}},
+			Timestamp: timestamp,
+		},
+	})
+	require.NoError(t, err)
+
+	client, err := kgo.NewClient(
+		kgo.SeedBrokers(address),
+		kgo.ConsumeTopics(testTopic, otherTopic),
+		kgo.ConsumeResetOffset(kgo.NewOffset().AtStart()),
+	)
+	require.NoError(t, err)
+	defer client.Close()
+
+	records := make(map[string]*kgo.Record)
+	for len(records) < 2 {
+		fetches := client.PollFetches(ctx)
+		require.NoError(t, ctx.Err())
+		fetches.EachRecord(func(record *kgo.Record) {
+			records[string(record.Key)] = record
+		})
+	}
+
+	require.Equal(t, testTopic, records["default"].Topic)
+	require.Empty(t, records["default"].Headers)
+
+	require.Equal(t, otherTopic, records["override"].Topic)
+	require.Equal(t, []kgo.RecordHeader{{Key: "correlation-id", Value: []byte("abc")}}, records["override"].Headers)
+	require.True(t, timestamp.Equal(records["override"].Timestamp))
+}
+
+func TestCheck_ShouldPingBrokers(t *testing.T) {
+	cluster, err := kfake.NewCluster(kfake.NumBrokers(1), kfake.SeedTopics(1, testTopic))
+	require.NoError(t, err)
+
+	writer, err := msgQueue.NewKafkaWriter(msgQueue.WriterConfig{Address: cluster.ListenAddrs()[0], Topic: testTopic})
+	require.NoError(t, err)
+	defer writer.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	require.NoError(t, writer.Check(ctx))
+
+	// The brokers can no longer be reached once the cluster stops
+	cluster.Close()
+
+	unreachableCtx, cancelUnreachable := context.WithTimeout(context.Background(), time.Second)
+	defer cancelUnreachable()
+
+	require.ErrorContains(t, writer.Check(unreachableCtx), "failed to ping Kafka brokers")
+}
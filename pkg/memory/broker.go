@@ -0,0 +1,254 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/ansoncht/flight-microservices/pkg/msgbus"
+)
+
+// redeliveryDelay specifies how long a rejected record waits before it is redelivered to its consumer group.
+const redeliveryDelay = time.Second
+
+// Broker holds the topics of an in-process message bus shared by its readers and writers.
+// Every consumer group reads all records of a topic from the first offset, while the readers of the same consumer
+// group share its records. Records are kept in memory for the lifetime of the broker.
+type Broker struct {
+	// mu guards the topics.
+	mu sync.Mutex
+	// topics specifies the topics by name, created on their first write or read.
+	topics map[string]*topic
+}
+
+// topic holds the records written to a topic and the consumer groups reading it.
+type topic struct {
+	// records specifies the records in offset order.
+	records []msgbus.Message
+	// groups specifies the consumer groups by ID.
+	groups map[string]*group
+	// written specifies the channel closed once records are appended, waking up waiting readers.
+	written chan struct{}
+}
+
+// group holds the progress of a consumer group through a topic.
+type group struct {
+	// next specifies the offset of the first record never delivered to the group.
+	next int64
+	// rejected specifies the offsets of rejected or abandoned records, delivered before new records.
+	rejected []int64
+	// pending specifies the reader holding each delivered record which has not been settled.
+	pending map[int64]*Reader
+}
+
+// NewBroker creates a new Broker instance without topics.
+func NewBroker() *Broker {
+	return &Broker{
+		topics: make(map[string]*topic),
+	}
+}
+
+// Committed returns the offset below which all records of the topic have been acknowledged by the consumer group.
+func (b *Broker) Committed(topicName string, groupID string) int64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	g := b.topic(topicName).group(groupID)
+
+	committed := g.next
+	for _, offset := range g.rejected {
+		committed = min(committed, offset)
+	}
+
+	for offset := range g.pending {
+		committed = min(committed, offset)
+	}
+
+	return committed
+}
+
+// publish appends the messages to their topics, defaulting to the given topic, and wakes up waiting readers.
+func (b *Broker) publish(defaultTopic string, msgs []msgbus.Message) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	written := make(map[*topic]struct{})
+	for _, msg := range msgs {
+		name := msg.Topic
+		if name == "" {
+			name = defaultTopic
+		}
+
+		t := b.topic(name)
+		t.records = append(t.records, newRecord(msg, name, int64(len(t.records))))
+		written[t] = struct{}{}
+	}
+
+	for t := range written {
+		close(t.written)
+		t.written = make(chan struct{})
+	}
+}
+
+// poll returns up to limit records of the topic for the consumer group, marking them pending on the reader.
+// It waits until records are available, the reader is closed or the context is done.
+func (b *Broker) poll(ctx context.Context, reader *Reader, limit int) ([]msgbus.Message, error) {
+	for {
+		b.mu.Lock()
+		t := b.topic(reader.cfg.Topic)
+		records := t.take(t.group(reader.cfg.GroupID), reader, limit)
+		written := t.written
+		b.mu.Unlock()
+
+		if len(records) > 0 {
+			return records, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-reader.closed:
+			return nil, nil
+		case <-written:
+		}
+	}
+}
+
+// ack settles a record acknowledged by the consumer group.
+func (b *Broker) ack(topicName string, groupID string, offset int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	delete(b.topic(topicName).group(groupID).pending, offset)
+}
+
+// nack settles a record rejected by the consumer group, redelivering it after the redelivery delay.
+func (b *Broker) nack(topicName string, groupID string, offset int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	g := b.topic(topicName).group(groupID)
+	if _, ok := g.pending[offset]; !ok {
+		return
+	}
+
+	// Keep the record pending until it is redelivered, so that it is not committed meanwhile
+	g.pending[offset] = nil
+
+	time.AfterFunc(redeliveryDelay, func() {
+		b.requeue(topicName, groupID, []int64{offset})
+	})
+}
+
+// release returns the unsettled records held by a closed reader to its consumer group.
+func (b *Broker) release(reader *Reader) {
+	b.mu.Lock()
+	g := b.topic(reader.cfg.Topic).group(reader.cfg.GroupID)
+
+	offsets := make([]int64, 0)
+	for offset, holder := range g.pending {
+		if holder == reader {
+			offsets = append(offsets, offset)
+		}
+	}
+	b.mu.Unlock()
+
+	if len(offsets) > 0 {
+		slog.Info("Returning unsettled records to the consumer group", "group", reader.cfg.GroupID, "count", len(offsets))
+		b.requeue(reader.cfg.Topic, reader.cfg.GroupID, offsets)
+	}
+}
+
+// requeue moves pending records back to the consumer group for redelivery and wakes up waiting readers.
+func (b *Broker) requeue(topicName string, groupID string, offsets []int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	t := b.topic(topicName)
+	g := t.group(groupID)
+
+	for _, offset := range offsets {
+		// Skip records acknowledged in the meantime
+		if _, ok := g.pending[offset]; !ok {
+			continue
+		}
+
+		delete(g.pending, offset)
+		g.rejected = append(g.rejected, offset)
+	}
+
+	close(t.written)
+	t.written = make(chan struct{})
+}
+
+// topic returns the topic of the given name, creating it if it does not exist. The caller must hold the lock.
+func (b *Broker) topic(name string) *topic {
+	t, ok := b.topics[name]
+	if !ok {
+		t = &topic{
+			groups:  make(map[string]*group),
+			written: make(chan struct{}),
+		}
+		b.topics[name] = t
+	}
+
+	return t
+}
+
+// group returns the consumer group of the given ID, starting it at the first offset if it does not exist.
+func (t *topic) group(id string) *group {
+	g, ok := t.groups[id]
+	if !ok {
+		g = &group{
+			pending: make(map[int64]*Reader),
+		}
+		t.groups[id] = g
+	}
+
+	return g
+}
+
+// take returns up to limit records for the consumer group, rejected records first, and marks them pending on the
+// reader.
+func (t *topic) take(g *group, reader *Reader, limit int) []msgbus.Message {
+	records := make([]msgbus.Message, 0)
+
+	for len(records) < limit && len(g.rejected) > 0 {
+		offset := g.rejected[0]
+		g.rejected = g.rejected[1:]
+		g.pending[offset] = reader
+		records = append(records, t.records[offset])
+	}
+
+	for len(records) < limit && g.next < int64(len(t.records)) {
+		g.pending[g.next] = reader
+		records = append(records, t.records[g.next])
+		g.next++
+	}
+
+	return records
+}
+
+// newRecord creates the record of a message at the given offset of a topic, copying its contents so that the
+// writer may reuse its buffers.
+func newRecord(msg msgbus.Message, topicName string, offset int64) msgbus.Message {
+	record := msgbus.Message{
+		Topic:     topicName,
+		Offset:    offset,
+		Key:       bytes.Clone(msg.Key),
+		Value:     bytes.Clone(msg.Value),
+		Timestamp: msg.Timestamp,
+	}
+
+	if record.Timestamp.IsZero() {
+		record.Timestamp = time.Now()
+	}
+
+	for _, header := range msg.Headers {
+		record.Headers = append(record.Headers, msgbus.Header{Key: header.Key, Value: bytes.Clone(header.Value)})
+	}
+
+	return record
+}
@@ -0,0 +1,155 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+
+	"github.com/ansoncht/flight-microservices/pkg/msgbus"
+)
+
+// defaultMaxPollRecords specifies the maximum number of records handled per poll when unset.
+const defaultMaxPollRecords = 500
+
+// ReaderConfig holds configuration settings for the in-memory reader.
+type ReaderConfig struct {
+	// Topic specifies the topic to read from.
+	Topic string `mapstructure:"topic"`
+	// GroupID specifies the consumer group sharing the records of the topic.
+	GroupID string `mapstructure:"group_id"`
+	// MaxPollRecords specifies the maximum number of records handled per poll. Defaults to 500.
+	MaxPollRecords int `mapstructure:"max_poll_records"`
+}
+
+// Reader holds the in-memory reader instance.
+// It implements the msgbus.MessageReader interface to read messages from a topic of the broker.
+type Reader struct {
+	// Broker specifies the broker holding the topic.
+	Broker *Broker
+	// cfg specifies the configuration of the topic and consumer group to read.
+	cfg ReaderConfig
+	// closed specifies the channel closed once the reader is closed.
+	closed chan struct{}
+	// closeOnce guards closing the reader more than once.
+	closeOnce sync.Once
+}
+
+// NewMemoryReader creates a new Reader instance reading from the provided broker.
+func NewMemoryReader(broker *Broker, cfg ReaderConfig) (*Reader, error) {
+	slog.Info("Initializing in-memory reader for the service", "topic", cfg.Topic, "group", cfg.GroupID)
+
+	if broker == nil {
+		return nil, fmt.Errorf("memory broker is nil")
+	}
+
+	if cfg.Topic == "" {
+		return nil, fmt.Errorf("memory topic is empty")
+	}
+
+	if cfg.GroupID == "" {
+		return nil, fmt.Errorf("memory consumer group is empty")
+	}
+
+	if cfg.MaxPollRecords < 0 {
+		return nil, fmt.Errorf("memory max poll records is invalid: %d", cfg.MaxPollRecords)
+	}
+
+	if cfg.MaxPollRecords == 0 {
+		cfg.MaxPollRecords = defaultMaxPollRecords
+	}
+
+	return &Reader{
+		Broker: broker,
+		cfg:    cfg,
+		closed: make(chan struct{}),
+	}, nil
+}
+
+// Check does nothing, as the broker lives in the same process.
+func (r *Reader) Check(_ context.Context) error {
+	return nil
+}
+
+// Close stops the reader and returns its unsettled records to the consumer group.
+func (r *Reader) Close() {
+	r.closeOnce.Do(func() {
+		close(r.closed)
+		r.Broker.release(r)
+	})
+}
+
+// ReadMessages reads messages from the topic and sends them to the channel until the context is done or the
+// reader is closed.
+func (r *Reader) ReadMessages(ctx context.Context, msgChan chan<- msgbus.Delivery) error {
+	slog.Info("Reading message from in-memory topic")
+
+	defer close(msgChan)
+
+	if r == nil {
+		return fmt.Errorf("memory reader is nil")
+	}
+
+	for {
+		records, err := r.Broker.poll(ctx, r, r.cfg.MaxPollRecords)
+		if err != nil {
+			return fmt.Errorf("context canceled while fetching memory messages: %w", err)
+		}
+
+		// The reader was closed
+		if records == nil {
+			break
+		}
+
+		if err := r.deliver(ctx, records, msgChan); err != nil {
+			return err
+		}
+	}
+
+	if ctx.Err() != nil {
+		return fmt.Errorf("context canceled while fetching memory messages: %w", ctx.Err())
+	}
+
+	return nil
+}
+
+// deliver sends the records to the channel, returning the undelivered records to the consumer group once the
+// context is done.
+func (r *Reader) deliver(ctx context.Context, records []msgbus.Message, msgChan chan<- msgbus.Delivery) error {
+	for i, record := range records {
+		select {
+		case <-ctx.Done():
+			offsets := make([]int64, 0, len(records)-i)
+			for _, undelivered := range records[i:] {
+				offsets = append(offsets, undelivered.Offset)
+			}
+
+			r.Broker.requeue(r.cfg.Topic, r.cfg.GroupID, offsets)
+
+			return fmt.Errorf("context canceled while fetching memory messages: %w", ctx.Err())
+		case msgChan <- msgbus.NewDelivery(record, acknowledger{broker: r.Broker, groupID: r.cfg.GroupID}):
+		}
+	}
+
+	return nil
+}
+
+// acknowledger implements the msgbus.Acknowledger interface for the records of a consumer group.
+type acknowledger struct {
+	// broker specifies the broker holding the records.
+	broker *Broker
+	// groupID specifies the consumer group settling the records.
+	groupID string
+}
+
+// Ack acknowledges the record, so that it is not redelivered to the consumer group.
+func (a acknowledger) Ack(msg msgbus.Message) {
+	a.broker.ack(msg.Topic, a.groupID, msg.Offset)
+}
+
+// Nack rejects the record, so that it is redelivered to the consumer group after the redelivery delay.
+func (a acknowledger) Nack(msg msgbus.Message, err error) {
+	slog.Warn("Rejected message, redelivering it later", "topic", msg.Topic, "offset", msg.Offset, "error", err)
+
+	a.broker.nack(msg.Topic, a.groupID, msg.Offset)
+}
@@ -0,0 +1,249 @@
+package memory_test
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/ansoncht/flight-microservices/pkg/memory"
+	"github.com/ansoncht/flight-microservices/pkg/msgbus"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testTopic        = "test-topic"
+	testGroupID      = "test-group"
+	testMessageKey   = "test-key"
+	testMessageValue = "hello, world"
+	timeout          = 5 * time.Second
+)
+
+// startReading starts reading from a new reader of the consumer group and returns its message channel and a
+// function stopping it.
+func startReading(t *testing.T, broker *memory.Broker, groupID string) (<-chan msgbus.Delivery, func()) {
+	t.Helper()
+
+	reader, err := memory.NewMemoryReader(broker, memory.ReaderConfig{Topic: testTopic, GroupID: groupID})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	msgChan := make(chan msgbus.Delivery)
+	readErrChan := make(chan error, 1)
+
+	go func() {
+		readErrChan <- reader.ReadMessages(ctx, msgChan)
+	}()
+
+	return msgChan, func() {
+		cancel()
+		reader.Close()
+
+		select {
+		case err := <-readErrChan:
+			require.ErrorIs(t, err, context.Canceled)
+		case <-time.After(timeout):
+			t.Error("ReadMessages goroutine did not exit cleanly after cancellation")
+		}
+	}
+}
+
+// receive waits for the next delivery on the channel.
+func receive(t *testing.T, msgChan <-chan msgbus.Delivery) msgbus.Delivery {
+	t.Helper()
+
+	select {
+	case delivery := <-msgChan:
+		return delivery
+	case <-time.After(timeout):
+		t.Fatal("timed out waiting for message")
+		return msgbus.Delivery{}
+	}
+}
+
+// write writes messages with the given keys to the test topic.
+func write(t *testing.T, broker *memory.Broker, keys ...string) {
+	t.Helper()
+
+	writer, err := memory.NewMemoryWriter(broker, memory.WriterConfig{Topic: testTopic})
+	require.NoError(t, err)
+
+	msgs := make([]msgbus.Message, 0, len(keys))
+	for _, key := range keys {
+		msgs = append(msgs, msgbus.Message{Key: []byte(key), Value: []byte(testMessageValue)})
+	}
+
+	require.NoError(t, writer.WriteMessages(context.Background(), msgs))
+}
+
+func TestNewMemoryReader_InvalidConfig_ShouldError(t *testing.T) {
+	cfg := memory.ReaderConfig{
+		Topic:   testTopic,
+		GroupID: testGroupID,
+	}
+
+	tests := []struct {
+		name    string
+		broker  *memory.Broker
+		cfg     memory.ReaderConfig
+		wantErr string
+	}{
+		{
+			name:    "Nil Broker",
+			cfg:     cfg,
+			wantErr: "memory broker is nil",
+		},
+		{
+			name:   "Missing Topic",
+			broker: memory.NewBroker(),
+			cfg: func() memory.ReaderConfig {
+				c := cfg
+				c.Topic = ""
+				return c
+			}(),
+			wantErr: "memory topic is empty",
+		},
+		{
+			name:   "Missing Group ID",
+			broker: memory.NewBroker(),
+			cfg: func() memory.ReaderConfig {
+				c := cfg
+				c.GroupID = ""
+				return c
+			}(),
+			wantErr: "memory consumer group is empty",
+		},
+		{
+			name:   "Negative Max Poll Records",
+			broker: memory.NewBroker(),
+			cfg: func() memory.ReaderConfig {
+				c := cfg
+				c.MaxPollRecords = -1
+				return c
+			}(),
+			wantErr: "memory max poll records is invalid",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader, err := memory.NewMemoryReader(tt.broker, tt.cfg)
+			require.ErrorContains(t, err, tt.wantErr)
+			require.Nil(t, reader)
+		})
+	}
+}
+
+func TestReadMessages_ConsumerGroups_ShouldFanOut(t *testing.T) {
+	broker := memory.NewBroker()
+
+	// Records written before a consumer group joins are read from the first offset
+	write(t, broker, "a", "b")
+
+	first, stopFirst := startReading(t, broker, "first")
+	defer stopFirst()
+
+	second, stopSecond := startReading(t, broker, "second")
+	defer stopSecond()
+
+	write(t, broker, "c")
+
+	for _, msgChan := range []<-chan msgbus.Delivery{first, second} {
+		for i, want := range []string{"a", "b", "c"} {
+			delivery := receive(t, msgChan)
+			require.Equal(t, want, string(delivery.Key))
+			require.Equal(t, testMessageValue, string(delivery.Value))
+			require.Equal(t, testTopic, delivery.Topic)
+			require.Equal(t, int64(i), delivery.Offset)
+			require.False(t, delivery.Timestamp.IsZero())
+			delivery.Ack()
+		}
+	}
+
+	require.Equal(t, int64(3), broker.Committed(testTopic, "first"))
+	require.Equal(t, int64(3), broker.Committed(testTopic, "second"))
+}
+
+func TestReadMessages_SameConsumerGroup_ShouldShareRecords(t *testing.T) {
+	broker := memory.NewBroker()
+
+	first, stopFirst := startReading(t, broker, testGroupID)
+	defer stopFirst()
+
+	second, stopSecond := startReading(t, broker, testGroupID)
+	defer stopSecond()
+
+	const records = 100
+	keys := make([]string, 0, records)
+	for i := range records {
+		keys = append(keys, strconv.Itoa(i))
+	}
+	write(t, broker, keys...)
+
+	seen := make(map[int64]bool, records)
+	for len(seen) < records {
+		var delivery msgbus.Delivery
+		select {
+		case delivery = <-first:
+		case delivery = <-second:
+		case <-time.After(timeout):
+			t.Fatalf("timed out after reading %d of %d messages", len(seen), records)
+		}
+
+		require.False(t, seen[delivery.Offset], "offset %d delivered twice", delivery.Offset)
+		seen[delivery.Offset] = true
+		delivery.Ack()
+	}
+
+	require.Equal(t, int64(records), broker.Committed(testTopic, testGroupID))
+}
+
+func TestReadMessages_Nack_ShouldRedeliver(t *testing.T) {
+	broker := memory.NewBroker()
+	write(t, broker, testMessageKey, "other")
+
+	msgChan, stop := startReading(t, broker, testGroupID)
+	defer stop()
+
+	rejected := receive(t, msgChan)
+	require.Equal(t, testMessageKey, string(rejected.Key))
+	rejected.Nack(errors.New("test error"))
+
+	acked := receive(t, msgChan)
+	require.Equal(t, "other", string(acked.Key))
+	acked.Ack()
+
+	// The rejected record holds back the committed offset until it is acknowledged
+	require.Equal(t, int64(0), broker.Committed(testTopic, testGroupID))
+
+	redelivered := receive(t, msgChan)
+	require.Equal(t, testMessageKey, string(redelivered.Key))
+	require.Equal(t, rejected.Offset, redelivered.Offset)
+	redelivered.Ack()
+
+	require.Equal(t, int64(2), broker.Committed(testTopic, testGroupID))
+}
+
+func TestReadMessages_Close_ShouldReturnUnsettledRecords(t *testing.T) {
+	broker := memory.NewBroker()
+	write(t, broker, testMessageKey)
+
+	msgChan, stop := startReading(t, broker, testGroupID)
+	unsettled := receive(t, msgChan)
+	require.Equal(t, testMessageKey, string(unsettled.Key))
+	stop()
+
+	msgChan, stop = startReading(t, broker, testGroupID)
+	defer stop()
+
+	redelivered := receive(t, msgChan)
+	require.Equal(t, unsettled.Offset, redelivered.Offset)
+	redelivered.Ack()
+}
+
+func TestReadMessages_NilReader_ShouldError(t *testing.T) {
+	var reader *memory.Reader
+	err := reader.ReadMessages(context.Background(), make(chan msgbus.Delivery, 1))
+	require.ErrorContains(t, err, "memory reader is nil")
+}
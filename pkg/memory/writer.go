@@ -0,0 +1,93 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/ansoncht/flight-microservices/pkg/msgbus"
+)
+
+// WriterConfig holds configuration settings for the in-memory writer.
+type WriterConfig struct {
+	// Topic specifies the topic to write to.
+	Topic string `mapstructure:"topic"`
+}
+
+// Writer holds the in-memory writer instance.
+// It implements the msgbus.MessageWriter interface to append messages to a topic of the broker.
+// Messages with a topic are appended to that topic, and messages without a timestamp are stamped when written.
+type Writer struct {
+	// Broker specifies the broker holding the topic.
+	Broker *Broker
+	// cfg specifies the configuration of the topic to write to.
+	cfg WriterConfig
+}
+
+// NewMemoryWriter creates a new Writer instance writing to the provided broker.
+func NewMemoryWriter(broker *Broker, cfg WriterConfig) (*Writer, error) {
+	slog.Info("Initializing in-memory writer for the service", "topic", cfg.Topic)
+
+	if broker == nil {
+		return nil, fmt.Errorf("memory broker is nil")
+	}
+
+	if cfg.Topic == "" {
+		return nil, fmt.Errorf("memory topic is empty")
+	}
+
+	return &Writer{
+		Broker: broker,
+		cfg:    cfg,
+	}, nil
+}
+
+// Check does nothing, as the broker lives in the same process.
+func (w *Writer) Check(_ context.Context) error {
+	return nil
+}
+
+// Close does nothing, as the broker outlives its writers.
+func (w *Writer) Close() {}
+
+// WriteMessage writes a message to the topic.
+func (w *Writer) WriteMessage(_ context.Context, key []byte, value []byte) error {
+	slog.Info("Writing message to in-memory topic", "key", string(key), "message", string(value))
+
+	if w == nil {
+		return fmt.Errorf("memory writer is nil")
+	}
+
+	msg := msgbus.Message{Key: key, Value: value}
+	if err := msg.Validate(); err != nil {
+		return fmt.Errorf("invalid message: %w", err)
+	}
+
+	w.Broker.publish(w.cfg.Topic, []msgbus.Message{msg})
+
+	return nil
+}
+
+// WriteMessages writes a batch of messages to their topics, defaulting to the topic of the writer, at once.
+func (w *Writer) WriteMessages(_ context.Context, msgs []msgbus.Message) error {
+	slog.Info("Writing messages to in-memory topic", "count", len(msgs))
+
+	if w == nil {
+		return fmt.Errorf("memory writer is nil")
+	}
+
+	for i, msg := range msgs {
+		if err := msg.Validate(); err != nil {
+			return fmt.Errorf("invalid message %d: %w", i, err)
+		}
+	}
+
+	w.Broker.publish(w.cfg.Topic, msgs)
+
+	return nil
+}
+
+// Flush does nothing, as writes to the broker complete before they return.
+func (w *Writer) Flush(_ context.Context) error {
+	return nil
+}
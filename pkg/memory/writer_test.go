@@ -0,0 +1,94 @@
+package memory_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ansoncht/flight-microservices/pkg/memory"
+	"github.com/ansoncht/flight-microservices/pkg/msgbus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewMemoryWriter_InvalidConfig_ShouldError(t *testing.T) {
+	t.Run("Nil Broker", func(t *testing.T) {
+		writer, err := memory.NewMemoryWriter(nil, memory.WriterConfig{Topic: testTopic})
+		require.ErrorContains(t, err, "memory broker is nil")
+		require.Nil(t, writer)
+	})
+
+	t.Run("Missing Topic", func(t *testing.T) {
+		writer, err := memory.NewMemoryWriter(memory.NewBroker(), memory.WriterConfig{})
+		require.ErrorContains(t, err, "memory topic is empty")
+		require.Nil(t, writer)
+	})
+}
+
+func TestWriteMessages_InvalidMessage_ShouldError(t *testing.T) {
+	broker := memory.NewBroker()
+	writer, err := memory.NewMemoryWriter(broker, memory.WriterConfig{Topic: testTopic})
+	require.NoError(t, err)
+	defer writer.Close()
+
+	err = writer.WriteMessage(context.Background(), []byte(testMessageKey), nil)
+	require.ErrorContains(t, err, "message value is nil or empty")
+
+	err = writer.WriteMessages(context.Background(), []msgbus.Message{
+		{Key: []byte(testMessageKey), Value: []byte(testMessageValue)},
+		{Value: []byte(testMessageValue)},
+	})
+	require.ErrorContains(t, err, "invalid message 1: message key is nil or empty")
+
+	// A batch with an invalid message is not written at all
+	require.Equal(t, int64(0), broker.Committed(testTopic, testGroupID))
+	msgChan, stop := startReading(t, broker, testGroupID)
+	defer stop()
+
+	select {
+	case delivery := <-msgChan:
+		t.Fatalf("unexpected message %s", delivery.Key)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestWriteMessages_MessageFields_ShouldBeKept(t *testing.T) {
+	broker := memory.NewBroker()
+	writer, err := memory.NewMemoryWriter(broker, memory.WriterConfig{Topic: testTopic})
+	require.NoError(t, err)
+
+	timestamp := time.Date(2025, 5, 7, 8, 0, 0, 0, time.UTC)
+	value := []byte(testMessageValue)
+
+	err = writer.WriteMessages(context.Background(), []msgbus.Message{
+		{
+			Key:       []byte(testMessageKey),
+			Value:     value,
+			Headers:   []msgbus.Header{{Key: "correlation-id", Value: []byte("abc")}},
+			Timestamp: timestamp,
+		},
+		{Topic: "other-topic", Key: []byte(testMessageKey), Value: value},
+	})
+	require.NoError(t, err)
+	require.NoError(t, writer.Flush(context.Background()))
+
+	// Reusing the buffer of a written message must not change it
+	copy(value, "HELLO")
+
+	msgChan, stop := startReading(t, broker, testGroupID)
+	defer stop()
+
+	delivery := receive(t, msgChan)
+	require.Equal(t, testMessageKey, string(delivery.Key))
+	require.Equal(t, testMessageValue, string(delivery.Value))
+	require.Equal(t, timestamp, delivery.Timestamp)
+	require.Len(t, delivery.Headers, 1)
+	require.Equal(t, "correlation-id", delivery.Headers[0].Key)
+	require.Equal(t, "abc", string(delivery.Headers[0].Value))
+	delivery.Ack()
+
+	select {
+	case delivery := <-msgChan:
+		t.Fatalf("unexpected message on topic %s", delivery.Topic)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
@@ -0,0 +1,31 @@
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const (
+	// Namespace specifies the namespace shared by the metrics of the flight services.
+	Namespace = "flight"
+	// Path specifies the path where the metrics are served.
+	Path = "/metrics"
+	// latencyBucketStart specifies the upper bound of the smallest latency bucket in seconds.
+	latencyBucketStart = 0.001
+	// latencyBucketFactor specifies the factor between the upper bounds of consecutive latency buckets.
+	latencyBucketFactor = 2
+	// latencyBucketCount specifies the number of latency buckets, spanning 1ms to about 8s.
+	latencyBucketCount = 14
+)
+
+// LatencyBuckets specifies the buckets of latency histograms in seconds.
+var LatencyBuckets = prometheus.ExponentialBuckets(latencyBucketStart, latencyBucketFactor, latencyBucketCount)
+
+// Handler returns the handler serving the metrics of the default registry in the Prometheus exposition format.
+// Collectors registered with the default registry by any package of the service are served, along with the Go
+// runtime and process metrics.
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
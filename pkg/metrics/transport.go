@@ -0,0 +1,56 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// statusError labels upstream requests which failed without a response.
+const statusError = "error"
+
+// upstreamRequestDuration observes the latency of requests to upstream APIs.
+var upstreamRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Namespace: Namespace,
+	Subsystem: "upstream",
+	Name:      "request_duration_seconds",
+	Help:      "Latency of requests to upstream APIs by host, method and response status.",
+	Buckets:   LatencyBuckets,
+}, []string{"host", "method", "status"})
+
+// Transport holds an instrumented HTTP transport.
+// It implements the http.RoundTripper interface to observe the latency and response status of each request.
+type Transport struct {
+	// next specifies the transport sending the requests.
+	next http.RoundTripper
+}
+
+// NewTransport creates a new Transport instance sending requests with the provided transport, or with the default
+// transport when it is nil.
+func NewTransport(next http.RoundTripper) *Transport {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	return &Transport{
+		next: next,
+	}
+}
+
+// RoundTrip sends the request and observes its latency by host, method and response status.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+
+	status := statusError
+	if err == nil {
+		status = strconv.Itoa(resp.StatusCode)
+	}
+
+	upstreamRequestDuration.WithLabelValues(req.URL.Host, req.Method, status).Observe(time.Since(start).Seconds())
+
+	return resp, err //nolint:wrapcheck // http.Client expects the errors of its transport as is
+}
@@ -0,0 +1,75 @@
+package metrics_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/ansoncht/flight-microservices/pkg/metrics"
+	"github.com/stretchr/testify/require"
+)
+
+// roundTripFunc implements the http.RoundTripper interface with a function.
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+// RoundTrip calls the function with the request.
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// scrape returns the metrics served by the metrics handler.
+func scrape(t *testing.T) string {
+	t.Helper()
+
+	recorder := httptest.NewRecorder()
+	metrics.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, metrics.Path, nil))
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	body, err := io.ReadAll(recorder.Body)
+	require.NoError(t, err)
+
+	return string(body)
+}
+
+func TestTransport_Response_ShouldObserveStatus(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	}))
+	defer upstream.Close()
+
+	client := &http.Client{Transport: metrics.NewTransport(nil)}
+	resp, err := client.Get(upstream.URL)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+
+	host, err := url.Parse(upstream.URL)
+	require.NoError(t, err)
+
+	require.Contains(t, scrape(t), fmt.Sprintf(
+		`flight_upstream_request_duration_seconds_count{host="%s",method="GET",status="418"} 1`,
+		host.Host,
+	))
+}
+
+func TestTransport_Error_ShouldObserveError(t *testing.T) {
+	failing := roundTripFunc(func(*http.Request) (*http.Response, error) {
+		return nil, errors.New("connection refused")
+	})
+
+	client := &http.Client{Transport: metrics.NewTransport(failing)}
+	resp, err := client.Post("http://unreachable.test/route", "application/json", nil)
+	require.ErrorContains(t, err, "connection refused")
+	require.Nil(t, resp)
+
+	require.Contains(t, scrape(t),
+		`flight_upstream_request_duration_seconds_count{host="unreachable.test",method="POST",status="error"} 1`,
+	)
+}
+
+func TestHandler_ShouldServeRuntimeMetrics(t *testing.T) {
+	require.Contains(t, scrape(t), "go_goroutines")
+}
@@ -8,4 +8,9 @@ type FlightRecord struct {
 	Destination  string `json:"destination"`
 	FirstSeen    int    `json:"firstSeen"`
 	LastSeen     int    `json:"lastSeen"`
+	// OriginCountry and DestinationCountry specify ISO 3166-1 alpha-2 country codes.
+	OriginCountry      string `json:"originCountry,omitempty"`
+	DestinationCountry string `json:"destinationCountry,omitempty"`
+	// Icao24 specifies the ICAO 24-bit transponder address of the aircraft.
+	Icao24 string `json:"icao24,omitempty"`
 }
@@ -0,0 +1,72 @@
+package model
+
+import (
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// FlightSummaryRollup holds statistics aggregated from the daily flight summaries of an airport over a period.
+type FlightSummaryRollup struct {
+	ID                primitive.ObjectID `bson:"_id,omitempty"`
+	StartDate         primitive.DateTime `bson:"startDate"`
+	EndDate           primitive.DateTime `bson:"endDate"`
+	Airport           string             `bson:"airport"`
+	Days              int                `bson:"days"`
+	TotalFlights      int                `bson:"totalFlights"`
+	DailyAverage      float64            `bson:"dailyAverage"`
+	BusiestDay        primitive.DateTime `bson:"busiestDay"`
+	BusiestDayFlights int                `bson:"busiestDayFlights"`
+	AirlineCounts     map[string]int     `bson:"airlineCounts"`
+	DestinationCounts map[string]int     `bson:"destinationCounts"`
+	TopDestinations   []string           `bson:"topDestinations,omitempty"`
+	TopAirlines       []string           `bson:"topAirlines,omitempty"`
+	// AirlineRanking and DestinationRanking hold the ranked top counts behind TopAirlines and TopDestinations.
+	AirlineRanking     []RankedEntry `bson:"airlineRanking,omitempty"`
+	DestinationRanking []RankedEntry `bson:"destinationRanking,omitempty"`
+}
+
+// WeeklyFlightSummary holds aggregated statistics for all flights departing from an airport in a Monday to Sunday week.
+type WeeklyFlightSummary struct {
+	FlightSummaryRollup `bson:",inline"`
+}
+
+// MonthlyFlightSummary holds aggregated statistics for all flights departing from an airport in a calendar month.
+type MonthlyFlightSummary struct {
+	FlightSummaryRollup `bson:",inline"`
+}
+
+// FormatForSocialMedia formats the WeeklyFlightSummary for social media content.
+func (s *WeeklyFlightSummary) FormatForSocialMedia() string {
+	return s.format("📆 **Weekly Flight Recap** 📆", "Week")
+}
+
+// FormatForSocialMedia formats the MonthlyFlightSummary for social media content.
+func (s *MonthlyFlightSummary) FormatForSocialMedia() string {
+	return s.format("🗓️ **Monthly Flight Recap** 🗓️", "Month")
+}
+
+// format formats the rollup with the given title and period label.
+func (s *FlightSummaryRollup) format(title string, period string) string {
+	return fmt.Sprintf(
+		"%s\n"+
+			"📍 **Airport**: %s\n"+
+			"📅 **%s**: %s to %s\n"+
+			"🛫 **Total Flights**: %d\n"+
+			"📊 **Daily Average**: %.1f\n"+
+			"🔥 **Busiest Day**: %s with %d flights\n\n"+
+			"🏆 **Top 5 Airlines**:\n%s\n\n"+
+			"🌍 **Top 5 Destinations**:\n%s\n",
+		title,
+		s.Airport,
+		period,
+		s.StartDate.Time().UTC().Format("2006-01-02"),
+		s.EndDate.Time().UTC().Format("2006-01-02"),
+		s.TotalFlights,
+		s.DailyAverage,
+		s.BusiestDay.Time().UTC().Format("Monday 2006-01-02"),
+		s.BusiestDayFlights,
+		formatTopList(s.AirlineRanking, s.TopAirlines),
+		formatTopList(s.DestinationRanking, s.TopDestinations),
+	)
+}
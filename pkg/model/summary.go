@@ -2,25 +2,115 @@ package model
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 const (
-	limit = 5
+	limit   = 5
+	percent = 100
 )
 
 // DailyFlightSummary holds aggregated statistics for all flights departing from a specific airport on a given day.
 type DailyFlightSummary struct {
-	ID                primitive.ObjectID `bson:"_id,omitempty"`
-	Date              primitive.DateTime `bson:"date"`
-	Airport           string             `bson:"airport"`
-	TotalFlights      int                `bson:"totalFlights"`
-	AirlineCounts     map[string]int     `bson:"airlineCounts"`
-	DestinationCounts map[string]int     `bson:"destinationCounts"`
-	TopDestinations   []string           `bson:"topDestinations,omitempty"`
-	TopAirlines       []string           `bson:"topAirlines,omitempty"`
+	ID           primitive.ObjectID `bson:"_id,omitempty"`
+	Date         primitive.DateTime `bson:"date"`
+	Airport      string             `bson:"airport"`
+	TotalFlights int                `bson:"totalFlights"`
+	// DuplicatesRemoved holds the number of duplicate flight records excluded from TotalFlights.
+	DuplicatesRemoved int            `bson:"duplicatesRemoved"`
+	AirlineCounts     map[string]int `bson:"airlineCounts"`
+	DestinationCounts map[string]int `bson:"destinationCounts"`
+	TopDestinations   []string       `bson:"topDestinations,omitempty"`
+	TopAirlines       []string       `bson:"topAirlines,omitempty"`
+	// AirlineRanking and DestinationRanking hold the ranked top counts behind TopAirlines and TopDestinations.
+	AirlineRanking     []RankedEntry `bson:"airlineRanking,omitempty"`
+	DestinationRanking []RankedEntry `bson:"destinationRanking,omitempty"`
+	// HourlyDepartures holds the number of departures in each of the 24 airport-local hours of the day.
+	HourlyDepartures    []int `bson:"hourlyDepartures,omitempty"`
+	BusiestHour         int   `bson:"busiestHour"`
+	BusiestHourFlights  int   `bson:"busiestHourFlights"`
+	QuietestHour        int   `bson:"quietestHour"`
+	QuietestHourFlights int   `bson:"quietestHourFlights"`
+	// DayOverDay holds the changes since the previous day, if it was summarized.
+	DayOverDay *SummaryComparison `bson:"dayOverDay,omitempty"`
+	// WeekOverWeek holds the changes since the same weekday last week, if it was summarized.
+	WeekOverWeek *SummaryComparison `bson:"weekOverWeek,omitempty"`
+	// Anomalies holds the counts which deviate from the trailing days.
+	Anomalies []Anomaly `bson:"anomalies,omitempty"`
+	// Stats holds the sections contributed by statistics without dedicated fields, in configured order.
+	// A statistic may contribute several sections, each under its own name.
+	Stats []StatSection `bson:"stats,omitempty"`
+}
+
+// RankedEntry holds a key of a count map with its count, rank and share of the total.
+type RankedEntry struct {
+	Key   string `bson:"key"`
+	Count int    `bson:"count"`
+	// Rank specifies the 1-based rank, shared by ties. The aggregate of unranked keys has rank 0.
+	Rank int `bson:"rank"`
+	// Share specifies the count as a fraction of the total of all counts.
+	Share float64 `bson:"share"`
+}
+
+// RouteStatName specifies the name of the stat section holding the flights on each route keyed by RouteKey.
+const RouteStatName = "routes"
+
+// RouteKey returns the key of the route from origin to destination, e.g. "HKG-NRT".
+func RouteKey(origin string, destination string) string {
+	return origin + "-" + destination
+}
+
+// StatSection holds the values a statistic contributes to a summary under its name.
+// Its json tags name the fields of the stats stored as JSON, which summaries are queried by.
+type StatSection struct {
+	Name   string      `bson:"name"   json:"name"`
+	Title  string      `bson:"title"  json:"title"`
+	Values []StatValue `bson:"values" json:"values"`
+}
+
+// StatValue holds a single labelled value of a statistic section.
+type StatValue struct {
+	// Key identifies what the value counts, such as an ISO country code, when the label is only for display.
+	Key   string  `bson:"key,omitempty"  json:"key,omitempty"`
+	Label string  `bson:"label"          json:"label"`
+	Value float64 `bson:"value"          json:"value"`
+	Unit  string  `bson:"unit,omitempty" json:"unit,omitempty"`
+}
+
+// SummaryComparison holds the changes of a daily flight summary against an earlier baseline summary.
+type SummaryComparison struct {
+	BaselineDate    primitive.DateTime `bson:"baselineDate"`
+	BaselineFlights int                `bson:"baselineFlights"`
+	FlightsChange   int                `bson:"flightsChange"`
+	// FlightsChangePercent is only meaningful when BaselineFlights is positive.
+	FlightsChangePercent float64  `bson:"flightsChangePercent"`
+	AirlinesGained       []string `bson:"airlinesGained,omitempty"`
+	AirlinesLost         []string `bson:"airlinesLost,omitempty"`
+	NewDestinations      []string `bson:"newDestinations,omitempty"`
+}
+
+// Anomaly metrics.
+const (
+	AnomalyMetricTotalFlights = "total_flights"
+	AnomalyMetricAirline      = "airline"
+	AnomalyMetricDestination  = "destination"
+)
+
+// Anomaly holds a daily count which deviates from the same count over the trailing days.
+type Anomaly struct {
+	// Metric specifies the count which deviates, one of the AnomalyMetric constants.
+	Metric string `bson:"metric"`
+	// Key specifies the airline or destination of the count, empty for total flights.
+	Key   string `bson:"key,omitempty"`
+	Value int    `bson:"value"`
+	// Baseline specifies the median or mean of the count over the trailing days.
+	Baseline float64 `bson:"baseline"`
+	// Score specifies the signed deviation of the value from the baseline in units of spread.
+	Score  float64 `bson:"score"`
+	Method string  `bson:"method"`
 }
 
 // ToMongoDateTime converts time.Time to primitive.DateTime for MongoDB.
@@ -28,38 +118,175 @@ func ToMongoDateTime(t time.Time) primitive.DateTime {
 	return primitive.NewDateTimeFromTime(t)
 }
 
+// Stat returns the stat section of the given name, or nil if no statistic contributed it.
+func (s *DailyFlightSummary) Stat(name string) *StatSection {
+	for i := range s.Stats {
+		if s.Stats[i].Name == name {
+			return &s.Stats[i]
+		}
+	}
+
+	return nil
+}
+
 // FormatForSocialMedia formats the DailyFlightSummary for social media content.
 func (s *DailyFlightSummary) FormatForSocialMedia() string {
 	// Convert MongoDB date to Go's time.Time
 	date := s.Date.Time()
 
-	// Limit the top airlines and destinations to 5
-	topAirlines := s.TopAirlines
-	if len(topAirlines) > limit {
-		topAirlines = topAirlines[:limit]
-	}
-
-	topDestinations := s.TopDestinations
-	if len(topDestinations) > limit {
-		topDestinations = topDestinations[:limit]
-	}
-
 	// Format the summary with emojis
 	return fmt.Sprintf(
 		"✈️ **Daily Flight Summary** ✈️\n"+
 			"📍 **Airport**: %s\n"+
 			"📅 **Date**: %s\n"+
-			"🛫 **Total Flights**: %d\n\n"+
+			"🛫 **Total Flights**: %d\n"+
+			"%s%s%s%s\n"+
 			"🏆 **Top 5 Airlines**:\n%s\n\n"+
 			"🌍 **Top 5 Destinations**:\n%s\n",
 		s.Airport,
 		date.Format("2006-01-02"), // Format date as YYYY-MM-DD
 		s.TotalFlights,
-		formatListWithNumbers(topAirlines),
-		formatListWithNumbers(topDestinations),
+		s.formatPeakHours(),
+		s.formatTrends(),
+		s.formatAnomalies(),
+		s.formatStats(),
+		formatTopList(s.AirlineRanking, s.TopAirlines),
+		formatTopList(s.DestinationRanking, s.TopDestinations),
+	)
+}
+
+// FormatAnomalyAlert formats the anomalies of the DailyFlightSummary as an alert for social media content.
+func (s *DailyFlightSummary) FormatAnomalyAlert() string {
+	return fmt.Sprintf(
+		"🚨 **Unusual Activity Alert** 🚨\n"+
+			"📍 **Airport**: %s\n"+
+			"📅 **Date**: %s\n"+
+			"%s",
+		s.Airport,
+		s.Date.Time().Format("2006-01-02"),
+		s.formatAnomalies(),
+	)
+}
+
+// formatPeakHours formats the busiest and quietest hours, or nothing when no departure was bucketed by hour.
+func (s *DailyFlightSummary) formatPeakHours() string {
+	if s.BusiestHourFlights == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf(
+		"⏰ **Peak Hour**: Peak hour was %02d:00 with %d departures\n"+
+			"🌙 **Quietest Hour**: Quietest hour was %02d:00 with %d departures\n",
+		s.BusiestHour,
+		s.BusiestHourFlights,
+		s.QuietestHour,
+		s.QuietestHourFlights,
 	)
 }
 
+// formatTrends formats the flight changes against yesterday and the same weekday last week,
+// or nothing when neither baseline has flights.
+func (s *DailyFlightSummary) formatTrends() string {
+	trends := make([]string, 0, 2)
+
+	if c := s.DayOverDay; c != nil && c.BaselineFlights > 0 {
+		trends = append(trends, fmt.Sprintf("%+.0f%% vs yesterday", c.FlightsChangePercent))
+	}
+
+	if c := s.WeekOverWeek; c != nil && c.BaselineFlights > 0 {
+		trends = append(trends, fmt.Sprintf("%+.0f%% vs last %s", c.FlightsChangePercent, s.Date.Time().UTC().Weekday()))
+	}
+
+	if len(trends) == 0 {
+		return ""
+	}
+
+	formatted := fmt.Sprintf("📈 **Trend**: %s\n", strings.Join(trends, ", "))
+
+	if c := s.WeekOverWeek; c != nil && len(c.NewDestinations) > 0 {
+		formatted += fmt.Sprintf("🆕 **New Destinations**: %s\n", strings.Join(c.NewDestinations, ", "))
+	}
+
+	return formatted
+}
+
+// formatAnomalies formats up to 5 anomalies with their usual counts, or nothing when there are none.
+func (s *DailyFlightSummary) formatAnomalies() string {
+	if len(s.Anomalies) == 0 {
+		return ""
+	}
+
+	formatted := "⚠️ **Unusual Activity**:\n"
+	for i, anomaly := range s.Anomalies {
+		if i == limit {
+			break
+		}
+
+		name := anomaly.Key
+		if anomaly.Metric == AnomalyMetricTotalFlights {
+			name = "All airlines"
+		}
+
+		formatted += fmt.Sprintf("• %s: %d flights (usually %.0f)\n", name, anomaly.Value, anomaly.Baseline)
+	}
+
+	return formatted
+}
+
+// formatStats formats each stat section on a line of its first 5 labelled values.
+func (s *DailyFlightSummary) formatStats() string {
+	formatted := ""
+
+	for _, section := range s.Stats {
+		sectionValues := section.Values
+		if len(sectionValues) > limit {
+			sectionValues = sectionValues[:limit]
+		}
+
+		values := make([]string, 0, len(sectionValues))
+		for _, value := range sectionValues {
+			values = append(values, strings.TrimSpace(fmt.Sprintf("%s %g %s", value.Label, value.Value, value.Unit)))
+		}
+
+		formatted += fmt.Sprintf("📊 **%s**: %s\n", section.Title, strings.Join(values, ", "))
+	}
+
+	return formatted
+}
+
+// formatTopList formats the top 5 entries of a ranking with their counts and shares, folding the rest into
+// an "Others" line. Summaries stored before rankings existed fall back to the plain top names.
+func formatTopList(ranking []RankedEntry, names []string) string {
+	if len(ranking) == 0 {
+		// Limit the top names to 5
+		if len(names) > limit {
+			names = names[:limit]
+		}
+
+		return formatListWithNumbers(names)
+	}
+
+	formatted := ""
+	emojis := []string{"1️⃣", "2️⃣", "3️⃣", "4️⃣", "5️⃣"}
+	others := RankedEntry{}
+
+	for i, entry := range ranking {
+		if i >= limit || entry.Rank == 0 {
+			others.Count += entry.Count
+			others.Share += entry.Share
+			continue
+		}
+
+		formatted += fmt.Sprintf("%s %s (%d, %.0f%%)\n", emojis[entry.Rank-1], entry.Key, entry.Count, entry.Share*percent)
+	}
+
+	if others.Count > 0 {
+		formatted += fmt.Sprintf("➕ Others (%d, %.0f%%)\n", others.Count, others.Share*percent)
+	}
+
+	return formatted
+}
+
 // formatListWithNumbers formats a list of strings with numbers (e.g., 1️⃣, 2️⃣).
 func formatListWithNumbers(items []string) string {
 	formatted := ""
@@ -8,6 +8,7 @@ import (
 
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
 )
 
 // ClientConfig holds configuration settings for the MongoDB client.
@@ -59,7 +60,8 @@ func NewMongoClient(ctx context.Context, cfg ClientConfig) (*Client, error) {
 	clientOpts := options.Client().ApplyURI(cfg.URI).
 		SetMaxPoolSize(cfg.PoolSize).
 		SetConnectTimeout(time.Duration(cfg.ConnectionTimeout) * time.Second).
-		SetSocketTimeout(time.Duration(cfg.SocketTimeout) * time.Second)
+		SetSocketTimeout(time.Duration(cfg.SocketTimeout) * time.Second).
+		SetMonitor(newCommandMonitor())
 
 	client, err := mongo.Connect(ctx, clientOpts)
 	if err != nil {
@@ -79,3 +81,12 @@ func NewMongoClient(ctx context.Context, cfg ClientConfig) (*Client, error) {
 		Database: db,
 	}, nil
 }
+
+// Check pings the primary MongoDB server.
+func (c *Client) Check(ctx context.Context) error {
+	if err := c.Client.Ping(ctx, readpref.Primary()); err != nil {
+		return fmt.Errorf("failed to ping MongoDB: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,37 @@
+package mongo
+
+import (
+	"context"
+
+	"github.com/ansoncht/flight-microservices/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.mongodb.org/mongo-driver/event"
+)
+
+// Outcomes of MongoDB operations labelled by the operation latency metric.
+const (
+	operationSucceeded = "success"
+	operationFailed    = "error"
+)
+
+// operationDuration observes the latency of MongoDB operations.
+var operationDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Namespace: metrics.Namespace,
+	Subsystem: "mongo",
+	Name:      "operation_duration_seconds",
+	Help:      "Latency of MongoDB commands by command name and outcome.",
+	Buckets:   metrics.LatencyBuckets,
+}, []string{"command", "status"})
+
+// newCommandMonitor creates a command monitor observing the latency of each MongoDB command.
+func newCommandMonitor() *event.CommandMonitor {
+	return &event.CommandMonitor{
+		Succeeded: func(_ context.Context, e *event.CommandSucceededEvent) {
+			operationDuration.WithLabelValues(e.CommandName, operationSucceeded).Observe(e.Duration.Seconds())
+		},
+		Failed: func(_ context.Context, e *event.CommandFailedEvent) {
+			operationDuration.WithLabelValues(e.CommandName, operationFailed).Observe(e.Duration.Seconds())
+		},
+	}
+}
@@ -0,0 +1,39 @@
+package msgbus
+
+// Acknowledger defines the interface for settling messages read from a message queue.
+type Acknowledger interface {
+	// Ack settles a message whose side effects succeeded, allowing its offset to be committed.
+	Ack(msg Message)
+	// Nack settles a message whose side effects failed, so that it is redelivered once the reader restarts.
+	Nack(msg Message, err error)
+}
+
+// Delivery holds a message read from a message queue along with the acknowledger settling it.
+// Each delivery should be settled with either Ack or Nack exactly once.
+type Delivery struct {
+	Message
+	// acknowledger specifies the acknowledger to settle the message with, or nil if it needs no settling.
+	acknowledger Acknowledger
+}
+
+// NewDelivery creates a new Delivery of a message settled by the provided acknowledger, which may be nil.
+func NewDelivery(msg Message, acknowledger Acknowledger) Delivery {
+	return Delivery{
+		Message:      msg,
+		acknowledger: acknowledger,
+	}
+}
+
+// Ack acknowledges the message after its side effects succeeded.
+func (d Delivery) Ack() {
+	if d.acknowledger != nil {
+		d.acknowledger.Ack(d.Message)
+	}
+}
+
+// Nack rejects the message after its side effects failed.
+func (d Delivery) Nack(err error) {
+	if d.acknowledger != nil {
+		d.acknowledger.Nack(d.Message, err)
+	}
+}
@@ -0,0 +1,34 @@
+package msgbus_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ansoncht/flight-microservices/internal/test/mock"
+	"github.com/ansoncht/flight-microservices/pkg/msgbus"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestDelivery_WithAcknowledger_ShouldSettleMessage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	msg := msgbus.Message{Key: []byte("key"), Value: []byte("value")}
+	reason := errors.New("failed")
+
+	acknowledger := mock.NewMockAcknowledger(ctrl)
+	acknowledger.EXPECT().Ack(msg)
+	acknowledger.EXPECT().Nack(msg, reason)
+
+	delivery := msgbus.NewDelivery(msg, acknowledger)
+	delivery.Ack()
+	delivery.Nack(reason)
+}
+
+func TestDelivery_WithoutAcknowledger_ShouldNotPanic(t *testing.T) {
+	delivery := msgbus.NewDelivery(msgbus.Message{Key: []byte("key")}, nil)
+	require.Equal(t, "key", string(delivery.Key))
+	delivery.Ack()
+	delivery.Nack(errors.New("failed"))
+}
@@ -0,0 +1,93 @@
+package msgbus
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel"
+)
+
+// Header holds a key-value pair of metadata carried alongside a message, such as a trace or correlation ID.
+type Header struct {
+	// Key specifies the header key.
+	Key string
+	// Value specifies the header value.
+	Value []byte
+}
+
+// Message holds a message written to or read from a message queue, independent of the broker carrying it.
+type Message struct {
+	// Topic specifies the topic of the message. Defaults to the topic of the writer when writing.
+	Topic string
+	// Partition specifies the partition the message was read from. It is ignored when writing.
+	Partition int32
+	// Offset specifies the position of the message within its partition when read. It is ignored when writing.
+	Offset int64
+	// Key specifies the message key, which also selects the partition of the message.
+	Key []byte
+	// Value specifies the message value.
+	Value []byte
+	// Headers specifies the metadata carried alongside the message.
+	Headers []Header
+	// Timestamp specifies the time of the message. Defaults to the time it is written.
+	Timestamp time.Time
+}
+
+// Validate checks that a message has both a key and a value.
+func (m Message) Validate() error {
+	if len(m.Key) == 0 {
+		return fmt.Errorf("message key is nil or empty")
+	}
+
+	if len(m.Value) == 0 {
+		return fmt.Errorf("message value is nil or empty")
+	}
+
+	return nil
+}
+
+// TraceContext returns a copy of the context carrying the trace context propagated in the headers of the message,
+// so that spans handling the message continue the trace of the service which wrote it.
+func (m Message) TraceContext(ctx context.Context) context.Context {
+	return otel.GetTextMapPropagator().Extract(ctx, headerCarrier{headers: &m.Headers})
+}
+
+// headerCarrier adapts the headers of a message to an OpenTelemetry text map carrier.
+type headerCarrier struct {
+	// headers specifies the headers of the message.
+	headers *[]Header
+}
+
+// Get returns the value of the header with the given key.
+func (c headerCarrier) Get(key string) string {
+	for _, header := range *c.headers {
+		if header.Key == key {
+			return string(header.Value)
+		}
+	}
+
+	return ""
+}
+
+// Set sets the value of the header with the given key, replacing any existing value.
+func (c headerCarrier) Set(key string, value string) {
+	for i, header := range *c.headers {
+		if header.Key == key {
+			(*c.headers)[i].Value = []byte(value)
+			return
+		}
+	}
+
+	*c.headers = append(*c.headers, Header{Key: key, Value: []byte(value)})
+}
+
+// Keys returns the keys of the headers.
+func (c headerCarrier) Keys() []string {
+	keys := make([]string, 0, len(*c.headers))
+	for _, header := range *c.headers {
+		keys = append(keys, header.Key)
+	}
+
+	return keys
+}
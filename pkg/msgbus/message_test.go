@@ -0,0 +1,52 @@
+package msgbus_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ansoncht/flight-microservices/pkg/msgbus"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestValidate_MissingKeyOrValue_ShouldError(t *testing.T) {
+	tests := []struct {
+		name        string
+		msg         msgbus.Message
+		expectedErr string
+	}{
+		{name: "Missing Key", msg: msgbus.Message{Value: []byte("value")}, expectedErr: "message key is nil or empty"},
+		{name: "Missing Value", msg: msgbus.Message{Key: []byte("key")}, expectedErr: "message value is nil or empty"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.ErrorContains(t, tt.msg.Validate(), tt.expectedErr)
+		})
+	}
+}
+
+func TestTraceContext_ShouldExtractFromHeaders(t *testing.T) {
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator())
+	})
+
+	msg := msgbus.Message{
+		Headers: []msgbus.Header{
+			{Key: "traceparent", Value: []byte("00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")},
+		},
+	}
+
+	spanContext := trace.SpanContextFromContext(msg.TraceContext(context.Background()))
+	require.True(t, spanContext.IsRemote())
+	require.Equal(t, "0af7651916cd43dd8448eb211c80319c", spanContext.TraceID().String())
+	require.Equal(t, "b7ad6b7169203331", spanContext.SpanID().String())
+}
+
+func TestTraceContext_NoHeaders_ShouldReturnContext(t *testing.T) {
+	ctx := msgbus.Message{}.TraceContext(context.Background())
+	require.False(t, trace.SpanContextFromContext(ctx).IsValid())
+}
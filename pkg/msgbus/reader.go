@@ -0,0 +1,18 @@
+package msgbus
+
+import "context"
+
+// DeliveryBufferSize specifies the number of deliveries buffered between a reader and its consumer, so that a reader
+// hands over a batch of messages without waiting for each of them to be handled.
+const DeliveryBufferSize = 500
+
+// MessageReader defines the interface for reading messages from a message queue.
+type MessageReader interface {
+	// ReadMessages reads messages from the message queue.
+	// A message is committed only once it and the messages before it have been acknowledged.
+	ReadMessages(ctx context.Context, msgChan chan<- Delivery) error
+	// Check returns an error if the message queue cannot be reached.
+	Check(ctx context.Context) error
+	// Close closes the message queue reader.
+	Close()
+}
@@ -0,0 +1,17 @@
+package msgbus
+
+import "context"
+
+// MessageWriter defines the interface for writing messages to a message queue.
+type MessageWriter interface {
+	// WriteMessage writes a message to the message queue.
+	WriteMessage(ctx context.Context, key []byte, value []byte) error
+	// WriteMessages writes a batch of messages to the message queue, each to its own topic if it sets one.
+	WriteMessages(ctx context.Context, msgs []Message) error
+	// Flush waits until the messages written have been delivered and returns the errors of asynchronous writes.
+	Flush(ctx context.Context) error
+	// Check returns an error if the message queue cannot be reached.
+	Check(ctx context.Context) error
+	// Close closes the message queue writer.
+	Close()
+}
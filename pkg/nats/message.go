@@ -0,0 +1,173 @@
+package nats
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/ansoncht/flight-microservices/pkg/msgbus"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+const (
+	// KeyHeader specifies the header carrying the message key, as NATS messages have no key of their own.
+	KeyHeader = "Flight-Msg-Key"
+	// setupTimeout specifies how long creating a stream or consumer may take.
+	setupTimeout = 10 * time.Second
+)
+
+// acknowledger implements the msgbus.Acknowledger interface for a single JetStream message.
+type acknowledger struct {
+	// msg specifies the JetStream message to settle.
+	msg jetstream.Msg
+	// redeliveryDelay specifies how long a rejected message waits before it is redelivered.
+	redeliveryDelay time.Duration
+	// held specifies the messages kept in progress until they are settled.
+	held *heldMessages
+}
+
+// heldMessages holds the delivered messages which have not been settled, so that they are kept in progress instead
+// of being redelivered once the ack wait passes.
+type heldMessages struct {
+	// mu protects msgs, as messages are settled concurrently.
+	mu sync.Mutex
+	// msgs specifies the messages which have not been settled.
+	msgs map[jetstream.Msg]struct{}
+}
+
+// newHeldMessages creates a new heldMessages instance holding no message.
+func newHeldMessages() *heldMessages {
+	return &heldMessages{
+		msgs: make(map[jetstream.Msg]struct{}),
+	}
+}
+
+// hold keeps a message in progress until it is released.
+func (h *heldMessages) hold(msg jetstream.Msg) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.msgs[msg] = struct{}{}
+}
+
+// release stops keeping a message in progress.
+func (h *heldMessages) release(msg jetstream.Msg) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	delete(h.msgs, msg)
+}
+
+// keepInProgress resets the ack wait of the held messages at every interval until the context is done.
+func (h *heldMessages) keepInProgress(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		h.mu.Lock()
+		msgs := make([]jetstream.Msg, 0, len(h.msgs))
+		for msg := range h.msgs {
+			msgs = append(msgs, msg)
+		}
+		h.mu.Unlock()
+
+		for _, msg := range msgs {
+			if err := msg.InProgress(); err != nil {
+				slog.Warn("Failed to keep NATS message in progress", "subject", msg.Subject(), "error", err)
+			}
+		}
+	}
+}
+
+// Ack acknowledges the message, so that it is not redelivered.
+func (a acknowledger) Ack(msg msgbus.Message) {
+	a.held.release(a.msg)
+
+	if err := a.msg.Ack(); err != nil {
+		slog.Warn("Failed to acknowledge NATS message", "subject", msg.Topic, "sequence", msg.Offset, "error", err)
+	}
+}
+
+// Nack rejects the message, so that it is redelivered after the redelivery delay.
+func (a acknowledger) Nack(msg msgbus.Message, err error) {
+	slog.Warn("Rejected message, redelivering it later", "subject", msg.Topic, "sequence", msg.Offset, "error", err)
+
+	a.held.release(a.msg)
+
+	if err := a.msg.NakWithDelay(a.redeliveryDelay); err != nil {
+		slog.Warn("Failed to reject NATS message", "subject", msg.Topic, "sequence", msg.Offset, "error", err)
+	}
+}
+
+// newDelivery creates the delivery of a JetStream message, held in progress until it is settled.
+func newDelivery(jsMsg jetstream.Msg, redeliveryDelay time.Duration, held *heldMessages) msgbus.Delivery {
+	msg := msgbus.Message{
+		Topic: jsMsg.Subject(),
+		Value: jsMsg.Data(),
+	}
+
+	for key, values := range jsMsg.Headers() {
+		for _, value := range values {
+			if key == KeyHeader {
+				msg.Key = []byte(value)
+				continue
+			}
+
+			msg.Headers = append(msg.Headers, msgbus.Header{Key: key, Value: []byte(value)})
+		}
+	}
+
+	if metadata, err := jsMsg.Metadata(); err == nil {
+		msg.Offset = int64(metadata.Sequence.Stream) //nolint:gosec // stream sequences stay far below MaxInt64
+		msg.Timestamp = metadata.Timestamp
+	}
+
+	held.hold(jsMsg)
+
+	return msgbus.NewDelivery(msg, acknowledger{msg: jsMsg, redeliveryDelay: redeliveryDelay, held: held})
+}
+
+// newMsg creates the NATS message of a message, publishing to its topic or the default subject.
+func newMsg(msg msgbus.Message, subject string) *nats.Msg {
+	if msg.Topic != "" {
+		subject = msg.Topic
+	}
+
+	natsMsg := nats.NewMsg(subject)
+	natsMsg.Data = msg.Value
+	natsMsg.Header.Set(KeyHeader, string(msg.Key))
+
+	for _, header := range msg.Headers {
+		natsMsg.Header.Add(header.Key, string(header.Value))
+	}
+
+	return natsMsg
+}
+
+// ensureStream creates the stream capturing the subject unless it already exists.
+func ensureStream(ctx context.Context, js jetstream.JetStream, stream string, subject string) error {
+	_, err := js.Stream(ctx, stream)
+	if err == nil {
+		return nil
+	}
+
+	if !errors.Is(err, jetstream.ErrStreamNotFound) {
+		return fmt.Errorf("failed to look up NATS stream: %w", err)
+	}
+
+	if _, err := js.CreateStream(ctx, jetstream.StreamConfig{Name: stream, Subjects: []string{subject}}); err != nil {
+		return fmt.Errorf("failed to create NATS stream: %w", err)
+	}
+
+	return nil
+}
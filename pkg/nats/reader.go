@@ -0,0 +1,217 @@
+package nats
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/ansoncht/flight-microservices/pkg/msgbus"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+const (
+	// defaultMaxPollRecords specifies the maximum number of messages buffered per pull when unset.
+	defaultMaxPollRecords = 500
+	// defaultAckWait specifies the seconds a delivered message may stay unsettled when unset.
+	defaultAckWait = 30
+)
+
+// ReaderConfig holds configuration settings for the NATS JetStream reader.
+type ReaderConfig struct {
+	// URL specifies the NATS server URL.
+	URL string `mapstructure:"url"`
+	// Stream specifies the JetStream stream to read from, created if it does not exist.
+	Stream string `mapstructure:"stream"`
+	// Subject specifies the subject to read.
+	Subject string `mapstructure:"subject"`
+	// Consumer specifies the durable consumer, shared by the instances of a service like a consumer group.
+	Consumer string `mapstructure:"consumer"`
+	// MaxPollRecords specifies the maximum number of messages buffered per pull. Defaults to 500.
+	MaxPollRecords int `mapstructure:"max_poll_records"`
+	// AckWait specifies the seconds a message of a reader which stopped may stay unsettled before it is
+	// redelivered, and the delay before a rejected message is redelivered. Messages held by a running reader are
+	// kept in progress, so they may stay unsettled for longer. Defaults to 30.
+	AckWait int `mapstructure:"ack_wait"`
+}
+
+// Reader holds the NATS JetStream reader instance.
+// It implements the msgbus.MessageReader interface to read messages from a JetStream consumer.
+type Reader struct {
+	// Conn specifies the NATS connection.
+	Conn *nats.Conn
+	// js specifies the JetStream context of the connection.
+	js jetstream.JetStream
+	// cfg specifies the configuration of the stream and consumer to read.
+	cfg ReaderConfig
+}
+
+// NewJetStreamReader creates a new Reader instance based on the provided configuration.
+func NewJetStreamReader(cfg ReaderConfig) (*Reader, error) {
+	slog.Info("Initializing NATS JetStream reader for the service", "url", cfg.URL, "subject", cfg.Subject)
+
+	if cfg.URL == "" {
+		return nil, fmt.Errorf("nats server URL is empty")
+	}
+
+	if cfg.Stream == "" {
+		return nil, fmt.Errorf("nats stream is empty")
+	}
+
+	if cfg.Subject == "" {
+		return nil, fmt.Errorf("nats subject is empty")
+	}
+
+	if cfg.Consumer == "" {
+		return nil, fmt.Errorf("nats consumer is empty")
+	}
+
+	if cfg.MaxPollRecords < 0 {
+		return nil, fmt.Errorf("nats max poll records is invalid: %d", cfg.MaxPollRecords)
+	}
+
+	if cfg.AckWait < 0 {
+		return nil, fmt.Errorf("nats ack wait is invalid: %d", cfg.AckWait)
+	}
+
+	if cfg.MaxPollRecords == 0 {
+		cfg.MaxPollRecords = defaultMaxPollRecords
+	}
+
+	if cfg.AckWait == 0 {
+		cfg.AckWait = defaultAckWait
+	}
+
+	conn, js, err := connect(cfg.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Reader{
+		Conn: conn,
+		js:   js,
+		cfg:  cfg,
+	}, nil
+}
+
+// Check returns an error if the NATS connection is not connected.
+func (r *Reader) Check(_ context.Context) error {
+	if !r.Conn.IsConnected() {
+		return fmt.Errorf("NATS connection is %s", r.Conn.Status())
+	}
+
+	return nil
+}
+
+// Close closes the NATS connection.
+func (r *Reader) Close() {
+	r.Conn.Close()
+}
+
+// ReadMessages reads messages from the JetStream consumer and sends them to the provided channel.
+// A message is redelivered if it is rejected, or if it is not settled within the ack wait once reading stops.
+// Until then, unsettled messages are kept in progress at every half of the ack wait, as a service may hold the
+// messages of a whole stream until the stream ends.
+func (r *Reader) ReadMessages(ctx context.Context, msgChan chan<- msgbus.Delivery) error {
+	slog.Info("Reading message from NATS JetStream subject")
+
+	defer close(msgChan)
+
+	if r == nil {
+		return fmt.Errorf("nats reader is nil")
+	}
+
+	consumer, err := r.consumer(ctx)
+	if err != nil {
+		return err
+	}
+
+	iter, err := consumer.Messages(jetstream.PullMaxMessages(r.cfg.MaxPollRecords))
+	if err != nil {
+		return fmt.Errorf("failed to consume NATS messages: %w", err)
+	}
+	defer iter.Stop()
+
+	// Stop the iterator once the context is done, unblocking Next
+	stop := context.AfterFunc(ctx, iter.Stop)
+	defer stop()
+
+	redeliveryDelay := time.Duration(r.cfg.AckWait) * time.Second
+
+	held := newHeldMessages()
+	keepCtx, stopKeeping := context.WithCancel(ctx)
+	defer stopKeeping()
+
+	go held.keepInProgress(keepCtx, redeliveryDelay/2) //nolint:mnd // well before the ack wait passes
+
+readingLoop:
+	for {
+		msg, err := iter.Next()
+		if errors.Is(err, jetstream.ErrMsgIteratorClosed) {
+			break readingLoop
+		}
+
+		if errors.Is(err, jetstream.ErrNoHeartbeat) {
+			slog.Warn("Missed NATS heartbeat, resuming", "error", err)
+			continue
+		}
+
+		if err != nil {
+			return fmt.Errorf("failed to fetch message from NATS: %w", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			break readingLoop
+		case msgChan <- newDelivery(msg, redeliveryDelay, held):
+		}
+	}
+
+	if ctx.Err() != nil {
+		return fmt.Errorf("context canceled while fetching nats messages: %w", ctx.Err())
+	}
+
+	return nil
+}
+
+// consumer creates the stream and the durable consumer unless they exist, and returns the consumer.
+func (r *Reader) consumer(ctx context.Context) (jetstream.Consumer, error) {
+	setupCtx, cancel := context.WithTimeout(ctx, setupTimeout)
+	defer cancel()
+
+	if err := ensureStream(setupCtx, r.js, r.cfg.Stream, r.cfg.Subject); err != nil {
+		return nil, err
+	}
+
+	consumer, err := r.js.CreateOrUpdateConsumer(setupCtx, r.cfg.Stream, jetstream.ConsumerConfig{
+		Durable:       r.cfg.Consumer,
+		FilterSubject: r.cfg.Subject,
+		AckPolicy:     jetstream.AckExplicitPolicy,
+		AckWait:       time.Duration(r.cfg.AckWait) * time.Second,
+		// Services hold messages until a whole stream of them is handled, so pending messages are not capped
+		MaxAckPending: -1,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create NATS consumer: %w", err)
+	}
+
+	return consumer, nil
+}
+
+// connect connects to the NATS server, retrying in the background if it is not reachable yet.
+func connect(url string) (*nats.Conn, jetstream.JetStream, error) {
+	conn, err := nats.Connect(url, nats.RetryOnFailedConnect(true), nats.MaxReconnects(-1))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to connect to NATS: %w", err)
+	}
+
+	js, err := jetstream.New(conn)
+	if err != nil {
+		conn.Close()
+		return nil, nil, fmt.Errorf("failed to create NATS JetStream context: %w", err)
+	}
+
+	return conn, js, nil
+}
@@ -0,0 +1,281 @@
+package nats_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ansoncht/flight-microservices/pkg/msgbus"
+	msgQueue "github.com/ansoncht/flight-microservices/pkg/nats"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go/modules/nats"
+)
+
+const (
+	testNATSImage      = "nats:2.10"
+	testStream         = "TEST"
+	testSubject        = "test.flights"
+	testConsumer       = "test-consumer"
+	testMessageKey     = "test-key"
+	testMessageValue   = "hello, world"
+	timeout            = 10 * time.Second
+	testRedeliverDelay = 1
+)
+
+// setupNATSTest spins up a NATS container with JetStream for testing and returns its URL and a cleanup function.
+func setupNATSTest(ctx context.Context, t *testing.T) (url string, cleanup func()) {
+	t.Helper()
+
+	natsContainer, err := nats.Run(ctx, testNATSImage)
+	require.NoError(t, err)
+	require.NotNil(t, natsContainer)
+
+	cleanup = func() {
+		err := natsContainer.Terminate(ctx)
+		require.NoError(t, err)
+	}
+
+	url, err = natsContainer.ConnectionString(ctx)
+	require.NoError(t, err)
+	require.NotEmpty(t, url)
+
+	return url, cleanup
+}
+
+func TestNewJetStreamReader_ValidConfig_ShouldSucceed(t *testing.T) {
+	cfg := msgQueue.ReaderConfig{
+		URL:      "nats://localhost:4222",
+		Stream:   testStream,
+		Subject:  testSubject,
+		Consumer: testConsumer,
+	}
+	reader, err := msgQueue.NewJetStreamReader(cfg)
+	require.NoError(t, err)
+	require.NotNil(t, reader)
+	reader.Close()
+}
+
+func TestNewJetStreamReader_InvalidConfig_ShouldError(t *testing.T) {
+	cfg := msgQueue.ReaderConfig{
+		URL:      "nats://localhost:4222",
+		Stream:   testStream,
+		Subject:  testSubject,
+		Consumer: testConsumer,
+	}
+
+	tests := []struct {
+		name    string
+		cfg     msgQueue.ReaderConfig
+		wantErr string
+	}{
+		{
+			name: "Missing URL",
+			cfg: func() msgQueue.ReaderConfig {
+				c := cfg
+				c.URL = ""
+				return c
+			}(),
+			wantErr: "nats server URL is empty",
+		},
+		{
+			name: "Missing Stream",
+			cfg: func() msgQueue.ReaderConfig {
+				c := cfg
+				c.Stream = ""
+				return c
+			}(),
+			wantErr: "nats stream is empty",
+		},
+		{
+			name: "Missing Subject",
+			cfg: func() msgQueue.ReaderConfig {
+				c := cfg
+				c.Subject = ""
+				return c
+			}(),
+			wantErr: "nats subject is empty",
+		},
+		{
+			name: "Missing Consumer",
+			cfg: func() msgQueue.ReaderConfig {
+				c := cfg
+				c.Consumer = ""
+				return c
+			}(),
+			wantErr: "nats consumer is empty",
+		},
+		{
+			name: "Negative Max Poll Records",
+			cfg: func() msgQueue.ReaderConfig {
+				c := cfg
+				c.MaxPollRecords = -1
+				return c
+			}(),
+			wantErr: "nats max poll records is invalid",
+		},
+		{
+			name: "Negative Ack Wait",
+			cfg: func() msgQueue.ReaderConfig {
+				c := cfg
+				c.AckWait = -1
+				return c
+			}(),
+			wantErr: "nats ack wait is invalid",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader, err := msgQueue.NewJetStreamReader(tt.cfg)
+			require.ErrorContains(t, err, tt.wantErr)
+			require.Nil(t, reader)
+		})
+	}
+}
+
+func TestReadMessages_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	url, cleanup := setupNATSTest(ctx, t)
+	defer cleanup()
+
+	writer, err := msgQueue.NewJetStreamWriter(msgQueue.WriterConfig{URL: url, Stream: testStream, Subject: testSubject})
+	require.NoError(t, err)
+	defer writer.Close()
+
+	writeCtx, writeCancel := context.WithTimeout(ctx, timeout)
+	defer writeCancel()
+
+	err = writer.WriteMessages(writeCtx, []msgbus.Message{
+		{
+			Key:     []byte(testMessageKey),
+			Value:   []byte(testMessageValue),
+			Headers: []msgbus.Header{{Key: "correlation-id", Value: []byte("abc")}},
+		},
+	})
+	require.NoError(t, err)
+
+	reader, err := msgQueue.NewJetStreamReader(msgQueue.ReaderConfig{
+		URL:      url,
+		Stream:   testStream,
+		Subject:  testSubject,
+		Consumer: testConsumer,
+		AckWait:  testRedeliverDelay,
+	})
+	require.NoError(t, err)
+	defer reader.Close()
+
+	// readOne reads a single message and stops the reader.
+	readOne := func(t *testing.T) msgbus.Delivery {
+		t.Helper()
+
+		msgChan := make(chan msgbus.Delivery, 1)
+		readErrChan := make(chan error, 1)
+		readCtx, readCancel := context.WithCancel(ctx)
+		defer readCancel()
+
+		go func() {
+			readErrChan <- reader.ReadMessages(readCtx, msgChan)
+		}()
+
+		var delivery msgbus.Delivery
+		select {
+		case delivery = <-msgChan:
+		case err := <-readErrChan:
+			require.NoError(t, err)
+		case <-time.After(timeout):
+			t.Fatal("timed out waiting for message from NATS")
+		}
+
+		readCancel()
+		select {
+		case err := <-readErrChan:
+			if err != nil && !errors.Is(err, context.Canceled) {
+				require.NoError(t, err)
+			}
+		case <-time.After(timeout):
+			t.Error("ReadMessages goroutine did not exit cleanly after cancellation")
+		}
+
+		return delivery
+	}
+
+	t.Run("Rejected Message Is Redelivered", func(t *testing.T) {
+		delivery := readOne(t)
+		require.Equal(t, testMessageKey, string(delivery.Key))
+		delivery.Nack(errors.New("test error"))
+
+		delivery = readOne(t)
+		require.Equal(t, testMessageKey, string(delivery.Key))
+		require.Equal(t, testMessageValue, string(delivery.Value))
+		require.Equal(t, testSubject, delivery.Topic)
+		require.Len(t, delivery.Headers, 1)
+		require.Equal(t, "correlation-id", delivery.Headers[0].Key)
+		delivery.Ack()
+	})
+
+	t.Run("Nil Reader", func(t *testing.T) {
+		var dummyReader *msgQueue.Reader
+		err := dummyReader.ReadMessages(ctx, make(chan msgbus.Delivery, 1))
+		require.ErrorContains(t, err, "nats reader is nil")
+	})
+}
+
+func TestReadMessages_HeldLongerThanAckWait_ShouldNotRedeliver_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	url, cleanup := setupNATSTest(ctx, t)
+	defer cleanup()
+
+	writer, err := msgQueue.NewJetStreamWriter(msgQueue.WriterConfig{URL: url, Stream: testStream, Subject: testSubject})
+	require.NoError(t, err)
+	defer writer.Close()
+
+	writeCtx, writeCancel := context.WithTimeout(ctx, timeout)
+	defer writeCancel()
+
+	err = writer.WriteMessage(writeCtx, []byte("start_of_stream"), []byte("HKG"))
+	require.NoError(t, err)
+
+	reader, err := msgQueue.NewJetStreamReader(msgQueue.ReaderConfig{
+		URL:      url,
+		Stream:   testStream,
+		Subject:  testSubject,
+		Consumer: testConsumer,
+		AckWait:  testRedeliverDelay,
+	})
+	require.NoError(t, err)
+	defer reader.Close()
+
+	msgChan := make(chan msgbus.Delivery, 1)
+	readCtx, readCancel := context.WithCancel(ctx)
+	defer readCancel()
+
+	go func() {
+		_ = reader.ReadMessages(readCtx, msgChan)
+	}()
+
+	var held msgbus.Delivery
+	select {
+	case held = <-msgChan:
+		require.Equal(t, "start_of_stream", string(held.Key))
+	case <-time.After(timeout):
+		t.Fatal("timed out waiting for message from NATS")
+	}
+
+	// Hold the message for several ack waits, as the processor holds the start of a stream until it ends
+	select {
+	case redelivered := <-msgChan:
+		t.Fatalf("held message was redelivered: %s", redelivered.Key)
+	case <-time.After(3 * testRedeliverDelay * time.Second):
+	}
+
+	held.Ack()
+}
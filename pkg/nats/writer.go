@@ -0,0 +1,212 @@
+package nats
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+
+	"github.com/ansoncht/flight-microservices/pkg/msgbus"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// WriterConfig holds configuration settings for the NATS JetStream writer.
+type WriterConfig struct {
+	// URL specifies the NATS server URL.
+	URL string `mapstructure:"url"`
+	// Stream specifies the JetStream stream capturing the subject, created if it does not exist.
+	Stream string `mapstructure:"stream"`
+	// Subject specifies the subject to write to.
+	Subject string `mapstructure:"subject"`
+	// Async specifies whether writes return once messages are sent, leaving their errors to Flush.
+	Async bool `mapstructure:"async"`
+}
+
+// Writer holds the NATS JetStream writer instance.
+// It implements the msgbus.MessageWriter interface to publish messages to a JetStream subject.
+// Message timestamps are set by the server, and messages with a topic are published to that subject, which
+// must be captured by a stream.
+type Writer struct {
+	// Conn specifies the NATS connection.
+	Conn *nats.Conn
+	// js specifies the JetStream context of the connection.
+	js jetstream.JetStream
+	// cfg specifies the configuration of the stream and subject to write to.
+	cfg WriterConfig
+	// mu protects streamReady and pending.
+	mu sync.Mutex
+	// streamReady specifies whether the stream is known to exist.
+	streamReady bool
+	// pending holds the acknowledgements of asynchronous writes since the last flush.
+	pending []jetstream.PubAckFuture
+}
+
+// NewJetStreamWriter creates a new Writer instance based on the provided configuration.
+func NewJetStreamWriter(cfg WriterConfig) (*Writer, error) {
+	slog.Info("Initializing NATS JetStream writer for the service", "url", cfg.URL, "subject", cfg.Subject)
+
+	if cfg.URL == "" {
+		return nil, fmt.Errorf("nats server URL is empty")
+	}
+
+	if cfg.Stream == "" {
+		return nil, fmt.Errorf("nats stream is empty")
+	}
+
+	if cfg.Subject == "" {
+		return nil, fmt.Errorf("nats subject is empty")
+	}
+
+	conn, js, err := connect(cfg.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Writer{
+		Conn: conn,
+		js:   js,
+		cfg:  cfg,
+	}, nil
+}
+
+// Check returns an error if the NATS connection is not connected.
+func (w *Writer) Check(_ context.Context) error {
+	if !w.Conn.IsConnected() {
+		return fmt.Errorf("NATS connection is %s", w.Conn.Status())
+	}
+
+	return nil
+}
+
+// Close delivers the messages not yet sent and closes the NATS connection.
+func (w *Writer) Close() {
+	if err := w.Conn.Drain(); err != nil {
+		slog.Warn("Failed to drain NATS connection", "error", err)
+		w.Conn.Close()
+	}
+}
+
+// WriteMessage writes a message to the NATS subject.
+func (w *Writer) WriteMessage(ctx context.Context, key []byte, value []byte) error {
+	slog.Info("Writing message to NATS subject", "key", string(key), "message", string(value))
+
+	if w == nil {
+		return fmt.Errorf("nats writer is nil")
+	}
+
+	msg := msgbus.Message{Key: key, Value: value}
+	if err := msg.Validate(); err != nil {
+		return fmt.Errorf("invalid message: %w", err)
+	}
+
+	return w.publish(ctx, []msgbus.Message{msg})
+}
+
+// WriteMessages writes a batch of messages to their subjects, defaulting to the NATS subject of the writer.
+// The messages are sent without waiting for each acknowledgement in turn.
+func (w *Writer) WriteMessages(ctx context.Context, msgs []msgbus.Message) error {
+	slog.Info("Writing messages to NATS subject", "count", len(msgs))
+
+	if w == nil {
+		return fmt.Errorf("nats writer is nil")
+	}
+
+	for i, msg := range msgs {
+		if err := msg.Validate(); err != nil {
+			return fmt.Errorf("invalid message %d: %w", i, err)
+		}
+	}
+
+	return w.publish(ctx, msgs)
+}
+
+// Flush waits until the messages written have been acknowledged and returns the errors of asynchronous writes
+// since the last flush.
+func (w *Writer) Flush(ctx context.Context) error {
+	if w == nil {
+		return fmt.Errorf("nats writer is nil")
+	}
+
+	w.mu.Lock()
+	pending := w.pending
+	w.pending = nil
+	w.mu.Unlock()
+
+	if err := awaitAcks(ctx, pending); err != nil {
+		return fmt.Errorf("failed to produce %d messages: %w", len(pending), err)
+	}
+
+	return nil
+}
+
+// publish sends the messages to the stream. In async mode it returns once the messages are sent and keeps
+// their acknowledgements for Flush, otherwise it waits until every message has been acknowledged.
+func (w *Writer) publish(ctx context.Context, msgs []msgbus.Message) error {
+	if len(msgs) == 0 {
+		return nil
+	}
+
+	if err := w.ensureStream(ctx); err != nil {
+		return err
+	}
+
+	futures := make([]jetstream.PubAckFuture, 0, len(msgs))
+	for _, msg := range msgs {
+		future, err := w.js.PublishMsgAsync(newMsg(msg, w.cfg.Subject))
+		if err != nil {
+			return fmt.Errorf("failed to publish NATS message: %w", err)
+		}
+
+		futures = append(futures, future)
+	}
+
+	if w.cfg.Async {
+		w.mu.Lock()
+		w.pending = append(w.pending, futures...)
+		w.mu.Unlock()
+
+		return nil
+	}
+
+	return awaitAcks(ctx, futures)
+}
+
+// ensureStream creates the stream of the writer once, unless it already exists.
+func (w *Writer) ensureStream(ctx context.Context) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.streamReady {
+		return nil
+	}
+
+	setupCtx, cancel := context.WithTimeout(ctx, setupTimeout)
+	defer cancel()
+
+	if err := ensureStream(setupCtx, w.js, w.cfg.Stream, w.cfg.Subject); err != nil {
+		return err
+	}
+
+	w.streamReady = true
+
+	return nil
+}
+
+// awaitAcks waits for the acknowledgements of published messages and returns their errors.
+func awaitAcks(ctx context.Context, futures []jetstream.PubAckFuture) error {
+	errs := make([]error, 0)
+
+	for _, future := range futures {
+		select {
+		case <-future.Ok():
+		case err := <-future.Err():
+			errs = append(errs, err)
+		case <-ctx.Done():
+			return fmt.Errorf("context canceled while producing message: %w", ctx.Err())
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,118 @@
+package nats_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ansoncht/flight-microservices/pkg/msgbus"
+	msgQueue "github.com/ansoncht/flight-microservices/pkg/nats"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewJetStreamWriter_InvalidConfig_ShouldError(t *testing.T) {
+	cfg := msgQueue.WriterConfig{
+		URL:     "nats://localhost:4222",
+		Stream:  testStream,
+		Subject: testSubject,
+	}
+
+	tests := []struct {
+		name    string
+		cfg     msgQueue.WriterConfig
+		wantErr string
+	}{
+		{
+			name: "Missing URL",
+			cfg: func() msgQueue.WriterConfig {
+				c := cfg
+				c.URL = ""
+				return c
+			}(),
+			wantErr: "nats server URL is empty",
+		},
+		{
+			name: "Missing Stream",
+			cfg: func() msgQueue.WriterConfig {
+				c := cfg
+				c.Stream = ""
+				return c
+			}(),
+			wantErr: "nats stream is empty",
+		},
+		{
+			name: "Missing Subject",
+			cfg: func() msgQueue.WriterConfig {
+				c := cfg
+				c.Subject = ""
+				return c
+			}(),
+			wantErr: "nats subject is empty",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writer, err := msgQueue.NewJetStreamWriter(tt.cfg)
+			require.ErrorContains(t, err, tt.wantErr)
+			require.Nil(t, writer)
+		})
+	}
+}
+
+func TestWriteMessages_InvalidMessage_ShouldError(t *testing.T) {
+	writer, err := msgQueue.NewJetStreamWriter(msgQueue.WriterConfig{
+		URL:     "nats://localhost:4222",
+		Stream:  testStream,
+		Subject: testSubject,
+	})
+	require.NoError(t, err)
+	defer writer.Close()
+
+	err = writer.WriteMessage(context.Background(), nil, []byte(testMessageValue))
+	require.ErrorContains(t, err, "message key is nil or empty")
+
+	err = writer.WriteMessages(context.Background(), []msgbus.Message{{Key: []byte(testMessageKey)}})
+	require.ErrorContains(t, err, "invalid message 0: message value is nil or empty")
+}
+
+func TestWriteMessages_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	url, cleanup := setupNATSTest(ctx, t)
+	defer cleanup()
+
+	writer, err := msgQueue.NewJetStreamWriter(msgQueue.WriterConfig{
+		URL:     url,
+		Stream:  testStream,
+		Subject: testSubject,
+		Async:   true,
+	})
+	require.NoError(t, err)
+	defer writer.Close()
+
+	writeCtx, writeCancel := context.WithTimeout(ctx, timeout)
+	defer writeCancel()
+
+	t.Run("Successful Async WriteMessages", func(t *testing.T) {
+		msgs := []msgbus.Message{
+			{Key: []byte(testMessageKey), Value: []byte(testMessageValue)},
+			{Key: []byte(testMessageKey), Value: []byte(testMessageValue)},
+		}
+		require.NoError(t, writer.WriteMessages(writeCtx, msgs))
+		require.NoError(t, writer.WriteMessage(writeCtx, []byte(testMessageKey), []byte(testMessageValue)))
+		require.NoError(t, writer.Flush(writeCtx))
+	})
+
+	t.Run("Subject Outside Stream", func(t *testing.T) {
+		err := writer.WriteMessages(writeCtx, []msgbus.Message{
+			{Topic: "other.subject", Key: []byte(testMessageKey), Value: []byte(testMessageValue)},
+		})
+		if err == nil {
+			err = writer.Flush(writeCtx)
+		}
+		require.Error(t, err)
+	})
+}
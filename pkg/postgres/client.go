@@ -0,0 +1,83 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ClientConfig holds configuration settings for the PostgreSQL client.
+type ClientConfig struct {
+	// URI specifies the connection URI for the PostgreSQL server.
+	URI string `mapstructure:"uri"`
+	// PoolSize specifies the maximum number of connections in the connection pool.
+	PoolSize int32 `mapstructure:"pool_size"`
+	// ConnectionTimeout specifies the maximum time to wait for a connection.
+	ConnectionTimeout int `mapstructure:"connection_timeout"`
+}
+
+// Client holds the PostgreSQL connection pool.
+type Client struct {
+	// Pool specifies the PostgreSQL connection pool used to communicate with the database.
+	Pool *pgxpool.Pool
+}
+
+// NewPostgresClient creates a new Client instance based on the provided configuration.
+func NewPostgresClient(ctx context.Context, cfg ClientConfig) (*Client, error) {
+	slog.Info("Initializing PostgreSQL client for the service")
+
+	if cfg.URI == "" {
+		return nil, fmt.Errorf("PostgreSQL connection URI is empty")
+	}
+
+	if cfg.PoolSize <= 0 {
+		return nil, fmt.Errorf("PostgreSQL pool size is invalid: %d", cfg.PoolSize)
+	}
+
+	if cfg.ConnectionTimeout <= 0 {
+		return nil, fmt.Errorf("PostgreSQL connection timeout is invalid: %d", cfg.ConnectionTimeout)
+	}
+
+	poolCfg, err := pgxpool.ParseConfig(cfg.URI)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse PostgreSQL connection URI: %w", err)
+	}
+
+	poolCfg.MaxConns = cfg.PoolSize
+	poolCfg.ConnConfig.ConnectTimeout = time.Duration(cfg.ConnectionTimeout) * time.Second
+	poolCfg.ConnConfig.Tracer = &queryMetrics{}
+
+	pool, err := pgxpool.NewWithConfig(ctx, poolCfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to PostgreSQL: %w", err)
+	}
+
+	// Ping the server to verify the connection.
+	ctxTimeout, cancel := context.WithTimeout(ctx, time.Duration(cfg.ConnectionTimeout)*time.Second)
+	defer cancel()
+	if err := pool.Ping(ctxTimeout); err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("failed to ping PostgreSQL: %w", err)
+	}
+
+	return &Client{
+		Pool: pool,
+	}, nil
+}
+
+// Check pings the PostgreSQL server.
+func (c *Client) Check(ctx context.Context) error {
+	if err := c.Pool.Ping(ctx); err != nil {
+		return fmt.Errorf("failed to ping PostgreSQL: %w", err)
+	}
+
+	return nil
+}
+
+// Close closes all connections in the pool.
+func (c *Client) Close() {
+	c.Pool.Close()
+}
@@ -0,0 +1,69 @@
+package postgres
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/ansoncht/flight-microservices/pkg/metrics"
+	"github.com/jackc/pgx/v5"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Outcomes of PostgreSQL operations labelled by the operation latency metric.
+const (
+	operationSucceeded = "success"
+	operationFailed    = "error"
+)
+
+// operationDuration observes the latency of PostgreSQL operations.
+var operationDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Namespace: metrics.Namespace,
+	Subsystem: "postgres",
+	Name:      "operation_duration_seconds",
+	Help:      "Latency of PostgreSQL commands by command name and outcome.",
+	Buckets:   metrics.LatencyBuckets,
+}, []string{"command", "status"})
+
+// queryStartKey specifies the context key of the start of a query.
+type queryStartKey struct{}
+
+// queryStart holds the command name of a query and when it started.
+type queryStart struct {
+	command string
+	at      time.Time
+}
+
+// queryMetrics implements the pgx.QueryTracer interface to observe the latency of each PostgreSQL command.
+type queryMetrics struct{}
+
+// TraceQueryStart records the command name of a query and when it started.
+func (m *queryMetrics) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	return context.WithValue(ctx, queryStartKey{}, queryStart{command: commandName(data.SQL), at: time.Now()})
+}
+
+// TraceQueryEnd observes the latency of a query by its command name and outcome.
+func (m *queryMetrics) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	start, ok := ctx.Value(queryStartKey{}).(queryStart)
+	if !ok {
+		return
+	}
+
+	status := operationSucceeded
+	if data.Err != nil {
+		status = operationFailed
+	}
+
+	operationDuration.WithLabelValues(start.command, status).Observe(time.Since(start.at).Seconds())
+}
+
+// commandName returns the lowercase command name of a SQL statement, such as select or insert.
+func commandName(sql string) string {
+	fields := strings.Fields(sql)
+	if len(fields) == 0 {
+		return "unknown"
+	}
+
+	return strings.ToLower(fields[0])
+}
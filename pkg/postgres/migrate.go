@@ -0,0 +1,165 @@
+package postgres
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// migrationLockID specifies the advisory lock key held while applying migrations,
+// so that several service instances starting at once do not race each other.
+const migrationLockID = 7_262_731_001
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// migration holds a single versioned SQL migration.
+type migration struct {
+	// version specifies the migration version parsed from the file name prefix.
+	version int
+	// name specifies the migration file name.
+	name string
+	// sql specifies the statements to execute.
+	sql string
+}
+
+// Migrate applies all pending migrations to the database in version order.
+// Each migration runs in its own transaction and is recorded in the schema_migrations table.
+func (c *Client) Migrate(ctx context.Context) error {
+	migrations, err := loadMigrations(migrationFiles)
+	if err != nil {
+		return fmt.Errorf("failed to load migrations: %w", err)
+	}
+
+	conn, err := c.Pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	defer conn.Release()
+
+	if _, err := conn.Exec(ctx, "SELECT pg_advisory_lock($1)", migrationLockID); err != nil {
+		return fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+	defer func() {
+		if _, err := conn.Exec(context.WithoutCancel(ctx), "SELECT pg_advisory_unlock($1)", migrationLockID); err != nil {
+			slog.Warn("Failed to release migration lock", "error", err)
+		}
+	}()
+
+	if _, err := conn.Exec(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    INTEGER PRIMARY KEY,
+		name       TEXT NOT NULL,
+		applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+	)`); err != nil {
+		return fmt.Errorf("failed to create schema_migrations table: %w", err)
+	}
+
+	applied, err := appliedVersions(ctx, conn.Conn())
+	if err != nil {
+		return err
+	}
+
+	for _, m := range migrations {
+		if applied[m.version] {
+			continue
+		}
+
+		slog.Info("Applying PostgreSQL migration", "version", m.version, "name", m.name)
+
+		if err := pgx.BeginFunc(ctx, conn.Conn(), func(tx pgx.Tx) error {
+			if _, err := tx.Exec(ctx, m.sql); err != nil {
+				return fmt.Errorf("failed to execute statements: %w", err)
+			}
+
+			if _, err := tx.Exec(
+				ctx,
+				"INSERT INTO schema_migrations (version, name) VALUES ($1, $2)",
+				m.version,
+				m.name,
+			); err != nil {
+				return fmt.Errorf("failed to record version: %w", err)
+			}
+
+			return nil
+		}); err != nil {
+			return fmt.Errorf("failed to apply migration %s: %w", m.name, err)
+		}
+	}
+
+	return nil
+}
+
+// appliedVersions returns the set of migration versions already applied to the database.
+func appliedVersions(ctx context.Context, conn *pgx.Conn) (map[int]bool, error) {
+	rows, err := conn.Query(ctx, "SELECT version FROM schema_migrations")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query applied migrations: %w", err)
+	}
+
+	versions, err := pgx.CollectRows(rows, pgx.RowTo[int])
+	if err != nil {
+		return nil, fmt.Errorf("failed to read applied migrations: %w", err)
+	}
+
+	applied := make(map[int]bool, len(versions))
+	for _, v := range versions {
+		applied[v] = true
+	}
+
+	return applied, nil
+}
+
+// loadMigrations reads the migration files named <version>_<description>.sql and sorts them by version.
+func loadMigrations(fsys fs.FS) ([]migration, error) {
+	files, err := fs.Glob(fsys, "migrations/*.sql")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list migration files: %w", err)
+	}
+
+	migrations := make([]migration, 0, len(files))
+	seen := make(map[int]string, len(files))
+
+	for _, file := range files {
+		name := path.Base(file)
+
+		prefix, _, found := strings.Cut(name, "_")
+		if !found {
+			return nil, fmt.Errorf("migration file name is invalid: %s", name)
+		}
+
+		version, err := strconv.Atoi(prefix)
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("migration version is invalid: %s", name)
+		}
+
+		if other, ok := seen[version]; ok {
+			return nil, fmt.Errorf("migration version %d is duplicated: %s and %s", version, other, name)
+		}
+		seen[version] = name
+
+		content, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration file %s: %w", name, err)
+		}
+
+		migrations = append(migrations, migration{
+			version: version,
+			name:    name,
+			sql:     string(content),
+		})
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].version < migrations[j].version
+	})
+
+	return migrations, nil
+}
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS daily_summaries (
+    id                 TEXT PRIMARY KEY,
+    date               TIMESTAMPTZ NOT NULL,
+    airport            TEXT NOT NULL,
+    total_flights      INTEGER NOT NULL DEFAULT 0,
+    airline_counts     JSONB NOT NULL DEFAULT '{}',
+    destination_counts JSONB NOT NULL DEFAULT '{}',
+    top_destinations   JSONB NOT NULL DEFAULT '[]',
+    top_airlines       JSONB NOT NULL DEFAULT '[]'
+);
+
+CREATE INDEX IF NOT EXISTS daily_summaries_airport_date_idx ON daily_summaries (airport, date);
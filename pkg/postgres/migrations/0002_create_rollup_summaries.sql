@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS weekly_summaries (
+    id                  TEXT PRIMARY KEY,
+    start_date          TIMESTAMPTZ NOT NULL,
+    end_date            TIMESTAMPTZ NOT NULL,
+    airport             TEXT NOT NULL,
+    days                INTEGER NOT NULL DEFAULT 0,
+    total_flights       INTEGER NOT NULL DEFAULT 0,
+    daily_average       DOUBLE PRECISION NOT NULL DEFAULT 0,
+    busiest_day         TIMESTAMPTZ NOT NULL,
+    busiest_day_flights INTEGER NOT NULL DEFAULT 0,
+    airline_counts      JSONB NOT NULL DEFAULT '{}',
+    destination_counts  JSONB NOT NULL DEFAULT '{}',
+    top_destinations    JSONB NOT NULL DEFAULT '[]',
+    top_airlines        JSONB NOT NULL DEFAULT '[]'
+);
+
+CREATE INDEX IF NOT EXISTS weekly_summaries_airport_start_date_idx ON weekly_summaries (airport, start_date);
+
+CREATE TABLE IF NOT EXISTS monthly_summaries (LIKE weekly_summaries INCLUDING ALL);
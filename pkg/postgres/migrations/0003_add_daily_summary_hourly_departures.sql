@@ -0,0 +1,6 @@
+ALTER TABLE daily_summaries
+    ADD COLUMN IF NOT EXISTS hourly_departures     JSONB NOT NULL DEFAULT '[]',
+    ADD COLUMN IF NOT EXISTS busiest_hour          INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS busiest_hour_flights  INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS quietest_hour         INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS quietest_hour_flights INTEGER NOT NULL DEFAULT 0;
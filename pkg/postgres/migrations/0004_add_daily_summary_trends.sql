@@ -0,0 +1,3 @@
+ALTER TABLE daily_summaries
+    ADD COLUMN IF NOT EXISTS day_over_day   JSONB,
+    ADD COLUMN IF NOT EXISTS week_over_week JSONB;
@@ -0,0 +1,2 @@
+ALTER TABLE daily_summaries
+    ADD COLUMN IF NOT EXISTS stats JSONB NOT NULL DEFAULT '[]';
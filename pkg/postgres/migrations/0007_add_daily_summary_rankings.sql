@@ -0,0 +1,3 @@
+ALTER TABLE daily_summaries
+    ADD COLUMN IF NOT EXISTS airline_ranking     JSONB NOT NULL DEFAULT '[]',
+    ADD COLUMN IF NOT EXISTS destination_ranking JSONB NOT NULL DEFAULT '[]';
@@ -0,0 +1,2 @@
+ALTER TABLE daily_summaries
+    ADD COLUMN IF NOT EXISTS duplicates_removed INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,15 @@
+DELETE FROM weekly_summaries duplicate
+    USING weekly_summaries kept
+    WHERE duplicate.airport = kept.airport
+        AND duplicate.start_date = kept.start_date
+        AND duplicate.id > kept.id;
+
+DELETE FROM monthly_summaries duplicate
+    USING monthly_summaries kept
+    WHERE duplicate.airport = kept.airport
+        AND duplicate.start_date = kept.start_date
+        AND duplicate.id > kept.id;
+
+CREATE UNIQUE INDEX IF NOT EXISTS weekly_summaries_airport_start_date_key ON weekly_summaries (airport, start_date);
+
+CREATE UNIQUE INDEX IF NOT EXISTS monthly_summaries_airport_start_date_key ON monthly_summaries (airport, start_date);
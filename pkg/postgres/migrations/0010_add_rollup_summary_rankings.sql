@@ -0,0 +1,7 @@
+ALTER TABLE weekly_summaries
+    ADD COLUMN IF NOT EXISTS airline_ranking     JSONB NOT NULL DEFAULT '[]',
+    ADD COLUMN IF NOT EXISTS destination_ranking JSONB NOT NULL DEFAULT '[]';
+
+ALTER TABLE monthly_summaries
+    ADD COLUMN IF NOT EXISTS airline_ranking     JSONB NOT NULL DEFAULT '[]',
+    ADD COLUMN IF NOT EXISTS destination_ranking JSONB NOT NULL DEFAULT '[]';
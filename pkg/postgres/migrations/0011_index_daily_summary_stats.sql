@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS daily_summaries_stats_idx ON daily_summaries USING GIN (stats jsonb_path_ops);
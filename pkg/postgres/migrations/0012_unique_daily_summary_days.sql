@@ -0,0 +1,7 @@
+DELETE FROM daily_summaries duplicate
+    USING daily_summaries kept
+    WHERE duplicate.airport = kept.airport
+        AND duplicate.date = kept.date
+        AND duplicate.id > kept.id;
+
+CREATE UNIQUE INDEX IF NOT EXISTS daily_summaries_airport_date_key ON daily_summaries (airport, date);
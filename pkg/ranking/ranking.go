@@ -0,0 +1,127 @@
+package ranking
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/ansoncht/flight-microservices/pkg/model"
+)
+
+// Ranking methods.
+const (
+	// MethodCompetition ranks ties equally and skips the following ranks, e.g. 1, 2, 2, 4.
+	MethodCompetition = "competition"
+	// MethodDense ranks ties equally without skipping ranks, e.g. 1, 2, 2, 3.
+	MethodDense = "dense"
+)
+
+// OthersKey specifies the key of the entry aggregating the counts beyond the limit.
+const OthersKey = "Others"
+
+// Options holds settings for ranking counts.
+type Options struct {
+	// Method specifies the ranking method, MethodCompetition when empty.
+	Method string
+	// Limit specifies the maximum number of ranked entries, unlimited when zero or negative.
+	Limit int
+	// Others specifies whether the counts beyond the limit are aggregated into an OthersKey entry.
+	Others bool
+	// Less breaks ties between keys with equal counts, alphabetical when nil.
+	Less func(a string, b string) bool
+}
+
+// Validate checks whether the ranking method is supported.
+func (o Options) Validate() error {
+	switch o.Method {
+	case "", MethodCompetition, MethodDense:
+		return nil
+	default:
+		return fmt.Errorf("ranking method is invalid: %s", o.Method)
+	}
+}
+
+// Rank ranks the keys of counts by descending count, breaking ties with the options so that the result
+// does not depend on map iteration order. Shares are fractions of the total of all counts, and the
+// OthersKey entry is unranked with rank 0.
+func Rank(counts map[string]int, opts Options) []model.RankedEntry {
+	less := opts.Less
+	if less == nil {
+		less = func(a string, b string) bool { return a < b }
+	}
+
+	keys := make([]string, 0, len(counts))
+	total := 0
+	for key, count := range counts {
+		keys = append(keys, key)
+		total += count
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if counts[keys[i]] != counts[keys[j]] {
+			return counts[keys[i]] > counts[keys[j]]
+		}
+
+		return less(keys[i], keys[j])
+	})
+
+	limit := len(keys)
+	if opts.Limit > 0 && opts.Limit < limit {
+		limit = opts.Limit
+	}
+
+	entries := make([]model.RankedEntry, 0, limit+1)
+	rank := 0
+
+	for i, key := range keys[:limit] {
+		if i == 0 || counts[key] != counts[keys[i-1]] {
+			if opts.Method == MethodDense {
+				rank++
+			} else {
+				rank = i + 1
+			}
+		}
+
+		entries = append(entries, model.RankedEntry{
+			Key:   key,
+			Count: counts[key],
+			Rank:  rank,
+			Share: share(counts[key], total),
+		})
+	}
+
+	if opts.Others && limit < len(keys) {
+		others := 0
+		for _, key := range keys[limit:] {
+			others += counts[key]
+		}
+
+		entries = append(entries, model.RankedEntry{
+			Key:   OthersKey,
+			Count: others,
+			Share: share(others, total),
+		})
+	}
+
+	return entries
+}
+
+// Keys returns the keys of the ranked entries, excluding the OthersKey entry.
+func Keys(entries []model.RankedEntry) []string {
+	keys := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.Rank > 0 {
+			keys = append(keys, entry.Key)
+		}
+	}
+
+	return keys
+}
+
+// share returns count as a fraction of total, or zero when total is zero.
+func share(count int, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+
+	return float64(count) / float64(total)
+}
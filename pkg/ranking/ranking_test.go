@@ -0,0 +1,115 @@
+package ranking_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ansoncht/flight-microservices/pkg/model"
+	"github.com/ansoncht/flight-microservices/pkg/ranking"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRank_Methods_ShouldRankTies(t *testing.T) {
+	counts := map[string]int{"United": 4, "Delta": 2, "American": 2, "JetBlue": 1, "Alaska": 1}
+
+	testCases := []struct {
+		name     string
+		opts     ranking.Options
+		expected []model.RankedEntry
+	}{
+		{
+			name: "Competition",
+			opts: ranking.Options{Method: ranking.MethodCompetition},
+			expected: []model.RankedEntry{
+				{Key: "United", Count: 4, Rank: 1, Share: 0.4},
+				{Key: "American", Count: 2, Rank: 2, Share: 0.2},
+				{Key: "Delta", Count: 2, Rank: 2, Share: 0.2},
+				{Key: "Alaska", Count: 1, Rank: 4, Share: 0.1},
+				{Key: "JetBlue", Count: 1, Rank: 4, Share: 0.1},
+			},
+		},
+		{
+			name: "Default Is Competition",
+			opts: ranking.Options{},
+			expected: []model.RankedEntry{
+				{Key: "United", Count: 4, Rank: 1, Share: 0.4},
+				{Key: "American", Count: 2, Rank: 2, Share: 0.2},
+				{Key: "Delta", Count: 2, Rank: 2, Share: 0.2},
+				{Key: "Alaska", Count: 1, Rank: 4, Share: 0.1},
+				{Key: "JetBlue", Count: 1, Rank: 4, Share: 0.1},
+			},
+		},
+		{
+			name: "Dense",
+			opts: ranking.Options{Method: ranking.MethodDense},
+			expected: []model.RankedEntry{
+				{Key: "United", Count: 4, Rank: 1, Share: 0.4},
+				{Key: "American", Count: 2, Rank: 2, Share: 0.2},
+				{Key: "Delta", Count: 2, Rank: 2, Share: 0.2},
+				{Key: "Alaska", Count: 1, Rank: 3, Share: 0.1},
+				{Key: "JetBlue", Count: 1, Rank: 3, Share: 0.1},
+			},
+		},
+		{
+			name: "Limit With Others",
+			opts: ranking.Options{Limit: 2, Others: true},
+			expected: []model.RankedEntry{
+				{Key: "United", Count: 4, Rank: 1, Share: 0.4},
+				{Key: "American", Count: 2, Rank: 2, Share: 0.2},
+				{Key: ranking.OthersKey, Count: 4, Rank: 0, Share: 0.4},
+			},
+		},
+		{
+			name: "Limit Without Others",
+			opts: ranking.Options{Limit: 3},
+			expected: []model.RankedEntry{
+				{Key: "United", Count: 4, Rank: 1, Share: 0.4},
+				{Key: "American", Count: 2, Rank: 2, Share: 0.2},
+				{Key: "Delta", Count: 2, Rank: 2, Share: 0.2},
+			},
+		},
+		{
+			name: "Custom Tie Break",
+			opts: ranking.Options{
+				Limit: 3,
+				Less:  func(a string, b string) bool { return strings.Compare(a, b) > 0 },
+			},
+			expected: []model.RankedEntry{
+				{Key: "United", Count: 4, Rank: 1, Share: 0.4},
+				{Key: "Delta", Count: 2, Rank: 2, Share: 0.2},
+				{Key: "American", Count: 2, Rank: 2, Share: 0.2},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Ties must not depend on map iteration order
+			for range 20 {
+				require.Equal(t, tc.expected, ranking.Rank(counts, tc.opts))
+			}
+		})
+	}
+}
+
+func TestRank_EmptyCounts_ShouldReturnEmpty(t *testing.T) {
+	entries := ranking.Rank(map[string]int{}, ranking.Options{Limit: 5, Others: true})
+	require.Empty(t, entries)
+	require.Empty(t, ranking.Keys(entries))
+}
+
+func TestRank_ZeroCounts_ShouldHaveZeroShare(t *testing.T) {
+	entries := ranking.Rank(map[string]int{"United": 0}, ranking.Options{})
+	require.Equal(t, []model.RankedEntry{{Key: "United", Count: 0, Rank: 1, Share: 0}}, entries)
+}
+
+func TestKeys_WithOthers_ShouldExcludeOthers(t *testing.T) {
+	entries := ranking.Rank(map[string]int{"A": 3, "B": 2, "C": 1}, ranking.Options{Limit: 2, Others: true})
+	require.Equal(t, []string{"A", "B"}, ranking.Keys(entries))
+}
+
+func TestValidate_InvalidMethod_ShouldError(t *testing.T) {
+	require.NoError(t, ranking.Options{}.Validate())
+	require.NoError(t, ranking.Options{Method: ranking.MethodDense}.Validate())
+	require.ErrorContains(t, ranking.Options{Method: "olympic"}.Validate(), "ranking method is invalid")
+}
@@ -0,0 +1,104 @@
+package redis
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ansoncht/flight-microservices/pkg/msgbus"
+	"github.com/redis/go-redis/v9"
+)
+
+// Fields of a stream entry carrying a message.
+const (
+	KeyField     = "key"
+	ValueField   = "value"
+	HeaderPrefix = "header:"
+)
+
+const (
+	// ackTimeout specifies how long acknowledging an entry may take.
+	ackTimeout = 5 * time.Second
+)
+
+// acknowledger implements the msgbus.Acknowledger interface for a single stream entry.
+type acknowledger struct {
+	// client specifies the Redis client acknowledging the entry.
+	client *redis.Client
+	// stream specifies the stream of the entry.
+	stream string
+	// group specifies the consumer group the entry was delivered to.
+	group string
+	// id specifies the ID of the entry.
+	id string
+}
+
+// Ack acknowledges the entry, removing it from the pending entries of the consumer group.
+func (a acknowledger) Ack(_ msgbus.Message) {
+	ctx, cancel := context.WithTimeout(context.Background(), ackTimeout)
+	defer cancel()
+
+	if err := a.client.XAck(ctx, a.stream, a.group, a.id).Err(); err != nil {
+		slog.Warn("Failed to acknowledge Redis stream entry", "stream", a.stream, "id", a.id, "error", err)
+	}
+}
+
+// Nack rejects the entry, leaving it pending so that it is redelivered once the reader restarts.
+func (a acknowledger) Nack(_ msgbus.Message, err error) {
+	slog.Warn("Rejected message, leaving it pending", "stream", a.stream, "id", a.id, "error", err)
+}
+
+// newDelivery creates the delivery of a stream entry.
+func newDelivery(client *redis.Client, stream string, group string, entry redis.XMessage) msgbus.Delivery {
+	msg := msgbus.Message{
+		Topic: stream,
+	}
+
+	for field, value := range entry.Values {
+		text, _ := value.(string)
+
+		switch {
+		case field == KeyField:
+			msg.Key = []byte(text)
+		case field == ValueField:
+			msg.Value = []byte(text)
+		case strings.HasPrefix(field, HeaderPrefix):
+			msg.Headers = append(msg.Headers, msgbus.Header{
+				Key:   strings.TrimPrefix(field, HeaderPrefix),
+				Value: []byte(text),
+			})
+		}
+	}
+
+	// Entry IDs start with the milliseconds at which they were added
+	if millis, _, ok := strings.Cut(entry.ID, "-"); ok {
+		if ms, err := strconv.ParseInt(millis, 10, 64); err == nil {
+			msg.Timestamp = time.UnixMilli(ms)
+		}
+	}
+
+	return msgbus.NewDelivery(msg, acknowledger{client: client, stream: stream, group: group, id: entry.ID})
+}
+
+// newXAddArgs creates the arguments adding a message to its topic or the default stream.
+func newXAddArgs(msg msgbus.Message, stream string, maxLen int64) *redis.XAddArgs {
+	if msg.Topic != "" {
+		stream = msg.Topic
+	}
+
+	values := make([]string, 0, 4+2*len(msg.Headers)) //nolint:mnd // key and value fields plus header fields
+	values = append(values, KeyField, string(msg.Key), ValueField, string(msg.Value))
+
+	for _, header := range msg.Headers {
+		values = append(values, HeaderPrefix+header.Key, string(header.Value))
+	}
+
+	return &redis.XAddArgs{
+		Stream: stream,
+		MaxLen: maxLen,
+		Approx: maxLen > 0,
+		Values: values,
+	}
+}
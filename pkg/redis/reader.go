@@ -0,0 +1,194 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/ansoncht/flight-microservices/pkg/msgbus"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// defaultMaxPollRecords specifies the maximum number of entries read per poll when unset.
+	defaultMaxPollRecords = 500
+	// blockTimeout specifies how long a poll waits for new entries.
+	blockTimeout = 5 * time.Second
+	// retryDelay specifies how long the reader waits before polling again after an error.
+	retryDelay = time.Second
+	// pendingID specifies the ID reading the entries already delivered to the consumer but not acknowledged.
+	pendingID = "0"
+	// newID specifies the ID reading the entries never delivered to the consumer group.
+	newID = ">"
+)
+
+// ReaderConfig holds configuration settings for the Redis Streams reader.
+type ReaderConfig struct {
+	// Address specifies the Redis server address.
+	Address string `mapstructure:"address"`
+	// Password specifies the Redis password.
+	Password string `mapstructure:"password"`
+	// DB specifies the Redis database.
+	DB int `mapstructure:"db"`
+	// Stream specifies the stream to read from.
+	Stream string `mapstructure:"stream"`
+	// Group specifies the consumer group, created with the stream if it does not exist.
+	Group string `mapstructure:"group"`
+	// Consumer specifies the consumer name, unique to each instance of a service.
+	Consumer string `mapstructure:"consumer"`
+	// MaxPollRecords specifies the maximum number of entries read per poll. Defaults to 500.
+	MaxPollRecords int `mapstructure:"max_poll_records"`
+}
+
+// Reader holds the Redis Streams reader instance.
+// It implements the msgbus.MessageReader interface to read messages from a stream consumer group.
+type Reader struct {
+	// Client specifies the Redis client.
+	Client *redis.Client
+	// cfg specifies the configuration of the stream and consumer group to read.
+	cfg ReaderConfig
+}
+
+// NewStreamReader creates a new Reader instance based on the provided configuration.
+func NewStreamReader(cfg ReaderConfig) (*Reader, error) {
+	slog.Info("Initializing Redis Streams reader for the service", "address", cfg.Address, "stream", cfg.Stream)
+
+	if cfg.Address == "" {
+		return nil, fmt.Errorf("redis server address is empty")
+	}
+
+	if cfg.Stream == "" {
+		return nil, fmt.Errorf("redis stream is empty")
+	}
+
+	if cfg.Group == "" {
+		return nil, fmt.Errorf("redis consumer group is empty")
+	}
+
+	if cfg.Consumer == "" {
+		return nil, fmt.Errorf("redis consumer is empty")
+	}
+
+	if cfg.MaxPollRecords < 0 {
+		return nil, fmt.Errorf("redis max poll records is invalid: %d", cfg.MaxPollRecords)
+	}
+
+	if cfg.MaxPollRecords == 0 {
+		cfg.MaxPollRecords = defaultMaxPollRecords
+	}
+
+	return &Reader{
+		Client: newClient(cfg.Address, cfg.Password, cfg.DB),
+		cfg:    cfg,
+	}, nil
+}
+
+// Check pings the Redis server.
+func (r *Reader) Check(ctx context.Context) error {
+	if err := r.Client.Ping(ctx).Err(); err != nil {
+		return fmt.Errorf("failed to ping Redis: %w", err)
+	}
+
+	return nil
+}
+
+// Close closes the Redis client.
+func (r *Reader) Close() {
+	if err := r.Client.Close(); err != nil {
+		slog.Warn("Failed to close Redis client", "error", err)
+	}
+}
+
+// ReadMessages reads messages from the stream consumer group and sends them to the provided channel.
+// The entries left pending by an earlier run, such as rejected ones, are redelivered before new entries.
+func (r *Reader) ReadMessages(ctx context.Context, msgChan chan<- msgbus.Delivery) error {
+	slog.Info("Reading message from Redis stream")
+
+	defer close(msgChan)
+
+	if r == nil {
+		return fmt.Errorf("redis reader is nil")
+	}
+
+	if err := r.createGroup(ctx); err != nil {
+		return err
+	}
+
+	start := pendingID
+
+readingLoop:
+	for {
+		streams, err := r.Client.XReadGroup(ctx, &redis.XReadGroupArgs{
+			Group:    r.cfg.Group,
+			Consumer: r.cfg.Consumer,
+			Streams:  []string{r.cfg.Stream, start},
+			Count:    int64(r.cfg.MaxPollRecords),
+			Block:    blockTimeout,
+		}).Result()
+		if ctx.Err() != nil {
+			break readingLoop
+		}
+
+		if errors.Is(err, redis.Nil) {
+			continue
+		}
+
+		if err != nil {
+			slog.Error("Failed to fetch message from Redis", "error", err)
+
+			select {
+			case <-ctx.Done():
+				break readingLoop
+			case <-time.After(retryDelay):
+				continue
+			}
+		}
+
+		entries := make([]redis.XMessage, 0)
+		for _, stream := range streams {
+			entries = append(entries, stream.Messages...)
+		}
+
+		if start != newID {
+			// Page through the pending entries until there are none left, then read new entries
+			if len(entries) == 0 {
+				start = newID
+				continue
+			}
+
+			start = entries[len(entries)-1].ID
+		}
+
+		for _, entry := range entries {
+			select {
+			case <-ctx.Done():
+				break readingLoop
+			case msgChan <- newDelivery(r.Client, r.cfg.Stream, r.cfg.Group, entry):
+			}
+		}
+	}
+
+	return fmt.Errorf("context canceled while fetching redis messages: %w", ctx.Err())
+}
+
+// createGroup creates the consumer group, and the stream with it, unless the group already exists.
+func (r *Reader) createGroup(ctx context.Context) error {
+	err := r.Client.XGroupCreateMkStream(ctx, r.cfg.Stream, r.cfg.Group, pendingID).Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return fmt.Errorf("failed to create Redis consumer group: %w", err)
+	}
+
+	return nil
+}
+
+// newClient creates a Redis client, which connects once it is first used.
+func newClient(address string, password string, db int) *redis.Client {
+	return redis.NewClient(&redis.Options{
+		Addr:     address,
+		Password: password,
+		DB:       db,
+	})
+}
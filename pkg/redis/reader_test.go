@@ -0,0 +1,219 @@
+package redis_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ansoncht/flight-microservices/pkg/msgbus"
+	msgQueue "github.com/ansoncht/flight-microservices/pkg/redis"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go/modules/redis"
+)
+
+const (
+	testRedisImage   = "redis:7"
+	testStream       = "test-stream"
+	testGroup        = "test-group"
+	testConsumer     = "test-consumer"
+	testMessageKey   = "test-key"
+	testMessageValue = "hello, world"
+	timeout          = 10 * time.Second
+)
+
+// setupRedisTest spins up a Redis container for testing and returns its address and a cleanup function.
+func setupRedisTest(ctx context.Context, t *testing.T) (address string, cleanup func()) {
+	t.Helper()
+
+	redisContainer, err := redis.Run(ctx, testRedisImage)
+	require.NoError(t, err)
+	require.NotNil(t, redisContainer)
+
+	cleanup = func() {
+		err := redisContainer.Terminate(ctx)
+		require.NoError(t, err)
+	}
+
+	address, err = redisContainer.Endpoint(ctx, "")
+	require.NoError(t, err)
+	require.NotEmpty(t, address)
+
+	return address, cleanup
+}
+
+func TestNewStreamReader_ValidConfig_ShouldSucceed(t *testing.T) {
+	cfg := msgQueue.ReaderConfig{
+		Address:  "localhost:6379",
+		Stream:   testStream,
+		Group:    testGroup,
+		Consumer: testConsumer,
+	}
+	reader, err := msgQueue.NewStreamReader(cfg)
+	require.NoError(t, err)
+	require.NotNil(t, reader)
+	reader.Close()
+}
+
+func TestNewStreamReader_InvalidConfig_ShouldError(t *testing.T) {
+	cfg := msgQueue.ReaderConfig{
+		Address:  "localhost:6379",
+		Stream:   testStream,
+		Group:    testGroup,
+		Consumer: testConsumer,
+	}
+
+	tests := []struct {
+		name    string
+		cfg     msgQueue.ReaderConfig
+		wantErr string
+	}{
+		{
+			name: "Missing Address",
+			cfg: func() msgQueue.ReaderConfig {
+				c := cfg
+				c.Address = ""
+				return c
+			}(),
+			wantErr: "redis server address is empty",
+		},
+		{
+			name: "Missing Stream",
+			cfg: func() msgQueue.ReaderConfig {
+				c := cfg
+				c.Stream = ""
+				return c
+			}(),
+			wantErr: "redis stream is empty",
+		},
+		{
+			name: "Missing Group",
+			cfg: func() msgQueue.ReaderConfig {
+				c := cfg
+				c.Group = ""
+				return c
+			}(),
+			wantErr: "redis consumer group is empty",
+		},
+		{
+			name: "Missing Consumer",
+			cfg: func() msgQueue.ReaderConfig {
+				c := cfg
+				c.Consumer = ""
+				return c
+			}(),
+			wantErr: "redis consumer is empty",
+		},
+		{
+			name: "Negative Max Poll Records",
+			cfg: func() msgQueue.ReaderConfig {
+				c := cfg
+				c.MaxPollRecords = -1
+				return c
+			}(),
+			wantErr: "redis max poll records is invalid",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader, err := msgQueue.NewStreamReader(tt.cfg)
+			require.ErrorContains(t, err, tt.wantErr)
+			require.Nil(t, reader)
+		})
+	}
+}
+
+func TestReadMessages_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	address, cleanup := setupRedisTest(ctx, t)
+	defer cleanup()
+
+	writer, err := msgQueue.NewStreamWriter(msgQueue.WriterConfig{Address: address, Stream: testStream})
+	require.NoError(t, err)
+	defer writer.Close()
+
+	reader, err := msgQueue.NewStreamReader(msgQueue.ReaderConfig{
+		Address:  address,
+		Stream:   testStream,
+		Group:    testGroup,
+		Consumer: testConsumer,
+	})
+	require.NoError(t, err)
+	defer reader.Close()
+
+	// readN reads the given number of messages and stops the reader.
+	readN := func(t *testing.T, n int) []msgbus.Delivery {
+		t.Helper()
+
+		msgChan := make(chan msgbus.Delivery, n)
+		readErrChan := make(chan error, 1)
+		readCtx, readCancel := context.WithCancel(ctx)
+		defer readCancel()
+
+		go func() {
+			readErrChan <- reader.ReadMessages(readCtx, msgChan)
+		}()
+
+		deliveries := make([]msgbus.Delivery, 0, n)
+		for len(deliveries) < n {
+			select {
+			case delivery := <-msgChan:
+				deliveries = append(deliveries, delivery)
+			case err := <-readErrChan:
+				require.NoError(t, err)
+			case <-time.After(timeout):
+				t.Fatal("timed out waiting for message from Redis")
+			}
+		}
+
+		readCancel()
+		select {
+		case err := <-readErrChan:
+			require.ErrorIs(t, err, context.Canceled)
+		case <-time.After(timeout):
+			t.Error("ReadMessages goroutine did not exit cleanly after cancellation")
+		}
+
+		return deliveries
+	}
+
+	// The consumer group starts at the beginning of the stream, so messages written before it are read
+	writeCtx, writeCancel := context.WithTimeout(ctx, timeout)
+	defer writeCancel()
+
+	err = writer.WriteMessages(writeCtx, []msgbus.Message{
+		{
+			Key:     []byte(testMessageKey),
+			Value:   []byte(testMessageValue),
+			Headers: []msgbus.Header{{Key: "correlation-id", Value: []byte("abc")}},
+		},
+		{Key: []byte("second"), Value: []byte(testMessageValue)},
+	})
+	require.NoError(t, err)
+
+	t.Run("Rejected Message Is Redelivered After Restart", func(t *testing.T) {
+		deliveries := readN(t, 2)
+		require.Equal(t, testMessageKey, string(deliveries[0].Key))
+		require.Equal(t, testMessageValue, string(deliveries[0].Value))
+		require.Equal(t, testStream, deliveries[0].Topic)
+		require.Len(t, deliveries[0].Headers, 1)
+		require.Equal(t, "correlation-id", deliveries[0].Headers[0].Key)
+		deliveries[0].Nack(errors.New("test error"))
+		deliveries[1].Ack()
+
+		deliveries = readN(t, 1)
+		require.Equal(t, testMessageKey, string(deliveries[0].Key))
+		deliveries[0].Ack()
+	})
+
+	t.Run("Nil Reader", func(t *testing.T) {
+		var dummyReader *msgQueue.Reader
+		err := dummyReader.ReadMessages(ctx, make(chan msgbus.Delivery, 1))
+		require.ErrorContains(t, err, "redis reader is nil")
+	})
+}
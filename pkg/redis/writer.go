@@ -0,0 +1,131 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/ansoncht/flight-microservices/pkg/msgbus"
+	"github.com/redis/go-redis/v9"
+)
+
+// WriterConfig holds configuration settings for the Redis Streams writer.
+type WriterConfig struct {
+	// Address specifies the Redis server address.
+	Address string `mapstructure:"address"`
+	// Password specifies the Redis password.
+	Password string `mapstructure:"password"`
+	// DB specifies the Redis database.
+	DB int `mapstructure:"db"`
+	// Stream specifies the stream to write to.
+	Stream string `mapstructure:"stream"`
+	// MaxLen specifies the approximate number of entries a stream is trimmed to. Defaults to no trimming.
+	MaxLen int64 `mapstructure:"max_len"`
+}
+
+// Writer holds the Redis Streams writer instance.
+// It implements the msgbus.MessageWriter interface to add messages to a stream.
+// Message timestamps are set by the server through the entry IDs, and messages with a topic are added to that
+// stream.
+type Writer struct {
+	// Client specifies the Redis client.
+	Client *redis.Client
+	// cfg specifies the configuration of the stream to write to.
+	cfg WriterConfig
+}
+
+// NewStreamWriter creates a new Writer instance based on the provided configuration.
+func NewStreamWriter(cfg WriterConfig) (*Writer, error) {
+	slog.Info("Initializing Redis Streams writer for the service", "address", cfg.Address, "stream", cfg.Stream)
+
+	if cfg.Address == "" {
+		return nil, fmt.Errorf("redis server address is empty")
+	}
+
+	if cfg.Stream == "" {
+		return nil, fmt.Errorf("redis stream is empty")
+	}
+
+	if cfg.MaxLen < 0 {
+		return nil, fmt.Errorf("redis stream max length is invalid: %d", cfg.MaxLen)
+	}
+
+	return &Writer{
+		Client: newClient(cfg.Address, cfg.Password, cfg.DB),
+		cfg:    cfg,
+	}, nil
+}
+
+// Check pings the Redis server.
+func (w *Writer) Check(ctx context.Context) error {
+	if err := w.Client.Ping(ctx).Err(); err != nil {
+		return fmt.Errorf("failed to ping Redis: %w", err)
+	}
+
+	return nil
+}
+
+// Close closes the Redis client.
+func (w *Writer) Close() {
+	if err := w.Client.Close(); err != nil {
+		slog.Warn("Failed to close Redis client", "error", err)
+	}
+}
+
+// WriteMessage writes a message to the Redis stream.
+func (w *Writer) WriteMessage(ctx context.Context, key []byte, value []byte) error {
+	slog.Info("Writing message to Redis stream", "key", string(key), "message", string(value))
+
+	if w == nil {
+		return fmt.Errorf("redis writer is nil")
+	}
+
+	msg := msgbus.Message{Key: key, Value: value}
+	if err := msg.Validate(); err != nil {
+		return fmt.Errorf("invalid message: %w", err)
+	}
+
+	if err := w.Client.XAdd(ctx, newXAddArgs(msg, w.cfg.Stream, w.cfg.MaxLen)).Err(); err != nil {
+		return fmt.Errorf("failed to add message to Redis stream: %w", err)
+	}
+
+	return nil
+}
+
+// WriteMessages writes a batch of messages to their streams, defaulting to the Redis stream of the writer, in a
+// single pipelined round trip.
+func (w *Writer) WriteMessages(ctx context.Context, msgs []msgbus.Message) error {
+	slog.Info("Writing messages to Redis stream", "count", len(msgs))
+
+	if w == nil {
+		return fmt.Errorf("redis writer is nil")
+	}
+
+	for i, msg := range msgs {
+		if err := msg.Validate(); err != nil {
+			return fmt.Errorf("invalid message %d: %w", i, err)
+		}
+	}
+
+	if len(msgs) == 0 {
+		return nil
+	}
+
+	_, err := w.Client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, msg := range msgs {
+			pipe.XAdd(ctx, newXAddArgs(msg, w.cfg.Stream, w.cfg.MaxLen))
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to add messages to Redis stream: %w", err)
+	}
+
+	return nil
+}
+
+// Flush does nothing, as writes to a Redis stream complete before they return.
+func (w *Writer) Flush(_ context.Context) error {
+	return nil
+}
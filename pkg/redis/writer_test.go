@@ -0,0 +1,108 @@
+package redis_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ansoncht/flight-microservices/pkg/msgbus"
+	msgQueue "github.com/ansoncht/flight-microservices/pkg/redis"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewStreamWriter_InvalidConfig_ShouldError(t *testing.T) {
+	cfg := msgQueue.WriterConfig{
+		Address: "localhost:6379",
+		Stream:  testStream,
+	}
+
+	tests := []struct {
+		name    string
+		cfg     msgQueue.WriterConfig
+		wantErr string
+	}{
+		{
+			name: "Missing Address",
+			cfg: func() msgQueue.WriterConfig {
+				c := cfg
+				c.Address = ""
+				return c
+			}(),
+			wantErr: "redis server address is empty",
+		},
+		{
+			name: "Missing Stream",
+			cfg: func() msgQueue.WriterConfig {
+				c := cfg
+				c.Stream = ""
+				return c
+			}(),
+			wantErr: "redis stream is empty",
+		},
+		{
+			name: "Negative Max Length",
+			cfg: func() msgQueue.WriterConfig {
+				c := cfg
+				c.MaxLen = -1
+				return c
+			}(),
+			wantErr: "redis stream max length is invalid",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writer, err := msgQueue.NewStreamWriter(tt.cfg)
+			require.ErrorContains(t, err, tt.wantErr)
+			require.Nil(t, writer)
+		})
+	}
+}
+
+func TestWriteMessages_InvalidMessage_ShouldError(t *testing.T) {
+	writer, err := msgQueue.NewStreamWriter(msgQueue.WriterConfig{Address: "localhost:6379", Stream: testStream})
+	require.NoError(t, err)
+	defer writer.Close()
+
+	err = writer.WriteMessage(context.Background(), []byte(testMessageKey), nil)
+	require.ErrorContains(t, err, "message value is nil or empty")
+
+	err = writer.WriteMessages(context.Background(), []msgbus.Message{{Value: []byte(testMessageValue)}})
+	require.ErrorContains(t, err, "invalid message 0: message key is nil or empty")
+}
+
+func TestWriteMessages_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	address, cleanup := setupRedisTest(ctx, t)
+	defer cleanup()
+
+	writer, err := msgQueue.NewStreamWriter(msgQueue.WriterConfig{Address: address, Stream: testStream, MaxLen: 100})
+	require.NoError(t, err)
+	defer writer.Close()
+
+	t.Run("Successful WriteMessages", func(t *testing.T) {
+		err := writer.WriteMessages(ctx, []msgbus.Message{
+			{Key: []byte(testMessageKey), Value: []byte(testMessageValue)},
+			{Topic: "other-stream", Key: []byte(testMessageKey), Value: []byte(testMessageValue)},
+		})
+		require.NoError(t, err)
+		require.NoError(t, writer.Flush(ctx))
+
+		length, err := writer.Client.XLen(ctx, testStream).Result()
+		require.NoError(t, err)
+		require.Equal(t, int64(1), length)
+
+		length, err = writer.Client.XLen(ctx, "other-stream").Result()
+		require.NoError(t, err)
+		require.Equal(t, int64(1), length)
+	})
+
+	t.Run("Nil Writer", func(t *testing.T) {
+		var dummyWriter *msgQueue.Writer
+		err := dummyWriter.WriteMessage(ctx, []byte(testMessageKey), []byte(testMessageValue))
+		require.ErrorContains(t, err, "redis writer is nil")
+	})
+}
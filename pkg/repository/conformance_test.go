@@ -0,0 +1,317 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ansoncht/flight-microservices/pkg/model"
+	"github.com/ansoncht/flight-microservices/pkg/repository"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// testSummaryRepositoryConformance runs the behaviour every SummaryRepository implementation must share.
+func testSummaryRepositoryConformance(t *testing.T, repo repository.SummaryRepository) {
+	t.Helper()
+
+	ctx := context.Background()
+
+	t.Run("Insert And Get Round Trip", func(t *testing.T) {
+		summary := model.DailyFlightSummary{
+			Date:              model.ToMongoDateTime(time.Date(2025, 5, 7, 0, 0, 0, 0, time.UTC)),
+			Airport:           "HKG",
+			TotalFlights:      3,
+			AirlineCounts:     map[string]int{"Cathay Pacific": 2, "HK Express": 1},
+			DestinationCounts: map[string]int{"NRT": 2, "TPE": 1},
+			TopDestinations:   []string{"NRT", "TPE"},
+			TopAirlines:       []string{"Cathay Pacific", "HK Express"},
+			DuplicatesRemoved: 1,
+			AirlineRanking: []model.RankedEntry{
+				{Key: "Cathay Pacific", Count: 2, Rank: 1, Share: 2.0 / 3},
+				{Key: "HK Express", Count: 1, Rank: 2, Share: 1.0 / 3},
+			},
+			DestinationRanking: []model.RankedEntry{
+				{Key: "NRT", Count: 2, Rank: 1, Share: 2.0 / 3},
+				{Key: "Others", Count: 1, Share: 1.0 / 3},
+			},
+			HourlyDepartures: []int{
+				0, 0, 0, 0, 0, 0, 0, 0, 1, 2, 0, 0,
+				0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+			},
+			BusiestHour:         9,
+			BusiestHourFlights:  2,
+			QuietestHour:        0,
+			QuietestHourFlights: 0,
+			WeekOverWeek: &model.SummaryComparison{
+				BaselineDate:         model.ToMongoDateTime(time.Date(2025, 4, 30, 0, 0, 0, 0, time.UTC)),
+				BaselineFlights:      2,
+				FlightsChange:        1,
+				FlightsChangePercent: 50,
+				AirlinesGained:       []string{"HK Express"},
+				NewDestinations:      []string{"TPE"},
+			},
+			Anomalies: []model.Anomaly{
+				{Metric: model.AnomalyMetricDestination, Key: "TPE", Value: 1, Score: 4.2, Method: "mad"},
+			},
+			Stats: []model.StatSection{
+				{
+					Name:  "durations",
+					Title: "Flight Durations",
+					Values: []model.StatValue{
+						{Label: "Average", Value: 245, Unit: "min"},
+						{Label: "Longest", Value: 290.5, Unit: "min"},
+					},
+				},
+				{
+					Name:  "top_countries",
+					Title: "Top Countries",
+					Values: []model.StatValue{
+						{Key: "JP", Label: "Japan", Value: 2},
+						{Key: "TW", Label: "Taiwan", Value: 1},
+					},
+				},
+			},
+		}
+
+		id, err := repo.Insert(ctx, summary)
+		require.NoError(t, err)
+		require.NotEmpty(t, id)
+
+		got, err := repo.Get(ctx, id)
+		require.NoError(t, err)
+		require.NotNil(t, got)
+		require.Equal(t, id, got.ID.Hex())
+		require.Equal(t, summary.Date, got.Date)
+		require.Equal(t, summary.Airport, got.Airport)
+		require.Equal(t, summary.TotalFlights, got.TotalFlights)
+		require.Equal(t, summary.AirlineCounts, got.AirlineCounts)
+		require.Equal(t, summary.DestinationCounts, got.DestinationCounts)
+		require.Equal(t, summary.TopDestinations, got.TopDestinations)
+		require.Equal(t, summary.TopAirlines, got.TopAirlines)
+		require.Equal(t, summary.AirlineRanking, got.AirlineRanking)
+		require.Equal(t, summary.DestinationRanking, got.DestinationRanking)
+		require.Equal(t, summary.DuplicatesRemoved, got.DuplicatesRemoved)
+		require.Equal(t, summary.HourlyDepartures, got.HourlyDepartures)
+		require.Equal(t, summary.BusiestHour, got.BusiestHour)
+		require.Equal(t, summary.BusiestHourFlights, got.BusiestHourFlights)
+		require.Equal(t, summary.QuietestHour, got.QuietestHour)
+		require.Equal(t, summary.QuietestHourFlights, got.QuietestHourFlights)
+		require.Nil(t, got.DayOverDay)
+		require.Equal(t, summary.WeekOverWeek, got.WeekOverWeek)
+		require.Equal(t, summary.Anomalies, got.Anomalies)
+		require.Equal(t, summary.Stats, got.Stats)
+	})
+
+	t.Run("Insert Empty Summary", func(t *testing.T) {
+		id, err := repo.Insert(ctx, model.DailyFlightSummary{})
+		require.NoError(t, err)
+		require.NotEmpty(t, id)
+	})
+
+	t.Run("Insert Returns Distinct IDs", func(t *testing.T) {
+		first, err := repo.Insert(ctx, model.DailyFlightSummary{Airport: "HKG"})
+		require.NoError(t, err)
+
+		second, err := repo.Insert(ctx, model.DailyFlightSummary{Airport: "TPE"})
+		require.NoError(t, err)
+		require.NotEqual(t, first, second)
+	})
+
+	t.Run("Get Unknown ID", func(t *testing.T) {
+		summary, err := repo.Get(ctx, primitive.NewObjectID().Hex())
+		require.Error(t, err)
+		require.Nil(t, summary)
+	})
+
+	t.Run("Get Invalid ID", func(t *testing.T) {
+		summary, err := repo.Get(ctx, "invalid")
+		require.ErrorContains(t, err, "failed to cast id to ObjectID")
+		require.Nil(t, summary)
+	})
+	t.Run("List By Date Range", func(t *testing.T) {
+		day := func(d int) primitive.DateTime {
+			return model.ToMongoDateTime(time.Date(2024, 3, d, 0, 0, 0, 0, time.UTC))
+		}
+
+		for _, summary := range []model.DailyFlightSummary{
+			{Date: day(3), Airport: "SIN", TotalFlights: 3},
+			{Date: day(1), Airport: "SIN", TotalFlights: 1},
+			{Date: day(2), Airport: "SIN", TotalFlights: 2},
+			{Date: day(2), Airport: "BKK", TotalFlights: 9},
+			{Date: day(5), Airport: "SIN", TotalFlights: 5},
+		} {
+			_, err := repo.Insert(ctx, summary)
+			require.NoError(t, err)
+		}
+
+		got, err := repo.ListByDateRange(ctx, "SIN", day(1).Time(), day(3).Time())
+		require.NoError(t, err)
+
+		totals := make([]int, 0, len(got))
+		for _, summary := range got {
+			require.Equal(t, "SIN", summary.Airport)
+			totals = append(totals, summary.TotalFlights)
+		}
+		require.Equal(t, []int{1, 2, 3}, totals)
+	})
+
+	t.Run("List By Route", func(t *testing.T) {
+		day := func(d int) primitive.DateTime {
+			return model.ToMongoDateTime(time.Date(2024, 6, d, 0, 0, 0, 0, time.UTC))
+		}
+
+		routes := func(keys ...string) []model.StatSection {
+			values := make([]model.StatValue, 0, len(keys))
+			for _, key := range keys {
+				values = append(values, model.StatValue{Key: key, Label: key, Value: 1})
+			}
+
+			return []model.StatSection{{Name: model.RouteStatName, Title: "Top Routes", Values: values}}
+		}
+
+		for _, summary := range []model.DailyFlightSummary{
+			{Date: day(1), Airport: "SIN", TotalFlights: 1, Stats: routes("SIN-NRT")},
+			{Date: day(2), Airport: "SIN", TotalFlights: 2, Stats: routes("SIN-BKK")},
+			{Date: day(3), Airport: "SIN", TotalFlights: 3, Stats: routes("SIN-BKK", "SIN-NRT")},
+			{Date: day(3), Airport: "BKK", TotalFlights: 9, Stats: routes("SIN-NRT")},
+			{Date: day(4), Airport: "SIN", TotalFlights: 5, Stats: []model.StatSection{
+				{Name: "airline_routes", Values: []model.StatValue{{Key: "SIN-NRT", Value: 1}}},
+			}},
+			{Date: day(9), Airport: "SIN", TotalFlights: 4, Stats: routes("SIN-NRT")},
+		} {
+			_, err := repo.Insert(ctx, summary)
+			require.NoError(t, err)
+		}
+
+		got, err := repo.ListByRoute(ctx, "SIN", "SIN-NRT", day(1).Time(), day(5).Time())
+		require.NoError(t, err)
+
+		totals := make([]int, 0, len(got))
+		for _, summary := range got {
+			totals = append(totals, summary.TotalFlights)
+		}
+		require.Equal(t, []int{1, 3}, totals)
+	})
+
+	t.Run("Upsert Replaces Summary Of Same Day", func(t *testing.T) {
+		date := model.ToMongoDateTime(time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC))
+
+		id, err := repo.Upsert(ctx, model.DailyFlightSummary{Date: date, Airport: "ICN", TotalFlights: 1})
+		require.NoError(t, err)
+		require.NotEmpty(t, id)
+
+		again, err := repo.Upsert(ctx, model.DailyFlightSummary{Date: date, Airport: "ICN", TotalFlights: 2})
+		require.NoError(t, err)
+		require.Equal(t, id, again)
+
+		other, err := repo.Upsert(ctx, model.DailyFlightSummary{Date: date, Airport: "GMP", TotalFlights: 3})
+		require.NoError(t, err)
+		require.NotEqual(t, id, other)
+
+		got, err := repo.Get(ctx, id)
+		require.NoError(t, err)
+		require.Equal(t, 2, got.TotalFlights)
+
+		listed, err := repo.ListByDateRange(ctx, "ICN", date.Time(), date.Time())
+		require.NoError(t, err)
+		require.Len(t, listed, 1)
+	})
+
+	t.Run("List By Date Range Empty", func(t *testing.T) {
+		got, err := repo.ListByDateRange(ctx, "XXX", time.Now().AddDate(0, 0, -7), time.Now())
+		require.NoError(t, err)
+		require.Empty(t, got)
+	})
+}
+
+// testRollupRepositoryConformance runs the behaviour every RollupRepository implementation must share.
+func testRollupRepositoryConformance(t *testing.T, repo repository.RollupRepository) {
+	t.Helper()
+
+	ctx := context.Background()
+	rollup := model.FlightSummaryRollup{
+		StartDate:         model.ToMongoDateTime(time.Date(2025, 5, 5, 0, 0, 0, 0, time.UTC)),
+		EndDate:           model.ToMongoDateTime(time.Date(2025, 5, 11, 0, 0, 0, 0, time.UTC)),
+		Airport:           "HKG",
+		Days:              7,
+		TotalFlights:      21,
+		DailyAverage:      3,
+		BusiestDay:        model.ToMongoDateTime(time.Date(2025, 5, 9, 0, 0, 0, 0, time.UTC)),
+		BusiestDayFlights: 6,
+		AirlineCounts:     map[string]int{"Cathay Pacific": 15, "HK Express": 6},
+		DestinationCounts: map[string]int{"NRT": 12, "TPE": 9},
+		TopDestinations:   []string{"NRT", "TPE"},
+		TopAirlines:       []string{"Cathay Pacific", "HK Express"},
+		AirlineRanking: []model.RankedEntry{
+			{Key: "Cathay Pacific", Count: 15, Rank: 1, Share: 15.0 / 21},
+			{Key: "HK Express", Count: 6, Rank: 2, Share: 6.0 / 21},
+		},
+		DestinationRanking: []model.RankedEntry{
+			{Key: "NRT", Count: 12, Rank: 1, Share: 12.0 / 21},
+			{Key: "TPE", Count: 9, Rank: 2, Share: 9.0 / 21},
+		},
+	}
+
+	requireRollupEqual := func(t *testing.T, id string, want model.FlightSummaryRollup, got model.FlightSummaryRollup) {
+		t.Helper()
+
+		require.Equal(t, id, got.ID.Hex())
+		want.ID = got.ID
+		require.Equal(t, want, got)
+	}
+
+	t.Run("Upsert And Get Weekly Round Trip", func(t *testing.T) {
+		id, err := repo.UpsertWeekly(ctx, model.WeeklyFlightSummary{FlightSummaryRollup: rollup})
+		require.NoError(t, err)
+		require.NotEmpty(t, id)
+
+		got, err := repo.GetWeekly(ctx, id)
+		require.NoError(t, err)
+		require.NotNil(t, got)
+		requireRollupEqual(t, id, rollup, got.FlightSummaryRollup)
+	})
+
+	t.Run("Upsert And Get Monthly Round Trip", func(t *testing.T) {
+		id, err := repo.UpsertMonthly(ctx, model.MonthlyFlightSummary{FlightSummaryRollup: rollup})
+		require.NoError(t, err)
+		require.NotEmpty(t, id)
+
+		got, err := repo.GetMonthly(ctx, id)
+		require.NoError(t, err)
+		require.NotNil(t, got)
+		requireRollupEqual(t, id, rollup, got.FlightSummaryRollup)
+	})
+
+	t.Run("Upsert Replaces Same Period", func(t *testing.T) {
+		first, err := repo.UpsertWeekly(ctx, model.WeeklyFlightSummary{FlightSummaryRollup: rollup})
+		require.NoError(t, err)
+
+		updated := rollup
+		updated.TotalFlights = 28
+		updated.DailyAverage = 4
+
+		second, err := repo.UpsertWeekly(ctx, model.WeeklyFlightSummary{FlightSummaryRollup: updated})
+		require.NoError(t, err)
+		require.Equal(t, first, second)
+
+		got, err := repo.GetWeekly(ctx, second)
+		require.NoError(t, err)
+		requireRollupEqual(t, second, updated, got.FlightSummaryRollup)
+	})
+
+	t.Run("Weekly And Monthly Are Separate", func(t *testing.T) {
+		id, err := repo.UpsertWeekly(ctx, model.WeeklyFlightSummary{FlightSummaryRollup: rollup})
+		require.NoError(t, err)
+
+		monthly, err := repo.GetMonthly(ctx, id)
+		require.Error(t, err)
+		require.Nil(t, monthly)
+	})
+
+	t.Run("Get Invalid ID", func(t *testing.T) {
+		weekly, err := repo.GetWeekly(ctx, "invalid")
+		require.ErrorContains(t, err, "failed to cast id to ObjectID")
+		require.Nil(t, weekly)
+	})
+}
@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ansoncht/flight-microservices/pkg/model"
+	db "github.com/ansoncht/flight-microservices/pkg/mongo"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	weeklySummaryCollection  = "weekly_summaries"
+	monthlySummaryCollection = "monthly_summaries"
+)
+
+// RollupRepository defines the interface for interacting with weekly and monthly flight summary data in database.
+type RollupRepository interface {
+	// UpsertWeekly replaces the weekly flight summary of the same airport and week in the database, or inserts it.
+	UpsertWeekly(ctx context.Context, summary model.WeeklyFlightSummary) (string, error)
+	// GetWeekly gets a weekly flight summary from the database.
+	GetWeekly(ctx context.Context, id string) (*model.WeeklyFlightSummary, error)
+	// UpsertMonthly replaces the monthly flight summary of the same airport and month in the database, or inserts it.
+	UpsertMonthly(ctx context.Context, summary model.MonthlyFlightSummary) (string, error)
+	// GetMonthly gets a monthly flight summary from the database.
+	GetMonthly(ctx context.Context, id string) (*model.MonthlyFlightSummary, error)
+}
+
+// MongoRollupRepository holds the MongoDB collections for weekly and monthly flight summaries.
+// It implements the RollupRepository interface to provide methods for storing rolled up summaries.
+type MongoRollupRepository struct {
+	// Weekly specifies the MongoDB collection for weekly flight summaries.
+	Weekly *mongo.Collection
+	// Monthly specifies the MongoDB collection for monthly flight summaries.
+	Monthly *mongo.Collection
+}
+
+// NewMongoRollupRepository creates a new MongoRollupRepository instance based on the provided MongoDB client.
+func NewMongoRollupRepository(client *db.Client) (*MongoRollupRepository, error) {
+	if client == nil {
+		return nil, fmt.Errorf("mongo client is nil")
+	}
+
+	return &MongoRollupRepository{
+		Weekly:  client.Database.Collection(weeklySummaryCollection),
+		Monthly: client.Database.Collection(monthlySummaryCollection),
+	}, nil
+}
+
+// UpsertWeekly replaces the weekly flight summary of the same airport and week in the MongoDB collection, or inserts
+// it.
+func (r *MongoRollupRepository) UpsertWeekly(ctx context.Context, summary model.WeeklyFlightSummary) (string, error) {
+	filter := rollupFilter(summary.FlightSummaryRollup)
+
+	// Keep the ID of the replaced summary
+	summary.ID = primitive.NilObjectID
+
+	return upsertDocument(ctx, r.Weekly, filter, summary)
+}
+
+// GetWeekly gets a weekly flight summary from the MongoDB collection.
+func (r *MongoRollupRepository) GetWeekly(ctx context.Context, id string) (*model.WeeklyFlightSummary, error) {
+	summary := &model.WeeklyFlightSummary{}
+	if err := findDocument(ctx, r.Weekly, id, summary); err != nil {
+		return nil, err
+	}
+
+	return summary, nil
+}
+
+// UpsertMonthly replaces the monthly flight summary of the same airport and month in the MongoDB collection, or
+// inserts it.
+func (r *MongoRollupRepository) UpsertMonthly(ctx context.Context, summary model.MonthlyFlightSummary) (string, error) {
+	filter := rollupFilter(summary.FlightSummaryRollup)
+
+	// Keep the ID of the replaced summary
+	summary.ID = primitive.NilObjectID
+
+	return upsertDocument(ctx, r.Monthly, filter, summary)
+}
+
+// GetMonthly gets a monthly flight summary from the MongoDB collection.
+func (r *MongoRollupRepository) GetMonthly(ctx context.Context, id string) (*model.MonthlyFlightSummary, error) {
+	summary := &model.MonthlyFlightSummary{}
+	if err := findDocument(ctx, r.Monthly, id, summary); err != nil {
+		return nil, err
+	}
+
+	return summary, nil
+}
+
+// insertDocument inserts a document into the collection and returns its ObjectID as a hex string.
+func insertDocument(ctx context.Context, collection *mongo.Collection, document any) (string, error) {
+	result, err := collection.InsertOne(ctx, document)
+	if err != nil {
+		return "", fmt.Errorf("failed to insert to collection %s: %w", collection.Name(), err)
+	}
+
+	oid, ok := result.InsertedID.(primitive.ObjectID)
+	if !ok {
+		return "", fmt.Errorf("failed to cast InsertedID to ObjectID")
+	}
+
+	return oid.Hex(), nil
+}
+
+// rollupFilter matches the rollups of the same airport and period as the given rollup.
+func rollupFilter(rollup model.FlightSummaryRollup) bson.D {
+	return bson.D{
+		{Key: "airport", Value: rollup.Airport},
+		{Key: "startDate", Value: rollup.StartDate},
+	}
+}
+
+// upsertDocument replaces the first document matching the filter with the given document, or inserts it, and returns
+// the ObjectID of the stored document as a hex string.
+func upsertDocument(ctx context.Context, collection *mongo.Collection, filter bson.D, document any) (string, error) {
+	opts := options.FindOneAndReplace().
+		SetUpsert(true).
+		SetReturnDocument(options.After).
+		SetSort(bson.D{{Key: "_id", Value: 1}}).
+		SetProjection(bson.D{{Key: "_id", Value: 1}})
+
+	var upserted struct {
+		ID primitive.ObjectID `bson:"_id"`
+	}
+	if err := collection.FindOneAndReplace(ctx, filter, document, opts).Decode(&upserted); err != nil {
+		return "", fmt.Errorf("failed to upsert to collection %s: %w", collection.Name(), err)
+	}
+
+	return upserted.ID.Hex(), nil
+}
+
+// findDocument finds the document with the given ObjectID hex string and decodes it into out.
+func findDocument(ctx context.Context, collection *mongo.Collection, id string, out any) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return fmt.Errorf("failed to cast id to ObjectID")
+	}
+
+	result := collection.FindOne(ctx, bson.D{{Key: "_id", Value: oid}})
+	if err := result.Decode(out); err != nil {
+		return fmt.Errorf("failed to find document with ID %s: %w", id, err)
+	}
+
+	return nil
+}
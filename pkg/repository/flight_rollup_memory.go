@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/ansoncht/flight-microservices/pkg/model"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MemoryRollupRepository holds weekly and monthly flight summaries in memory.
+// It implements the RollupRepository interface for running without a database.
+type MemoryRollupRepository struct {
+	// mu guards the rollups.
+	mu sync.RWMutex
+	// weekly specifies the weekly flight summaries by ID.
+	weekly map[primitive.ObjectID]model.WeeklyFlightSummary
+	// monthly specifies the monthly flight summaries by ID.
+	monthly map[primitive.ObjectID]model.MonthlyFlightSummary
+}
+
+// NewMemoryRollupRepository creates a new MemoryRollupRepository instance without summaries.
+func NewMemoryRollupRepository() *MemoryRollupRepository {
+	return &MemoryRollupRepository{
+		weekly:  make(map[primitive.ObjectID]model.WeeklyFlightSummary),
+		monthly: make(map[primitive.ObjectID]model.MonthlyFlightSummary),
+	}
+}
+
+// UpsertWeekly replaces the weekly flight summary of the same airport and week in memory, or inserts it.
+func (r *MemoryRollupRepository) UpsertWeekly(_ context.Context, summary model.WeeklyFlightSummary) (string, error) {
+	return upsertRollup(&r.mu, r.weekly, summary, func(s *model.WeeklyFlightSummary) *model.FlightSummaryRollup {
+		return &s.FlightSummaryRollup
+	})
+}
+
+// GetWeekly gets a weekly flight summary from memory.
+func (r *MemoryRollupRepository) GetWeekly(_ context.Context, id string) (*model.WeeklyFlightSummary, error) {
+	return getRollup(&r.mu, r.weekly, id)
+}
+
+// UpsertMonthly replaces the monthly flight summary of the same airport and month in memory, or inserts it.
+func (r *MemoryRollupRepository) UpsertMonthly(_ context.Context, summary model.MonthlyFlightSummary) (string, error) {
+	return upsertRollup(&r.mu, r.monthly, summary, func(s *model.MonthlyFlightSummary) *model.FlightSummaryRollup {
+		return &s.FlightSummaryRollup
+	})
+}
+
+// GetMonthly gets a monthly flight summary from memory.
+func (r *MemoryRollupRepository) GetMonthly(_ context.Context, id string) (*model.MonthlyFlightSummary, error) {
+	return getRollup(&r.mu, r.monthly, id)
+}
+
+// upsertRollup stores a copy of a rollup keyed by its ID, replacing the rollup of the same airport and period and
+// keeping its ID. The base function returns the rollup embedded in a weekly or monthly summary.
+func upsertRollup[T any](
+	mu *sync.RWMutex,
+	rollups map[primitive.ObjectID]T,
+	rollup T,
+	base func(*T) *model.FlightSummaryRollup,
+) (string, error) {
+	stored, err := cloneDocument(rollup)
+	if err != nil {
+		return "", err
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	period := base(&stored)
+	period.ID = primitive.NilObjectID
+
+	for id, existing := range rollups {
+		if replaced := base(&existing); replaced.Airport == period.Airport && replaced.StartDate == period.StartDate {
+			period.ID = id
+			break
+		}
+	}
+
+	if period.ID.IsZero() {
+		period.ID = primitive.NewObjectID()
+	}
+
+	rollups[period.ID] = stored
+
+	return period.ID.Hex(), nil
+}
+
+// getRollup returns a copy of the rollup with the given ObjectID hex string.
+func getRollup[T any](mu *sync.RWMutex, rollups map[primitive.ObjectID]T, id string) (*T, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to cast id to ObjectID")
+	}
+
+	mu.RLock()
+	stored, ok := rollups[oid]
+	mu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("failed to find rollup with ID %s", id)
+	}
+
+	found, err := cloneDocument(stored)
+	if err != nil {
+		return nil, err
+	}
+
+	return &found, nil
+}
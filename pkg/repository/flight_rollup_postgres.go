@@ -0,0 +1,173 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ansoncht/flight-microservices/pkg/model"
+	"github.com/ansoncht/flight-microservices/pkg/postgres"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// rollupColumns lists the columns of the weekly and monthly summary tables in scan order.
+const rollupColumns = `id, start_date, end_date, airport, days, total_flights, daily_average, busiest_day,
+	busiest_day_flights, airline_counts, destination_counts, top_destinations, top_airlines, airline_ranking,
+	destination_ranking`
+
+// PostgresRollupRepository holds the PostgreSQL connection pool for weekly and monthly flight summaries.
+// It implements the RollupRepository interface to provide methods for storing rolled up summaries.
+type PostgresRollupRepository struct {
+	// Pool specifies the PostgreSQL connection pool for rolled up summaries.
+	Pool *pgxpool.Pool
+}
+
+// NewPostgresRollupRepository creates a new PostgresRollupRepository instance based on the provided PostgreSQL client.
+func NewPostgresRollupRepository(client *postgres.Client) (*PostgresRollupRepository, error) {
+	if client == nil {
+		return nil, fmt.Errorf("postgres client is nil")
+	}
+
+	return &PostgresRollupRepository{
+		Pool: client.Pool,
+	}, nil
+}
+
+// UpsertWeekly replaces the weekly flight summary of the same airport and week in the PostgreSQL table, or inserts it.
+func (r *PostgresRollupRepository) UpsertWeekly(ctx context.Context, summary model.WeeklyFlightSummary) (string, error) {
+	return r.upsert(ctx, weeklySummaryCollection, summary.FlightSummaryRollup)
+}
+
+// GetWeekly gets a weekly flight summary from the PostgreSQL table.
+func (r *PostgresRollupRepository) GetWeekly(ctx context.Context, id string) (*model.WeeklyFlightSummary, error) {
+	rollup, err := r.get(ctx, weeklySummaryCollection, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.WeeklyFlightSummary{FlightSummaryRollup: *rollup}, nil
+}
+
+// UpsertMonthly replaces the monthly flight summary of the same airport and month in the PostgreSQL table, or inserts
+// it.
+func (r *PostgresRollupRepository) UpsertMonthly(ctx context.Context, summary model.MonthlyFlightSummary) (string, error) {
+	return r.upsert(ctx, monthlySummaryCollection, summary.FlightSummaryRollup)
+}
+
+// GetMonthly gets a monthly flight summary from the PostgreSQL table.
+func (r *PostgresRollupRepository) GetMonthly(ctx context.Context, id string) (*model.MonthlyFlightSummary, error) {
+	rollup, err := r.get(ctx, monthlySummaryCollection, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.MonthlyFlightSummary{FlightSummaryRollup: *rollup}, nil
+}
+
+// upsert replaces the rollup of the same airport and period in the given table, or inserts it, keeping the ID of the
+// replaced rollup. The table must be one of the rollup table constants.
+func (r *PostgresRollupRepository) upsert(
+	ctx context.Context,
+	table string,
+	rollup model.FlightSummaryRollup,
+) (string, error) {
+	id := rollup.ID
+	if id.IsZero() {
+		id = primitive.NewObjectID()
+	}
+
+	//nolint:gosec // table is one of the rollup table constants
+	query := fmt.Sprintf(
+		`INSERT INTO %s (%s) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9,
+			COALESCE($10::jsonb, '{}'), COALESCE($11::jsonb, '{}'), COALESCE($12::jsonb, '[]'), COALESCE($13::jsonb, '[]'),
+			COALESCE($14::jsonb, '[]'), COALESCE($15::jsonb, '[]'))
+		ON CONFLICT (airport, start_date) DO UPDATE SET
+			end_date = EXCLUDED.end_date,
+			days = EXCLUDED.days,
+			total_flights = EXCLUDED.total_flights,
+			daily_average = EXCLUDED.daily_average,
+			busiest_day = EXCLUDED.busiest_day,
+			busiest_day_flights = EXCLUDED.busiest_day_flights,
+			airline_counts = EXCLUDED.airline_counts,
+			destination_counts = EXCLUDED.destination_counts,
+			top_destinations = EXCLUDED.top_destinations,
+			top_airlines = EXCLUDED.top_airlines,
+			airline_ranking = EXCLUDED.airline_ranking,
+			destination_ranking = EXCLUDED.destination_ranking
+		RETURNING id`,
+		table,
+		rollupColumns,
+	)
+
+	var upserted string
+	err := r.Pool.QueryRow(
+		ctx,
+		query,
+		id.Hex(),
+		rollup.StartDate.Time().UTC(),
+		rollup.EndDate.Time().UTC(),
+		rollup.Airport,
+		rollup.Days,
+		rollup.TotalFlights,
+		rollup.DailyAverage,
+		rollup.BusiestDay.Time().UTC(),
+		rollup.BusiestDayFlights,
+		rollup.AirlineCounts,
+		rollup.DestinationCounts,
+		rollup.TopDestinations,
+		rollup.TopAirlines,
+		rollup.AirlineRanking,
+		rollup.DestinationRanking,
+	).Scan(&upserted)
+	if err != nil {
+		return "", fmt.Errorf("failed to upsert to table %s: %w", table, err)
+	}
+
+	return upserted, nil
+}
+
+// get gets a rollup from the given table, which must be one of the rollup table constants.
+func (r *PostgresRollupRepository) get(ctx context.Context, table string, id string) (*model.FlightSummaryRollup, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to cast id to ObjectID")
+	}
+
+	var (
+		rollup     = &model.FlightSummaryRollup{ID: oid}
+		rowID      string
+		startDate  time.Time
+		endDate    time.Time
+		busiestDay time.Time
+	)
+
+	//nolint:gosec // table is one of the rollup table constants
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE id = $1", rollupColumns, table)
+
+	if err := r.Pool.QueryRow(ctx, query, id).Scan(
+		&rowID,
+		&startDate,
+		&endDate,
+		&rollup.Airport,
+		&rollup.Days,
+		&rollup.TotalFlights,
+		&rollup.DailyAverage,
+		&busiestDay,
+		&rollup.BusiestDayFlights,
+		&rollup.AirlineCounts,
+		&rollup.DestinationCounts,
+		&rollup.TopDestinations,
+		&rollup.TopAirlines,
+		&rollup.AirlineRanking,
+		&rollup.DestinationRanking,
+	); err != nil {
+		return nil, fmt.Errorf("failed to find row with ID %s: %w", id, err)
+	}
+
+	rollup.StartDate = model.ToMongoDateTime(startDate)
+	rollup.EndDate = model.ToMongoDateTime(endDate)
+	rollup.BusiestDay = model.ToMongoDateTime(busiestDay)
+
+	return rollup, nil
+}
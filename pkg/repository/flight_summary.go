@@ -3,12 +3,14 @@ package repository
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/ansoncht/flight-microservices/pkg/model"
 	db "github.com/ansoncht/flight-microservices/pkg/mongo"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 const dailySummaryCollection = "daily_summaries"
@@ -17,8 +19,22 @@ const dailySummaryCollection = "daily_summaries"
 type SummaryRepository interface {
 	// Insert inserts a new flight summary into the database.
 	Insert(ctx context.Context, summary model.DailyFlightSummary) (string, error)
+	// Upsert replaces the flight summary of the same airport and date, keeping its ID, or inserts it if none exists.
+	// Upserting the same summary again leaves a single summary for the day.
+	Upsert(ctx context.Context, summary model.DailyFlightSummary) (string, error)
 	// Get gets a flight summary from the database.
 	Get(ctx context.Context, id string) (*model.DailyFlightSummary, error)
+	// ListByDateRange lists the flight summaries of an airport dated within [from, to], ordered by date.
+	ListByDateRange(ctx context.Context, airport string, from time.Time, to time.Time) ([]model.DailyFlightSummary, error)
+	// ListByRoute lists the flight summaries of an airport dated within [from, to] which counted flights on the
+	// route keyed by model.RouteKey, ordered by date.
+	ListByRoute(
+		ctx context.Context,
+		airport string,
+		route string,
+		from time.Time,
+		to time.Time,
+	) ([]model.DailyFlightSummary, error)
 }
 
 // MongoSummaryRepository holds the MongoDB collection for flight summaries.
@@ -43,36 +59,99 @@ func NewMongoSummaryRepository(client *db.Client) (*MongoSummaryRepository, erro
 
 // Insert adds a flight summary to the MongoDB collection.
 func (r *MongoSummaryRepository) Insert(ctx context.Context, summary model.DailyFlightSummary) (string, error) {
-	result, err := r.Collection.InsertOne(ctx, summary)
-	if err != nil {
-		return "", fmt.Errorf("failed to insert to collection %s: %w", dailySummaryCollection, err)
+	return insertDocument(ctx, r.Collection, summary)
+}
+
+// Upsert replaces the flight summary of the same airport and date in the MongoDB collection, or inserts it.
+func (r *MongoSummaryRepository) Upsert(ctx context.Context, summary model.DailyFlightSummary) (string, error) {
+	filter := bson.D{
+		{Key: "airport", Value: summary.Airport},
+		{Key: "date", Value: summary.Date},
 	}
+	opts := options.FindOneAndReplace().
+		SetUpsert(true).
+		SetReturnDocument(options.After).
+		SetSort(bson.D{{Key: "_id", Value: 1}}).
+		SetProjection(bson.D{{Key: "_id", Value: 1}})
 
-	// convert objectid to string
-	oid, ok := result.InsertedID.(primitive.ObjectID)
-	if !ok {
-		return "", fmt.Errorf("failed to cast InsertedID to ObjectID")
+	// Keep the ID of the replaced summary
+	summary.ID = primitive.NilObjectID
+
+	var upserted struct {
+		ID primitive.ObjectID `bson:"_id"`
+	}
+	if err := r.Collection.FindOneAndReplace(ctx, filter, summary, opts).Decode(&upserted); err != nil {
+		return "", fmt.Errorf("failed to upsert to collection %s: %w", dailySummaryCollection, err)
 	}
 
-	return oid.Hex(), nil
+	return upserted.ID.Hex(), nil
 }
 
 // Get gets a flight summary from the MongoDB collection.
 func (r *MongoSummaryRepository) Get(ctx context.Context, id string) (*model.DailyFlightSummary, error) {
-	oid, err := primitive.ObjectIDFromHex(id)
+	summary := &model.DailyFlightSummary{}
+	if err := findDocument(ctx, r.Collection, id, summary); err != nil {
+		return nil, err
+	}
+
+	return summary, nil
+}
+
+// ListByDateRange lists the flight summaries of an airport dated within [from, to] from the MongoDB collection.
+// Summaries of the same date are ordered by insertion.
+func (r *MongoSummaryRepository) ListByDateRange(
+	ctx context.Context,
+	airport string,
+	from time.Time,
+	to time.Time,
+) ([]model.DailyFlightSummary, error) {
+	return r.list(ctx, dateRangeFilter(airport, from, to))
+}
+
+// ListByRoute lists the flight summaries of an airport dated within [from, to] which counted flights on the route
+// from the MongoDB collection. Summaries of the same date are ordered by insertion.
+func (r *MongoSummaryRepository) ListByRoute(
+	ctx context.Context,
+	airport string,
+	route string,
+	from time.Time,
+	to time.Time,
+) ([]model.DailyFlightSummary, error) {
+	filter := append(dateRangeFilter(airport, from, to), bson.E{
+		Key: "stats",
+		Value: bson.D{{Key: "$elemMatch", Value: bson.D{
+			{Key: "name", Value: model.RouteStatName},
+			{Key: "values.key", Value: route},
+		}}},
+	})
+
+	return r.list(ctx, filter)
+}
+
+// list finds the flight summaries matching the filter ordered by date and insertion.
+func (r *MongoSummaryRepository) list(ctx context.Context, filter bson.D) ([]model.DailyFlightSummary, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "date", Value: 1}, {Key: "_id", Value: 1}})
+
+	cursor, err := r.Collection.Find(ctx, filter, opts)
 	if err != nil {
-		return nil, fmt.Errorf("failed to cast id to ObjectID")
+		return nil, fmt.Errorf("failed to find documents in collection %s: %w", dailySummaryCollection, err)
 	}
 
-	result := r.Collection.FindOne(ctx, bson.D{{Key: "_id", Value: oid}})
+	summaries := make([]model.DailyFlightSummary, 0)
+	if err := cursor.All(ctx, &summaries); err != nil {
+		return nil, fmt.Errorf("failed to decode documents: %w", err)
+	}
 
-	var doc bson.M
-	_ = result.Decode(&doc)
-	fmt.Printf("Document: %+v\n", doc)
+	return summaries, nil
+}
 
-	summary := &model.DailyFlightSummary{}
-	if err := result.Decode(summary); err != nil {
-		return nil, fmt.Errorf("failed to find document with ID %s: %w", id, err)
+// dateRangeFilter matches the flight summaries of an airport dated within [from, to].
+func dateRangeFilter(airport string, from time.Time, to time.Time) bson.D {
+	return bson.D{
+		{Key: "airport", Value: airport},
+		{Key: "date", Value: bson.D{
+			{Key: "$gte", Value: model.ToMongoDateTime(from)},
+			{Key: "$lte", Value: model.ToMongoDateTime(to)},
+		}},
 	}
-	return summary, nil
 }
@@ -0,0 +1,195 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ansoncht/flight-microservices/pkg/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MemorySummaryRepository holds flight summaries in memory.
+// It implements the SummaryRepository interface for running without a database, keeping summaries for the lifetime
+// of the process.
+type MemorySummaryRepository struct {
+	// mu guards the summaries.
+	mu sync.RWMutex
+	// summaries specifies the stored summaries in insertion order.
+	summaries []model.DailyFlightSummary
+}
+
+// NewMemorySummaryRepository creates a new MemorySummaryRepository instance without summaries.
+func NewMemorySummaryRepository() *MemorySummaryRepository {
+	return &MemorySummaryRepository{
+		summaries: make([]model.DailyFlightSummary, 0),
+	}
+}
+
+// Insert adds a flight summary to memory.
+func (r *MemorySummaryRepository) Insert(_ context.Context, summary model.DailyFlightSummary) (string, error) {
+	stored, err := cloneDocument(summary)
+	if err != nil {
+		return "", err
+	}
+
+	if stored.ID.IsZero() {
+		stored.ID = primitive.NewObjectID()
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.summaries = append(r.summaries, stored)
+
+	return stored.ID.Hex(), nil
+}
+
+// Upsert replaces the flight summary of the same airport and date in memory, or inserts it.
+func (r *MemorySummaryRepository) Upsert(_ context.Context, summary model.DailyFlightSummary) (string, error) {
+	stored, err := cloneDocument(summary)
+	if err != nil {
+		return "", err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	kept := make([]model.DailyFlightSummary, 0, len(r.summaries))
+	stored.ID = primitive.NilObjectID
+
+	for _, existing := range r.summaries {
+		if existing.Airport != stored.Airport || existing.Date != stored.Date {
+			kept = append(kept, existing)
+			continue
+		}
+
+		// Keep the ID of the first replaced summary
+		if stored.ID.IsZero() {
+			stored.ID = existing.ID
+		}
+	}
+
+	if stored.ID.IsZero() {
+		stored.ID = primitive.NewObjectID()
+	}
+
+	r.summaries = append(kept, stored)
+
+	return stored.ID.Hex(), nil
+}
+
+// Get gets a flight summary from memory.
+func (r *MemorySummaryRepository) Get(_ context.Context, id string) (*model.DailyFlightSummary, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to cast id to ObjectID")
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, summary := range r.summaries {
+		if summary.ID == oid {
+			found, err := cloneDocument(summary)
+			if err != nil {
+				return nil, err
+			}
+
+			return &found, nil
+		}
+	}
+
+	return nil, fmt.Errorf("failed to find summary with ID %s", id)
+}
+
+// ListByDateRange lists the flight summaries of an airport dated within [from, to] from memory.
+// Summaries of the same date are ordered by insertion.
+func (r *MemorySummaryRepository) ListByDateRange(
+	_ context.Context,
+	airport string,
+	from time.Time,
+	to time.Time,
+) ([]model.DailyFlightSummary, error) {
+	return r.list(airport, from, to, func(model.DailyFlightSummary) bool { return true })
+}
+
+// ListByRoute lists the flight summaries of an airport dated within [from, to] which counted flights on the route
+// from memory. Summaries of the same date are ordered by insertion.
+func (r *MemorySummaryRepository) ListByRoute(
+	_ context.Context,
+	airport string,
+	route string,
+	from time.Time,
+	to time.Time,
+) ([]model.DailyFlightSummary, error) {
+	return r.list(airport, from, to, func(summary model.DailyFlightSummary) bool {
+		routes := summary.Stat(model.RouteStatName)
+		if routes == nil {
+			return false
+		}
+
+		for _, value := range routes.Values {
+			if value.Key == route {
+				return true
+			}
+		}
+
+		return false
+	})
+}
+
+// list returns the flight summaries of an airport dated within [from, to] matching the filter, ordered by date and
+// insertion.
+func (r *MemorySummaryRepository) list(
+	airport string,
+	from time.Time,
+	to time.Time,
+	filter func(model.DailyFlightSummary) bool,
+) ([]model.DailyFlightSummary, error) {
+	lower := model.ToMongoDateTime(from)
+	upper := model.ToMongoDateTime(to)
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	summaries := make([]model.DailyFlightSummary, 0)
+	for _, summary := range r.summaries {
+		if summary.Airport != airport || summary.Date < lower || summary.Date > upper || !filter(summary) {
+			continue
+		}
+
+		found, err := cloneDocument(summary)
+		if err != nil {
+			return nil, err
+		}
+
+		summaries = append(summaries, found)
+	}
+
+	sort.SliceStable(summaries, func(i, j int) bool {
+		return summaries[i].Date < summaries[j].Date
+	})
+
+	return summaries, nil
+}
+
+// cloneDocument deep copies a document through its BSON encoding, so that stored documents are decoded the way the
+// MongoDB repository decodes them and are not shared with callers.
+func cloneDocument[T any](document T) (T, error) {
+	var clone T
+
+	data, err := bson.Marshal(document)
+	if err != nil {
+		return clone, fmt.Errorf("failed to encode document: %w", err)
+	}
+
+	if err := bson.Unmarshal(data, &clone); err != nil {
+		return clone, fmt.Errorf("failed to decode document: %w", err)
+	}
+
+	return clone, nil
+}
@@ -0,0 +1,51 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ansoncht/flight-microservices/pkg/model"
+	"github.com/ansoncht/flight-microservices/pkg/mongo"
+	"github.com/ansoncht/flight-microservices/pkg/postgres"
+	"github.com/ansoncht/flight-microservices/pkg/repository"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemorySummaryRepository_Conformance(t *testing.T) {
+	testSummaryRepositoryConformance(t, repository.NewMemorySummaryRepository())
+	testRollupRepositoryConformance(t, repository.NewMemoryRollupRepository())
+}
+
+func TestMemorySummaryRepository_Get_ShouldReturnCopy(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewMemorySummaryRepository()
+
+	summary := model.DailyFlightSummary{Airport: "HKG", AirlineCounts: map[string]int{"Cathay Pacific": 1}}
+	id, err := repo.Insert(ctx, summary)
+	require.NoError(t, err)
+
+	// Changing the inserted or returned summary must not change the stored one
+	summary.AirlineCounts["Cathay Pacific"] = 2
+
+	got, err := repo.Get(ctx, id)
+	require.NoError(t, err)
+	require.Equal(t, 1, got.AirlineCounts["Cathay Pacific"])
+	got.AirlineCounts["Cathay Pacific"] = 3
+
+	got, err = repo.Get(ctx, id)
+	require.NoError(t, err)
+	require.Equal(t, 1, got.AirlineCounts["Cathay Pacific"])
+}
+
+func TestNewRepositories_MemoryDriver_ShouldSucceed(t *testing.T) {
+	repos, err := repository.NewRepositories(
+		context.Background(),
+		repository.Config{Driver: repository.DriverMemory},
+		mongo.ClientConfig{},
+		postgres.ClientConfig{},
+	)
+	require.NoError(t, err)
+	require.IsType(t, &repository.MemorySummaryRepository{}, repos.Summaries)
+	require.IsType(t, &repository.MemoryRollupRepository{}, repos.Rollups)
+	require.NoError(t, repos.Close(context.Background()))
+}
@@ -0,0 +1,265 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ansoncht/flight-microservices/pkg/model"
+	"github.com/ansoncht/flight-microservices/pkg/postgres"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// insertDailySummary inserts a flight summary bound by the arguments of dailySummaryArgs.
+const insertDailySummary = `INSERT INTO daily_summaries (
+			id, date, airport, total_flights, duplicates_removed, airline_counts, destination_counts,
+			top_destinations, top_airlines, airline_ranking, destination_ranking, hourly_departures, busiest_hour,
+			busiest_hour_flights, quietest_hour, quietest_hour_flights, day_over_day, week_over_week, anomalies, stats
+		) VALUES (
+			@id, @date, @airport, @total_flights, @duplicates_removed,
+			COALESCE(@airline_counts::jsonb, '{}'), COALESCE(@destination_counts::jsonb, '{}'),
+			COALESCE(@top_destinations::jsonb, '[]'), COALESCE(@top_airlines::jsonb, '[]'),
+			COALESCE(@airline_ranking::jsonb, '[]'), COALESCE(@destination_ranking::jsonb, '[]'),
+			COALESCE(@hourly_departures::jsonb, '[]'), @busiest_hour, @busiest_hour_flights, @quietest_hour,
+			@quietest_hour_flights, @day_over_day, @week_over_week, COALESCE(@anomalies::jsonb, '[]'),
+			COALESCE(@stats::jsonb, '[]')
+		)`
+
+// PostgresSummaryRepository holds the PostgreSQL connection pool for flight summaries.
+// It implements the SummaryRepository interface to provide methods for inserting flight summary data.
+type PostgresSummaryRepository struct {
+	// Pool specifies the PostgreSQL connection pool for flight summaries.
+	Pool *pgxpool.Pool
+}
+
+// NewPostgresSummaryRepository creates a new PostgresSummaryRepository instance based on the provided PostgreSQL client.
+func NewPostgresSummaryRepository(client *postgres.Client) (*PostgresSummaryRepository, error) {
+	if client == nil {
+		return nil, fmt.Errorf("postgres client is nil")
+	}
+
+	return &PostgresSummaryRepository{
+		Pool: client.Pool,
+	}, nil
+}
+
+// Insert adds a flight summary to the PostgreSQL table.
+// Summaries are keyed by an ObjectID hex string so that IDs are interchangeable with the MongoDB repository.
+// Nil maps and slices are encoded as SQL NULL, so they are stored as empty JSON instead.
+// A day holds a single summary per airport, so inserting a second summary of the same airport and date fails.
+func (r *PostgresSummaryRepository) Insert(ctx context.Context, summary model.DailyFlightSummary) (string, error) {
+	id := summary.ID
+	if id.IsZero() {
+		id = primitive.NewObjectID()
+	}
+
+	if _, err := r.Pool.Exec(ctx, insertDailySummary, dailySummaryArgs(id, summary)); err != nil {
+		return "", fmt.Errorf("failed to insert to table %s: %w", dailySummaryCollection, err)
+	}
+
+	return id.Hex(), nil
+}
+
+// Upsert replaces the flight summary of the same airport and date in the PostgreSQL table, or inserts it, keeping the
+// ID of the replaced summary. The unique index on the airport and date makes concurrent upserts of a day update the
+// same row instead of inserting one each.
+func (r *PostgresSummaryRepository) Upsert(ctx context.Context, summary model.DailyFlightSummary) (string, error) {
+	id := summary.ID
+	if id.IsZero() {
+		id = primitive.NewObjectID()
+	}
+
+	var upserted string
+	err := r.Pool.QueryRow(
+		ctx,
+		insertDailySummary+`
+		ON CONFLICT (airport, date) DO UPDATE SET
+			total_flights = EXCLUDED.total_flights,
+			duplicates_removed = EXCLUDED.duplicates_removed,
+			airline_counts = EXCLUDED.airline_counts,
+			destination_counts = EXCLUDED.destination_counts,
+			top_destinations = EXCLUDED.top_destinations,
+			top_airlines = EXCLUDED.top_airlines,
+			airline_ranking = EXCLUDED.airline_ranking,
+			destination_ranking = EXCLUDED.destination_ranking,
+			hourly_departures = EXCLUDED.hourly_departures,
+			busiest_hour = EXCLUDED.busiest_hour,
+			busiest_hour_flights = EXCLUDED.busiest_hour_flights,
+			quietest_hour = EXCLUDED.quietest_hour,
+			quietest_hour_flights = EXCLUDED.quietest_hour_flights,
+			day_over_day = EXCLUDED.day_over_day,
+			week_over_week = EXCLUDED.week_over_week,
+			anomalies = EXCLUDED.anomalies,
+			stats = EXCLUDED.stats
+		RETURNING id`,
+		dailySummaryArgs(id, summary),
+	).Scan(&upserted)
+	if err != nil {
+		return "", fmt.Errorf("failed to upsert to table %s: %w", dailySummaryCollection, err)
+	}
+
+	return upserted, nil
+}
+
+// dailySummaryArgs returns the arguments inserting a flight summary with the given ID.
+func dailySummaryArgs(id primitive.ObjectID, summary model.DailyFlightSummary) pgx.NamedArgs {
+	return pgx.NamedArgs{
+		"id":                    id.Hex(),
+		"date":                  summary.Date.Time().UTC(),
+		"airport":               summary.Airport,
+		"total_flights":         summary.TotalFlights,
+		"duplicates_removed":    summary.DuplicatesRemoved,
+		"airline_counts":        summary.AirlineCounts,
+		"destination_counts":    summary.DestinationCounts,
+		"top_destinations":      summary.TopDestinations,
+		"top_airlines":          summary.TopAirlines,
+		"airline_ranking":       summary.AirlineRanking,
+		"destination_ranking":   summary.DestinationRanking,
+		"hourly_departures":     summary.HourlyDepartures,
+		"busiest_hour":          summary.BusiestHour,
+		"busiest_hour_flights":  summary.BusiestHourFlights,
+		"quietest_hour":         summary.QuietestHour,
+		"quietest_hour_flights": summary.QuietestHourFlights,
+		"day_over_day":          summary.DayOverDay,
+		"week_over_week":        summary.WeekOverWeek,
+		"anomalies":             summary.Anomalies,
+		"stats":                 summary.Stats,
+	}
+}
+
+// Get gets a flight summary from the PostgreSQL table.
+func (r *PostgresSummaryRepository) Get(ctx context.Context, id string) (*model.DailyFlightSummary, error) {
+	if _, err := primitive.ObjectIDFromHex(id); err != nil {
+		return nil, fmt.Errorf("failed to cast id to ObjectID")
+	}
+
+	summary, err := scanDailySummary(r.Pool.QueryRow(
+		ctx,
+		"SELECT "+dailySummaryColumns+" FROM daily_summaries WHERE id = $1",
+		id,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find row with ID %s: %w", id, err)
+	}
+
+	return summary, nil
+}
+
+// ListByDateRange lists the flight summaries of an airport dated within [from, to] from the PostgreSQL table.
+// Summaries of the same date are ordered by insertion.
+func (r *PostgresSummaryRepository) ListByDateRange(
+	ctx context.Context,
+	airport string,
+	from time.Time,
+	to time.Time,
+) ([]model.DailyFlightSummary, error) {
+	return r.list(ctx, "airport = $1 AND date BETWEEN $2 AND $3", airport, from.UTC(), to.UTC())
+}
+
+// ListByRoute lists the flight summaries of an airport dated within [from, to] which counted flights on the route
+// from the PostgreSQL table. Summaries of the same date are ordered by insertion.
+// Stats are stored as JSON with the Go field names, which the containment query matches.
+func (r *PostgresSummaryRepository) ListByRoute(
+	ctx context.Context,
+	airport string,
+	route string,
+	from time.Time,
+	to time.Time,
+) ([]model.DailyFlightSummary, error) {
+	return r.list(
+		ctx,
+		`airport = $1 AND date BETWEEN $2 AND $3 AND stats @> jsonb_build_array(jsonb_build_object(
+			'name', $4::text, 'values', jsonb_build_array(jsonb_build_object('key', $5::text))
+		))`,
+		airport,
+		from.UTC(),
+		to.UTC(),
+		model.RouteStatName,
+		route,
+	)
+}
+
+// list selects the flight summaries matching the where clause ordered by date and insertion.
+func (r *PostgresSummaryRepository) list(
+	ctx context.Context,
+	where string,
+	args ...any,
+) ([]model.DailyFlightSummary, error) {
+	rows, err := r.Pool.Query(
+		ctx,
+		"SELECT "+dailySummaryColumns+" FROM daily_summaries WHERE "+where+" ORDER BY date, id",
+		args...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query table %s: %w", dailySummaryCollection, err)
+	}
+	defer rows.Close()
+
+	summaries := make([]model.DailyFlightSummary, 0)
+	for rows.Next() {
+		summary, err := scanDailySummary(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+
+		summaries = append(summaries, *summary)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read rows: %w", err)
+	}
+
+	return summaries, nil
+}
+
+// dailySummaryColumns lists the columns read by scanDailySummary in scan order.
+const dailySummaryColumns = `id, date, airport, total_flights, airline_counts, destination_counts,
+	top_destinations, top_airlines, hourly_departures, busiest_hour, busiest_hour_flights, quietest_hour,
+	quietest_hour_flights, day_over_day, week_over_week,
+	anomalies, stats, airline_ranking, destination_ranking, duplicates_removed`
+
+// scanDailySummary scans a row selected with dailySummaryColumns into a DailyFlightSummary.
+func scanDailySummary(row pgx.Row) (*model.DailyFlightSummary, error) {
+	var (
+		summary model.DailyFlightSummary
+		id      string
+		date    time.Time
+	)
+
+	if err := row.Scan(
+		&id,
+		&date,
+		&summary.Airport,
+		&summary.TotalFlights,
+		&summary.AirlineCounts,
+		&summary.DestinationCounts,
+		&summary.TopDestinations,
+		&summary.TopAirlines,
+		&summary.HourlyDepartures,
+		&summary.BusiestHour,
+		&summary.BusiestHourFlights,
+		&summary.QuietestHour,
+		&summary.QuietestHourFlights,
+		&summary.DayOverDay,
+		&summary.WeekOverWeek,
+		&summary.Anomalies,
+		&summary.Stats,
+		&summary.AirlineRanking,
+		&summary.DestinationRanking,
+		&summary.DuplicatesRemoved,
+	); err != nil {
+		return nil, fmt.Errorf("failed to scan daily summary: %w", err)
+	}
+
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to cast id to ObjectID: %w", err)
+	}
+
+	summary.ID = oid
+	summary.Date = model.ToMongoDateTime(date)
+
+	return &summary, nil
+}
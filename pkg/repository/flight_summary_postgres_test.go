@@ -0,0 +1,121 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ansoncht/flight-microservices/pkg/model"
+	"github.com/ansoncht/flight-microservices/pkg/postgres"
+	"github.com/ansoncht/flight-microservices/pkg/repository"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+	tcpostgres "github.com/testcontainers/testcontainers-go/modules/postgres"
+	"golang.org/x/sync/errgroup"
+)
+
+// setupPostgresClient spins up a migrated PostgreSQL container and returns a client connected to it.
+func setupPostgresClient(ctx context.Context, t *testing.T) *postgres.Client {
+	t.Helper()
+
+	postgresContainer, err := tcpostgres.Run(ctx,
+		"postgres:16-alpine",
+		tcpostgres.WithDatabase("testdb"),
+		tcpostgres.WithUsername("test"),
+		tcpostgres.WithPassword("test"),
+		tcpostgres.BasicWaitStrategies(),
+	)
+	t.Cleanup(func() {
+		err := testcontainers.TerminateContainer(postgresContainer)
+		require.NoError(t, err)
+	})
+	require.NoError(t, err)
+
+	uri, err := postgresContainer.ConnectionString(ctx, "sslmode=disable")
+	require.NoError(t, err)
+
+	cfg := postgres.ClientConfig{
+		URI:               uri,
+		PoolSize:          5,
+		ConnectionTimeout: 10,
+	}
+	client, err := postgres.NewPostgresClient(ctx, cfg)
+	require.NoError(t, err)
+	t.Cleanup(client.Close)
+
+	err = client.Migrate(ctx)
+	require.NoError(t, err)
+
+	return client
+}
+
+func TestNewPostgresSummaryRepository_NilClient_ShouldError(t *testing.T) {
+	var client *postgres.Client
+	repo, err := repository.NewPostgresSummaryRepository(client)
+	require.ErrorContains(t, err, "postgres client is nil")
+	require.Nil(t, repo)
+}
+
+func TestPostgresSummaryRepository_Conformance_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	client := setupPostgresClient(ctx, t)
+
+	repo, err := repository.NewPostgresSummaryRepository(client)
+	require.NoError(t, err)
+	require.NotNil(t, repo)
+
+	rollups, err := repository.NewPostgresRollupRepository(client)
+	require.NoError(t, err)
+	require.NotNil(t, rollups)
+
+	testSummaryRepositoryConformance(t, repo)
+	testRollupRepositoryConformance(t, rollups)
+}
+
+func TestPostgresSummaryRepository_ConcurrentUpsert_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	client := setupPostgresClient(ctx, t)
+
+	repo, err := repository.NewPostgresSummaryRepository(client)
+	require.NoError(t, err)
+
+	date := model.ToMongoDateTime(time.Date(2024, 4, 2, 0, 0, 0, 0, time.UTC))
+	ids := make([]string, 5)
+
+	g, gCtx := errgroup.WithContext(ctx)
+	for i := range ids {
+		g.Go(func() error {
+			id, err := repo.Upsert(gCtx, model.DailyFlightSummary{Date: date, Airport: "HKG", TotalFlights: i})
+			ids[i] = id
+
+			return err
+		})
+	}
+	require.NoError(t, g.Wait())
+
+	for _, id := range ids {
+		require.Equal(t, ids[0], id)
+	}
+
+	listed, err := repo.ListByDateRange(ctx, "HKG", date.Time(), date.Time())
+	require.NoError(t, err)
+	require.Len(t, listed, 1)
+
+	_, err = repo.Insert(ctx, model.DailyFlightSummary{Date: date, Airport: "HKG"})
+	require.ErrorContains(t, err, "failed to insert to table")
+}
+
+func TestNewPostgresRollupRepository_NilClient_ShouldError(t *testing.T) {
+	var client *postgres.Client
+	repo, err := repository.NewPostgresRollupRepository(client)
+	require.ErrorContains(t, err, "postgres client is nil")
+	require.Nil(t, repo)
+}
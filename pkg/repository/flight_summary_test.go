@@ -99,3 +99,56 @@ func TestInsert_Integration(t *testing.T) {
 	require.NoError(t, err)
 	require.NotEmpty(t, id)
 }
+
+func TestMongoSummaryRepository_Conformance_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+
+	// Start a MongoDB container
+	mongodbContainer, err := mongodb.Run(ctx, "mongo:6")
+	defer func() {
+		err := testcontainers.TerminateContainer(mongodbContainer)
+		require.NoError(t, err)
+	}()
+	require.NoError(t, err)
+
+	uri, err := mongodbContainer.ConnectionString(ctx)
+	require.NoError(t, err)
+
+	cfg := mongo.ClientConfig{
+		URI:               uri,
+		DB:                "testdb",
+		PoolSize:          5,
+		ConnectionTimeout: 10,
+		SocketTimeout:     10,
+	}
+
+	mongo, err := mongo.NewMongoClient(ctx, cfg)
+	defer func() {
+		err = mongo.Client.Disconnect(ctx)
+		require.NoError(t, err)
+	}()
+	require.NoError(t, err)
+	require.NotNil(t, mongo)
+
+	repo, err := repository.NewMongoSummaryRepository(mongo)
+	require.NoError(t, err)
+	require.NotNil(t, repo)
+
+	rollups, err := repository.NewMongoRollupRepository(mongo)
+	require.NoError(t, err)
+	require.NotNil(t, rollups)
+
+	testSummaryRepositoryConformance(t, repo)
+	testRollupRepositoryConformance(t, rollups)
+}
+
+func TestNewMongoRollupRepository_NilClient_ShouldError(t *testing.T) {
+	var client *mongo.Client
+	repo, err := repository.NewMongoRollupRepository(client)
+	require.ErrorContains(t, err, "mongo client is nil")
+	require.Nil(t, repo)
+}
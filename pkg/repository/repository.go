@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	db "github.com/ansoncht/flight-microservices/pkg/mongo"
+	"github.com/ansoncht/flight-microservices/pkg/postgres"
+)
+
+const (
+	// DriverMongo selects the MongoDB repositories.
+	DriverMongo = "mongo"
+	// DriverPostgres selects the PostgreSQL repositories.
+	DriverPostgres = "postgres"
+	// DriverMemory selects the in-memory repositories, which keep summaries for the lifetime of the process.
+	DriverMemory = "memory"
+)
+
+// Config holds configuration settings for the repositories.
+type Config struct {
+	// Driver specifies the database backend storing summaries, either "mongo", "postgres" or "memory".
+	Driver string `mapstructure:"driver"`
+}
+
+// Repositories holds the repositories backed by the configured database driver.
+type Repositories struct {
+	// Summaries specifies the repository for daily flight summaries.
+	Summaries SummaryRepository
+	// Rollups specifies the repository for weekly and monthly flight summaries.
+	Rollups RollupRepository
+	// Check returns an error if the underlying database cannot be reached.
+	Check func(ctx context.Context) error
+	// Close closes the underlying database client.
+	Close func(ctx context.Context) error
+}
+
+// NewRepositories creates the repositories for the configured driver.
+// Pending PostgreSQL migrations are applied on creation.
+func NewRepositories(
+	ctx context.Context,
+	cfg Config,
+	mongoCfg db.ClientConfig,
+	postgresCfg postgres.ClientConfig,
+) (*Repositories, error) {
+	switch cfg.Driver {
+	case DriverMongo:
+		client, err := db.NewMongoClient(ctx, mongoCfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create MongoDB client: %w", err)
+		}
+
+		summaries, err := NewMongoSummaryRepository(client)
+		if err != nil {
+			return nil, err
+		}
+
+		rollups, err := NewMongoRollupRepository(client)
+		if err != nil {
+			return nil, err
+		}
+
+		return &Repositories{
+			Summaries: summaries,
+			Rollups:   rollups,
+			Check:     client.Check,
+			Close:     client.Client.Disconnect,
+		}, nil
+	case DriverPostgres:
+		client, err := postgres.NewPostgresClient(ctx, postgresCfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create PostgreSQL client: %w", err)
+		}
+
+		if err := client.Migrate(ctx); err != nil {
+			client.Close()
+			return nil, fmt.Errorf("failed to migrate PostgreSQL: %w", err)
+		}
+
+		summaries, err := NewPostgresSummaryRepository(client)
+		if err != nil {
+			return nil, err
+		}
+
+		rollups, err := NewPostgresRollupRepository(client)
+		if err != nil {
+			return nil, err
+		}
+
+		return &Repositories{
+			Summaries: summaries,
+			Rollups:   rollups,
+			Check:     client.Check,
+			Close: func(context.Context) error {
+				client.Close()
+				return nil
+			},
+		}, nil
+	case DriverMemory:
+		return &Repositories{
+			Summaries: NewMemorySummaryRepository(),
+			Rollups:   NewMemoryRollupRepository(),
+			Check: func(context.Context) error {
+				return nil
+			},
+			Close: func(context.Context) error {
+				return nil
+			},
+		}, nil
+	default:
+		return nil, fmt.Errorf("repository driver is invalid: %s", cfg.Driver)
+	}
+}
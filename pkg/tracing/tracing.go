@@ -0,0 +1,144 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Supported span exporters.
+const (
+	ExporterOTLP   = "otlp"
+	ExporterStdout = "stdout"
+)
+
+// Config holds configuration settings for tracing.
+type Config struct {
+	// Enabled specifies whether spans are recorded and exported.
+	Enabled bool `mapstructure:"enabled"`
+	// Exporter specifies where spans are exported: otlp or stdout.
+	Exporter string `mapstructure:"exporter"`
+	// Endpoint specifies the host and port of the OTLP HTTP collector.
+	// Defaults to the OTEL_EXPORTER_OTLP_ENDPOINT environment variable, or localhost:4318.
+	Endpoint string `mapstructure:"endpoint"`
+	// Insecure specifies whether spans are exported to the OTLP collector without TLS.
+	Insecure bool `mapstructure:"insecure"`
+	// SampleRatio specifies the fraction of traces started by the service which are sampled, from 0 to 1.
+	// Traces continued from another service follow the sampling decision of their parent.
+	SampleRatio float64 `mapstructure:"sample_ratio"`
+}
+
+// Provider holds the tracer provider of the service.
+type Provider struct {
+	// provider specifies the tracer provider exporting spans, nil when tracing is disabled.
+	provider *sdktrace.TracerProvider
+}
+
+// NewTracerProvider creates a new Provider instance based on the provided configuration and installs it as the
+// global tracer provider, along with the W3C trace context propagator.
+// Tracing is a no-op when it is disabled.
+func NewTracerProvider(ctx context.Context, cfg Config, serviceName string) (*Provider, error) {
+	slog.Info(
+		"Initializing tracer provider for the service",
+		"enabled", cfg.Enabled,
+		"exporter", cfg.Exporter,
+		"endpoint", cfg.Endpoint,
+	)
+
+	if !cfg.Enabled {
+		return &Provider{}, nil
+	}
+
+	if serviceName == "" {
+		return nil, fmt.Errorf("tracing service name is empty")
+	}
+
+	if cfg.SampleRatio < 0 || cfg.SampleRatio > 1 {
+		return nil, fmt.Errorf("tracing sample ratio is invalid: %v", cfg.SampleRatio)
+	}
+
+	exporter, err := newExporter(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(semconv.ServiceName(serviceName))),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	)
+
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	return &Provider{
+		provider: provider,
+	}, nil
+}
+
+// Close flushes the spans which have not been exported yet and stops the tracer provider.
+func (p *Provider) Close(ctx context.Context) error {
+	if p.provider == nil {
+		return nil
+	}
+
+	if err := p.provider.Shutdown(ctx); err != nil {
+		return fmt.Errorf("failed to shutdown tracer provider: %w", err)
+	}
+
+	return nil
+}
+
+// newExporter creates the span exporter set in the configuration.
+func newExporter(ctx context.Context, cfg Config) (sdktrace.SpanExporter, error) {
+	switch cfg.Exporter {
+	case ExporterOTLP:
+		opts := make([]otlptracehttp.Option, 0)
+		if cfg.Endpoint != "" {
+			opts = append(opts, otlptracehttp.WithEndpoint(cfg.Endpoint))
+		}
+
+		if cfg.Insecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+
+		exporter, err := otlptracehttp.New(ctx, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+		}
+
+		return exporter, nil
+	case ExporterStdout:
+		exporter, err := stdouttrace.New()
+		if err != nil {
+			return nil, fmt.Errorf("failed to create stdout exporter: %w", err)
+		}
+
+		return exporter, nil
+	default:
+		return nil, fmt.Errorf("tracing exporter is invalid: %s", cfg.Exporter)
+	}
+}
+
+// End records the error, if any, on the span and ends it.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End()
+}
@@ -0,0 +1,106 @@
+package tracing_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ansoncht/flight-microservices/pkg/tracing"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestNewTracerProvider_Disabled_ShouldSucceed(t *testing.T) {
+	provider, err := tracing.NewTracerProvider(context.Background(), tracing.Config{}, "")
+	require.NoError(t, err)
+	require.NotNil(t, provider)
+	require.NoError(t, provider.Close(context.Background()))
+}
+
+func TestNewTracerProvider_StdoutExporter_ShouldSucceed(t *testing.T) {
+	provider, err := tracing.NewTracerProvider(context.Background(), tracing.Config{
+		Enabled:     true,
+		Exporter:    tracing.ExporterStdout,
+		SampleRatio: 1,
+	}, "flight-test")
+	require.NoError(t, err)
+	require.NotNil(t, provider)
+	require.NoError(t, provider.Close(context.Background()))
+}
+
+func TestNewTracerProvider_OTLPExporter_ShouldSucceed(t *testing.T) {
+	provider, err := tracing.NewTracerProvider(context.Background(), tracing.Config{
+		Enabled:     true,
+		Exporter:    tracing.ExporterOTLP,
+		Endpoint:    "localhost:4318",
+		Insecure:    true,
+		SampleRatio: 0.5,
+	}, "flight-test")
+	require.NoError(t, err)
+	require.NotNil(t, provider)
+}
+
+func TestNewTracerProvider_InvalidConfig_ShouldError(t *testing.T) {
+	tests := []struct {
+		name        string
+		cfg         tracing.Config
+		serviceName string
+		wantErr     string
+	}{
+		{
+			name:        "Empty Service Name",
+			cfg:         tracing.Config{Enabled: true, Exporter: tracing.ExporterStdout, SampleRatio: 1},
+			serviceName: "",
+			wantErr:     "tracing service name is empty",
+		},
+		{
+			name:        "Negative Sample Ratio",
+			cfg:         tracing.Config{Enabled: true, Exporter: tracing.ExporterStdout, SampleRatio: -0.1},
+			serviceName: "flight-test",
+			wantErr:     "tracing sample ratio is invalid",
+		},
+		{
+			name:        "Sample Ratio Above One",
+			cfg:         tracing.Config{Enabled: true, Exporter: tracing.ExporterStdout, SampleRatio: 1.5},
+			serviceName: "flight-test",
+			wantErr:     "tracing sample ratio is invalid",
+		},
+		{
+			name:        "Invalid Exporter",
+			cfg:         tracing.Config{Enabled: true, Exporter: "zipkin", SampleRatio: 1},
+			serviceName: "flight-test",
+			wantErr:     "tracing exporter is invalid",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider, err := tracing.NewTracerProvider(context.Background(), tt.cfg, tt.serviceName)
+			require.ErrorContains(t, err, tt.wantErr)
+			require.Nil(t, provider)
+		})
+	}
+}
+
+func TestEnd_ShouldRecordError(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("test")
+
+	_, succeeded := tracer.Start(context.Background(), "succeeded")
+	tracing.End(succeeded, nil)
+
+	_, failed := tracer.Start(context.Background(), "failed")
+	tracing.End(failed, errors.New("upstream unavailable"))
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+
+	require.Equal(t, codes.Unset, spans[0].Status().Code)
+	require.Empty(t, spans[0].Events())
+
+	require.Equal(t, codes.Error, spans[1].Status().Code)
+	require.Equal(t, "upstream unavailable", spans[1].Status().Description)
+	require.Len(t, spans[1].Events(), 1)
+}